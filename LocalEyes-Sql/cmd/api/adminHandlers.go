@@ -0,0 +1,153 @@
+package api
+
+import (
+	"fmt"
+	"localEyes/internal/models"
+	"localEyes/internal/render"
+	"localEyes/utils"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+func (s *Server) adminListUsers(w http.ResponseWriter, r *http.Request, admin *models.Admin) {
+	page, ok := pageRequest(w, r)
+	if !ok {
+		return
+	}
+	users, err := s.Admin.ListUsers(admin, page)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	utils.Logger.Println("INFO:Admin", admin.User.Username, "viewed page", users.Request.Number, "of the users")
+	writePage(w, r, render.Users(users.Items).Records, users.Request, users.HasPrev(), users.HasNext)
+}
+
+// adminListPosts serves GET /admin/posts, optionally only the posts from
+// ?city=.
+func (s *Server) adminListPosts(w http.ResponseWriter, r *http.Request, admin *models.Admin) {
+	city := r.URL.Query().Get("city")
+	if err := s.Cities.ValidateCity(city); err != nil {
+		writeError(w, err)
+		return
+	}
+	page, ok := pageRequest(w, r)
+	if !ok {
+		return
+	}
+	posts, err := s.Admin.ListPosts(admin, city, page)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	utils.Logger.Println("INFO:Admin", admin.User.Username, "viewed page", posts.Request.Number, "of the posts")
+	writePage(w, r, render.Posts(posts.Items).Records, posts.Request, posts.HasPrev(), posts.HasNext)
+}
+
+// adminListQuestions serves GET /admin/questions, optionally only the
+// questions on posts from ?city=.
+func (s *Server) adminListQuestions(w http.ResponseWriter, r *http.Request, admin *models.Admin) {
+	city := r.URL.Query().Get("city")
+	if err := s.Cities.ValidateCity(city); err != nil {
+		writeError(w, err)
+		return
+	}
+	page, ok := pageRequest(w, r)
+	if !ok {
+		return
+	}
+	questions, err := s.Admin.ListQuestions(admin, city, page)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	utils.Logger.Println("INFO:Admin", admin.User.Username, "viewed page", questions.Request.Number, "of the questions")
+	writePage(w, r, render.Questions(questions.Items).Records, questions.Request, questions.HasPrev(), questions.HasNext)
+}
+
+func (s *Server) adminDeleteUser(w http.ResponseWriter, r *http.Request, admin *models.Admin) {
+	s.adminModify(w, r, admin, "deleted user", s.Admin.DeleteUser)
+}
+
+func (s *Server) adminReactivateUser(w http.ResponseWriter, r *http.Request, admin *models.Admin) {
+	s.adminModify(w, r, admin, "reactivated user", s.Admin.ReActivate)
+}
+
+func (s *Server) adminDeletePost(w http.ResponseWriter, r *http.Request, admin *models.Admin) {
+	s.adminModify(w, r, admin, "deleted post", s.Admin.DeletePost)
+}
+
+func (s *Server) adminDeleteQuestion(w http.ResponseWriter, r *http.Request, admin *models.Admin) {
+	s.adminModify(w, r, admin, "deleted question", s.Admin.DeleteQuestion)
+}
+
+func (s *Server) adminDeleteAnswer(w http.ResponseWriter, r *http.Request, admin *models.Admin) {
+	s.adminModify(w, r, admin, "deleted answer", s.Admin.DeleteAnswer)
+}
+
+func (s *Server) adminDeleteComment(w http.ResponseWriter, r *http.Request, admin *models.Admin) {
+	s.adminModify(w, r, admin, "deleted comment", s.Admin.DeleteComment)
+}
+
+func (s *Server) adminGrantRole(w http.ResponseWriter, r *http.Request, admin *models.Admin) {
+	var body struct {
+		Role string `json:"role"`
+	}
+	if !decode(w, r, &body) || !required(w, "role", body.Role) {
+		return
+	}
+	s.adminModify(w, r, admin, "granted role "+body.Role+" to user", func(admin *models.Admin, id int) error {
+		return s.Admin.GrantRole(admin, id, body.Role)
+	})
+}
+
+func (s *Server) adminRevokeRole(w http.ResponseWriter, r *http.Request, admin *models.Admin) {
+	s.adminModify(w, r, admin, "revoked the role of user", s.Admin.RevokeRole)
+}
+
+// adminAuditLog filters on the actor, target_type, target_id, from and to
+// query parameters, all optional.
+func (s *Server) adminAuditLog(w http.ResponseWriter, r *http.Request, admin *models.Admin) {
+	query := r.URL.Query()
+	var filter models.AuditFilter
+	var err error
+	filter.TargetType = query.Get("target_type")
+	for name, id := range map[string]*int{"actor": &filter.ActorId, "target_id": &filter.TargetId} {
+		if value := query.Get(name); value != "" {
+			if *id, err = strconv.Atoi(value); err != nil || *id <= 0 {
+				writeMessage(w, http.StatusBadRequest, fmt.Sprintf("invalid %s %q", name, value))
+				return
+			}
+		}
+	}
+	for name, bound := range map[string]*time.Time{"from": &filter.From, "to": &filter.To} {
+		if value := query.Get(name); value != "" {
+			if *bound, err = utils.ParseTimeBound(value, name == "to"); err != nil {
+				writeMessage(w, http.StatusBadRequest, name+": "+err.Error())
+				return
+			}
+		}
+	}
+	entries, err := s.Admin.GetAuditLog(admin, filter)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	utils.Logger.Println("INFO:Admin", admin.User.Username, "viewed the audit log")
+	writeJSON(w, http.StatusOK, render.AuditLog(entries).Records)
+}
+
+// adminModify runs an admin operation on the {id} in the path.
+func (s *Server) adminModify(w http.ResponseWriter, r *http.Request, admin *models.Admin, action string, operation func(admin *models.Admin, id int) error) {
+	id, ok := pathId(w, r)
+	if !ok {
+		return
+	}
+	if err := operation(admin, id); err != nil {
+		writeError(w, err)
+		return
+	}
+	utils.Logger.Printf("INFO:Admin %s %s with id- %d", admin.User.Username, action, id)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"localEyes/internal/models"
+	"localEyes/internal/render"
+	"localEyes/utils"
+	"net/http"
+)
+
+type categoryRequest struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+	Active      *bool   `json:"active"`
+}
+
+// listCategories serves GET /categories, the categories open for new posts.
+// It needs no credentials, so clients can offer the choice before signup.
+func (s *Server) listCategories(w http.ResponseWriter, r *http.Request) {
+	categories, err := s.Categories.GiveCategories(true)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, render.Categories(categories).Records)
+}
+
+func (s *Server) adminListCategories(w http.ResponseWriter, r *http.Request, admin *models.Admin) {
+	categories, err := s.Admin.ListCategories(admin)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, render.Categories(categories).Records)
+}
+
+func (s *Server) adminCreateCategory(w http.ResponseWriter, r *http.Request, admin *models.Admin) {
+	var req categoryRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.Name == nil {
+		req.Name = new(string)
+	}
+	if !required(w, "name", *req.Name) {
+		return
+	}
+	if req.Active != nil {
+		writeMessage(w, http.StatusBadRequest, "new categories start out active")
+		return
+	}
+	description := ""
+	if req.Description != nil {
+		description = *req.Description
+	}
+	category, err := s.Admin.CreateCategory(admin, *req.Name, description)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	utils.Logger.Println("INFO:Admin", admin.User.Username, "created category", category.Name)
+	writeJSON(w, http.StatusCreated, render.Categories([]*models.Category{category}).Records[0])
+}
+
+// adminUpdateCategory changes only the fields present in the body.
+func (s *Server) adminUpdateCategory(w http.ResponseWriter, r *http.Request, admin *models.Admin) {
+	id, ok := pathId(w, r)
+	if !ok {
+		return
+	}
+	var req categoryRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	update := models.CategoryUpdate{Name: req.Name, Description: req.Description, IsActive: req.Active}
+	if update == (models.CategoryUpdate{}) {
+		writeMessage(w, http.StatusBadRequest, "name, description or active is required")
+		return
+	}
+	category, err := s.Admin.UpdateCategory(admin, id, update)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	utils.Logger.Println("INFO:Admin", admin.User.Username, "updated category", category.Name)
+	writeJSON(w, http.StatusOK, render.Categories([]*models.Category{category}).Records[0])
+}
+
+func (s *Server) adminDeleteCategory(w http.ResponseWriter, r *http.Request, admin *models.Admin) {
+	s.adminModify(w, r, admin, "deleted category", s.Admin.DeleteCategory)
+}
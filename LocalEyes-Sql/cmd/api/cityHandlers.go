@@ -0,0 +1,57 @@
+package api
+
+import (
+	"localEyes/internal/models"
+	"localEyes/internal/render"
+	"localEyes/utils"
+	"net/http"
+)
+
+type cityRequest struct {
+	Name string `json:"name"`
+}
+
+// listCities serves GET /cities. It needs no credentials, so clients can
+// offer the choice before signup.
+func (s *Server) listCities(w http.ResponseWriter, r *http.Request) {
+	cities, err := s.Cities.GiveCities()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, render.Cities(cities).Records)
+}
+
+// changeCity serves PUT /me/city, moving the user's feed and notifications
+// to another city.
+func (s *Server) changeCity(w http.ResponseWriter, r *http.Request, user *models.User) {
+	var req cityRequest
+	if !decode(w, r, &req) || !required(w, "name", req.Name) {
+		return
+	}
+	if err := s.Users.ChangeCity(user.UId, req.Name); err != nil {
+		writeError(w, err)
+		return
+	}
+	utils.Logger.Println("INFO: User id-", user.UId, "moved to", req.Name)
+	moved, err := s.Users.Repo.FindByUId(user.UId)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, userRecord(moved))
+}
+
+func (s *Server) adminCreateCity(w http.ResponseWriter, r *http.Request, admin *models.Admin) {
+	var req cityRequest
+	if !decode(w, r, &req) || !required(w, "name", req.Name) {
+		return
+	}
+	city, err := s.Admin.CreateCity(admin, req.Name)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	utils.Logger.Println("INFO:Admin", admin.User.Username, "opened city", city.Name)
+	writeJSON(w, http.StatusCreated, render.Cities([]*models.City{city}).Records[0])
+}
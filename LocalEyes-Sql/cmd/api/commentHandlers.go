@@ -0,0 +1,119 @@
+package api
+
+import (
+	"localEyes/internal/models"
+	"localEyes/internal/render"
+	"net/http"
+	"strconv"
+)
+
+// listComments serves GET /posts/{id}/comments, the post's threads with
+// every reply nested, or only ?depth= levels of them.
+func (s *Server) listComments(w http.ResponseWriter, r *http.Request, user *models.User) {
+	id, ok := pathId(w, r)
+	if !ok {
+		return
+	}
+	depth, ok := commentDepth(w, r)
+	if !ok || !s.postExists(w, id) {
+		return
+	}
+	threads, err := s.Comments.GetPostComments(id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, render.Comments(threads, depth).Records)
+}
+
+// getComment serves GET /comments/{id}, one comment with its replies.
+func (s *Server) getComment(w http.ResponseWriter, r *http.Request, user *models.User) {
+	id, ok := pathId(w, r)
+	if !ok {
+		return
+	}
+	depth, ok := commentDepth(w, r)
+	if !ok {
+		return
+	}
+	thread, err := s.Comments.GetThread(id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, render.Comments([]*models.Comment{thread}, depth).Records[0])
+}
+
+func (s *Server) addComment(w http.ResponseWriter, r *http.Request, user *models.User) {
+	id, ok := pathId(w, r)
+	if !ok {
+		return
+	}
+	var req textRequest
+	if !decode(w, r, &req) || !required(w, "text", req.Text) {
+		return
+	}
+	if err := s.Comments.AddComment(user.UId, id, req.Text); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) replyComment(w http.ResponseWriter, r *http.Request, user *models.User) {
+	id, ok := pathId(w, r)
+	if !ok {
+		return
+	}
+	var req textRequest
+	if !decode(w, r, &req) || !required(w, "text", req.Text) {
+		return
+	}
+	if err := s.Comments.Reply(user.UId, id, req.Text); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) editComment(w http.ResponseWriter, r *http.Request, user *models.User) {
+	id, ok := pathId(w, r)
+	if !ok {
+		return
+	}
+	var req textRequest
+	if !decode(w, r, &req) || !required(w, "text", req.Text) {
+		return
+	}
+	if err := s.Comments.EditComment(user.UId, id, req.Text); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) deleteComment(w http.ResponseWriter, r *http.Request, user *models.User) {
+	id, ok := pathId(w, r)
+	if !ok {
+		return
+	}
+	if err := s.Comments.DeleteMyComment(user.UId, id); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// commentDepth reads ?depth=, which defaults to 0, every level.
+func commentDepth(w http.ResponseWriter, r *http.Request) (int, bool) {
+	value := r.URL.Query().Get("depth")
+	if value == "" {
+		return 0, true
+	}
+	depth, err := strconv.Atoi(value)
+	if err != nil || depth < 0 {
+		writeMessage(w, http.StatusBadRequest, "depth must be a number, 0 or more")
+		return 0, false
+	}
+	return depth, true
+}
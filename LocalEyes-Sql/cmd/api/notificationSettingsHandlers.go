@@ -0,0 +1,89 @@
+package api
+
+import (
+	"localEyes/internal/models"
+	"localEyes/internal/render"
+	"net/http"
+)
+
+type notificationSettingsRequest struct {
+	Delivery string `json:"delivery"`
+	Posts    string `json:"posts"`
+}
+
+type notificationSettingsResponse struct {
+	Delivery      string `json:"delivery"`
+	Posts         string `json:"posts"`
+	Subscriptions []any  `json:"subscriptions"`
+}
+
+// subscriptionKinds maps the {kind} in /me/subscriptions/{kind}/{name} to
+// what is subscribed to.
+var subscriptionKinds = map[string]string{
+	"categories": models.TargetCategory,
+	"authors":    models.TargetUser,
+}
+
+func (s *Server) notificationSettings(w http.ResponseWriter, r *http.Request, user *models.User) {
+	prefs, err := s.Notifications.Preferences(user.UId)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, settingsResponse(prefs))
+}
+
+// updateNotificationSettings changes delivery, posts or both; a field left
+// out keeps its value.
+func (s *Server) updateNotificationSettings(w http.ResponseWriter, r *http.Request, user *models.User) {
+	var req notificationSettingsRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.Delivery == "" && req.Posts == "" {
+		writeMessage(w, http.StatusBadRequest, "delivery or posts is required")
+		return
+	}
+	if _, err := s.Notifications.UpdatePreferences(user.UId, req.Delivery, req.Posts); err != nil {
+		writeError(w, err)
+		return
+	}
+	s.notificationSettings(w, r, user)
+}
+
+func (s *Server) subscribe(w http.ResponseWriter, r *http.Request, user *models.User) {
+	kind, ok := subscriptionKind(w, r)
+	if !ok {
+		return
+	}
+	if err := s.Notifications.Subscribe(user.UId, kind, r.PathValue("name")); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) unsubscribe(w http.ResponseWriter, r *http.Request, user *models.User) {
+	kind, ok := subscriptionKind(w, r)
+	if !ok {
+		return
+	}
+	if err := s.Notifications.Unsubscribe(user.UId, kind, r.PathValue("name")); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func subscriptionKind(w http.ResponseWriter, r *http.Request) (string, bool) {
+	kind, ok := subscriptionKinds[r.PathValue("kind")]
+	if !ok {
+		writeMessage(w, http.StatusNotFound, "subscribe to categories or authors")
+	}
+	return kind, ok
+}
+
+func settingsResponse(prefs *models.NotificationPrefs) notificationSettingsResponse {
+	return notificationSettingsResponse{Delivery: prefs.Delivery, Posts: prefs.Posts,
+		Subscriptions: render.Subscriptions(prefs.Subscriptions).Records}
+}
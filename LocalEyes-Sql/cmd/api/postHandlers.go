@@ -0,0 +1,182 @@
+package api
+
+import (
+	"localEyes/internal/models"
+	"localEyes/internal/render"
+	"localEyes/utils"
+	"net/http"
+	"strconv"
+)
+
+type postRequest struct {
+	Type          string   `json:"type"`
+	Title         string   `json:"title"`
+	Content       string   `json:"content"`
+	Neighbourhood string   `json:"neighbourhood"`
+	Latitude      *float64 `json:"latitude"`
+	Longitude     *float64 `json:"longitude"`
+}
+
+// listPosts serves GET /posts, the posts in the user's city, optionally
+// filtered by ?type=, a category name, ?neighbourhood= and ?mine=true.
+func (s *Server) listPosts(w http.ResponseWriter, r *http.Request, user *models.User) {
+	filter := models.PostFilter{
+		Type:          r.URL.Query().Get("type"),
+		City:          user.City,
+		Neighbourhood: r.URL.Query().Get("neighbourhood"),
+	}
+	if err := s.Categories.ValidateCategory(filter.Type); err != nil {
+		writeError(w, err)
+		return
+	}
+	if r.URL.Query().Get("mine") == "true" {
+		filter.UId = user.UId
+	}
+	page, ok := pageRequest(w, r)
+	if !ok {
+		return
+	}
+	posts, err := s.Posts.ListPosts(user.UId, filter, page)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writePage(w, r, render.Posts(posts.Items).Records, posts.Request, posts.HasPrev(), posts.HasNext)
+}
+
+// searchPosts serves GET /posts/search?q=, most relevant posts in the
+// user's city first.
+func (s *Server) searchPosts(w http.ResponseWriter, r *http.Request, user *models.User) {
+	page, ok := pageRequest(w, r)
+	if !ok {
+		return
+	}
+	posts, err := s.Posts.SearchPosts(user.UId, user.City, r.URL.Query().Get("q"), page)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writePage(w, r, render.Posts(posts.Items).Records, posts.Request, posts.HasPrev(), posts.HasNext)
+}
+
+// nearPosts serves GET /posts/near?lat=&lng=, the posts tagged within
+// ?radius_km= (5 by default) of the point, nearest first.
+func (s *Server) nearPosts(w http.ResponseWriter, r *http.Request, user *models.User) {
+	query := r.URL.Query()
+	if !required(w, "lat", query.Get("lat"), "lng", query.Get("lng")) {
+		return
+	}
+	if query.Get("radius_km") == "" {
+		query.Set("radius_km", strconv.FormatFloat(models.DefaultNearRadiusKm, 'g', -1, 64))
+	}
+	var point [3]float64
+	for i, name := range []string{"lat", "lng", "radius_km"} {
+		n, err := strconv.ParseFloat(query.Get(name), 64)
+		if err != nil {
+			writeMessage(w, http.StatusBadRequest, name+" must be a number")
+			return
+		}
+		point[i] = n
+	}
+	page, ok := pageRequest(w, r)
+	if !ok {
+		return
+	}
+	posts, err := s.Posts.NearPosts(user.UId, point[0], point[1], point[2], page)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writePage(w, r, render.Posts(posts.Items).Records, posts.Request, posts.HasPrev(), posts.HasNext)
+}
+
+// createPost serves POST /posts, answering with the new post, which the
+// Location header points at.
+func (s *Server) createPost(w http.ResponseWriter, r *http.Request, user *models.User) {
+	var req postRequest
+	if !decode(w, r, &req) || !required(w, "type", req.Type, "title", req.Title, "content", req.Content) {
+		return
+	}
+	post, err := s.Posts.CreatePost(user.UId, req.Title, req.Content, req.Type, models.Location{
+		Neighbourhood: req.Neighbourhood,
+		Latitude:      req.Latitude,
+		Longitude:     req.Longitude,
+	})
+	if err != nil {
+		utils.Logger.Println("ERROR: Error creating post: " + err.Error())
+		writeError(w, err)
+		return
+	}
+	utils.Logger.Println("INFO: Post created:", req.Title)
+	w.Header().Set("Location", "/posts/"+strconv.Itoa(post.PostId))
+	writeJSON(w, http.StatusCreated, render.Posts([]*models.Post{post}).Records[0])
+}
+
+func (s *Server) getPost(w http.ResponseWriter, r *http.Request, user *models.User) {
+	id, ok := pathId(w, r)
+	if !ok {
+		return
+	}
+	post, err := s.Posts.GivePost(user.UId, id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, render.Posts([]*models.Post{post}).Records[0])
+}
+
+func (s *Server) updatePost(w http.ResponseWriter, r *http.Request, user *models.User) {
+	id, ok := pathId(w, r)
+	if !ok {
+		return
+	}
+	var req postRequest
+	if !decode(w, r, &req) || !required(w, "title", req.Title, "content", req.Content) {
+		return
+	}
+	if req.Type != "" {
+		writeMessage(w, http.StatusBadRequest, "the type of a post cannot be changed")
+		return
+	}
+	if err := s.Posts.UpdateMyPost(id, user.UId, req.Title, req.Content); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) deletePost(w http.ResponseWriter, r *http.Request, user *models.User) {
+	id, ok := pathId(w, r)
+	if !ok {
+		return
+	}
+	if err := s.Posts.DeleteMyPost(user.UId, id); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) likePost(w http.ResponseWriter, r *http.Request, user *models.User) {
+	id, ok := pathId(w, r)
+	if !ok {
+		return
+	}
+	if err := s.Posts.Like(user.UId, id); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) unlikePost(w http.ResponseWriter, r *http.Request, user *models.User) {
+	id, ok := pathId(w, r)
+	if !ok {
+		return
+	}
+	if err := s.Posts.Unlike(user.UId, id); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
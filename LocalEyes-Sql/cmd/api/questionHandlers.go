@@ -0,0 +1,131 @@
+package api
+
+import (
+	"localEyes/internal/models"
+	"localEyes/internal/render"
+	"net/http"
+)
+
+type textRequest struct {
+	Text string `json:"text"`
+}
+
+func (s *Server) listQuestions(w http.ResponseWriter, r *http.Request, user *models.User) {
+	id, ok := pathId(w, r)
+	if !ok {
+		return
+	}
+	if !s.postExists(w, id) {
+		return
+	}
+	questions, err := s.Questions.GetPostQuestions(id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, render.Questions(questions).Records)
+}
+
+// searchQuestions serves GET /questions/search?q=, matching question text and
+// answers, most relevant questions first.
+func (s *Server) searchQuestions(w http.ResponseWriter, r *http.Request, user *models.User) {
+	page, ok := pageRequest(w, r)
+	if !ok {
+		return
+	}
+	questions, err := s.Questions.SearchQuestions(r.URL.Query().Get("q"), page)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writePage(w, r, render.Questions(questions.Items).Records, questions.Request, questions.HasPrev(), questions.HasNext)
+}
+
+func (s *Server) askQuestion(w http.ResponseWriter, r *http.Request, user *models.User) {
+	id, ok := pathId(w, r)
+	if !ok {
+		return
+	}
+	var req textRequest
+	if !decode(w, r, &req) || !required(w, "text", req.Text) {
+		return
+	}
+	if !s.postExists(w, id) {
+		return
+	}
+	if err := s.Questions.AskQuestion(user.UId, id, req.Text); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+// postExists answers 404 when there is no post with the id.
+func (s *Server) postExists(w http.ResponseWriter, PId int) bool {
+	exists, err := s.Posts.PostIdExist(PId)
+	if err != nil {
+		writeError(w, err)
+		return false
+	}
+	if !exists {
+		writeMessage(w, http.StatusNotFound, "No post exist with this id")
+		return false
+	}
+	return true
+}
+
+func (s *Server) deleteQuestion(w http.ResponseWriter, r *http.Request, user *models.User) {
+	id, ok := pathId(w, r)
+	if !ok {
+		return
+	}
+	if err := s.Questions.DeleteUserQues(user.UId, id); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) addAnswer(w http.ResponseWriter, r *http.Request, user *models.User) {
+	id, ok := pathId(w, r)
+	if !ok {
+		return
+	}
+	var req textRequest
+	if !decode(w, r, &req) || !required(w, "text", req.Text) {
+		return
+	}
+	if err := s.Questions.AddAnswer(id, user.UId, req.Text); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) editAnswer(w http.ResponseWriter, r *http.Request, user *models.User) {
+	id, ok := pathId(w, r)
+	if !ok {
+		return
+	}
+	var req textRequest
+	if !decode(w, r, &req) || !required(w, "text", req.Text) {
+		return
+	}
+	if err := s.Questions.EditAnswer(user.UId, id, req.Text); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) deleteAnswer(w http.ResponseWriter, r *http.Request, user *models.User) {
+	id, ok := pathId(w, r)
+	if !ok {
+		return
+	}
+	if err := s.Questions.DeleteMyAnswer(user.UId, id); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
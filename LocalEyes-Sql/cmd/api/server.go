@@ -0,0 +1,299 @@
+// Package api serves the LocalEyes services as a JSON REST API, so web and
+// mobile clients can share the backend the terminal front ends use.
+//
+// Listings are paged: ?page= and ?per_page= pick the page, ?sort= its order,
+// and the Link header points at the previous and next pages.
+//
+// Users log in with POST /login and send the session token it returns as a
+// Bearer token; HTTP Basic credentials are accepted as well. The admin
+// endpoints authenticate the same way and then check the user's role.
+//
+// GET /me/notifications/stream keeps the connection open and sends each new
+// notification as a server-sent event, shaped like an inbox entry.
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"localEyes/internal/models"
+	"localEyes/internal/services"
+	"localEyes/utils"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// maxBodyBytes caps the size of a request body.
+const maxBodyBytes = 1 << 20
+
+const authChallenge = `Bearer realm="localeyes", Basic realm="localeyes"`
+
+type Server struct {
+	Users         *services.UserService
+	Posts         *services.PostService
+	Questions     *services.QuestionService
+	Admin         *services.AdminService
+	Sessions      *services.SessionService
+	Categories    *services.CategoryService
+	Cities        *services.CityService
+	Comments      *services.CommentService
+	Notifications *services.NotificationService
+	mux           *http.ServeMux
+}
+
+func NewServer(userService *services.UserService, postService *services.PostService, questionService *services.QuestionService, adminService *services.AdminService, sessionService *services.SessionService, categoryService *services.CategoryService, cityService *services.CityService, commentService *services.CommentService, notificationService *services.NotificationService) *Server {
+	s := &Server{Users: userService, Posts: postService, Questions: questionService, Admin: adminService, Sessions: sessionService, Categories: categoryService, Cities: cityService, Comments: commentService, Notifications: notificationService, mux: http.NewServeMux()}
+	s.routes()
+	return s
+}
+
+func (s *Server) routes() {
+	s.mux.HandleFunc("POST /users", s.signup)
+	s.mux.HandleFunc("POST /login", s.login)
+	s.mux.HandleFunc("POST /logout", s.logout)
+	s.mux.HandleFunc("POST /logout-all", s.withUser(s.logoutAll))
+	s.mux.HandleFunc("GET /me", s.withUser(s.profile))
+	s.mux.HandleFunc("DELETE /me", s.withUser(s.deactivate))
+	s.mux.HandleFunc("PUT /me/city", s.withUser(s.changeCity))
+	s.mux.HandleFunc("GET /me/notifications", s.withUser(s.notifications))
+	s.mux.HandleFunc("GET /me/notifications/stream", s.withUser(s.notificationStream))
+	s.mux.HandleFunc("POST /me/notifications/read", s.withUser(s.markAllNotificationsRead))
+	s.mux.HandleFunc("POST /me/notifications/{id}/read", s.withUser(s.markNotificationRead))
+	s.mux.HandleFunc("GET /me/notification-settings", s.withUser(s.notificationSettings))
+	s.mux.HandleFunc("PUT /me/notification-settings", s.withUser(s.updateNotificationSettings))
+	s.mux.HandleFunc("PUT /me/subscriptions/{kind}/{name}", s.withUser(s.subscribe))
+	s.mux.HandleFunc("DELETE /me/subscriptions/{kind}/{name}", s.withUser(s.unsubscribe))
+
+	s.mux.HandleFunc("GET /categories", s.listCategories)
+	s.mux.HandleFunc("GET /cities", s.listCities)
+
+	s.mux.HandleFunc("GET /posts", s.withUser(s.listPosts))
+	s.mux.HandleFunc("GET /posts/search", s.withUser(s.searchPosts))
+	s.mux.HandleFunc("GET /posts/near", s.withUser(s.nearPosts))
+	s.mux.HandleFunc("POST /posts", s.withUser(s.createPost))
+	s.mux.HandleFunc("GET /posts/{id}", s.withUser(s.getPost))
+	s.mux.HandleFunc("PUT /posts/{id}", s.withUser(s.updatePost))
+	s.mux.HandleFunc("DELETE /posts/{id}", s.withUser(s.deletePost))
+	s.mux.HandleFunc("PUT /posts/{id}/like", s.withUser(s.likePost))
+	s.mux.HandleFunc("DELETE /posts/{id}/like", s.withUser(s.unlikePost))
+
+	s.mux.HandleFunc("GET /posts/{id}/questions", s.withUser(s.listQuestions))
+	s.mux.HandleFunc("POST /posts/{id}/questions", s.withUser(s.askQuestion))
+	s.mux.HandleFunc("GET /questions/search", s.withUser(s.searchQuestions))
+	s.mux.HandleFunc("DELETE /questions/{id}", s.withUser(s.deleteQuestion))
+	s.mux.HandleFunc("POST /questions/{id}/answers", s.withUser(s.addAnswer))
+	s.mux.HandleFunc("PUT /answers/{id}", s.withUser(s.editAnswer))
+	s.mux.HandleFunc("DELETE /answers/{id}", s.withUser(s.deleteAnswer))
+
+	s.mux.HandleFunc("GET /posts/{id}/comments", s.withUser(s.listComments))
+	s.mux.HandleFunc("POST /posts/{id}/comments", s.withUser(s.addComment))
+	s.mux.HandleFunc("GET /comments/{id}", s.withUser(s.getComment))
+	s.mux.HandleFunc("POST /comments/{id}/replies", s.withUser(s.replyComment))
+	s.mux.HandleFunc("PUT /comments/{id}", s.withUser(s.editComment))
+	s.mux.HandleFunc("DELETE /comments/{id}", s.withUser(s.deleteComment))
+
+	s.mux.HandleFunc("GET /admin/users", s.withAdmin(s.adminListUsers))
+	s.mux.HandleFunc("DELETE /admin/users/{id}", s.withAdmin(s.adminDeleteUser))
+	s.mux.HandleFunc("POST /admin/users/{id}/reactivate", s.withAdmin(s.adminReactivateUser))
+	s.mux.HandleFunc("PUT /admin/users/{id}/role", s.withAdmin(s.adminGrantRole))
+	s.mux.HandleFunc("DELETE /admin/users/{id}/role", s.withAdmin(s.adminRevokeRole))
+	s.mux.HandleFunc("GET /admin/posts", s.withAdmin(s.adminListPosts))
+	s.mux.HandleFunc("DELETE /admin/posts/{id}", s.withAdmin(s.adminDeletePost))
+	s.mux.HandleFunc("GET /admin/questions", s.withAdmin(s.adminListQuestions))
+	s.mux.HandleFunc("DELETE /admin/questions/{id}", s.withAdmin(s.adminDeleteQuestion))
+	s.mux.HandleFunc("DELETE /admin/answers/{id}", s.withAdmin(s.adminDeleteAnswer))
+	s.mux.HandleFunc("DELETE /admin/comments/{id}", s.withAdmin(s.adminDeleteComment))
+	s.mux.HandleFunc("GET /admin/categories", s.withAdmin(s.adminListCategories))
+	s.mux.HandleFunc("POST /admin/categories", s.withAdmin(s.adminCreateCategory))
+	s.mux.HandleFunc("PUT /admin/categories/{id}", s.withAdmin(s.adminUpdateCategory))
+	s.mux.HandleFunc("DELETE /admin/categories/{id}", s.withAdmin(s.adminDeleteCategory))
+	s.mux.HandleFunc("POST /admin/cities", s.withAdmin(s.adminCreateCity))
+	s.mux.HandleFunc("GET /admin/audit", s.withAdmin(s.adminAuditLog))
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+type userHandler func(w http.ResponseWriter, r *http.Request, user *models.User)
+
+// withUser authenticates the request's session token, or its Basic
+// credentials, as a user.
+func (s *Server) withUser(next userHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var user *models.User
+		var err error
+		if token, ok := bearerToken(r); ok {
+			user, err = s.Sessions.Authenticate(token)
+		} else if username, password, ok := r.BasicAuth(); ok {
+			user, err = s.Users.Login(username, password)
+		} else {
+			unauthorized(w, "missing credentials")
+			return
+		}
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		next(w, r, user)
+	}
+}
+
+type adminHandler func(w http.ResponseWriter, r *http.Request, admin *models.Admin)
+
+// withAdmin authenticates the request as a user whose role allows admin
+// endpoints; each operation checks its own permission on top.
+func (s *Server) withAdmin(next adminHandler) http.HandlerFunc {
+	return s.withUser(func(w http.ResponseWriter, r *http.Request, user *models.User) {
+		admin, err := s.Admin.AdminFor(user)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		next(w, r, admin)
+	})
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, found := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return "", false
+	}
+	return strings.TrimSpace(token), true
+}
+
+func unauthorized(w http.ResponseWriter, message string) {
+	w.Header().Set("WWW-Authenticate", authChallenge)
+	writeMessage(w, http.StatusUnauthorized, message)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		utils.Logger.Println("ERROR: Error writing response:", err)
+	}
+}
+
+func writeMessage(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
+
+// errorStatuses maps the kinds of error the services fail with to status
+// codes.
+var errorStatuses = []struct {
+	kind   error
+	status int
+}{
+	{models.ErrUnauthorized, http.StatusUnauthorized},
+	{models.ErrForbidden, http.StatusForbidden},
+	{models.ErrInvalid, http.StatusBadRequest},
+	{models.ErrConflict, http.StatusConflict},
+	{models.ErrNotFound, http.StatusNotFound},
+}
+
+// writeError reports a service error, hiding the details of unexpected ones.
+func writeError(w http.ResponseWriter, err error) {
+	for _, known := range errorStatuses {
+		if errors.Is(err, known.kind) {
+			if known.status == http.StatusUnauthorized {
+				w.Header().Set("WWW-Authenticate", authChallenge)
+			}
+			writeMessage(w, known.status, utils.PlainText(err.Error()))
+			return
+		}
+	}
+	utils.Logger.Println("ERROR: API request failed:", err)
+	writeMessage(w, http.StatusInternalServerError, "internal server error")
+}
+
+// decode reads a JSON body into dst, rejecting unknown fields and trailing data.
+func decode(w http.ResponseWriter, r *http.Request, dst any) bool {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(dst)
+	if err == nil && decoder.Decode(&struct{}{}) != io.EOF {
+		err = errors.New("body must hold a single JSON object")
+	}
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeMessage(w, http.StatusRequestEntityTooLarge, "request body too large")
+			return false
+		}
+		writeMessage(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return false
+	}
+	return true
+}
+
+// pathId reads the {id} path value, answering 400 when it is not an id.
+func pathId(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id <= 0 {
+		writeMessage(w, http.StatusBadRequest, fmt.Sprintf("invalid id %q", r.PathValue("id")))
+		return 0, false
+	}
+	return id, true
+}
+
+// required takes field name and value pairs and answers 400 naming the
+// first empty field, if any.
+func required(w http.ResponseWriter, namesAndValues ...string) bool {
+	for i := 0; i+1 < len(namesAndValues); i += 2 {
+		if strings.TrimSpace(namesAndValues[i+1]) == "" {
+			writeMessage(w, http.StatusBadRequest, namesAndValues[i]+" is required")
+			return false
+		}
+	}
+	return true
+}
+
+// pageRequest reads the page, per_page and sort query parameters; the
+// services fill in whatever is left out.
+func pageRequest(w http.ResponseWriter, r *http.Request) (models.PageRequest, bool) {
+	query := r.URL.Query()
+	page := models.PageRequest{Sort: query.Get("sort")}
+	for _, param := range []struct {
+		name string
+		dst  *int
+	}{{"page", &page.Number}, {"per_page", &page.Size}} {
+		value := query.Get(param.name)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			writeMessage(w, http.StatusBadRequest, fmt.Sprintf("%s must be a positive number", param.name))
+			return page, false
+		}
+		*param.dst = n
+	}
+	return page, true
+}
+
+// writePage answers with the page's records and links to its neighbours.
+func writePage(w http.ResponseWriter, r *http.Request, records []any, page models.PageRequest, hasPrev, hasNext bool) {
+	var links []string
+	link := func(target models.PageRequest, rel string) {
+		query := r.URL.Query()
+		query.Set("page", strconv.Itoa(target.Number))
+		query.Set("per_page", strconv.Itoa(target.Size))
+		query.Set("sort", target.Sort)
+		next := url.URL{Path: r.URL.Path, RawQuery: query.Encode()}
+		links = append(links, fmt.Sprintf("<%s>; rel=%q", next.String(), rel))
+	}
+	if hasPrev {
+		link(page.Prev(), "prev")
+	}
+	if hasNext {
+		link(page.Next(), "next")
+	}
+	if len(links) > 0 {
+		w.Header().Set("Link", strings.Join(links, ", "))
+	}
+	writeJSON(w, http.StatusOK, records)
+}
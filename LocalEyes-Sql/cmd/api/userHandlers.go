@@ -0,0 +1,195 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"localEyes/internal/models"
+	"localEyes/internal/render"
+	"localEyes/utils"
+	"net/http"
+	"time"
+)
+
+type signupRequest struct {
+	Username    string `json:"username"`
+	Password    string `json:"password"`
+	City        string `json:"city"`
+	DwellingAge *int   `json:"dwelling_age"`
+}
+
+type credentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+func (s *Server) signup(w http.ResponseWriter, r *http.Request) {
+	var req signupRequest
+	if !decode(w, r, &req) || !required(w, "username", req.Username, "password", req.Password, "city", req.City) {
+		return
+	}
+	if req.DwellingAge == nil || *req.DwellingAge < 0 {
+		writeMessage(w, http.StatusBadRequest, "dwelling_age must be a number of years")
+		return
+	}
+	if !utils.ValidateUsername(req.Username, s.Users.Repo) {
+		writeMessage(w, http.StatusConflict, "Username already taken")
+		return
+	}
+	if !utils.ValidatePassword(req.Password) {
+		writeMessage(w, http.StatusBadRequest, "password is weak: use at least 6 characters with a special character and a number")
+		return
+	}
+	tag := "newbie"
+	if *req.DwellingAge > 2 {
+		tag = "resident"
+	}
+	if err := s.Users.Signup(req.Username, req.Password, req.City, *req.DwellingAge, tag); err != nil {
+		writeError(w, err)
+		return
+	}
+	user, err := s.Users.Repo.FindByUsername(req.Username)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, userRecord(user))
+}
+
+type loginResponse struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+	User      any       `json:"user"`
+}
+
+// login checks a username and password and starts a session; later requests
+// send the returned token as "Authorization: Bearer <token>".
+func (s *Server) login(w http.ResponseWriter, r *http.Request) {
+	var req credentials
+	if !decode(w, r, &req) || !required(w, "username", req.Username, "password", req.Password) {
+		return
+	}
+	user, err := s.Users.Login(req.Username, req.Password)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	token, session, err := s.Sessions.Start(user.UId)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, loginResponse{Token: token, ExpiresAt: session.ExpiresAt, User: userRecord(user)})
+}
+
+// logout ends the session of the Bearer token; it succeeds even when the
+// session has already expired.
+func (s *Server) logout(w http.ResponseWriter, r *http.Request) {
+	token, ok := bearerToken(r)
+	if !ok {
+		unauthorized(w, "missing session token")
+		return
+	}
+	if err := s.Sessions.Logout(token); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) logoutAll(w http.ResponseWriter, r *http.Request, user *models.User) {
+	if err := s.Sessions.LogoutAll(user.UId); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) profile(w http.ResponseWriter, r *http.Request, user *models.User) {
+	writeJSON(w, http.StatusOK, userRecord(user))
+}
+
+func (s *Server) deactivate(w http.ResponseWriter, r *http.Request, user *models.User) {
+	if err := s.Users.DeActivate(user.UId); err != nil {
+		writeError(w, err)
+		return
+	}
+	utils.Logger.Println("INFO: User Deactivated with id-", user.UId)
+	if err := s.Sessions.LogoutAll(user.UId); err != nil {
+		utils.Logger.Println("ERROR: Error ending sessions of user id-", user.UId, err)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// notifications lists the inbox, or with ?unread=true only what is unread.
+func (s *Server) notifications(w http.ResponseWriter, r *http.Request, user *models.User) {
+	page, ok := pageRequest(w, r)
+	if !ok {
+		return
+	}
+	notifications, err := s.Notifications.Inbox(user.UId, r.URL.Query().Get("unread") == "true", page)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writePage(w, r, render.Notifications(notifications.Items).Records, notifications.Request, notifications.HasPrev(), notifications.HasNext)
+}
+
+// notificationStream sends the user's new notifications as server-sent
+// events, until the client goes away or the server shuts down.
+func (s *Server) notificationStream(w http.ResponseWriter, r *http.Request, user *models.User) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeMessage(w, http.StatusInternalServerError, "streaming is not supported")
+		return
+	}
+	live, stop := s.Notifications.Listen(user.UId)
+	defer stop()
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case notification, ok := <-live:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(render.Notifications([]*models.Notification{notification}).Records[0])
+			if err != nil {
+				utils.Logger.Println("ERROR: Error encoding notification:", err)
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "id: %d\nevent: notification\ndata: %s\n\n", notification.Id, data); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func (s *Server) markNotificationRead(w http.ResponseWriter, r *http.Request, user *models.User) {
+	id, ok := pathId(w, r)
+	if !ok {
+		return
+	}
+	if err := s.Notifications.MarkRead(user.UId, id); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) markAllNotificationsRead(w http.ResponseWriter, r *http.Request, user *models.User) {
+	if err := s.Notifications.MarkAllRead(user.UId); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// userRecord gives a user the JSON shape the listings use, without the password.
+func userRecord(user *models.User) any {
+	return render.Users([]*models.User{user}).Records[0]
+}
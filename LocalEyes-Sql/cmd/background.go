@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"localEyes/internal/services"
+	"localEyes/utils"
+	"time"
+)
+
+// background is the work that runs alongside a front end: the outbox worker
+// announcing new posts, and the feed pushing new notifications to the
+// sessions listening for them.
+type background struct {
+	outbox        *services.OutboxWorker
+	notifications *services.NotificationService
+	live          services.NotificationSource
+	endLive       context.CancelFunc
+	liveEnded     chan struct{}
+}
+
+func (b *background) start() {
+	b.outbox.Start()
+	ctx, cancel := context.WithCancel(context.Background())
+	b.endLive = cancel
+	b.liveEnded = make(chan struct{})
+	go func() {
+		defer close(b.liveEnded)
+		if err := b.notifications.Follow(ctx, b.live); err != nil {
+			utils.Logger.Println("ERROR: Error following new notifications:", err)
+		}
+	}()
+}
+
+// stop ends the live feed, which closes every listener, and gives the
+// outbox worker a little while to announce what is still due; whatever it
+// cannot finish waits in the outbox for the next run.
+func (b *background) stop() {
+	b.endLive()
+	<-b.liveEnded
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := b.outbox.Stop(ctx); err != nil {
+		utils.Logger.Println("ERROR: Error stopping the notification outbox:", err)
+	}
+}
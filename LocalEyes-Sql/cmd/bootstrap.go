@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+	"localEyes/config"
+	"localEyes/internal/services"
+	"localEyes/utils"
+	"os"
+)
+
+// runBootstrapAdmin makes an existing account the first admin of a fresh
+// install. It refuses once any admin exists, so it cannot be used to take
+// over a running site.
+func runBootstrapAdmin(args []string) int {
+	if len(args) != 1 || args[0] == "" {
+		fmt.Println("usage: localeyes bootstrap-admin USERNAME")
+		return 2
+	}
+	if config.GetDBDriver() == config.MemoryDriver {
+		fmt.Println("The memory driver keeps no accounts between runs, nothing to bootstrap")
+		return 0
+	}
+	repos, uow := newRepositories(config.GetDBDriver())
+	adminService := services.NewAdminService(repos.Users, repos.Posts, repos.Questions, repos.Answers, repos.Audit, uow)
+	user, err := adminService.BootstrapAdmin(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, config.Red+utils.PlainText(err.Error())+config.Reset)
+		return 1
+	}
+	fmt.Println(config.Green + user.Username + " is now an admin" + config.Reset)
+	return 0
+}
@@ -0,0 +1,265 @@
+package cli
+
+import (
+	"flag"
+	"localEyes/internal/models"
+	"localEyes/internal/render"
+	"localEyes/utils"
+)
+
+// runAdmin handles "admin <noun> <verb>". Every admin command logs in first,
+// and the account's role decides which commands it may run.
+func (c *CLI) runAdmin(args []string) int {
+	if len(args) < 2 {
+		return c.usageError("admin needs a noun and a verb, e.g. admin user list")
+	}
+	noun, verb, rest := args[0], args[1], args[2:]
+	switch noun + " " + verb {
+	case "user list", "post list", "question list":
+		fs := c.flags("admin " + noun + " list")
+		orders := models.RecordSorts
+		if noun == "post" {
+			orders = models.PostSorts
+		}
+		page := c.pageFlags(fs, orders)
+		c.outputFlag(fs)
+		var city string
+		if noun != "user" {
+			fs.StringVar(&city, "city", "", "only list content from this city")
+		}
+		if code := c.parse(fs, rest); code >= 0 {
+			return code
+		}
+		if code := c.checkCity(city); code >= 0 {
+			return code
+		}
+		admin, code := c.adminLogin()
+		if code != ExitOK {
+			return code
+		}
+		return c.adminList(admin, noun, city, *page)
+	case "user delete", "user reactivate", "post delete", "question delete", "answer delete", "comment delete":
+		fs := c.flags("admin " + noun + " " + verb)
+		id := fs.Int("id", 0, "id of the "+noun)
+		if code := c.parse(fs, rest, "id"); code >= 0 {
+			return code
+		}
+		admin, code := c.adminLogin()
+		if code != ExitOK {
+			return code
+		}
+		return c.adminModify(admin, noun, verb, *id)
+	case "role grant", "role revoke":
+		fs := c.flags("admin role " + verb)
+		id := fs.Int("id", 0, "id of the user")
+		role := models.RoleUser
+		requiredFlags := []string{"id"}
+		if verb == "grant" {
+			fs.StringVar(&role, "role", "", "role to grant: user, moderator or admin")
+			requiredFlags = append(requiredFlags, "role")
+		}
+		if code := c.parse(fs, rest, requiredFlags...); code >= 0 {
+			return code
+		}
+		admin, code := c.adminLogin()
+		if code != ExitOK {
+			return code
+		}
+		return c.adminRole(admin, *id, role)
+	case "audit list":
+		fs := c.flags("admin audit list")
+		c.outputFlag(fs)
+		var filter models.AuditFilter
+		fs.IntVar(&filter.ActorId, "actor", 0, "only entries by this user id")
+		fs.StringVar(&filter.TargetType, "target-type", "", "only entries on a user, post, question, answer, comment or category")
+		fs.IntVar(&filter.TargetId, "target", 0, "only entries on this target id")
+		from := fs.String("from", "", "only entries at or after this time, YYYY-MM-DD[ HH:MM]")
+		to := fs.String("to", "", "only entries at or before this time, YYYY-MM-DD[ HH:MM]")
+		if code := c.parse(fs, rest); code >= 0 {
+			return code
+		}
+		var err error
+		if *from != "" {
+			if filter.From, err = utils.ParseTimeBound(*from, false); err != nil {
+				return c.usageError("-from: %v", err)
+			}
+		}
+		if *to != "" {
+			if filter.To, err = utils.ParseTimeBound(*to, true); err != nil {
+				return c.usageError("-to: %v", err)
+			}
+		}
+		admin, code := c.adminLogin()
+		if code != ExitOK {
+			return code
+		}
+		return c.adminAudit(admin, filter)
+	case "category list", "category create", "category update", "category delete":
+		return c.adminCategory(verb, rest)
+	case "city create":
+		fs := c.flags("admin city create")
+		name := fs.String("name", "", "name of the city")
+		if code := c.parse(fs, rest, "name"); code >= 0 {
+			return code
+		}
+		admin, code := c.adminLogin()
+		if code != ExitOK {
+			return code
+		}
+		city, err := c.Services.Admin.CreateCity(admin, *name)
+		if err != nil {
+			return c.fail(err)
+		}
+		utils.Logger.Println("INFO:Admin", admin.User.Username, "opened city", city.Name)
+		return c.done("city %d created: %s", city.Id, city.Name)
+	default:
+		return c.usageError("unknown admin command %q", noun+" "+verb)
+	}
+}
+
+func (c *CLI) adminList(admin *models.Admin, noun, city string, page models.PageRequest) int {
+	service := c.Services.Admin
+	var listing render.Listing
+	var hasPrev, hasNext bool
+	switch noun {
+	case "user":
+		users, err := service.ListUsers(admin, page)
+		if err != nil {
+			return c.fail(err)
+		}
+		listing, page, hasPrev, hasNext = render.Users(users.Items), users.Request, users.HasPrev(), users.HasNext
+	case "post":
+		posts, err := service.ListPosts(admin, city, page)
+		if err != nil {
+			return c.fail(err)
+		}
+		listing, page, hasPrev, hasNext = render.Posts(posts.Items), posts.Request, posts.HasPrev(), posts.HasNext
+	case "question":
+		questions, err := service.ListQuestions(admin, city, page)
+		if err != nil {
+			return c.fail(err)
+		}
+		listing, page, hasPrev, hasNext = render.Questions(questions.Items), questions.Request, questions.HasPrev(), questions.HasNext
+	}
+	utils.Logger.Println("INFO:Admin", admin.User.Username, "viewed page", page.Number, "of the", noun+"s")
+	return c.renderPage(listing, page, hasPrev, hasNext)
+}
+
+func (c *CLI) adminModify(admin *models.Admin, noun, verb string, id int) int {
+	service := c.Services.Admin
+	var err error
+	switch noun + " " + verb {
+	case "user delete":
+		err = service.DeleteUser(admin, id)
+	case "user reactivate":
+		err = service.ReActivate(admin, id)
+	case "post delete":
+		err = service.DeletePost(admin, id)
+	case "question delete":
+		err = service.DeleteQuestion(admin, id)
+	case "answer delete":
+		err = service.DeleteAnswer(admin, id)
+	case "comment delete":
+		err = service.DeleteComment(admin, id)
+	}
+	if err != nil {
+		return c.fail(err)
+	}
+	utils.Logger.Printf("INFO:Admin %s ran %s %s on id- %d", admin.User.Username, noun, verb, id)
+	past := map[string]string{"delete": "deleted", "reactivate": "reactivated"}[verb]
+	return c.done("%s %d %s", noun, id, past)
+}
+
+func (c *CLI) adminRole(admin *models.Admin, id int, role string) int {
+	if err := c.Services.Admin.GrantRole(admin, id, role); err != nil {
+		return c.fail(err)
+	}
+	utils.Logger.Printf("INFO:Admin %s set the role of user id- %d to %s", admin.User.Username, id, role)
+	return c.done("user %d is now a %s", id, role)
+}
+
+func (c *CLI) adminCategory(verb string, args []string) int {
+	fs := c.flags("admin category " + verb)
+	var id *int
+	var requiredFlags []string
+	if verb == "update" || verb == "delete" {
+		id = fs.Int("id", 0, "id of the category")
+		requiredFlags = append(requiredFlags, "id")
+	}
+	var name, description *string
+	var active *bool
+	if verb == "create" || verb == "update" {
+		name = fs.String("name", "", "category name: lowercase letters, digits and hyphens")
+		description = fs.String("description", "", "what the category is for")
+	}
+	if verb == "create" {
+		requiredFlags = append(requiredFlags, "name")
+	}
+	if verb == "update" {
+		active = fs.Bool("active", true, "whether the category takes new posts")
+	}
+	if verb == "list" {
+		c.outputFlag(fs)
+	}
+	if code := c.parse(fs, args, requiredFlags...); code >= 0 {
+		return code
+	}
+	var update models.CategoryUpdate
+	if verb == "update" {
+		fs.Visit(func(f *flag.Flag) {
+			switch f.Name {
+			case "name":
+				update.Name = name
+			case "description":
+				update.Description = description
+			case "active":
+				update.IsActive = active
+			}
+		})
+		if update == (models.CategoryUpdate{}) {
+			return c.usageError("admin category update needs --name, --description or --active")
+		}
+	}
+	admin, code := c.adminLogin()
+	if code != ExitOK {
+		return code
+	}
+	service := c.Services.Admin
+	switch verb {
+	case "list":
+		categories, err := service.ListCategories(admin)
+		if err != nil {
+			return c.fail(err)
+		}
+		return c.render(render.Categories(categories))
+	case "create":
+		category, err := service.CreateCategory(admin, *name, *description)
+		if err != nil {
+			return c.fail(err)
+		}
+		utils.Logger.Println("INFO:Admin", admin.User.Username, "created category", category.Name)
+		return c.done("category %d created: %s", category.Id, category.Name)
+	case "update":
+		category, err := service.UpdateCategory(admin, *id, update)
+		if err != nil {
+			return c.fail(err)
+		}
+		utils.Logger.Println("INFO:Admin", admin.User.Username, "updated category", category.Name)
+		return c.done("category %d updated: %s", category.Id, category.Name)
+	default:
+		if err := service.DeleteCategory(admin, *id); err != nil {
+			return c.fail(err)
+		}
+		utils.Logger.Printf("INFO:Admin %s deleted category id- %d", admin.User.Username, *id)
+		return c.done("category %d deleted", *id)
+	}
+}
+
+func (c *CLI) adminAudit(admin *models.Admin, filter models.AuditFilter) int {
+	entries, err := c.Services.Admin.GetAuditLog(admin, filter)
+	if err != nil {
+		return c.fail(err)
+	}
+	utils.Logger.Println("INFO:Admin", admin.User.Username, "viewed the audit log")
+	return c.render(render.AuditLog(entries))
+}
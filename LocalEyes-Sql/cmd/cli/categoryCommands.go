@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"errors"
+	"localEyes/internal/models"
+	"localEyes/internal/render"
+	"localEyes/utils"
+)
+
+func (c *CLI) runCategory(args []string) int {
+	if len(args) == 0 {
+		return c.usageError("category needs a subcommand")
+	}
+	switch args[0] {
+	case "list":
+		return c.categoryList(args[1:])
+	default:
+		return c.usageError("unknown category subcommand %q", args[0])
+	}
+}
+
+// categoryList shows the categories open for new posts; it needs no login.
+func (c *CLI) categoryList(args []string) int {
+	fs := c.flags("category list")
+	c.outputFlag(fs)
+	if code := c.parse(fs, args); code >= 0 {
+		return code
+	}
+	categories, err := c.Services.Categories.GiveCategories(true)
+	if err != nil {
+		return c.fail(err)
+	}
+	return c.render(render.Categories(categories))
+}
+
+// checkCategory reports an unknown --type as a usage error before logging
+// in; like parse it returns -1 when the command may go on.
+func (c *CLI) checkCategory(name string) int {
+	err := c.Services.Categories.ValidateCategory(name)
+	if err == nil {
+		return -1
+	}
+	if errors.Is(err, models.ErrInvalid) {
+		return c.usageError("%s", utils.PlainText(err.Error()))
+	}
+	return c.fail(err)
+}
@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"errors"
+	"localEyes/internal/models"
+	"localEyes/internal/render"
+	"localEyes/utils"
+)
+
+func (c *CLI) runCity(args []string) int {
+	if len(args) == 0 {
+		return c.usageError("city needs a subcommand")
+	}
+	switch args[0] {
+	case "list":
+		return c.cityList(args[1:])
+	default:
+		return c.usageError("unknown city subcommand %q", args[0])
+	}
+}
+
+// cityList shows the cities LocalEyes is open in; it needs no login.
+func (c *CLI) cityList(args []string) int {
+	fs := c.flags("city list")
+	c.outputFlag(fs)
+	if code := c.parse(fs, args); code >= 0 {
+		return code
+	}
+	cities, err := c.Services.Cities.GiveCities()
+	if err != nil {
+		return c.fail(err)
+	}
+	return c.render(render.Cities(cities))
+}
+
+// checkCity reports an unknown --city as a usage error before logging in;
+// like parse it returns -1 when the command may go on.
+func (c *CLI) checkCity(name string) int {
+	err := c.Services.Cities.ValidateCity(name)
+	if err == nil {
+		return -1
+	}
+	if errors.Is(err, models.ErrInvalid) {
+		return c.usageError("%s", utils.PlainText(err.Error()))
+	}
+	return c.fail(err)
+}
@@ -0,0 +1,345 @@
+// Package cli is the non-interactive face of LocalEyes: every operation the
+// menus offer is also reachable as "localeyes <noun> <verb> [flags]", with
+// credentials taken from the environment and the outcome reported through the
+// exit code, so LocalEyes can be scripted and used in shell pipelines.
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"localEyes/internal/models"
+	"localEyes/internal/render"
+	"localEyes/internal/services"
+	"localEyes/utils"
+	"os"
+	"strings"
+)
+
+// Exit codes returned by Run.
+const (
+	ExitOK    = 0 // the command succeeded
+	ExitError = 1 // the command ran but the operation failed
+	ExitUsage = 2 // the command line could not be understood
+	ExitAuth  = 3 // credentials were missing or rejected
+)
+
+// Environment variables holding the credentials.
+const (
+	UsernameEnv = "LOCALEYES_USERNAME"
+	PasswordEnv = "LOCALEYES_PASSWORD"
+	TokenEnv    = "LOCALEYES_TOKEN"
+)
+
+// OutputEnv picks the default output format of the list commands.
+const OutputEnv = "LOCALEYES_OUTPUT"
+
+type Services struct {
+	Users         *services.UserService
+	Posts         *services.PostService
+	Questions     *services.QuestionService
+	Admin         *services.AdminService
+	Sessions      *services.SessionService
+	Categories    *services.CategoryService
+	Cities        *services.CityService
+	Comments      *services.CommentService
+	Notifications *services.NotificationService
+}
+
+type CLI struct {
+	Services Services
+	Stdout   io.Writer
+	Stderr   io.Writer
+	Getenv   func(key string) string
+	// Output is the format listings are printed in; when empty it comes from
+	// OutputEnv, falling back to a table.
+	Output render.Format
+	// SessionFile is where "user login" caches the session token; when empty
+	// tokens are not cached.
+	SessionFile string
+}
+
+func New(svc Services) *CLI {
+	return &CLI{
+		Services: svc,
+		Stdout:   os.Stdout,
+		Stderr:   os.Stderr,
+		Getenv:   os.Getenv,
+
+		SessionFile: defaultSessionFile(),
+	}
+}
+
+const usage = `usage: localeyes [--output FORMAT] <command> [flags]
+
+Run without a command to start the interactive menus.
+
+Listings are printed as a table unless --output (or LOCALEYES_OUTPUT, which
+the menus honour too) asks for json, ndjson or csv. The list commands also
+take --output themselves.
+
+User commands need LOCALEYES_USERNAME and LOCALEYES_PASSWORD, or a session
+from "user login", which is cached in the user config dir (or LOCALEYES_TOKEN):
+  user signup --city CITY --dwelling-age N [--username NAME]
+  user login [--print-token]
+  user logout
+  user logout-all
+  user profile
+  user move --city CITY
+  user notifications
+  user deactivate
+
+Posts, feeds and notifications only cover your own city, see "city list".
+
+Post commands (TYPE is a category name, see "category list"):
+  city list
+  category list
+  post list [--type TYPE] [--mine] [--neighbourhood NAME] [PAGING]
+  post search --query WORDS [PAGING]
+  post near --lat DEG --lng DEG [--radius KM] [PAGING]
+  post create --type TYPE --title TITLE --content CONTENT
+              [--neighbourhood NAME] [--lat DEG --lng DEG]
+  post update --id ID --title TITLE --content CONTENT
+  post delete --id ID
+  post like --id ID
+  post unlike --id ID
+
+Question and answer commands:
+  question list --post ID
+  question search --query WORDS [PAGING]
+  question ask --post ID --text TEXT
+  question delete --id ID
+  question answer --id ID --text TEXT
+  answer edit --id ID --text TEXT
+  answer delete --id ID
+
+Comment commands (--depth N shows N levels of replies, 0 for all):
+  comment list --post ID [--depth N]
+  comment show --id ID [--depth N]
+  comment add --post ID --text TEXT
+  comment reply --id ID --text TEXT
+  comment edit --id ID --text TEXT
+  comment delete --id ID
+
+Notification commands ("user notifications" shows the unread ones and marks
+them read):
+  notification list [--unread] [PAGING]
+  notification read --id ID | --all
+  notification settings
+  notification set [--delivery immediate|digest|off] [--posts all|subscribed|none]
+  notification subscribe --category NAME | --author NAME
+  notification unsubscribe --category NAME | --author NAME
+
+With --posts subscribed only new posts in subscribed categories or by
+subscribed authors are notified. Digest delivery holds notifications back and
+sums them up once a day, the first time you look after the day is over.
+
+Admin commands (need a moderator or admin account; on a fresh install the
+operator makes the first admin with "localeyes bootstrap-admin USERNAME"):
+  admin user list [PAGING] | delete --id ID | reactivate --id ID
+  admin role grant --id ID --role user|moderator|admin
+  admin role revoke --id ID
+  admin audit list [--actor ID] [--target-type TYPE] [--target ID] [--from TIME] [--to TIME]
+  admin post list [--city CITY] [PAGING] | delete --id ID
+  admin question list [--city CITY] [PAGING] | delete --id ID
+  admin answer delete --id ID
+  admin comment delete --id ID
+  admin category list | create --name NAME [--description TEXT]
+  admin category update --id ID [--name NAME] [--description TEXT] [--active true|false]
+  admin category delete --id ID
+  admin city create --name NAME
+
+PAGING is [--page N] [--per-page N] [--sort ORDER]. Posts sort by newest,
+oldest, most-liked or most-questions, users, questions and notifications
+by newest or oldest, search results by relevance and posts near you by
+nearest. Under a table the command tells on stderr how to reach the
+previous and next pages.
+
+Schema commands:
+  migrate up | down [steps] | status
+
+Server commands:
+  serve [--addr :8080]    serve the JSON REST API
+
+Exit codes: 0 success, 1 operation failed, 2 usage error, 3 authentication failed.
+`
+
+// Run executes the command in args (without the program name) and returns
+// the process exit code.
+func (c *CLI) Run(args []string) int {
+	if c.Output == "" {
+		c.Output = render.Table
+		if env := c.Getenv(OutputEnv); env != "" {
+			if err := c.Output.Set(env); err != nil {
+				return c.usageError("%s: %v", OutputEnv, err)
+			}
+		}
+	}
+	fs := c.flags("")
+	fs.Usage = func() { fmt.Fprint(c.Stdout, usage) }
+	c.outputFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return ExitOK
+		}
+		return ExitUsage
+	}
+	args = fs.Args()
+	if len(args) == 0 {
+		fmt.Fprint(c.Stderr, usage)
+		return ExitUsage
+	}
+	switch args[0] {
+	case "user":
+		return c.runUser(args[1:])
+	case "post":
+		return c.runPost(args[1:])
+	case "category":
+		return c.runCategory(args[1:])
+	case "city":
+		return c.runCity(args[1:])
+	case "question":
+		return c.runQuestion(args[1:])
+	case "answer":
+		return c.runAnswer(args[1:])
+	case "comment":
+		return c.runComment(args[1:])
+	case "notification":
+		return c.runNotification(args[1:])
+	case "admin":
+		return c.runAdmin(args[1:])
+	case "help":
+		fmt.Fprint(c.Stdout, usage)
+		return ExitOK
+	default:
+		return c.usageError("unknown command %q", args[0])
+	}
+}
+
+func (c *CLI) usageError(format string, args ...any) int {
+	fmt.Fprintf(c.Stderr, "localeyes: "+format+"\n\n", args...)
+	fmt.Fprint(c.Stderr, usage)
+	return ExitUsage
+}
+
+// fail reports a failed operation and returns ExitError.
+func (c *CLI) fail(err error) int {
+	fmt.Fprintln(c.Stderr, "localeyes: "+utils.PlainText(err.Error()))
+	return ExitError
+}
+
+func (c *CLI) done(message string, args ...any) int {
+	fmt.Fprintf(c.Stdout, message+"\n", args...)
+	return ExitOK
+}
+
+// flags returns a flag set for "localeyes <command>" that reports to Stderr.
+func (c *CLI) flags(command string) *flag.FlagSet {
+	fs := flag.NewFlagSet(strings.TrimSpace("localeyes "+command), flag.ContinueOnError)
+	fs.SetOutput(c.Stderr)
+	return fs
+}
+
+// outputFlag lets a listing command override the output format.
+func (c *CLI) outputFlag(fs *flag.FlagSet) {
+	fs.Var(&c.Output, "output", "output format: table, json, ndjson or csv")
+}
+
+// render prints a listing to Stdout in the chosen output format.
+func (c *CLI) render(listing render.Listing) int {
+	if err := render.New(c.Output).Render(c.Stdout, listing); err != nil {
+		return c.fail(err)
+	}
+	return ExitOK
+}
+
+// pageFlags adds the flags that pick a page of a listing and its order.
+func (c *CLI) pageFlags(fs *flag.FlagSet, orders []string) *models.PageRequest {
+	page := &models.PageRequest{}
+	fs.IntVar(&page.Number, "page", 1, "page to show, counting from 1")
+	fs.IntVar(&page.Size, "per-page", models.DefaultPageSize, fmt.Sprintf("entries per page, at most %d", models.MaxPageSize))
+	fs.StringVar(&page.Sort, "sort", orders[0], "sort order: "+strings.Join(orders, ", "))
+	return page
+}
+
+// renderPage prints one page of a listing. Under a table it tells people on
+// Stderr how to reach the previous and next pages; the other formats are for
+// programs, which know which page they asked for.
+func (c *CLI) renderPage(listing render.Listing, page models.PageRequest, hasPrev, hasNext bool) int {
+	if code := c.render(listing); code != ExitOK || c.Output != render.Table {
+		return code
+	}
+	var moves []string
+	if hasPrev {
+		moves = append(moves, fmt.Sprintf("previous: --page %d", page.Prev().Number))
+	}
+	if hasNext {
+		moves = append(moves, fmt.Sprintf("next: --page %d", page.Next().Number))
+	}
+	if len(moves) > 0 {
+		fmt.Fprintf(c.Stderr, "page %d (%s)\n", page.Number, strings.Join(moves, ", "))
+	}
+	return ExitOK
+}
+
+// oneLine keeps a record on a single output line.
+func oneLine(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// parse parses args into fs and checks that every flag in required was set.
+// It returns -1 when the command should go on, or the exit code otherwise.
+func (c *CLI) parse(fs *flag.FlagSet, args []string, required ...string) int {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return ExitOK
+		}
+		return ExitUsage
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(c.Stderr, "unexpected argument %q\n", fs.Arg(0))
+		fs.Usage()
+		return ExitUsage
+	}
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	for _, name := range required {
+		if !set[name] {
+			fmt.Fprintf(c.Stderr, "flag -%s is required\n", name)
+			fs.Usage()
+			return ExitUsage
+		}
+	}
+	return -1
+}
+
+// login authenticates the user named in the environment, or else the
+// session in LOCALEYES_TOKEN or the session file.
+func (c *CLI) login() (*models.User, int) {
+	username, password := c.Getenv(UsernameEnv), c.Getenv(PasswordEnv)
+	if username == "" || password == "" {
+		return c.resumeSession()
+	}
+	user, err := c.Services.Users.Login(username, password)
+	if err != nil {
+		fmt.Fprintln(c.Stderr, "localeyes: "+utils.PlainText(err.Error()))
+		return nil, ExitAuth
+	}
+	return user, ExitOK
+}
+
+// adminLogin logs in like any other command and then checks that the
+// account's role allows admin commands.
+func (c *CLI) adminLogin() (*models.Admin, int) {
+	user, code := c.login()
+	if code != ExitOK {
+		return nil, code
+	}
+	admin, err := c.Services.Admin.AdminFor(user)
+	if err != nil {
+		fmt.Fprintln(c.Stderr, "localeyes: "+utils.PlainText(err.Error()))
+		return nil, ExitAuth
+	}
+	return admin, ExitOK
+}
@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"flag"
+	"localEyes/internal/models"
+	"localEyes/internal/render"
+)
+
+func (c *CLI) runComment(args []string) int {
+	if len(args) == 0 {
+		return c.usageError("comment needs a subcommand")
+	}
+	switch args[0] {
+	case "list":
+		return c.commentList(args[1:])
+	case "show":
+		return c.commentShow(args[1:])
+	case "add":
+		return c.commentAdd(args[1:])
+	case "reply":
+		return c.commentReply(args[1:])
+	case "edit":
+		return c.commentEdit(args[1:])
+	case "delete":
+		return c.commentDelete(args[1:])
+	default:
+		return c.usageError("unknown comment subcommand %q", args[0])
+	}
+}
+
+// depthFlag adds the flag limiting how many levels of replies are shown.
+func (c *CLI) depthFlag(fs *flag.FlagSet) *int {
+	return fs.Int("depth", models.DefaultCommentDepth, "levels of replies to show, 0 for all")
+}
+
+func (c *CLI) commentList(args []string) int {
+	fs := c.flags("comment list")
+	postId := fs.Int("post", 0, "id of the post")
+	depth := c.depthFlag(fs)
+	c.outputFlag(fs)
+	if code := c.parse(fs, args, "post"); code >= 0 {
+		return code
+	}
+	if _, code := c.login(); code != ExitOK {
+		return code
+	}
+	threads, err := c.Services.Comments.GetPostComments(*postId)
+	if err != nil {
+		return c.fail(err)
+	}
+	return c.render(render.Comments(threads, *depth))
+}
+
+// commentShow prints one comment and the replies below it, which is how to
+// read the part of a thread that comment list folds away.
+func (c *CLI) commentShow(args []string) int {
+	fs := c.flags("comment show")
+	id := fs.Int("id", 0, "id of the comment")
+	depth := c.depthFlag(fs)
+	c.outputFlag(fs)
+	if code := c.parse(fs, args, "id"); code >= 0 {
+		return code
+	}
+	if _, code := c.login(); code != ExitOK {
+		return code
+	}
+	thread, err := c.Services.Comments.GetThread(*id)
+	if err != nil {
+		return c.fail(err)
+	}
+	return c.render(render.Comments([]*models.Comment{thread}, *depth))
+}
+
+func (c *CLI) commentAdd(args []string) int {
+	fs := c.flags("comment add")
+	postId := fs.Int("post", 0, "id of the post to comment on")
+	text := fs.String("text", "", "the comment")
+	if code := c.parse(fs, args, "post", "text"); code >= 0 {
+		return code
+	}
+	user, code := c.login()
+	if code != ExitOK {
+		return code
+	}
+	if err := c.Services.Comments.AddComment(user.UId, *postId, *text); err != nil {
+		return c.fail(err)
+	}
+	return c.done("Comment added")
+}
+
+func (c *CLI) commentReply(args []string) int {
+	fs := c.flags("comment reply")
+	id := fs.Int("id", 0, "id of the comment to reply to")
+	text := fs.String("text", "", "the reply")
+	if code := c.parse(fs, args, "id", "text"); code >= 0 {
+		return code
+	}
+	user, code := c.login()
+	if code != ExitOK {
+		return code
+	}
+	if err := c.Services.Comments.Reply(user.UId, *id, *text); err != nil {
+		return c.fail(err)
+	}
+	return c.done("Reply added")
+}
+
+func (c *CLI) commentEdit(args []string) int {
+	fs := c.flags("comment edit")
+	id := fs.Int("id", 0, "id of the comment to edit")
+	text := fs.String("text", "", "the new comment")
+	if code := c.parse(fs, args, "id", "text"); code >= 0 {
+		return code
+	}
+	user, code := c.login()
+	if code != ExitOK {
+		return code
+	}
+	if err := c.Services.Comments.EditComment(user.UId, *id, *text); err != nil {
+		return c.fail(err)
+	}
+	return c.done("Comment %d updated", *id)
+}
+
+func (c *CLI) commentDelete(args []string) int {
+	fs := c.flags("comment delete")
+	id := fs.Int("id", 0, "id of the comment to delete")
+	if code := c.parse(fs, args, "id"); code >= 0 {
+		return code
+	}
+	user, code := c.login()
+	if code != ExitOK {
+		return code
+	}
+	if err := c.Services.Comments.DeleteMyComment(user.UId, *id); err != nil {
+		return c.fail(err)
+	}
+	return c.done("Comment %d deleted", *id)
+}
@@ -0,0 +1,152 @@
+package cli
+
+import (
+	"fmt"
+	"localEyes/internal/models"
+	"localEyes/internal/render"
+)
+
+func (c *CLI) runNotification(args []string) int {
+	if len(args) == 0 {
+		return c.usageError("notification needs a subcommand")
+	}
+	switch args[0] {
+	case "list":
+		return c.notificationList(args[1:])
+	case "read":
+		return c.notificationRead(args[1:])
+	case "settings":
+		return c.notificationSettings(args[1:])
+	case "set":
+		return c.notificationSet(args[1:])
+	case "subscribe":
+		return c.notificationSubscribe(args[1:], true)
+	case "unsubscribe":
+		return c.notificationSubscribe(args[1:], false)
+	default:
+		return c.usageError("unknown notification subcommand %q", args[0])
+	}
+}
+
+// notificationList pages through the inbox without marking anything read.
+func (c *CLI) notificationList(args []string) int {
+	fs := c.flags("notification list")
+	unread := fs.Bool("unread", false, "only list unread notifications")
+	page := c.pageFlags(fs, models.RecordSorts)
+	c.outputFlag(fs)
+	if code := c.parse(fs, args); code >= 0 {
+		return code
+	}
+	user, code := c.login()
+	if code != ExitOK {
+		return code
+	}
+	notifications, err := c.Services.Notifications.Inbox(user.UId, *unread, *page)
+	if err != nil {
+		return c.fail(err)
+	}
+	return c.renderPage(render.Notifications(notifications.Items), notifications.Request, notifications.HasPrev(), notifications.HasNext)
+}
+
+func (c *CLI) notificationRead(args []string) int {
+	fs := c.flags("notification read")
+	id := fs.Int("id", 0, "id of the notification to mark read")
+	all := fs.Bool("all", false, "mark every notification read")
+	if code := c.parse(fs, args); code >= 0 {
+		return code
+	}
+	if (*id == 0) == !*all {
+		return c.usageError("notification read needs either --id or --all")
+	}
+	user, code := c.login()
+	if code != ExitOK {
+		return code
+	}
+	if *all {
+		if err := c.Services.Notifications.MarkAllRead(user.UId); err != nil {
+			return c.fail(err)
+		}
+		return c.done("All notifications marked read")
+	}
+	if err := c.Services.Notifications.MarkRead(user.UId, *id); err != nil {
+		return c.fail(err)
+	}
+	return c.done("Notification %d marked read", *id)
+}
+
+// notificationSettings lists the subscriptions, with the delivery and posts
+// settings above them on stderr so stdout stays a clean listing.
+func (c *CLI) notificationSettings(args []string) int {
+	fs := c.flags("notification settings")
+	c.outputFlag(fs)
+	if code := c.parse(fs, args); code >= 0 {
+		return code
+	}
+	user, code := c.login()
+	if code != ExitOK {
+		return code
+	}
+	prefs, err := c.Services.Notifications.Preferences(user.UId)
+	if err != nil {
+		return c.fail(err)
+	}
+	fmt.Fprintf(c.Stderr, "Delivery: %s\nPosts: %s\n", prefs.Delivery, prefs.Posts)
+	return c.render(render.Subscriptions(prefs.Subscriptions))
+}
+
+func (c *CLI) notificationSet(args []string) int {
+	fs := c.flags("notification set")
+	delivery := fs.String("delivery", "", "immediate, digest or off")
+	posts := fs.String("posts", "", "which new posts to hear about: all, subscribed or none")
+	if code := c.parse(fs, args); code >= 0 {
+		return code
+	}
+	if *delivery == "" && *posts == "" {
+		return c.usageError("notification set needs --delivery, --posts or both")
+	}
+	user, code := c.login()
+	if code != ExitOK {
+		return code
+	}
+	prefs, err := c.Services.Notifications.UpdatePreferences(user.UId, *delivery, *posts)
+	if err != nil {
+		return c.fail(err)
+	}
+	return c.done("Delivery %s, posts %s", prefs.Delivery, prefs.Posts)
+}
+
+// notificationSubscribe subscribes to, or unsubscribes from, one category or
+// author.
+func (c *CLI) notificationSubscribe(args []string, subscribe bool) int {
+	command := "notification unsubscribe"
+	if subscribe {
+		command = "notification subscribe"
+	}
+	fs := c.flags(command)
+	category := fs.String("category", "", "category name, see category list")
+	author := fs.String("author", "", "username of the author")
+	if code := c.parse(fs, args); code >= 0 {
+		return code
+	}
+	if (*category == "") == (*author == "") {
+		return c.usageError("%s needs either --category or --author", command)
+	}
+	user, code := c.login()
+	if code != ExitOK {
+		return code
+	}
+	kind, name := models.TargetCategory, *category
+	if *author != "" {
+		kind, name = models.TargetUser, *author
+	}
+	if !subscribe {
+		if err := c.Services.Notifications.Unsubscribe(user.UId, kind, name); err != nil {
+			return c.fail(err)
+		}
+		return c.done("Unsubscribed from %s", name)
+	}
+	if err := c.Services.Notifications.Subscribe(user.UId, kind, name); err != nil {
+		return c.fail(err)
+	}
+	return c.done("Subscribed to %s", name)
+}
@@ -0,0 +1,209 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"localEyes/internal/models"
+	"localEyes/internal/render"
+	"localEyes/utils"
+	"strconv"
+)
+
+func (c *CLI) runPost(args []string) int {
+	if len(args) == 0 {
+		return c.usageError("post needs a subcommand")
+	}
+	switch args[0] {
+	case "list":
+		return c.postList(args[1:])
+	case "search":
+		return c.postSearch(args[1:])
+	case "near":
+		return c.postNear(args[1:])
+	case "create":
+		return c.postCreate(args[1:])
+	case "update":
+		return c.postUpdate(args[1:])
+	case "delete":
+		return c.postDelete(args[1:])
+	case "like":
+		return c.postLike(args[1:], true)
+	case "unlike":
+		return c.postLike(args[1:], false)
+	default:
+		return c.usageError("unknown post subcommand %q", args[0])
+	}
+}
+
+func (c *CLI) postList(args []string) int {
+	fs := c.flags("post list")
+	postType := fs.String("type", "", "only list posts in this category")
+	mine := fs.Bool("mine", false, "only list my own posts")
+	neighbourhood := fs.String("neighbourhood", "", "only list posts tagged with this neighbourhood")
+	page := c.pageFlags(fs, models.PostSorts)
+	c.outputFlag(fs)
+	if code := c.parse(fs, args); code >= 0 {
+		return code
+	}
+	if code := c.checkCategory(*postType); code >= 0 {
+		return code
+	}
+	user, code := c.login()
+	if code != ExitOK {
+		return code
+	}
+	filter := models.PostFilter{Type: *postType, City: user.City, Neighbourhood: *neighbourhood}
+	if *mine {
+		filter.UId = user.UId
+	}
+	posts, err := c.Services.Posts.ListPosts(user.UId, filter, *page)
+	if err != nil {
+		return c.fail(err)
+	}
+	return c.renderPage(render.Posts(posts.Items), posts.Request, posts.HasPrev(), posts.HasNext)
+}
+
+func (c *CLI) postSearch(args []string) int {
+	fs := c.flags("post search")
+	search := fs.String("query", "", "words to look for in post titles and content")
+	page := c.pageFlags(fs, models.SearchSorts)
+	c.outputFlag(fs)
+	if code := c.parse(fs, args, "query"); code >= 0 {
+		return code
+	}
+	user, code := c.login()
+	if code != ExitOK {
+		return code
+	}
+	posts, err := c.Services.Posts.SearchPosts(user.UId, user.City, *search, *page)
+	if err != nil {
+		return c.fail(err)
+	}
+	return c.renderPage(render.Posts(posts.Items), posts.Request, posts.HasPrev(), posts.HasNext)
+}
+
+// postNear lists the posts tagged within --radius km of the given point.
+func (c *CLI) postNear(args []string) int {
+	fs := c.flags("post near")
+	latitude := fs.Float64("lat", 0, "your latitude in degrees")
+	longitude := fs.Float64("lng", 0, "your longitude in degrees")
+	radius := fs.Float64("radius", models.DefaultNearRadiusKm, fmt.Sprintf("how far to look in km, at most %g", models.MaxNearRadiusKm))
+	page := c.pageFlags(fs, models.NearSorts)
+	c.outputFlag(fs)
+	if code := c.parse(fs, args, "lat", "lng"); code >= 0 {
+		return code
+	}
+	user, code := c.login()
+	if code != ExitOK {
+		return code
+	}
+	posts, err := c.Services.Posts.NearPosts(user.UId, *latitude, *longitude, *radius, *page)
+	if err != nil {
+		return c.fail(err)
+	}
+	return c.renderPage(render.Posts(posts.Items), posts.Request, posts.HasPrev(), posts.HasNext)
+}
+
+func (c *CLI) postCreate(args []string) int {
+	fs := c.flags("post create")
+	postType := fs.String("type", "", "category of the post, see category list")
+	title := fs.String("title", "", "post title")
+	content := fs.String("content", "", "post content")
+	var location models.Location
+	fs.StringVar(&location.Neighbourhood, "neighbourhood", "", "neighbourhood the post is about")
+	fs.Func("lat", "latitude the post is about, needs --lng", coordinateFlag(&location.Latitude))
+	fs.Func("lng", "longitude the post is about, needs --lat", coordinateFlag(&location.Longitude))
+	if code := c.parse(fs, args, "type", "title", "content"); code >= 0 {
+		return code
+	}
+	if *postType == "" {
+		return c.usageError("the post needs a --type")
+	}
+	if code := c.checkCategory(*postType); code >= 0 {
+		return code
+	}
+	user, code := c.login()
+	if code != ExitOK {
+		return code
+	}
+	_, err := c.Services.Posts.CreatePost(user.UId, *title, *content, *postType, location)
+	if err != nil {
+		utils.Logger.Println("ERROR: Error creating post: " + err.Error())
+		return c.fail(err)
+	}
+	utils.Logger.Println("INFO: Post created:", *title)
+	return c.done("Post created: %s", *title)
+}
+
+func (c *CLI) postUpdate(args []string) int {
+	fs := c.flags("post update")
+	id := fs.Int("id", 0, "id of the post to update")
+	title := fs.String("title", "", "new post title")
+	content := fs.String("content", "", "new post content")
+	if code := c.parse(fs, args, "id", "title", "content"); code >= 0 {
+		return code
+	}
+	user, code := c.login()
+	if code != ExitOK {
+		return code
+	}
+	if err := c.Services.Posts.UpdateMyPost(*id, user.UId, *title, *content); err != nil {
+		return c.fail(err)
+	}
+	return c.done("Post updated: %s", *title)
+}
+
+func (c *CLI) postDelete(args []string) int {
+	fs := c.flags("post delete")
+	id := fs.Int("id", 0, "id of the post to delete")
+	if code := c.parse(fs, args, "id"); code >= 0 {
+		return code
+	}
+	user, code := c.login()
+	if code != ExitOK {
+		return code
+	}
+	if err := c.Services.Posts.DeleteMyPost(user.UId, *id); err != nil {
+		return c.fail(err)
+	}
+	return c.done("Post %d deleted", *id)
+}
+
+func (c *CLI) postLike(args []string, like bool) int {
+	command := "post like"
+	if !like {
+		command = "post unlike"
+	}
+	fs := c.flags(command)
+	id := fs.Int("id", 0, "id of the post")
+	if code := c.parse(fs, args, "id"); code >= 0 {
+		return code
+	}
+	user, code := c.login()
+	if code != ExitOK {
+		return code
+	}
+	if !like {
+		if err := c.Services.Posts.Unlike(user.UId, *id); err != nil {
+			return c.fail(err)
+		}
+		return c.done("Post %d unliked", *id)
+	}
+	if err := c.Services.Posts.Like(user.UId, *id); err != nil {
+		return c.fail(err)
+	}
+	return c.done("Post %d liked", *id)
+}
+
+// coordinateFlag parses an optional coordinate into *dst, which stays nil
+// when the flag is not given.
+func coordinateFlag(dst **float64) func(string) error {
+	return func(value string) error {
+		degrees, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return errors.New("not a number of degrees")
+		}
+		*dst = &degrees
+		return nil
+	}
+}
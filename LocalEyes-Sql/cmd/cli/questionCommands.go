@@ -0,0 +1,166 @@
+package cli
+
+import (
+	"errors"
+	"localEyes/internal/models"
+	"localEyes/internal/render"
+)
+
+func (c *CLI) runQuestion(args []string) int {
+	if len(args) == 0 {
+		return c.usageError("question needs a subcommand")
+	}
+	switch args[0] {
+	case "list":
+		return c.questionList(args[1:])
+	case "search":
+		return c.questionSearch(args[1:])
+	case "ask":
+		return c.questionAsk(args[1:])
+	case "delete":
+		return c.questionDelete(args[1:])
+	case "answer":
+		return c.questionAnswer(args[1:])
+	default:
+		return c.usageError("unknown question subcommand %q", args[0])
+	}
+}
+
+func (c *CLI) runAnswer(args []string) int {
+	if len(args) == 0 {
+		return c.usageError("answer needs a subcommand")
+	}
+	switch args[0] {
+	case "edit":
+		return c.answerEdit(args[1:])
+	case "delete":
+		return c.answerDelete(args[1:])
+	default:
+		return c.usageError("unknown answer subcommand %q", args[0])
+	}
+}
+
+func (c *CLI) questionList(args []string) int {
+	fs := c.flags("question list")
+	postId := fs.Int("post", 0, "id of the post")
+	c.outputFlag(fs)
+	if code := c.parse(fs, args, "post"); code >= 0 {
+		return code
+	}
+	if _, code := c.login(); code != ExitOK {
+		return code
+	}
+	questions, err := c.Services.Questions.GetPostQuestions(*postId)
+	if err != nil {
+		return c.fail(err)
+	}
+	return c.render(render.Questions(questions))
+}
+
+func (c *CLI) questionSearch(args []string) int {
+	fs := c.flags("question search")
+	search := fs.String("query", "", "words to look for in questions and their answers")
+	page := c.pageFlags(fs, models.SearchSorts)
+	c.outputFlag(fs)
+	if code := c.parse(fs, args, "query"); code >= 0 {
+		return code
+	}
+	if _, code := c.login(); code != ExitOK {
+		return code
+	}
+	questions, err := c.Services.Questions.SearchQuestions(*search, *page)
+	if err != nil {
+		return c.fail(err)
+	}
+	return c.renderPage(render.Questions(questions.Items), questions.Request, questions.HasPrev(), questions.HasNext)
+}
+
+func (c *CLI) questionAsk(args []string) int {
+	fs := c.flags("question ask")
+	postId := fs.Int("post", 0, "id of the post to ask on")
+	text := fs.String("text", "", "the question")
+	if code := c.parse(fs, args, "post", "text"); code >= 0 {
+		return code
+	}
+	user, code := c.login()
+	if code != ExitOK {
+		return code
+	}
+	exists, err := c.Services.Posts.PostIdExist(*postId)
+	if err != nil {
+		return c.fail(err)
+	}
+	if !exists {
+		return c.fail(errors.New("Post Id does not exist"))
+	}
+	if err := c.Services.Questions.AskQuestion(user.UId, *postId, *text); err != nil {
+		return c.fail(err)
+	}
+	return c.done("Question added")
+}
+
+func (c *CLI) questionDelete(args []string) int {
+	fs := c.flags("question delete")
+	id := fs.Int("id", 0, "id of the question to delete")
+	if code := c.parse(fs, args, "id"); code >= 0 {
+		return code
+	}
+	user, code := c.login()
+	if code != ExitOK {
+		return code
+	}
+	if err := c.Services.Questions.DeleteUserQues(user.UId, *id); err != nil {
+		return c.fail(err)
+	}
+	return c.done("Question %d deleted", *id)
+}
+
+func (c *CLI) questionAnswer(args []string) int {
+	fs := c.flags("question answer")
+	id := fs.Int("id", 0, "id of the question to answer")
+	text := fs.String("text", "", "the answer")
+	if code := c.parse(fs, args, "id", "text"); code >= 0 {
+		return code
+	}
+	user, code := c.login()
+	if code != ExitOK {
+		return code
+	}
+	if err := c.Services.Questions.AddAnswer(*id, user.UId, *text); err != nil {
+		return c.fail(err)
+	}
+	return c.done("Answer added")
+}
+
+func (c *CLI) answerEdit(args []string) int {
+	fs := c.flags("answer edit")
+	id := fs.Int("id", 0, "id of the answer to edit")
+	text := fs.String("text", "", "the new answer")
+	if code := c.parse(fs, args, "id", "text"); code >= 0 {
+		return code
+	}
+	user, code := c.login()
+	if code != ExitOK {
+		return code
+	}
+	if err := c.Services.Questions.EditAnswer(user.UId, *id, *text); err != nil {
+		return c.fail(err)
+	}
+	return c.done("Answer %d updated", *id)
+}
+
+func (c *CLI) answerDelete(args []string) int {
+	fs := c.flags("answer delete")
+	id := fs.Int("id", 0, "id of the answer to delete")
+	if code := c.parse(fs, args, "id"); code >= 0 {
+		return code
+	}
+	user, code := c.login()
+	if code != ExitOK {
+		return code
+	}
+	if err := c.Services.Questions.DeleteMyAnswer(user.UId, *id); err != nil {
+		return c.fail(err)
+	}
+	return c.done("Answer %d deleted", *id)
+}
@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"localEyes/internal/models"
+	"localEyes/utils"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// defaultSessionFile is <user config dir>/localeyes/session, or "" when the
+// platform has no config dir.
+func defaultSessionFile() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "localeyes", "session")
+}
+
+// token returns the session token from the environment or the session file.
+func (c *CLI) token() string {
+	if token := c.Getenv(TokenEnv); token != "" {
+		return token
+	}
+	if c.SessionFile == "" {
+		return ""
+	}
+	data, err := os.ReadFile(c.SessionFile)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// saveToken caches the token where only the current user can read it.
+func (c *CLI) saveToken(token string) error {
+	if c.SessionFile == "" {
+		return errors.New("no config dir to keep the session in, use --print-token")
+	}
+	if err := os.MkdirAll(filepath.Dir(c.SessionFile), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(c.SessionFile, []byte(token+"\n"), 0o600)
+}
+
+func (c *CLI) removeToken() {
+	if c.SessionFile == "" {
+		return
+	}
+	if err := os.Remove(c.SessionFile); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		fmt.Fprintln(c.Stderr, "localeyes: warning: could not remove "+c.SessionFile+": "+err.Error())
+	}
+}
+
+// resumeSession authenticates with the cached session token.
+func (c *CLI) resumeSession() (*models.User, int) {
+	token := c.token()
+	if token == "" {
+		fmt.Fprintf(c.Stderr, "localeyes: set %s and %s, or run \"localeyes user login\" first\n", UsernameEnv, PasswordEnv)
+		return nil, ExitAuth
+	}
+	user, err := c.Services.Sessions.Authenticate(token)
+	if err != nil {
+		fmt.Fprintln(c.Stderr, "localeyes: "+utils.PlainText(err.Error())+", run \"localeyes user login\" again")
+		return nil, ExitAuth
+	}
+	return user, ExitOK
+}
+
+// userLogin checks the credentials in the environment once and starts a
+// session, so later commands need neither the password nor a prompt.
+func (c *CLI) userLogin(args []string) int {
+	fs := c.flags("user login")
+	printToken := fs.Bool("print-token", false, "print the token instead of caching it, for use in "+TokenEnv)
+	if code := c.parse(fs, args); code >= 0 {
+		return code
+	}
+	username, password := c.Getenv(UsernameEnv), c.Getenv(PasswordEnv)
+	if username == "" || password == "" {
+		fmt.Fprintf(c.Stderr, "localeyes: set %s and %s to log in\n", UsernameEnv, PasswordEnv)
+		return ExitAuth
+	}
+	user, err := c.Services.Users.Login(username, password)
+	if err != nil {
+		fmt.Fprintln(c.Stderr, "localeyes: "+utils.PlainText(err.Error()))
+		return ExitAuth
+	}
+	token, session, err := c.Services.Sessions.Start(user.UId)
+	if err != nil {
+		return c.fail(err)
+	}
+	if *printToken {
+		return c.done("%s", token)
+	}
+	if err := c.saveToken(token); err != nil {
+		_ = c.Services.Sessions.Logout(token)
+		return c.fail(err)
+	}
+	return c.done("Logged in as %s until %s", user.Username, session.ExpiresAt.Format(time.DateTime))
+}
+
+func (c *CLI) userLogout(args []string) int {
+	if code := c.parse(c.flags("user logout"), args); code >= 0 {
+		return code
+	}
+	token := c.token()
+	if token == "" {
+		return c.done("Not logged in")
+	}
+	if err := c.Services.Sessions.Logout(token); err != nil {
+		return c.fail(err)
+	}
+	c.removeToken()
+	return c.done("Logged out")
+}
+
+// userLogoutAll ends every session of the user, on every device.
+func (c *CLI) userLogoutAll(args []string) int {
+	if code := c.parse(c.flags("user logout-all"), args); code >= 0 {
+		return code
+	}
+	user, code := c.login()
+	if code != ExitOK {
+		return code
+	}
+	if err := c.Services.Sessions.LogoutAll(user.UId); err != nil {
+		return c.fail(err)
+	}
+	c.removeToken()
+	return c.done("Logged out of all devices")
+}
@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"fmt"
+	"localEyes/internal/services"
+	"localEyes/utils"
+)
+
+func (c *CLI) runUser(args []string) int {
+	if len(args) == 0 {
+		return c.usageError("user needs a subcommand")
+	}
+	switch args[0] {
+	case "signup":
+		return c.userSignup(args[1:])
+	case "login":
+		return c.userLogin(args[1:])
+	case "logout":
+		return c.userLogout(args[1:])
+	case "logout-all":
+		return c.userLogoutAll(args[1:])
+	case "profile":
+		return c.userProfile(args[1:])
+	case "move":
+		return c.userMove(args[1:])
+	case "notifications":
+		return c.userNotifications(args[1:])
+	case "deactivate":
+		return c.userDeactivate(args[1:])
+	default:
+		return c.usageError("unknown user subcommand %q", args[0])
+	}
+}
+
+// userSignup creates the account; the password always comes from the
+// environment so it never shows up in the process list or shell history.
+func (c *CLI) userSignup(args []string) int {
+	fs := c.flags("user signup")
+	username := fs.String("username", c.Getenv(UsernameEnv), "username, defaults to $"+UsernameEnv)
+	city := fs.String("city", "", "city you live in, see city list")
+	dwellingAge := fs.Int("dwelling-age", 0, "years lived in the city")
+	if code := c.parse(fs, args, "city", "dwelling-age"); code >= 0 {
+		return code
+	}
+	password := c.Getenv(PasswordEnv)
+	if *username == "" || password == "" {
+		fmt.Fprintf(c.Stderr, "localeyes: set %s and %s (or pass -username) to sign up\n", UsernameEnv, PasswordEnv)
+		return ExitUsage
+	}
+	if code := c.checkCity(*city); code >= 0 {
+		return code
+	}
+	if !utils.ValidateUsername(*username, c.Services.Users.Repo) {
+		return c.fail(fmt.Errorf("username %q is already taken", *username))
+	}
+	if !utils.ValidatePassword(password) {
+		return c.fail(fmt.Errorf("password is weak: use at least 6 characters with a special character and a number"))
+	}
+	tag := "newbie"
+	if *dwellingAge > 2 {
+		tag = "resident"
+	}
+	if err := c.Services.Users.Signup(*username, password, *city, *dwellingAge, tag); err != nil {
+		return c.fail(err)
+	}
+	return c.done("Signed up %s", *username)
+}
+
+func (c *CLI) userProfile(args []string) int {
+	if code := c.parse(c.flags("user profile"), args); code >= 0 {
+		return code
+	}
+	user, code := c.login()
+	if code != ExitOK {
+		return code
+	}
+	fmt.Fprintf(c.Stdout, "Username: %s\nCity: %s\nType of user: %s\nLiving in city for: %d years\n", user.Username, user.City, user.Tag, user.DwellingAge)
+	return ExitOK
+}
+
+// userMove changes the city the user's feed and notifications come from.
+func (c *CLI) userMove(args []string) int {
+	fs := c.flags("user move")
+	city := fs.String("city", "", "city you now live in, see city list")
+	if code := c.parse(fs, args, "city"); code >= 0 {
+		return code
+	}
+	if code := c.checkCity(*city); code >= 0 {
+		return code
+	}
+	user, code := c.login()
+	if code != ExitOK {
+		return code
+	}
+	if err := c.Services.Users.ChangeCity(user.UId, *city); err != nil {
+		return c.fail(err)
+	}
+	utils.Logger.Println("INFO: User id-", user.UId, "moved to", *city)
+	return c.done("Moved %s to %s", user.Username, services.CityName(*city))
+}
+
+// userNotifications prints the unread notifications and marks them read, like
+// logging in to the menus does; "notification list" still shows them after.
+func (c *CLI) userNotifications(args []string) int {
+	if code := c.parse(c.flags("user notifications"), args); code >= 0 {
+		return code
+	}
+	user, code := c.login()
+	if code != ExitOK {
+		return code
+	}
+	unread, err := c.Services.Notifications.TakeUnread(user.UId)
+	if err != nil {
+		return c.fail(err)
+	}
+	for _, notification := range unread {
+		fmt.Fprintln(c.Stdout, oneLine(notification.Message))
+	}
+	return ExitOK
+}
+
+func (c *CLI) userDeactivate(args []string) int {
+	if code := c.parse(c.flags("user deactivate"), args); code >= 0 {
+		return code
+	}
+	user, code := c.login()
+	if code != ExitOK {
+		return code
+	}
+	if err := c.Services.Users.DeActivate(user.UId); err != nil {
+		return c.fail(err)
+	}
+	utils.Logger.Println("INFO: User Deactivated with id-", user.UId)
+	if err := c.Services.Sessions.LogoutAll(user.UId); err != nil {
+		utils.Logger.Println("ERROR: Error ending sessions of user id-", user.UId, err)
+	}
+	c.removeToken()
+	return c.done("Deactivated %s", user.Username)
+}
@@ -5,12 +5,16 @@ import (
 	"fmt"
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/joho/godotenv"
+	"localEyes/cmd/cli"
 	"localEyes/cmd/ui"
 	"localEyes/config"
+	"localEyes/internal/interfaces"
+	"localEyes/internal/render"
 	"localEyes/internal/repositories"
 	"localEyes/internal/services"
 	"localEyes/utils"
 	"log"
+	"os"
 )
 
 var dbClient *sql.DB
@@ -20,24 +24,96 @@ func init() {
 	if err != nil {
 		log.Fatal("Error loading .env file", err)
 	}
-	dbClient = config.GetSQLClient()
+	if config.GetDBDriver() != config.MemoryDriver {
+		dbClient = config.GetSQLClient()
+	}
 	utils.InitLoggerFile()
 }
 
 func main() {
 	defer config.CloseDBClient()
 	defer utils.CloseLoggerFile()
-	userService := services.NewUserService(repositories.NewMySQLUserRepository(dbClient))
+	if len(os.Args) > 1 {
+		var code int
+		switch os.Args[1] {
+		case "migrate":
+			code = runMigrate(os.Args[2:])
+		case "bootstrap-admin":
+			code = runBootstrapAdmin(os.Args[2:])
+		case "serve":
+			code = runServe(os.Args[2:])
+		default:
+			// One-shot commands leave the background work to the UI and
+			// serve; what they queue waits in the outbox until one runs.
+			svc, _ := newServices()
+			code = cli.New(svc).Run(os.Args[1:])
+		}
+		config.CloseDBClient()
+		utils.CloseLoggerFile()
+		os.Exit(code)
+	}
+	svc, jobs := newServices()
+	jobs.start()
+	defer jobs.stop()
+	if format, err := render.ParseFormat(os.Getenv(cli.OutputEnv)); err == nil {
+		ui.SetOutput(format)
+	}
+
+	ui.RootCli(svc.Users, svc.Posts, svc.Questions, svc.Admin, svc.Sessions, svc.Categories, svc.Cities, svc.Comments, svc.Notifications)
+
+	fmt.Println(config.Magenta + "Thank you 😊, Visit Again" + config.Reset)
+}
 
-	postService := services.NewPostService(repositories.NewMySQLPostRepository(dbClient))
+// newServices builds the services along with the background work they rely
+// on; long-running callers start and stop it.
+func newServices() (cli.Services, *background) {
+	repos, uow := newRepositories(config.GetDBDriver())
 
-	questionService := services.NewQuestionService(repositories.NewMySQLQuestionRepository(dbClient))
+	notificationService := services.NewNotificationService(repos.Notifications, repos.NotificationPrefs, uow)
+	uow = notificationService.Publishing(uow)
 
-	adminService := services.NewAdminService(repositories.NewMySQLUserRepository(dbClient),
-		repositories.NewMySQLPostRepository(dbClient),
-		repositories.NewMySQLQuestionRepository(dbClient))
+	userService := services.NewUserService(repos.Users, uow)
 
-	ui.RootCli(userService, postService, questionService, adminService)
+	postService := services.NewPostService(repos.Posts, repos.Likes, uow)
 
-	fmt.Println(config.Magenta + "Thank you 😊, Visit Again" + config.Reset)
+	questionService := services.NewQuestionService(repos.Questions, repos.Answers, uow)
+
+	adminService := services.NewAdminService(repos.Users, repos.Posts, repos.Questions, repos.Answers, repos.Audit, uow)
+
+	sessionService := services.NewSessionService(repos.Sessions, repos.Users)
+
+	categoryService := services.NewCategoryService(repos.Categories)
+
+	cityService := services.NewCityService(repos.Cities)
+
+	commentService := services.NewCommentService(repos.Comments, uow)
+
+	jobs := &background{
+		outbox:        services.NewOutboxWorker(repos.Outbox, uow),
+		notifications: notificationService,
+		live:          services.NewPollingSource(repos.Notifications),
+	}
+
+	return cli.Services{Users: userService, Posts: postService, Questions: questionService, Admin: adminService, Sessions: sessionService, Categories: categoryService, Cities: cityService, Comments: commentService, Notifications: notificationService}, jobs
+}
+
+func newRepositories(driver string) (interfaces.Repositories, interfaces.UnitOfWork) {
+	if driver == config.MemoryDriver {
+		users := repositories.NewInMemoryUserRepository()
+		posts := repositories.NewInMemoryPostRepository()
+		questions := repositories.NewInMemoryQuestionRepository()
+		answers := repositories.NewInMemoryAnswerRepository()
+		likes := repositories.NewInMemoryPostLikeRepository()
+		sessions := repositories.NewInMemorySessionRepository()
+		audit := repositories.NewInMemoryAuditRepository()
+		categories := repositories.NewInMemoryCategoryRepository()
+		cities := repositories.NewInMemoryCityRepository()
+		comments := repositories.NewInMemoryCommentRepository()
+		notifications := repositories.NewInMemoryNotificationRepository()
+		notificationPrefs := repositories.NewInMemoryNotificationPrefsRepository()
+		outbox := repositories.NewInMemoryOutboxRepository()
+		return interfaces.Repositories{Users: users, Posts: posts, Questions: questions, Answers: answers, Likes: likes, Sessions: sessions, Audit: audit, Categories: categories, Cities: cities, Comments: comments, Notifications: notifications, NotificationPrefs: notificationPrefs, Outbox: outbox},
+			repositories.NewInMemoryUnitOfWork(users, posts, questions, answers, likes, sessions, audit, categories, cities, comments, notifications, notificationPrefs, outbox)
+	}
+	return repositories.NewSQLRepositories(driver, dbClient), repositories.NewSQLUnitOfWork(dbClient, driver)
 }
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"localEyes/config"
+	"localEyes/internal/migrations"
+	"os"
+	"strconv"
+)
+
+func runMigrate(args []string) int {
+	if len(args) < 1 {
+		fmt.Println("usage: localeyes migrate up|down [steps]|status")
+		return 2
+	}
+	if config.GetDBDriver() == config.MemoryDriver {
+		fmt.Println("The memory driver keeps no schema, nothing to migrate")
+		return 0
+	}
+	migrator, err := migrations.NewMigrator(dbClient, config.GetDBDriver())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, config.Red+"Error loading migrations: "+err.Error()+config.Reset)
+		return 1
+	}
+
+	switch args[0] {
+	case "up":
+		ran, err := migrator.Up()
+		for _, migration := range ran {
+			fmt.Printf(config.Green+"Applied %04d_%s\n"+config.Reset, migration.Version, migration.Name)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, config.Red+err.Error()+config.Reset)
+			return 1
+		}
+		if len(ran) == 0 {
+			fmt.Println("Schema is up to date")
+		}
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil {
+				fmt.Fprintln(os.Stderr, config.Red+"Invalid number of steps: "+args[1]+config.Reset)
+				return 2
+			}
+		}
+		reverted, err := migrator.Down(steps)
+		for _, migration := range reverted {
+			fmt.Printf(config.Yellow+"Reverted %04d_%s\n"+config.Reset, migration.Version, migration.Name)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, config.Red+err.Error()+config.Reset)
+			return 1
+		}
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, config.Red+err.Error()+config.Reset)
+			return 1
+		}
+		for _, status := range statuses {
+			state := config.Yellow + "pending" + config.Reset
+			if status.Applied {
+				state = config.Green + "applied " + status.AppliedAt.Format("2006-01-02 15:04:05") + config.Reset
+			}
+			fmt.Printf("%04d_%-30s %s\n", status.Migration.Version, status.Migration.Name, state)
+		}
+	default:
+		fmt.Println("usage: localeyes migrate up|down [steps]|status")
+		return 2
+	}
+	return 0
+}
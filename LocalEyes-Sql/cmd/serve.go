@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"localEyes/cmd/api"
+	"localEyes/config"
+	"localEyes/utils"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// runServe serves the REST API until interrupted, then lets in-flight
+// requests finish and announces posts still in the outbox before returning.
+func runServe(args []string) int {
+	fs := flag.NewFlagSet("localeyes serve", flag.ContinueOnError)
+	addr := fs.String("addr", ":8080", "address to listen on")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	svc, jobs := newServices()
+	jobs.start()
+	defer jobs.stop()
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           api.NewServer(svc.Users, svc.Posts, svc.Questions, svc.Admin, svc.Sessions, svc.Categories, svc.Cities, svc.Comments, svc.Notifications),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	// Event streams only end when the live feed does.
+	server.RegisterOnShutdown(jobs.endLive)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	failed := make(chan error, 1)
+	go func() {
+		failed <- server.ListenAndServe()
+	}()
+	fmt.Println(config.Green + "Serving the LocalEyes API on " + *addr + config.Reset)
+	utils.Logger.Println("INFO: API server listening on", *addr)
+
+	select {
+	case err := <-failed:
+		fmt.Fprintln(os.Stderr, config.Red+"Error serving the API: "+err.Error()+config.Reset)
+		return 1
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		fmt.Fprintln(os.Stderr, config.Red+"Error shutting down the API: "+err.Error()+config.Reset)
+		return 1
+	}
+	utils.Logger.Println("INFO: API server stopped")
+	return 0
+}
@@ -7,27 +7,29 @@ import (
 	"fmt"
 	"github.com/manifoldco/promptui"
 	"localEyes/config"
+	"localEyes/internal/models"
 	"localEyes/internal/services"
 	"localEyes/utils"
+	"strconv"
 )
 
-func adminLogin(adminService *services.AdminService) {
+func adminLogin(adminService *services.AdminService, cityService *services.CityService) {
 	fmt.Println(config.Blue + "\n==============================")
 	fmt.Println("ADMIN LOGIN")
 	fmt.Println("=============================" + config.Reset)
-	//username := utils.PromptInput("Enter your username:")
+	username := utils.PromptInput("Enter your username:")
 	prompt := &promptui.Prompt{
 		Label:     config.Cyan + "Enter your password" + config.Reset,
 		Mask:      '*',
 		IsConfirm: false,
 	}
 	password := utils.PromptPassword(prompt)
-	_, err := adminService.Login(password)
+	admin, err := adminService.Login(username, password)
 	if err != nil {
 		fmt.Println(err)
 		return
 	}
-	fmt.Println(config.Green + "\nAdmin logged in successfully" + config.Reset)
+	fmt.Println(config.Green + "\n" + admin.User.Role + " " + admin.User.Username + " logged in successfully" + config.Reset)
 
 	for {
 		fmt.Println(config.Blue + "\n1.View Users")
@@ -37,36 +39,39 @@ func adminLogin(adminService *services.AdminService) {
 		fmt.Println("5.Delete a question")
 		fmt.Println("6.Delete a post")
 		fmt.Println("7.ReActivate User")
-		fmt.Println("8.Return" + config.Reset)
+		fmt.Println("8.Delete an answer")
+		fmt.Println("9.Grant a role")
+		fmt.Println("10.Revoke a role")
+		fmt.Println("11.View audit log")
+		fmt.Println("12.Manage categories")
+		fmt.Println("13.Add a city")
+		fmt.Println("14.Delete a comment")
+		fmt.Println("15.Return" + config.Reset)
 		choice := utils.GetChoice()
 		switch choice {
 		case 1:
-			users, err := adminService.GetAllUsers()
-			if err != nil {
-				fmt.Println(err)
-			} else {
-				displayUsers(users)
-				utils.Logger.Println("INFO:Admin viewed all users")
-			}
+			page := models.PageRequest{Sort: promptSort(models.RecordSorts)}
+			pageThrough(page, func(page models.PageRequest) (*models.Page[*models.User], error) {
+				return adminService.ListUsers(admin, page)
+			}, displayUsers)
+			utils.Logger.Println("INFO:Admin viewed the users")
 		case 2:
-			questions, err := adminService.GetAllQuestions()
-			if err != nil {
-				fmt.Println(err)
-			} else {
-				displayQuestions(questions)
-				utils.Logger.Println("INFO:Admin viewed all questions")
-			}
+			city := promptCity(cityService, "Enter city", true)
+			page := models.PageRequest{Sort: promptSort(models.RecordSorts)}
+			pageThrough(page, func(page models.PageRequest) (*models.Page[*models.Question], error) {
+				return adminService.ListQuestions(admin, city, page)
+			}, displayQuestions)
+			utils.Logger.Println("INFO:Admin viewed the questions")
 		case 3:
-			posts, err := adminService.GetAllPosts()
-			if err != nil {
-				fmt.Println(err)
-			} else {
-				displayPosts(posts)
-				utils.Logger.Println("INFO:Admin viewed all posts")
-			}
+			city := promptCity(cityService, "Enter city", true)
+			page := models.PageRequest{Sort: promptSort(models.PostSorts)}
+			pageThrough(page, func(page models.PageRequest) (*models.Page[*models.Post], error) {
+				return adminService.ListPosts(admin, city, page)
+			}, displayPosts)
+			utils.Logger.Println("INFO:Admin viewed the posts")
 		case 4:
 			uId, err := utils.PromptIntInput("Enter User Id to delete user:")
-			err = adminService.DeleteUser(uId)
+			err = adminService.DeleteUser(admin, uId)
 			if err != nil {
 				fmt.Println(config.Red + "Error deleting user:" + err.Error() + config.Reset)
 			} else {
@@ -75,7 +80,7 @@ func adminLogin(adminService *services.AdminService) {
 			}
 		case 5:
 			qId, err := utils.PromptIntInput("Enter Question Id to delete question:")
-			err = adminService.DeleteQuestion(qId)
+			err = adminService.DeleteQuestion(admin, qId)
 			if err != nil {
 				fmt.Println(config.Red + "Error deleting question:" + err.Error() + config.Reset)
 			} else {
@@ -84,7 +89,7 @@ func adminLogin(adminService *services.AdminService) {
 			}
 		case 6:
 			pId, err := utils.PromptIntInput("Enter Post Id to delete post:")
-			err = adminService.DeletePost(pId)
+			err = adminService.DeletePost(admin, pId)
 			if err != nil {
 				fmt.Println(config.Red + "Error deleting post:" + err.Error() + config.Reset)
 			} else {
@@ -93,7 +98,7 @@ func adminLogin(adminService *services.AdminService) {
 			}
 		case 7:
 			uId, err := utils.PromptIntInput("Enter User Id to Activate user:")
-			err = adminService.ReActivate(uId)
+			err = adminService.ReActivate(admin, uId)
 			if err != nil {
 				fmt.Println(config.Red + "Error activating user:" + err.Error() + config.Reset)
 			} else {
@@ -101,6 +106,55 @@ func adminLogin(adminService *services.AdminService) {
 				utils.Logger.Println("INFO:Admin activated user with id-", uId)
 			}
 		case 8:
+			answerId, err := utils.PromptIntInput("Enter Answer Id to delete answer:")
+			err = adminService.DeleteAnswer(admin, answerId)
+			if err != nil {
+				fmt.Println(config.Red + "Error deleting answer:" + err.Error() + config.Reset)
+			} else {
+				fmt.Println(config.Green + "Answer deleted" + config.Reset)
+				utils.Logger.Println("INFO:Admin deleted answer with id-", answerId)
+			}
+		case 9:
+			uId, err := utils.PromptIntInput("Enter User Id to grant a role:")
+			role := utils.PromptInput("Enter role (user, moderator or admin):")
+			err = adminService.GrantRole(admin, uId, role)
+			if err != nil {
+				fmt.Println(config.Red + "Error granting role:" + err.Error() + config.Reset)
+			} else {
+				fmt.Println(config.Green + "Role granted" + config.Reset)
+				utils.Logger.Println("INFO:Admin granted role", role, "to user with id-", uId)
+			}
+		case 10:
+			uId, err := utils.PromptIntInput("Enter User Id to revoke the role of:")
+			err = adminService.RevokeRole(admin, uId)
+			if err != nil {
+				fmt.Println(config.Red + "Error revoking role:" + err.Error() + config.Reset)
+			} else {
+				fmt.Println(config.Green + "Role revoked" + config.Reset)
+				utils.Logger.Println("INFO:Admin revoked the role of user with id-", uId)
+			}
+		case 11:
+			viewAuditLog(adminService, admin)
+		case 12:
+			manageCategories(adminService, admin)
+		case 13:
+			city, err := adminService.CreateCity(admin, utils.PromptInput("Enter city name:"))
+			if err != nil {
+				fmt.Println(config.Red + "Error adding city:" + err.Error() + config.Reset)
+			} else {
+				fmt.Println(config.Green + "City added: " + city.Name + config.Reset)
+				utils.Logger.Println("INFO:Admin opened city", city.Name)
+			}
+		case 14:
+			commentId, err := utils.PromptIntInput("Enter Comment Id to delete comment:")
+			err = adminService.DeleteComment(admin, commentId)
+			if err != nil {
+				fmt.Println(config.Red + "Error deleting comment:" + err.Error() + config.Reset)
+			} else {
+				fmt.Println(config.Green + "Comment deleted" + config.Reset)
+				utils.Logger.Println("INFO:Admin deleted comment with id-", commentId)
+			}
+		case 15:
 			return
 		default:
 			fmt.Println(config.Red + "Invalid choice" + config.Reset)
@@ -108,3 +162,108 @@ func adminLogin(adminService *services.AdminService) {
 
 	}
 }
+
+// viewAuditLog asks for the filters one by one; an empty answer skips one.
+func viewAuditLog(adminService *services.AdminService, admin *models.Admin) {
+	var filter models.AuditFilter
+	var err error
+	if actor := utils.PromptInput("Actor user id (empty for all):"); actor != "" {
+		if filter.ActorId, err = strconv.Atoi(actor); err != nil {
+			fmt.Println(config.Red + "Invalid user id" + config.Reset)
+			return
+		}
+	}
+	filter.TargetType = utils.PromptInput("Target type, user, post, question, answer, comment or category (empty for all):")
+	if target := utils.PromptInput("Target id (empty for all):"); target != "" {
+		if filter.TargetId, err = strconv.Atoi(target); err != nil {
+			fmt.Println(config.Red + "Invalid target id" + config.Reset)
+			return
+		}
+	}
+	if from := utils.PromptInput("From, YYYY-MM-DD or YYYY-MM-DD HH:MM (empty for the beginning):"); from != "" {
+		if filter.From, err = utils.ParseTimeBound(from, false); err != nil {
+			fmt.Println(config.Red + err.Error() + config.Reset)
+			return
+		}
+	}
+	if to := utils.PromptInput("To, YYYY-MM-DD or YYYY-MM-DD HH:MM (empty for now):"); to != "" {
+		if filter.To, err = utils.ParseTimeBound(to, true); err != nil {
+			fmt.Println(config.Red + err.Error() + config.Reset)
+			return
+		}
+	}
+	entries, err := adminService.GetAuditLog(admin, filter)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	displayAuditLog(entries)
+	utils.Logger.Println("INFO:Admin", admin.User.Username, "viewed the audit log")
+}
+
+// manageCategories lists every category and then changes one of them; the
+// posts menus pick up the changes straight away.
+func manageCategories(adminService *services.AdminService, admin *models.Admin) {
+	categories, err := adminService.ListCategories(admin)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	displayCategories(categories)
+	fmt.Println(config.Blue + "1.Add a category")
+	fmt.Println("2.Rename a category")
+	fmt.Println("3.Change a description")
+	fmt.Println("4.Activate a category")
+	fmt.Println("5.Deactivate a category")
+	fmt.Println("6.Delete a category")
+	fmt.Println("7.Return" + config.Reset)
+	choice := utils.GetChoice()
+	if choice == 1 {
+		name := utils.PromptInput("Enter category name:")
+		description := utils.PromptInput("Enter category description:")
+		category, err := adminService.CreateCategory(admin, name, description)
+		if err != nil {
+			fmt.Println(config.Red + "Error adding category:" + err.Error() + config.Reset)
+			return
+		}
+		fmt.Println(config.Green + "Category added: " + category.Name + config.Reset)
+		utils.Logger.Println("INFO:Admin added category", category.Name)
+		return
+	}
+	if choice < 2 || choice > 6 {
+		return
+	}
+	id, err := utils.PromptIntInput("Enter category id:")
+	if err != nil {
+		fmt.Println(config.Red + err.Error() + config.Reset)
+		return
+	}
+	if choice == 6 {
+		if err := adminService.DeleteCategory(admin, id); err != nil {
+			fmt.Println(config.Red + "Error deleting category:" + err.Error() + config.Reset)
+			return
+		}
+		fmt.Println(config.Green + "Category deleted" + config.Reset)
+		utils.Logger.Println("INFO:Admin deleted category with id-", id)
+		return
+	}
+	var update models.CategoryUpdate
+	switch choice {
+	case 2:
+		name := utils.PromptInput("Enter new category name:")
+		update.Name = &name
+	case 3:
+		description := utils.PromptInput("Enter new category description:")
+		update.Description = &description
+	default:
+		active := choice == 4
+		update.IsActive = &active
+	}
+	category, err := adminService.UpdateCategory(admin, id, update)
+	if err != nil {
+		fmt.Println(config.Red + "Error updating category:" + err.Error() + config.Reset)
+		return
+	}
+	fmt.Println(config.Green + "Category updated: " + category.Name + config.Reset)
+	utils.Logger.Println("INFO:Admin updated category with id-", id)
+}
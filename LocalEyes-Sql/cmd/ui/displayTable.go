@@ -4,59 +4,55 @@
 package ui
 
 import (
-	"github.com/olekukonko/tablewriter"
+	"fmt"
+	"localEyes/config"
 	"localEyes/internal/models"
+	"localEyes/internal/render"
 	"os"
-	"strconv"
-	"strings"
 )
 
-func displayUsers(users []*models.User) {
-	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"UserId", "UserName", "City", "Resident Till", "ActiveStatus", "Tag"})
-
-	// Add rows to the table, only including Name and City
-	for _, user := range users {
-		uIdStr := strconv.Itoa(user.UId)
-		dwelling := strconv.Itoa(user.DwellingAge)
-		activeStatus := "No"
-		if user.IsActive {
-			activeStatus = "Yes"
-		}
-		table.Append([]string{uIdStr, user.Username, user.City, dwelling, activeStatus, user.Tag})
-	}
+// output is the format the menus print listings in.
+var output = render.Table
 
-	// Render the table
-	table.Render()
+// SetOutput picks the format for every listing printed by the menus.
+func SetOutput(format render.Format) {
+	output = format
 }
 
-func displayPosts(posts []*models.Post) {
-	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"PostId", "Title", "Type", "Content", "Likes", "Created At"})
-
-	// Add rows to the table, only including Name and City
-	for _, post := range posts {
-		pIdStr := strconv.Itoa(post.PostId)
-		likes := strconv.Itoa(post.Likes)
-		time := post.CreatedAt.Format("2006-01-02 15:04:05")
-		table.Append([]string{pIdStr, post.Title, post.Type, post.Content, likes, time})
-	}
+func displayUsers(users []*models.User) {
+	display(render.Users(users))
+}
 
-	// Render the table
-	table.Render()
+func displayPosts(posts []*models.Post) {
+	display(render.Posts(posts))
 }
 
 func displayQuestions(questions []*models.Question) {
-	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"QID", "Question", "Replies", "Created At"})
-
-	// Add rows to the table, only including Name and City
-	for _, question := range questions {
-		qIdStr := strconv.Itoa(question.QId)
-		time := question.CreatedAt.Format("2006-01-02 15:04:05")
-		replies := strings.Join(question.Replies, ", ")
-		table.Append([]string{qIdStr, question.Text, replies, time})
+	display(render.Questions(questions))
+}
+
+func displayComments(threads []*models.Comment, depth int) {
+	display(render.Comments(threads, depth))
+}
+
+func displayNotifications(notifications []*models.Notification) {
+	display(render.Notifications(notifications))
+}
+
+func displaySubscriptions(subscriptions []*models.Subscription) {
+	display(render.Subscriptions(subscriptions))
+}
+
+func displayCategories(categories []*models.Category) {
+	display(render.Categories(categories))
+}
+
+func displayAuditLog(entries []*models.AuditEntry) {
+	display(render.AuditLog(entries))
+}
+
+func display(listing render.Listing) {
+	if err := render.New(output).Render(os.Stdout, listing); err != nil {
+		fmt.Println(config.Red + "Error displaying results: " + err.Error() + config.Reset)
 	}
-	// Render the table
-	table.Render()
 }
@@ -7,11 +7,12 @@ import (
 	"fmt"
 	"github.com/manifoldco/promptui"
 	"localEyes/config"
+	"localEyes/internal/models"
 	"localEyes/internal/services"
 	"localEyes/utils"
 )
 
-func login(userService *services.UserService, questionService *services.QuestionService, postService *services.PostService) {
+func login(userService *services.UserService, questionService *services.QuestionService, postService *services.PostService, sessionService *services.SessionService, categoryService *services.CategoryService, cityService *services.CityService, commentService *services.CommentService, notificationService *services.NotificationService) {
 	fmt.Println(config.Blue + "==============================")
 	fmt.Println("LOGIN")
 	fmt.Println("=============================" + config.Reset)
@@ -27,35 +28,46 @@ func login(userService *services.UserService, questionService *services.Question
 		fmt.Println(err)
 		return
 	}
-	user.NotifyChannel = make(chan string)
-	go func() {
-		for _, s := range user.Notification {
-			user.NotifyChannel <- s
+	// The menus keep their session for as long as the user stays logged in, so
+	// "log out of all devices" ends this one as well.
+	token, _, err := sessionService.Start(user.UId)
+	if err != nil {
+		fmt.Println(config.Red + "Error starting session:" + err.Error() + config.Reset)
+		return
+	}
+	defer func() {
+		if err := sessionService.Logout(token); err != nil {
+			utils.Logger.Println("ERROR: Error ending session:", err)
 		}
 	}()
-	//for _, s := range user.Notification {
-	//	user.NotifyChannel <- s
-	//}
 	fmt.Println(config.Green + "\nUser logged in successfully 😊" + config.Reset)
-	for i := 0; i < len(user.Notification); i++ {
-		select {
-		case msg := <-user.NotifyChannel:
-			fmt.Print(config.Gray + msg + config.Reset)
-		default:
-			break
-		}
-	}
-	err = userService.UnNotifyUsers(user.UId)
+	// What was shown here is marked read but stays in the inbox.
+	unread, err := notificationService.TakeUnread(user.UId)
 	if err != nil {
 		fmt.Println(err)
 	}
+	for _, notification := range unread {
+		fmt.Println(config.Gray + notification.Message + config.Reset)
+	}
+	// Newer ones queue up while the user is busy and stay unread in the
+	// inbox.
+	live, stopLive := notificationService.Listen(user.UId)
+	defer stopLive()
 
 	for {
+		printLive(live)
 		fmt.Println(config.Blue + "\n1.View my Profile")
 		fmt.Println("2.Manage posts")
-		fmt.Println("3.Deactivate account")
-		fmt.Println("4.Return" + config.Reset)
+		fmt.Println("3.Change city")
+		fmt.Println("4.Deactivate account")
+		fmt.Println("5.Log out of all devices")
+		fmt.Println("6.Notifications")
+		fmt.Println("7.Return" + config.Reset)
 		choice := utils.GetChoice()
+		if _, err := sessionService.Authenticate(token); err != nil {
+			fmt.Println(err)
+			return
+		}
 		switch choice {
 		case 1:
 			fmt.Println(config.Magenta + "\n~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~")
@@ -65,8 +77,17 @@ func login(userService *services.UserService, questionService *services.Question
 			fmt.Println("Type of user:", user.Tag)
 			fmt.Printf("Living in City for:%v years\n", user.DwellingAge)
 		case 2:
-			managePost(postService, questionService, userService, user.UId)
+			managePost(postService, questionService, categoryService, commentService, user.UId, user.City)
 		case 3:
+			city := promptCity(cityService, "Enter your new city", false)
+			if err := userService.ChangeCity(user.UId, city); err != nil {
+				fmt.Println(config.Red + "Error changing city:" + err.Error() + config.Reset)
+			} else {
+				user.City = city
+				fmt.Println(config.Green + "You now see posts from " + city + config.Reset)
+				utils.Logger.Println("INFO: User id-", user.UId, "moved to", city)
+			}
+		case 4:
 			err := userService.DeActivate(user.UId)
 			if err != nil {
 				fmt.Println(config.Red + "Error Deactivating user:" + err.Error() + config.Reset)
@@ -74,12 +95,41 @@ func login(userService *services.UserService, questionService *services.Question
 			} else {
 				fmt.Println(config.Green + "User Deactivated successfully" + config.Reset)
 				utils.Logger.Println("INFO: User Deactivated with id-", user.UId)
+				if err := sessionService.LogoutAll(user.UId); err != nil {
+					utils.Logger.Println("ERROR: Error ending sessions of user id-", user.UId, err)
+				}
 				return
 			}
-		case 4:
+		case 5:
+			err := sessionService.LogoutAll(user.UId)
+			if err != nil {
+				fmt.Println(config.Red + "Error logging out:" + err.Error() + config.Reset)
+			} else {
+				fmt.Println(config.Green + "Logged out of all devices" + config.Reset)
+				return
+			}
+		case 6:
+			manageNotifications(notificationService, user.UId)
+		case 7:
 			return
 		default:
 			fmt.Println(config.Red + "Invalid Choice,Try Again" + config.Reset)
 		}
 	}
 }
+
+// printLive shows the notifications that arrived since the menu was last
+// shown, between prompts rather than in the middle of one.
+func printLive(live <-chan *models.Notification) {
+	for {
+		select {
+		case notification, ok := <-live:
+			if !ok {
+				return
+			}
+			fmt.Println(config.Gray + "🔔 " + notification.Message + config.Reset)
+		default:
+			return
+		}
+	}
+}
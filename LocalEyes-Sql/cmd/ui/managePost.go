@@ -3,21 +3,27 @@ package ui
 import (
 	"fmt"
 	"localEyes/config"
+	"localEyes/internal/models"
 	"localEyes/internal/services"
 	"localEyes/utils"
+	"strconv"
+	"strings"
 )
 
-func managePost(postService *services.PostService, questionService *services.QuestionService, userService *services.UserService, uId int) {
+func managePost(postService *services.PostService, questionService *services.QuestionService, categoryService *services.CategoryService, commentService *services.CommentService, uId int, city string) {
 	fmt.Println(config.Blue + "1.Create post")
 	fmt.Println("2.Update Post")
 	fmt.Println("3.View Posts")
 	fmt.Println("4.Open Post")
 	fmt.Println("5.Like Post")
-	fmt.Println("6.Delete Post" + config.Reset)
+	fmt.Println("6.Delete Post")
+	fmt.Println("7.Unlike Post")
+	fmt.Println("8.Search")
+	fmt.Println("9.Posts near me" + config.Reset)
 	choice := utils.GetChoice()
 	switch choice {
 	case 1:
-		postCreate(postService, userService, uId)
+		postCreate(postService, categoryService, uId)
 	case 2:
 		myPosts, err := postService.GiveMyPosts(uId)
 		if err != nil {
@@ -40,32 +46,12 @@ func managePost(postService *services.PostService, questionService *services.Que
 		}
 
 	case 3:
-		var filterType string
-		for {
-			filterType = utils.PromptInput("Enter filter [food/travel/shopping/other/blank for no filter]:")
-			if utils.ValidateFilter(filterType) {
-				break
-			} else {
-				fmt.Println("Invalid filter type:", filterType)
-			}
-		}
-		if filterType == "" {
-			posts, err := postService.GiveAllPosts()
-			if err != nil {
-				utils.Logger.Println("ERROR: Error loading posts: " + err.Error())
-				fmt.Println(config.Red + "Error loading posts:" + err.Error() + config.Reset)
-			} else {
-				displayPosts(posts)
-			}
-		} else {
-			posts, err := postService.GiveFilteredPosts(filterType)
-			if err != nil {
-				utils.Logger.Println("ERROR: Error loading posts: " + err.Error())
-				fmt.Println(config.Red + "Error loading posts:" + err.Error() + config.Reset)
-			} else {
-				displayPosts(posts)
-			}
-		}
+		filter := models.PostFilter{Type: promptCategory(categoryService), City: city}
+		filter.Neighbourhood = utils.PromptInput("Enter neighbourhood [blank for all]:")
+		page := models.PageRequest{Sort: promptSort(models.PostSorts)}
+		pageThrough(page, func(page models.PageRequest) (*models.Page[*models.Post], error) {
+			return postService.ListPosts(uId, filter, page)
+		}, displayPosts)
 
 	case 4:
 		pId, err := utils.PromptIntInput("Enter post id to open:")
@@ -73,11 +59,11 @@ func managePost(postService *services.PostService, questionService *services.Que
 			fmt.Println(config.Red + err.Error() + config.Reset)
 			break
 		}
-		openPost(questionService, postService, pId, uId)
+		openPost(questionService, postService, commentService, pId, uId)
 
 	case 5:
 		pId, err := utils.PromptIntInput("Enter post id to like:")
-		err = postService.Like(pId)
+		err = postService.Like(uId, pId)
 		if err != nil {
 			fmt.Println(config.Red + "Error liking post:" + err.Error() + config.Reset)
 		} else {
@@ -100,87 +86,130 @@ func managePost(postService *services.PostService, questionService *services.Que
 		if err != nil {
 			fmt.Println(config.Red + "Error deleting post:" + err.Error() + config.Reset)
 		} else {
-			err = questionService.DeleteQuesByPId(pId)
-			if err != nil {
-				fmt.Println(config.Red + "Error deleting question:" + err.Error() + config.Reset)
-			} else {
-				fmt.Println(config.Green + "Post deleted successfully" + config.Reset)
-			}
+			fmt.Println(config.Green + "Post deleted successfully" + config.Reset)
 		}
 
-	}
-}
-
-func postCreate(postService *services.PostService, userService *services.UserService, uId int) {
-	fmt.Println(config.Blue + "1.Create Food post")
-	fmt.Println("2.Create Travel post")
-	fmt.Println("3.Create Shopping post")
-	fmt.Println("4.Create Other post" + config.Reset)
-	choice := utils.GetChoice()
-	switch choice {
-	case 1:
-		title := utils.PromptInput("Enter post title:")
-		content := utils.PromptInput("Enter post content:")
-		err := postService.CreatePost(uId, title, content, "food")
+	case 7:
+		pId, err := utils.PromptIntInput("Enter post id to unlike:")
+		err = postService.Unlike(uId, pId)
 		if err != nil {
-			utils.Logger.Println("ERROR: Error creating post: " + err.Error())
-			fmt.Println(err)
+			fmt.Println(config.Red + "Error unliking post:" + err.Error() + config.Reset)
 		} else {
-			fmt.Println(config.Green+"Post created:", title)
-			utils.Logger.Println("INFO: Post created:", title)
-			err := userService.NotifyUsers(uId, title)
-			if err != nil {
-				utils.Logger.Println("ERROR: Error Notifying user: " + err.Error())
-				fmt.Println(err)
-			}
+			fmt.Println(config.Green + "Post Unliked" + config.Reset)
 		}
-	case 2:
-		title := utils.PromptInput("Enter post title:")
-		content := utils.PromptInput("Enter post content:")
-		err := postService.CreatePost(uId, title, content, "travel")
-		if err != nil {
-			utils.Logger.Println("ERROR: Error creating post: " + err.Error())
-			fmt.Println(err)
-		} else {
-			fmt.Println(config.Green+"Post created:", title)
-			utils.Logger.Println("INFO: Post created:", title)
-			err := userService.NotifyUsers(uId, title)
-			if err != nil {
-				utils.Logger.Println("ERROR: Error UnNotifying user: " + err.Error())
-				fmt.Println(err)
-			}
+
+	case 8:
+		search := strings.TrimSpace(utils.PromptInput("Enter words to search for:"))
+		if search == "" {
+			fmt.Println(config.Red + "Enter something to search for" + config.Reset)
+			break
 		}
-	case 3:
-		title := utils.PromptInput("Enter post title:")
-		content := utils.PromptInput("Enter post content:")
-		err := postService.CreatePost(uId, title, content, "shopping")
-		if err != nil {
-			utils.Logger.Println("ERROR: Error creating post: " + err.Error())
-			fmt.Println(err)
-		} else {
-			fmt.Println(config.Green+"Post created:", title)
-			utils.Logger.Println("INFO: Post created:", title)
-			err := userService.NotifyUsers(uId, title)
-			if err != nil {
-				utils.Logger.Println("ERROR: Error UnNotifying user: " + err.Error())
-				fmt.Println(err)
-			}
+		fmt.Println(config.Blue + "Posts:" + config.Reset)
+		pageThrough(models.PageRequest{}, func(page models.PageRequest) (*models.Page[*models.Post], error) {
+			return postService.SearchPosts(uId, city, search, page)
+		}, displayPosts)
+		fmt.Println(config.Blue + "Questions:" + config.Reset)
+		pageThrough(models.PageRequest{}, func(page models.PageRequest) (*models.Page[*models.Question], error) {
+			return questionService.SearchQuestions(search, page)
+		}, displayQuestions)
+
+	case 9:
+		latitude, longitude, ok := promptCoordinates("your")
+		if !ok || latitude == nil {
+			fmt.Println(config.Red + "Enter your latitude and longitude to find posts near you" + config.Reset)
+			break
 		}
-	case 4:
-		title := utils.PromptInput("Enter post title:")
-		content := utils.PromptInput("Enter post content:")
-		err := postService.CreatePost(uId, title, content, "other")
-		if err != nil {
-			fmt.Println(err)
-		} else {
-			fmt.Println(config.Green+"Post created:", title)
-			err := userService.NotifyUsers(uId, title)
+		radius := models.DefaultNearRadiusKm
+		if input := strings.TrimSpace(utils.PromptInput(fmt.Sprintf("Enter radius in km [blank for %g]:", radius))); input != "" {
+			value, err := strconv.ParseFloat(input, 64)
 			if err != nil {
-				utils.Logger.Println("ERROR: Error UnNotifying user: " + err.Error())
-				fmt.Println(err)
+				fmt.Println(config.Red + "Enter the radius as a number of km" + config.Reset)
+				break
 			}
+			radius = value
 		}
-	default:
+		pageThrough(models.PageRequest{}, func(page models.PageRequest) (*models.Page[*models.Post], error) {
+			return postService.NearPosts(uId, *latitude, *longitude, radius, page)
+		}, displayPosts)
+	}
+}
+
+// postCreate offers the active categories by number, so a new category
+// shows up here as soon as an admin adds it.
+func postCreate(postService *services.PostService, categoryService *services.CategoryService, uId int) {
+	categories, err := categoryService.GiveCategories(true)
+	if err != nil {
+		utils.Logger.Println("ERROR: Error loading categories: " + err.Error())
+		fmt.Println(config.Red + "Error loading categories:" + err.Error() + config.Reset)
+		return
+	}
+	for i, category := range categories {
+		line := fmt.Sprintf("%d.Create %s post", i+1, category.Name)
+		if i == 0 {
+			line = config.Blue + line
+		}
+		if i == len(categories)-1 {
+			line += config.Reset
+		}
+		fmt.Println(line)
+	}
+	choice := utils.GetChoice()
+	if choice < 1 || choice > len(categories) {
 		fmt.Println(config.Red + "invalid choice" + config.Reset)
+		return
+	}
+	title := utils.PromptInput("Enter post title:")
+	content := utils.PromptInput("Enter post content:")
+	location := models.Location{Neighbourhood: utils.PromptInput("Enter neighbourhood [blank for none]:")}
+	var ok bool
+	if location.Latitude, location.Longitude, ok = promptCoordinates("the post's"); !ok {
+		fmt.Println(config.Red + "Enter the latitude and longitude as numbers of degrees" + config.Reset)
+		return
+	}
+	_, err = postService.CreatePost(uId, title, content, categories[choice-1].Name, location)
+	if err != nil {
+		utils.Logger.Println("ERROR: Error creating post: " + err.Error())
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(config.Green+"Post created:", title)
+	utils.Logger.Println("INFO: Post created:", title)
+}
+
+// promptCategory asks for a category to filter by until it gets a known one
+// or a blank for no filter.
+func promptCategory(categoryService *services.CategoryService) string {
+	var names []string
+	if categories, err := categoryService.GiveCategories(false); err == nil {
+		for _, category := range categories {
+			names = append(names, category.Name)
+		}
+	}
+	for {
+		filterType := utils.PromptInput("Enter filter [" + strings.Join(append(names, "blank for no filter"), "/") + "]:")
+		err := categoryService.ValidateCategory(filterType)
+		if err == nil {
+			return filterType
+		}
+		fmt.Println(err)
+	}
+}
+
+// promptCoordinates asks for a latitude and longitude. Both come back nil
+// when the latitude is left blank, and ok is false when either is not a
+// number.
+func promptCoordinates(whose string) (latitude, longitude *float64, ok bool) {
+	input := strings.TrimSpace(utils.PromptInput("Enter " + whose + " latitude [blank for none]:"))
+	if input == "" {
+		return nil, nil, true
+	}
+	lat, err := strconv.ParseFloat(input, 64)
+	if err != nil {
+		return nil, nil, false
+	}
+	lng, err := strconv.ParseFloat(strings.TrimSpace(utils.PromptInput("Enter "+whose+" longitude:")), 64)
+	if err != nil {
+		return nil, nil, false
 	}
+	return &lat, &lng, true
 }
@@ -0,0 +1,116 @@
+//go:build !test
+// +build !test
+
+package ui
+
+import (
+	"fmt"
+	"localEyes/config"
+	"localEyes/internal/models"
+	"localEyes/internal/services"
+	"localEyes/utils"
+	"strings"
+)
+
+// manageNotifications shows the inbox, read and unread, and lets the user
+// mark notifications read or change how they are notified.
+func manageNotifications(notificationService *services.NotificationService, uId int) {
+	count, err := notificationService.UnreadCount(uId)
+	if err != nil {
+		fmt.Println(config.Red + "Error loading notifications:" + err.Error() + config.Reset)
+		return
+	}
+	fmt.Printf(config.Blue+"You have %d unread notifications\n", count)
+	fmt.Println("1.View unread")
+	fmt.Println("2.View all")
+	fmt.Println("3.Mark one read")
+	fmt.Println("4.Mark all read")
+	fmt.Println("5.Notification settings")
+	fmt.Println("6.Return" + config.Reset)
+	choice := utils.GetChoice()
+	switch choice {
+	case 1, 2:
+		page := models.PageRequest{Sort: promptSort(models.RecordSorts)}
+		pageThrough(page, func(page models.PageRequest) (*models.Page[*models.Notification], error) {
+			return notificationService.Inbox(uId, choice == 1, page)
+		}, displayNotifications)
+	case 3:
+		Id, err := utils.PromptIntInput("Enter notification id:")
+		if err != nil {
+			fmt.Println(config.Red + err.Error() + config.Reset)
+			return
+		}
+		if err := notificationService.MarkRead(uId, Id); err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Println(config.Green + "Notification marked read" + config.Reset)
+	case 4:
+		if err := notificationService.MarkAllRead(uId); err != nil {
+			fmt.Println(config.Red + "Error marking notifications read:" + err.Error() + config.Reset)
+			return
+		}
+		fmt.Println(config.Green + "All notifications marked read" + config.Reset)
+	case 5:
+		manageNotificationSettings(notificationService, uId)
+	case 6:
+		return
+	default:
+		fmt.Println(config.Red + "Invalid Choice,Try Again" + config.Reset)
+	}
+}
+
+// manageNotificationSettings shows the delivery and posts settings with the
+// subscriptions and changes one of them.
+func manageNotificationSettings(notificationService *services.NotificationService, uId int) {
+	prefs, err := notificationService.Preferences(uId)
+	if err != nil {
+		fmt.Println(config.Red + "Error loading notification settings:" + err.Error() + config.Reset)
+		return
+	}
+	fmt.Println(config.Blue + "Delivery: " + prefs.Delivery + ", new posts: " + prefs.Posts + config.Reset)
+	displaySubscriptions(prefs.Subscriptions)
+	fmt.Println(config.Blue + "1.Change delivery")
+	fmt.Println("2.Change which new posts to hear about")
+	fmt.Println("3.Subscribe to a category")
+	fmt.Println("4.Subscribe to an author")
+	fmt.Println("5.Unsubscribe from a category")
+	fmt.Println("6.Unsubscribe from an author")
+	fmt.Println("7.Return" + config.Reset)
+	choice := utils.GetChoice()
+	switch choice {
+	case 1, 2:
+		delivery, posts := "", ""
+		if choice == 1 {
+			delivery = strings.TrimSpace(utils.PromptInput("Enter delivery [" + strings.Join(models.Deliveries, "/") + "]:"))
+		} else {
+			posts = strings.TrimSpace(utils.PromptInput("Enter new posts [" + strings.Join(models.PostScopes, "/") + "]:"))
+		}
+		prefs, err := notificationService.UpdatePreferences(uId, delivery, posts)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Println(config.Green + "Delivery: " + prefs.Delivery + ", new posts: " + prefs.Posts + config.Reset)
+	case 3, 4, 5, 6:
+		kind, label := models.TargetCategory, "Enter category name:"
+		if choice%2 == 0 {
+			kind, label = models.TargetUser, "Enter author's username:"
+		}
+		name := strings.TrimSpace(utils.PromptInput(label))
+		if choice <= 4 {
+			err = notificationService.Subscribe(uId, kind, name)
+		} else {
+			err = notificationService.Unsubscribe(uId, kind, name)
+		}
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Println(config.Green + "Subscriptions updated" + config.Reset)
+	case 7:
+		return
+	default:
+		fmt.Println(config.Red + "Invalid Choice,Try Again" + config.Reset)
+	}
+}
@@ -6,11 +6,14 @@ package ui
 import (
 	"fmt"
 	"localEyes/config"
+	"localEyes/internal/models"
 	"localEyes/internal/services"
 	"localEyes/utils"
+	"strconv"
+	"strings"
 )
 
-func openPost(questionService *services.QuestionService, postService *services.PostService, PId, UId int) {
+func openPost(questionService *services.QuestionService, postService *services.PostService, commentService *services.CommentService, PId, UId int) {
 	boolVal, err := postService.PostIdExist(PId)
 	if err != nil {
 		fmt.Println(config.Red + err.Error() + config.Reset)
@@ -25,7 +28,14 @@ func openPost(questionService *services.QuestionService, postService *services.P
 		fmt.Println("2.Answer a Question")
 		fmt.Println("3.View Questions")
 		fmt.Println("4.Delete Question")
-		fmt.Println("5 Return" + config.Reset)
+		fmt.Println("5.Edit my Answer")
+		fmt.Println("6.Delete my Answer")
+		fmt.Println("7.Add Comment")
+		fmt.Println("8.Reply to a Comment")
+		fmt.Println("9.View Comments")
+		fmt.Println("10.Edit my Comment")
+		fmt.Println("11.Delete my Comment")
+		fmt.Println("12 Return" + config.Reset)
 		choice := utils.GetChoice()
 		switch choice {
 		case 1:
@@ -39,7 +49,7 @@ func openPost(questionService *services.QuestionService, postService *services.P
 		case 2:
 			QId, err := utils.PromptIntInput("Enter QId:")
 			answer := utils.PromptInput("Enter your answer:")
-			err = questionService.AddAnswer(QId, answer)
+			err = questionService.AddAnswer(QId, UId, answer)
 			if err != nil {
 				fmt.Println(config.Red + "Error Adding answer:" + err.Error() + config.Reset)
 			} else {
@@ -67,9 +77,96 @@ func openPost(questionService *services.QuestionService, postService *services.P
 				fmt.Println(config.Green + "Question deleted" + config.Reset)
 			}
 		case 5:
+			answerId, err := utils.PromptIntInput("Enter Answer Id to edit:")
+			text := utils.PromptInput("Enter your answer:")
+			err = questionService.EditAnswer(UId, answerId, text)
+			if err != nil {
+				fmt.Println(config.Red + "Error updating answer:" + err.Error() + config.Reset)
+			} else {
+				fmt.Println(config.Green + "Answer updated" + config.Reset)
+			}
+		case 6:
+			answerId, err := utils.PromptIntInput("Enter Answer Id to delete:")
+			err = questionService.DeleteMyAnswer(UId, answerId)
+			if err != nil {
+				fmt.Println(config.Red + "Error deleting answer:" + err.Error() + config.Reset)
+			} else {
+				fmt.Println(config.Green + "Answer deleted" + config.Reset)
+			}
+		case 7:
+			err := commentService.AddComment(UId, PId, utils.PromptInput("Enter your comment:"))
+			if err != nil {
+				fmt.Println(config.Red + "Error adding comment:" + err.Error() + config.Reset)
+			} else {
+				fmt.Println(config.Green + "Comment added" + config.Reset)
+			}
+		case 8:
+			commentId, err := utils.PromptIntInput("Enter Comment Id to reply to:")
+			if err != nil {
+				fmt.Println(config.Red + err.Error() + config.Reset)
+				break
+			}
+			err = commentService.Reply(UId, commentId, utils.PromptInput("Enter your reply:"))
+			if err != nil {
+				fmt.Println(config.Red + "Error adding reply:" + err.Error() + config.Reset)
+			} else {
+				fmt.Println(config.Green + "Reply added" + config.Reset)
+			}
+		case 9:
+			viewComments(commentService, PId)
+		case 10:
+			commentId, err := utils.PromptIntInput("Enter Comment Id to edit:")
+			text := utils.PromptInput("Enter your comment:")
+			err = commentService.EditComment(UId, commentId, text)
+			if err != nil {
+				fmt.Println(config.Red + "Error updating comment:" + err.Error() + config.Reset)
+			} else {
+				fmt.Println(config.Green + "Comment updated" + config.Reset)
+			}
+		case 11:
+			commentId, err := utils.PromptIntInput("Enter Comment Id to delete:")
+			err = commentService.DeleteMyComment(UId, commentId)
+			if err != nil {
+				fmt.Println(config.Red + "Error deleting comment:" + err.Error() + config.Reset)
+			} else {
+				fmt.Println(config.Green + "Comment deleted" + config.Reset)
+			}
+		case 12:
 			return
 		default:
 			fmt.Println(config.Red + "Invalid Choice" + config.Reset)
 		}
 	}
 }
+
+// viewComments shows the post's threads a few levels deep and then lets the
+// user open any comment to read the replies folded away below it.
+func viewComments(commentService *services.CommentService, PId int) {
+	threads, err := commentService.GetPostComments(PId)
+	if err != nil {
+		fmt.Println(config.Red + err.Error() + config.Reset)
+		return
+	}
+	if len(threads) == 0 {
+		fmt.Println(config.Yellow + "No comments yet" + config.Reset)
+		return
+	}
+	displayComments(threads, models.DefaultCommentDepth)
+	for {
+		input := strings.TrimSpace(utils.PromptInput("Enter a Comment Id to open its thread [blank to go back]:"))
+		if input == "" {
+			return
+		}
+		commentId, err := strconv.Atoi(input)
+		if err != nil {
+			fmt.Println(config.Red + "Enter a comment id" + config.Reset)
+			continue
+		}
+		thread, err := commentService.GetThread(commentId)
+		if err != nil {
+			fmt.Println(config.Red + err.Error() + config.Reset)
+			continue
+		}
+		displayComments([]*models.Comment{thread}, models.DefaultCommentDepth)
+	}
+}
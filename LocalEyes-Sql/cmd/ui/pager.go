@@ -0,0 +1,68 @@
+//go:build !test
+// +build !test
+
+package ui
+
+import (
+	"fmt"
+	"localEyes/config"
+	"localEyes/internal/models"
+	"localEyes/utils"
+	"strings"
+)
+
+// promptSort asks for one of the listing's sort orders, the first being the
+// default.
+func promptSort(orders []string) string {
+	for {
+		order := strings.TrimSpace(utils.PromptInput("Sort by [" + strings.Join(orders, "/") + ", blank for " + orders[0] + "]:"))
+		if order == "" {
+			return orders[0]
+		}
+		for _, known := range orders {
+			if order == known {
+				return order
+			}
+		}
+		fmt.Println(config.Red+"Invalid sort order:", order+config.Reset)
+	}
+}
+
+// pageThrough shows a listing one page at a time, moving to the next or
+// previous page as asked until the user goes back to the menu.
+func pageThrough[T any](page models.PageRequest, load func(models.PageRequest) (*models.Page[T], error), show func([]T)) {
+	for {
+		result, err := load(page)
+		if err != nil {
+			utils.Logger.Println("ERROR: Error loading page: " + err.Error())
+			fmt.Println(config.Red + "Error loading page:" + err.Error() + config.Reset)
+			return
+		}
+		show(result.Items)
+		var moves []string
+		if result.HasNext {
+			moves = append(moves, "n for next")
+		}
+		if result.HasPrev() {
+			moves = append(moves, "p for previous")
+		}
+		if len(moves) == 0 {
+			return
+		}
+		fmt.Println(config.Blue+"Page", result.Request.Number, config.Reset)
+		switch strings.ToLower(strings.TrimSpace(utils.PromptInput("Enter " + strings.Join(moves, ", ") + ", anything else to go back:"))) {
+		case "n":
+			if !result.HasNext {
+				return
+			}
+			page = result.Request.Next()
+		case "p":
+			if !result.HasPrev() {
+				return
+			}
+			page = result.Request.Prev()
+		default:
+			return
+		}
+	}
+}
@@ -10,7 +10,7 @@ import (
 	"localEyes/utils"
 )
 
-func RootCli(userService *services.UserService, postService *services.PostService, questionService *services.QuestionService, adminService *services.AdminService) {
+func RootCli(userService *services.UserService, postService *services.PostService, questionService *services.QuestionService, adminService *services.AdminService, sessionService *services.SessionService, categoryService *services.CategoryService, cityService *services.CityService, commentService *services.CommentService, notificationService *services.NotificationService) {
 	for {
 		fmt.Println(config.Magenta + "\n=====================================================")
 		fmt.Println("Welcome to Local Eyes!")
@@ -23,11 +23,11 @@ func RootCli(userService *services.UserService, postService *services.PostServic
 		choice := utils.GetChoice()
 		switch choice {
 		case 1:
-			signUp(userService)
+			signUp(userService, cityService)
 		case 2:
-			login(userService, questionService, postService)
+			login(userService, questionService, postService, sessionService, categoryService, cityService, commentService, notificationService)
 		case 3:
-			adminLogin(adminService)
+			adminLogin(adminService, cityService)
 		case 4:
 			return
 		default:
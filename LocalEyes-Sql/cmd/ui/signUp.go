@@ -4,18 +4,16 @@
 package ui
 
 import (
-	"errors"
 	"fmt"
 	"github.com/manifoldco/promptui"
 	"localEyes/config"
 	"localEyes/internal/services"
 	"localEyes/utils"
-	"log"
 	"strconv"
 	"strings"
 )
 
-func signUp(userService *services.UserService) {
+func signUp(userService *services.UserService, cityService *services.CityService) {
 	fmt.Println(config.Blue + "==============================")
 	fmt.Println("SIGN UP")
 	fmt.Println("==============================" + config.Reset)
@@ -41,17 +39,14 @@ func signUp(userService *services.UserService) {
 			fmt.Println(config.Red + "Password is weak" + config.Reset)
 		}
 	}
-	if city := utils.PromptInput("Enter your city:"); strings.ToLower(city) != "delhi" {
-		err := errors.New(config.Red + "You are not a vaid user for this application" + config.Reset)
-		log.Fatal(err)
-	}
+	city := promptCity(cityService, "Enter your city", false)
 	DwellingAge, _ := strconv.Atoi(utils.PromptInput("For how many years you are living here/lived here:"))
 	if DwellingAge > 2 {
 		tag = "resident"
 	} else {
 		tag = "newbie"
 	}
-	err := userService.Signup(username, password, DwellingAge, tag)
+	err := userService.Signup(username, password, city, DwellingAge, tag)
 	if err != nil {
 		fmt.Println(config.Red + "Error Signing Up\n" + err.Error() + config.Reset)
 		return
@@ -59,3 +54,29 @@ func signUp(userService *services.UserService) {
 		fmt.Println("\n" + config.Green + "Successfully Signed Up!\n" + config.Reset)
 	}
 }
+
+// promptCity asks for one of the cities LocalEyes is open in until it gets
+// one, or a blank when blank is allowed.
+func promptCity(cityService *services.CityService, label string, blank bool) string {
+	var names []string
+	if cities, err := cityService.GiveCities(); err == nil {
+		for _, city := range cities {
+			names = append(names, city.Name)
+		}
+	}
+	if blank {
+		names = append(names, "blank for all cities")
+	}
+	for {
+		city := services.CityName(utils.PromptInput(label + " [" + strings.Join(names, "/") + "]:"))
+		if city == "" && !blank {
+			fmt.Println(config.Red + "Enter a city" + config.Reset)
+			continue
+		}
+		err := cityService.ValidateCity(city)
+		if err == nil {
+			return city
+		}
+		fmt.Println(err)
+	}
+}
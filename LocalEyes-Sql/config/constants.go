@@ -12,4 +12,19 @@ const (
 	UserTable="users"
 	PostTable="posts"
 	QuestionTable="questions"
+	AnswerTable="answers"
+	PostLikeTable="post_likes"
+	SessionTable="sessions"
+	AuditTable="audit_log"
+	CategoryTable="categories"
+	CityTable="cities"
+	CommentTable="comments"
+	NotificationTable="notifications"
+	NotificationPrefsTable="notification_prefs"
+	SubscriptionTable="notification_subscriptions"
+	OutboxTable="notification_outbox"
+	SchemaVersionTable="schema_version"
+	MySQLDriver="mysql"
+	SQLiteDriver="sqlite"
+	MemoryDriver="memory"
 )
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strings"
 	"sync"
 	"time"
 )
@@ -12,16 +13,40 @@ import (
 var dbClient *sql.DB
 var once sync.Once
 
+// GetDBDriver returns the backend chosen through the DBDriver setting,
+// defaulting to MySQL when it is not set.
+func GetDBDriver() string {
+	driver := strings.ToLower(os.Getenv("DBDriver"))
+	if driver == "" {
+		return MySQLDriver
+	}
+	return driver
+}
+
 func GetSQLClient() *sql.DB {
 	once.Do(func() {
-		dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?parseTime=true",
-			os.Getenv("DBUser"),
-			os.Getenv("DBPassword"),
-			os.Getenv("DBHost"),
-			os.Getenv("DBPort"),
-			os.Getenv("DBName"),
-		)
-		db, err := sql.Open("mysql", dsn)
+		var db *sql.DB
+		var err error
+		switch GetDBDriver() {
+		case SQLiteDriver:
+			dsn := fmt.Sprintf("file:%s?_pragma=busy_timeout(5000)&_pragma=foreign_keys(1)", os.Getenv("DBPath"))
+			db, err = sql.Open("sqlite", dsn)
+			if err == nil {
+				// SQLite allows a single writer; serialising connections avoids SQLITE_BUSY.
+				db.SetMaxOpenConns(1)
+			}
+		case MySQLDriver:
+			dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?parseTime=true",
+				os.Getenv("DBUser"),
+				os.Getenv("DBPassword"),
+				os.Getenv("DBHost"),
+				os.Getenv("DBPort"),
+				os.Getenv("DBName"),
+			)
+			db, err = sql.Open("mysql", dsn)
+		default:
+			err = fmt.Errorf("unsupported DBDriver %q", GetDBDriver())
+		}
 		if err != nil {
 			log.Fatal(err)
 		}
@@ -37,6 +62,9 @@ func GetSQLClient() *sql.DB {
 }
 
 func CloseDBClient() {
+	if dbClient == nil {
+		return
+	}
 	err := dbClient.Close()
 	if err != nil {
 		fmt.Println("Error closing DB client")
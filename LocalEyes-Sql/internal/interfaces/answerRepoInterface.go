@@ -0,0 +1,19 @@
+package interfaces
+
+import (
+	"localEyes/internal/models"
+)
+
+type AnswerRepository interface {
+	Create(answer *models.Answer) error
+	GetAnswersByQId(QId int) ([]*models.Answer, error)
+	GetAnswersByPId(PId int) ([]*models.Answer, error)
+	GetAllAnswers() ([]*models.Answer, error)
+	FindByAnswerId(AnswerId int) (*models.Answer, error)
+	UpdateUserAnswer(AnswerId, UId int, text string) error
+	DeleteByAnswerIdUId(AnswerId, UId int) error
+	DeleteByAnswerId(AnswerId int) error
+	DeleteByQId(QId int) error
+	DeleteByPId(PId int) error
+	DeleteForUser(UId int) error
+}
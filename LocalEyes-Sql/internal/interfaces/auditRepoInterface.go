@@ -0,0 +1,11 @@
+package interfaces
+
+import (
+	"localEyes/internal/models"
+)
+
+// AuditRepository is append-only: entries are never updated or removed.
+type AuditRepository interface {
+	Create(entry *models.AuditEntry) error
+	Find(filter models.AuditFilter) ([]*models.AuditEntry, error)
+}
@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"localEyes/internal/models"
+)
+
+type CategoryRepository interface {
+	Create(category *models.Category) error
+	FindById(id int) (*models.Category, error)
+	FindByName(name string) (*models.Category, error)
+	GetAll() ([]*models.Category, error)
+	Update(category *models.Category) error
+	DeleteById(id int) error
+}
@@ -0,0 +1,11 @@
+package interfaces
+
+import (
+	"localEyes/internal/models"
+)
+
+type CityRepository interface {
+	Create(city *models.City) error
+	FindByName(name string) (*models.City, error)
+	GetAll() ([]*models.City, error)
+}
@@ -0,0 +1,17 @@
+package interfaces
+
+import (
+	"localEyes/internal/models"
+)
+
+type CommentRepository interface {
+	Create(comment *models.Comment) error
+	FindByCommentId(CommentId int) (*models.Comment, error)
+	GetCommentsByPId(PId int) ([]*models.Comment, error)
+	CountReplies(CommentId int) (int, error)
+	UpdateUserComment(CommentId, UId int, text string) error
+	MarkDeleted(CommentId int) error
+	DeleteByCommentId(CommentId int) error
+	DeleteByPId(PId int) error
+	DeleteForUser(UId int) error
+}
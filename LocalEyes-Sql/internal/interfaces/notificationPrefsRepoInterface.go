@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"localEyes/internal/models"
+)
+
+type NotificationPrefsRepository interface {
+	FindByUId(UId int) (*models.NotificationPrefs, error)
+	Save(prefs *models.NotificationPrefs) error
+	Subscribe(subscription *models.Subscription) error
+	Unsubscribe(UId int, kind string, targetId int) error
+	DeleteForUser(UId int) error
+	DeleteSubscriptionsTo(kind string, targetId int) error
+}
@@ -0,0 +1,20 @@
+package interfaces
+
+import (
+	"localEyes/internal/models"
+)
+
+type NotificationRepository interface {
+	Create(notification *models.Notification) error
+	CreateForPost(notification *models.Notification, post *models.Post) error
+	FindByNotificationId(Id int) (*models.Notification, error)
+	GetUserNotifications(UId int, unreadOnly bool, order string, limit, offset int) ([]*models.Notification, error)
+	CountUnread(UId int) (int, error)
+	MarkRead(UId int, Ids []int) error
+	MarkAllRead(UId int) error
+	DeleteForUser(UId int) error
+	GetHeld(UId int) ([]*models.Notification, error)
+	Release(UId int) error
+	GetSince(afterId, limit int) ([]*models.Notification, error)
+	LastId() (int, error)
+}
@@ -0,0 +1,18 @@
+package interfaces
+
+import (
+	"localEyes/internal/models"
+	"time"
+)
+
+type OutboxRepository interface {
+	Create(entry *models.OutboxEntry) error
+	FindById(Id int) (*models.OutboxEntry, error)
+	GetDue(now time.Time, limit int) ([]*models.OutboxEntry, error)
+	Claim(entry *models.OutboxEntry, until time.Time) (bool, error)
+	Retry(Id int, at time.Time, lastError string) error
+	MarkDead(Id int, lastError string) error
+	Complete(entry *models.OutboxEntry) (bool, error)
+	DeleteByPId(PId int) error
+	DeleteForUser(UId int) error
+}
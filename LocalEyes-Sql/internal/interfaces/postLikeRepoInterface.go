@@ -0,0 +1,9 @@
+package interfaces
+
+type PostLikeRepository interface {
+	Create(UId, PId int) error
+	Delete(UId, PId int) error
+	GetLikedPIds(UId int) ([]int, error)
+	DeleteByPId(PId int) error
+	DeleteForUser(UId int) error
+}
@@ -10,9 +10,15 @@ type PostRepository interface {
 	DeleteByPId(PId int) error
 	DeleteByUId(UId int) error
 	GetPostsByFilter(filter string) ([]*models.Post, error)
+	ListPosts(filter models.PostFilter, order string, limit, offset int) ([]*models.Post, error)
+	SearchPosts(city, search string, limit, offset int) ([]*models.Post, error)
+	PostsInArea(area models.Area) ([]*models.Post, error)
 	GetPostsByUId(UId int) ([]*models.Post, error)
 	UpdateUserPost(PId int, UId int, title string, content string) error
+	UpdateType(from, to string) error
 	UpdateLike(PId int) error
+	RemoveLike(PId int) error
+	RemoveUserLikes(UId int) error
 	DeleteByUIdPId(UId, PId int) error
 	GetPostsByPId(PId int) ([]*models.Post, error)
 }
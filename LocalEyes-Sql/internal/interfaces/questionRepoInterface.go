@@ -7,9 +7,13 @@ import (
 type QuestionRepository interface {
 	Create(question *models.Question) error
 	GetAllQuestions() ([]*models.Question, error)
+	ListQuestions(city, order string, limit, offset int) ([]*models.Question, error)
+	SearchQuestions(search string, limit, offset int) ([]*models.Question, error)
 	DeleteByQIdUId(QId, UId int) error
 	DeleteByPId(PId int) error
+	DeleteByUId(UId int) error
+	DeleteByPostOwner(UId int) error
 	GetQuestionsByPId(PId int) ([]*models.Question, error)
-	UpdateQuestion(QId int, answer string) error
+	FindByQId(QId int) (*models.Question, error)
 	DeleteByQId(QId int) error
 }
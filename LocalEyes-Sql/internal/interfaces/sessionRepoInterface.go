@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"localEyes/internal/models"
+	"time"
+)
+
+type SessionRepository interface {
+	Create(session *models.Session) error
+	FindByTokenHash(tokenHash string) (*models.Session, error)
+	DeleteByTokenHash(tokenHash string) error
+	DeleteForUser(UId int) error
+	DeleteExpired(now time.Time) (int64, error)
+}
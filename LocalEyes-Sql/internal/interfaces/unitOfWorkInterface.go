@@ -0,0 +1,22 @@
+package interfaces
+
+// Repositories groups the repositories taking part in one unit of work.
+type Repositories struct {
+	Users             UserRepository
+	Posts             PostRepository
+	Questions         QuestionRepository
+	Answers           AnswerRepository
+	Likes             PostLikeRepository
+	Sessions          SessionRepository
+	Audit             AuditRepository
+	Categories        CategoryRepository
+	Cities            CityRepository
+	Comments          CommentRepository
+	Notifications     NotificationRepository
+	NotificationPrefs NotificationPrefsRepository
+	Outbox            OutboxRepository
+}
+
+type UnitOfWork interface {
+	Do(fn func(repos Repositories) error) error
+}
@@ -20,11 +20,12 @@ type UserRepository interface {
 	Create(user *models.User) error
 	FindByUId(UId int) (*models.User, error)
 	FindByUsername(username string) (*models.User, error)
-	FindByUsernamePassword(username string, password string) (*models.User, error)
-	FindAdminByUsernamePassword(username string, password string) (*models.Admin, error)
 	GetAllUsers() ([]*models.User, error)
+	ListUsers(order string, limit, offset int) ([]*models.User, error)
 	DeleteByUId(UId int) error
 	UpdateActiveStatus(UId int, status bool) error
-	PushNotification(UId int, title string) error
-	ClearNotification(UId int) error
+	UpdatePassword(UId int, password string) error
+	UpdateRole(UId int, role string) error
+	CountByRole(role string) (int, error)
+	UpdateCity(UId int, city string) error
 }
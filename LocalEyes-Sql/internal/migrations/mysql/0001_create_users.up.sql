@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS users (
+    id           INT AUTO_INCREMENT PRIMARY KEY,
+    username     VARCHAR(100) NOT NULL,
+    password     VARCHAR(255) NOT NULL,
+    is_active    BOOLEAN      NOT NULL DEFAULT TRUE,
+    city         VARCHAR(100) NOT NULL,
+    dwelling_age INT          NOT NULL DEFAULT 0,
+    tag          VARCHAR(50)  NOT NULL,
+    notification JSON         NOT NULL,
+    UNIQUE KEY uq_users_username (username)
+);
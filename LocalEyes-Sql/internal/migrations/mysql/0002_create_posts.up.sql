@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS posts (
+    post_id    INT AUTO_INCREMENT PRIMARY KEY,
+    user_id    INT          NOT NULL,
+    title      VARCHAR(255) NOT NULL,
+    type       VARCHAR(50)  NOT NULL,
+    content    TEXT         NOT NULL,
+    likes      INT          NOT NULL DEFAULT 0,
+    created_at DATETIME     NOT NULL,
+    KEY idx_posts_user_id (user_id),
+    KEY idx_posts_type (type)
+);
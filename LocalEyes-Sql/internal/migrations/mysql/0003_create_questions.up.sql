@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS questions (
+    q_id       INT AUTO_INCREMENT PRIMARY KEY,
+    post_id    INT      NOT NULL,
+    user_id    INT      NOT NULL,
+    text       TEXT     NOT NULL,
+    replies    JSON     NOT NULL,
+    created_at DATETIME NOT NULL,
+    KEY idx_questions_post_id (post_id),
+    KEY idx_questions_user_id (user_id)
+);
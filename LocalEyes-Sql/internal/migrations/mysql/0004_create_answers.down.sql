@@ -0,0 +1,5 @@
+-- Fold the answers back into questions.replies before dropping the table.
+UPDATE questions q
+SET replies = (SELECT COALESCE(JSON_ARRAYAGG(a.text), JSON_ARRAY()) FROM answers a WHERE a.q_id = q.q_id);
+
+DROP TABLE IF EXISTS answers;
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS answers (
+    answer_id  INT AUTO_INCREMENT PRIMARY KEY,
+    q_id       INT      NOT NULL,
+    user_id    INT      NOT NULL DEFAULT 0,
+    text       TEXT     NOT NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NULL,
+    KEY idx_answers_q_id (q_id),
+    KEY idx_answers_user_id (user_id)
+);
+
+-- Replies stored in questions.replies carry no author, so they are copied with
+-- user_id 0 and the question's timestamp.
+INSERT INTO answers (q_id, user_id, text, created_at)
+SELECT q.q_id, 0, jt.reply, q.created_at
+FROM questions q,
+     JSON_TABLE(q.replies, '$[*]' COLUMNS (idx FOR ORDINALITY, reply TEXT PATH '$')) AS jt
+ORDER BY q.q_id, jt.idx;
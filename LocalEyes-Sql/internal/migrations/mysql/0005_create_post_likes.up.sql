@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS post_likes (
+    user_id    INT      NOT NULL,
+    post_id    INT      NOT NULL,
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (user_id, post_id),
+    KEY idx_post_likes_post_id (post_id)
+);
+-- posts.likes is left as it is: likes given before this table existed cannot
+-- be attributed to anyone, so they stay in the counter, which from now on moves
+-- together with post_likes.
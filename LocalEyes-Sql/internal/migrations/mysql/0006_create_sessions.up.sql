@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS sessions (
+    token_hash CHAR(64) NOT NULL PRIMARY KEY,
+    user_id    INT      NOT NULL,
+    created_at DATETIME NOT NULL,
+    expires_at BIGINT   NOT NULL,
+    KEY idx_sessions_user_id (user_id),
+    KEY idx_sessions_expires_at (expires_at)
+);
+-- expires_at holds Unix seconds so that expiry checks compare plain numbers
+-- on every driver.
@@ -0,0 +1,4 @@
+ALTER TABLE users ADD COLUMN role VARCHAR(16) NOT NULL DEFAULT 'user';
+-- Admin rights used to belong to whoever was called "admin"; that account
+-- keeps them as a role.
+UPDATE users SET role = 'admin' WHERE username = 'admin';
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS audit_log (
+    id           INT AUTO_INCREMENT PRIMARY KEY,
+    actor_id     INT          NOT NULL,
+    actor_name   VARCHAR(255) NOT NULL,
+    action       VARCHAR(32)  NOT NULL,
+    target_type  VARCHAR(16)  NOT NULL,
+    target_id    INT          NOT NULL,
+    before_state TEXT         NOT NULL,
+    after_state  TEXT         NOT NULL,
+    created_at   BIGINT       NOT NULL,
+    KEY idx_audit_log_actor (actor_id),
+    KEY idx_audit_log_target (target_type, target_id),
+    KEY idx_audit_log_created_at (created_at)
+);
+-- created_at holds Unix seconds, like sessions.expires_at, so time ranges
+-- compare plain numbers on every driver. The triggers keep the log
+-- append-only.
+CREATE TRIGGER audit_log_no_update BEFORE UPDATE ON audit_log FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit_log is append-only';
+CREATE TRIGGER audit_log_no_delete BEFORE DELETE ON audit_log FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit_log is append-only';
@@ -0,0 +1,3 @@
+ALTER TABLE answers DROP INDEX ft_answers_text;
+ALTER TABLE questions DROP INDEX ft_questions_text;
+ALTER TABLE posts DROP INDEX ft_posts_title_content;
@@ -0,0 +1,5 @@
+-- Keyword search ranks posts and questions by MATCH ... AGAINST relevance,
+-- which needs a FULLTEXT index over exactly the columns it matches.
+ALTER TABLE posts ADD FULLTEXT INDEX ft_posts_title_content (title, content);
+ALTER TABLE questions ADD FULLTEXT INDEX ft_questions_text (text);
+ALTER TABLE answers ADD FULLTEXT INDEX ft_answers_text (text);
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS categories (
+    id          INT AUTO_INCREMENT PRIMARY KEY,
+    name        VARCHAR(50)  NOT NULL,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    is_active   BOOLEAN      NOT NULL DEFAULT TRUE,
+    UNIQUE KEY uq_categories_name (name)
+);
+-- posts.type keeps holding the category name, so existing posts need no
+-- change; the seeds are the four types that used to be hardcoded.
+INSERT INTO categories (name, description) VALUES
+    ('food', 'Places to eat and dishes to try'),
+    ('travel', 'Getting around and places to visit'),
+    ('shopping', 'Markets, shops and deals'),
+    ('other', 'Everything else');
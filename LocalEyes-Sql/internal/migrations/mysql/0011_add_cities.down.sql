@@ -0,0 +1,4 @@
+DROP INDEX idx_users_city ON users;
+DROP INDEX idx_posts_city ON posts;
+ALTER TABLE posts DROP COLUMN city;
+DROP TABLE IF EXISTS cities;
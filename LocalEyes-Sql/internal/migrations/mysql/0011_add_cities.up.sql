@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS cities (
+    id   INT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(50) NOT NULL,
+    UNIQUE KEY uq_cities_name (name)
+);
+-- Every account so far was signed up in Delhi; the users keep their city and
+-- their posts move into it.
+UPDATE users SET city = LOWER(TRIM(city));
+INSERT IGNORE INTO cities (name) VALUES ('delhi');
+INSERT IGNORE INTO cities (name) SELECT DISTINCT city FROM users WHERE city <> '';
+ALTER TABLE posts ADD COLUMN city VARCHAR(50) NOT NULL DEFAULT 'delhi';
+UPDATE posts p JOIN users u ON u.id = p.user_id SET p.city = u.city;
+CREATE INDEX idx_posts_city ON posts (city);
+CREATE INDEX idx_users_city ON users (city);
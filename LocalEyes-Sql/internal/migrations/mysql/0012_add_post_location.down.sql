@@ -0,0 +1,5 @@
+DROP INDEX idx_posts_location ON posts;
+DROP INDEX idx_posts_neighbourhood ON posts;
+ALTER TABLE posts DROP COLUMN longitude;
+ALTER TABLE posts DROP COLUMN latitude;
+ALTER TABLE posts DROP COLUMN neighbourhood;
@@ -0,0 +1,6 @@
+-- Posts may name a neighbourhood and carry coordinates; both are optional.
+ALTER TABLE posts ADD COLUMN neighbourhood VARCHAR(60) NOT NULL DEFAULT '';
+ALTER TABLE posts ADD COLUMN latitude DOUBLE NULL;
+ALTER TABLE posts ADD COLUMN longitude DOUBLE NULL;
+CREATE INDEX idx_posts_neighbourhood ON posts (city, neighbourhood);
+CREATE INDEX idx_posts_location ON posts (latitude, longitude);
@@ -0,0 +1,16 @@
+-- Threaded comments on posts. parent_id is 0 for a top-level comment. A
+-- deleted comment that still has replies stays behind as a placeholder with
+-- deleted set and its text cleared, so the thread under it keeps its place.
+CREATE TABLE IF NOT EXISTS comments (
+    comment_id INT AUTO_INCREMENT PRIMARY KEY,
+    post_id    INT        NOT NULL,
+    parent_id  INT        NOT NULL DEFAULT 0,
+    user_id    INT        NOT NULL,
+    text       TEXT       NOT NULL,
+    created_at DATETIME   NOT NULL,
+    updated_at DATETIME   NULL,
+    deleted    BOOLEAN    NOT NULL DEFAULT FALSE,
+    KEY idx_comments_post_id (post_id),
+    KEY idx_comments_parent_id (parent_id),
+    KEY idx_comments_user_id (user_id)
+);
@@ -0,0 +1,8 @@
+-- Put the unread notifications back into users.notification before dropping
+-- the table, with the trailing newline the old scheme stored; read ones had
+-- been cleared under it.
+UPDATE users u
+SET notification = (SELECT COALESCE(JSON_ARRAYAGG(CONCAT(n.message, '\n')), JSON_ARRAY()) FROM notifications n
+                    WHERE n.user_id = u.id AND n.is_read = FALSE);
+
+DROP TABLE IF EXISTS notifications;
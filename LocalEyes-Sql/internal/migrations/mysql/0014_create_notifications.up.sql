@@ -0,0 +1,24 @@
+-- Each notification is one row in its recipient's inbox. source_type and
+-- source_id point at the post, question, answer or comment it is about, and
+-- are empty when there is nothing to link to.
+CREATE TABLE IF NOT EXISTS notifications (
+    id          INT AUTO_INCREMENT PRIMARY KEY,
+    user_id     INT         NOT NULL,
+    type        VARCHAR(20) NOT NULL,
+    source_type VARCHAR(20) NOT NULL DEFAULT '',
+    source_id   INT         NOT NULL DEFAULT 0,
+    message     TEXT        NOT NULL,
+    is_read     BOOLEAN     NOT NULL DEFAULT FALSE,
+    created_at  DATETIME    NOT NULL,
+    KEY idx_notifications_user_id (user_id, is_read)
+);
+
+-- Pending notifications in users.notification only carry their text, so they
+-- move into the inbox unread and without a link.
+INSERT INTO notifications (user_id, type, message, created_at)
+SELECT u.id, 'new_post', TRIM(TRAILING '\n' FROM jt.message), NOW()
+FROM users u,
+     JSON_TABLE(u.notification, '$[*]' COLUMNS (idx FOR ORDINALITY, message TEXT PATH '$')) AS jt
+ORDER BY u.id, jt.idx;
+
+UPDATE users SET notification = JSON_ARRAY();
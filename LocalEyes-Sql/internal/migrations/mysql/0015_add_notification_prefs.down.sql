@@ -0,0 +1,5 @@
+-- Dropping held hands any notification waiting for a digest over as it is.
+ALTER TABLE notifications DROP COLUMN held;
+
+DROP TABLE IF EXISTS notification_subscriptions;
+DROP TABLE IF EXISTS notification_prefs;
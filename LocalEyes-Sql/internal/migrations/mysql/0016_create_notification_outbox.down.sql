@@ -0,0 +1,2 @@
+-- Posts still waiting in the outbox are never announced.
+DROP TABLE IF EXISTS notification_outbox;
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS users (
+    id           INTEGER PRIMARY KEY AUTOINCREMENT,
+    username     TEXT    NOT NULL UNIQUE,
+    password     TEXT    NOT NULL,
+    is_active    BOOLEAN NOT NULL DEFAULT 1,
+    city         TEXT    NOT NULL,
+    dwelling_age INTEGER NOT NULL DEFAULT 0,
+    tag          TEXT    NOT NULL,
+    notification TEXT    NOT NULL DEFAULT '[]'
+);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS posts (
+    post_id    INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id    INTEGER  NOT NULL,
+    title      TEXT     NOT NULL,
+    type       TEXT     NOT NULL,
+    content    TEXT     NOT NULL,
+    likes      INTEGER  NOT NULL DEFAULT 0,
+    created_at DATETIME NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_posts_user_id ON posts (user_id);
+CREATE INDEX IF NOT EXISTS idx_posts_type ON posts (type);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS questions (
+    q_id       INTEGER PRIMARY KEY AUTOINCREMENT,
+    post_id    INTEGER  NOT NULL,
+    user_id    INTEGER  NOT NULL,
+    text       TEXT     NOT NULL,
+    replies    TEXT     NOT NULL DEFAULT '[]',
+    created_at DATETIME NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_questions_post_id ON questions (post_id);
+CREATE INDEX IF NOT EXISTS idx_questions_user_id ON questions (user_id);
@@ -0,0 +1,5 @@
+-- Fold the answers back into questions.replies before dropping the table.
+UPDATE questions
+SET replies = (SELECT COALESCE(json_group_array(a.text), '[]') FROM answers a WHERE a.q_id = questions.q_id);
+
+DROP TABLE IF EXISTS answers;
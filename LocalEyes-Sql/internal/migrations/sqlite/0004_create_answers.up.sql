@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS answers (
+    answer_id  INTEGER PRIMARY KEY AUTOINCREMENT,
+    q_id       INTEGER  NOT NULL,
+    user_id    INTEGER  NOT NULL DEFAULT 0,
+    text       TEXT     NOT NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME
+);
+CREATE INDEX IF NOT EXISTS idx_answers_q_id ON answers (q_id);
+CREATE INDEX IF NOT EXISTS idx_answers_user_id ON answers (user_id);
+
+-- Replies stored in questions.replies carry no author, so they are copied with
+-- user_id 0 and the question's timestamp.
+INSERT INTO answers (q_id, user_id, text, created_at)
+SELECT q.q_id, 0, r.value, q.created_at
+FROM questions q, json_each(q.replies) r
+ORDER BY q.q_id, r.key;
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS post_likes (
+    user_id    INTEGER  NOT NULL,
+    post_id    INTEGER  NOT NULL,
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (user_id, post_id)
+);
+CREATE INDEX IF NOT EXISTS idx_post_likes_post_id ON post_likes (post_id);
+-- posts.likes is left as it is: likes given before this table existed cannot
+-- be attributed to anyone, so they stay in the counter, which from now on moves
+-- together with post_likes.
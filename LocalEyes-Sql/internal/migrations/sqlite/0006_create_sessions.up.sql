@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS sessions (
+    token_hash TEXT     NOT NULL PRIMARY KEY,
+    user_id    INTEGER  NOT NULL,
+    created_at DATETIME NOT NULL,
+    expires_at INTEGER  NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions (user_id);
+CREATE INDEX IF NOT EXISTS idx_sessions_expires_at ON sessions (expires_at);
+-- expires_at holds Unix seconds so that expiry checks compare plain numbers
+-- on every driver.
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS audit_log (
+    id           INTEGER PRIMARY KEY AUTOINCREMENT,
+    actor_id     INTEGER NOT NULL,
+    actor_name   TEXT    NOT NULL,
+    action       TEXT    NOT NULL,
+    target_type  TEXT    NOT NULL,
+    target_id    INTEGER NOT NULL,
+    before_state TEXT    NOT NULL,
+    after_state  TEXT    NOT NULL,
+    created_at   INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_audit_log_actor ON audit_log (actor_id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_target ON audit_log (target_type, target_id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log (created_at);
+-- created_at holds Unix seconds, like sessions.expires_at, so time ranges
+-- compare plain numbers on every driver. The triggers keep the log
+-- append-only; each is kept on one line for the statement splitter.
+CREATE TRIGGER IF NOT EXISTS audit_log_no_update BEFORE UPDATE ON audit_log BEGIN SELECT RAISE(ABORT, 'audit_log is append-only'); END;
+CREATE TRIGGER IF NOT EXISTS audit_log_no_delete BEFORE DELETE ON audit_log BEGIN SELECT RAISE(ABORT, 'audit_log is append-only'); END;
@@ -0,0 +1,2 @@
+-- SQLite has no FULLTEXT indexes; its repositories search with LIKE instead.
+-- The migration is kept so versions line up with the MySQL schema.
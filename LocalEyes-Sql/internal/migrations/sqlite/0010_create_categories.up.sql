@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS categories (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    name        TEXT    NOT NULL UNIQUE,
+    description TEXT    NOT NULL DEFAULT '',
+    is_active   BOOLEAN NOT NULL DEFAULT 1
+);
+-- posts.type keeps holding the category name, so existing posts need no
+-- change; the seeds are the four types that used to be hardcoded.
+INSERT INTO categories (name, description) VALUES
+    ('food', 'Places to eat and dishes to try'),
+    ('travel', 'Getting around and places to visit'),
+    ('shopping', 'Markets, shops and deals'),
+    ('other', 'Everything else');
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_users_city;
+DROP INDEX IF EXISTS idx_posts_city;
+ALTER TABLE posts DROP COLUMN city;
+DROP TABLE IF EXISTS cities;
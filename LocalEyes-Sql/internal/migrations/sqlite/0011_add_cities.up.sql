@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS cities (
+    id   INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT    NOT NULL UNIQUE
+);
+-- Every account so far was signed up in Delhi; the users keep their city and
+-- their posts move into it.
+UPDATE users SET city = LOWER(TRIM(city));
+INSERT OR IGNORE INTO cities (name) VALUES ('delhi');
+INSERT OR IGNORE INTO cities (name) SELECT DISTINCT city FROM users WHERE city <> '';
+ALTER TABLE posts ADD COLUMN city TEXT NOT NULL DEFAULT 'delhi';
+UPDATE posts SET city = (SELECT u.city FROM users u WHERE u.id = posts.user_id)
+    WHERE EXISTS (SELECT 1 FROM users u WHERE u.id = posts.user_id);
+CREATE INDEX IF NOT EXISTS idx_posts_city ON posts (city);
+CREATE INDEX IF NOT EXISTS idx_users_city ON users (city);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_posts_location;
+DROP INDEX IF EXISTS idx_posts_neighbourhood;
+ALTER TABLE posts DROP COLUMN longitude;
+ALTER TABLE posts DROP COLUMN latitude;
+ALTER TABLE posts DROP COLUMN neighbourhood;
@@ -0,0 +1,6 @@
+-- Posts may name a neighbourhood and carry coordinates; both are optional.
+ALTER TABLE posts ADD COLUMN neighbourhood TEXT NOT NULL DEFAULT '';
+ALTER TABLE posts ADD COLUMN latitude REAL;
+ALTER TABLE posts ADD COLUMN longitude REAL;
+CREATE INDEX IF NOT EXISTS idx_posts_neighbourhood ON posts (city, neighbourhood);
+CREATE INDEX IF NOT EXISTS idx_posts_location ON posts (latitude, longitude);
@@ -0,0 +1,16 @@
+-- Threaded comments on posts. parent_id is 0 for a top-level comment. A
+-- deleted comment that still has replies stays behind as a placeholder with
+-- deleted set and its text cleared, so the thread under it keeps its place.
+CREATE TABLE IF NOT EXISTS comments (
+    comment_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    post_id    INTEGER  NOT NULL,
+    parent_id  INTEGER  NOT NULL DEFAULT 0,
+    user_id    INTEGER  NOT NULL,
+    text       TEXT     NOT NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME,
+    deleted    BOOLEAN  NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS idx_comments_post_id ON comments (post_id);
+CREATE INDEX IF NOT EXISTS idx_comments_parent_id ON comments (parent_id);
+CREATE INDEX IF NOT EXISTS idx_comments_user_id ON comments (user_id);
@@ -0,0 +1,8 @@
+-- Put the unread notifications back into users.notification before dropping
+-- the table, with the trailing newline the old scheme stored; read ones had
+-- been cleared under it.
+UPDATE users
+SET notification = (SELECT COALESCE(json_group_array(n.message || char(10)), '[]') FROM notifications n
+                    WHERE n.user_id = users.id AND n.is_read = 0);
+
+DROP TABLE IF EXISTS notifications;
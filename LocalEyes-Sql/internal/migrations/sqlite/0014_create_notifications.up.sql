@@ -0,0 +1,23 @@
+-- Each notification is one row in its recipient's inbox. source_type and
+-- source_id point at the post, question, answer or comment it is about, and
+-- are empty when there is nothing to link to.
+CREATE TABLE IF NOT EXISTS notifications (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id     INTEGER  NOT NULL,
+    type        TEXT     NOT NULL,
+    source_type TEXT     NOT NULL DEFAULT '',
+    source_id   INTEGER  NOT NULL DEFAULT 0,
+    message     TEXT     NOT NULL,
+    is_read     BOOLEAN  NOT NULL DEFAULT 0,
+    created_at  DATETIME NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_notifications_user_id ON notifications (user_id, is_read);
+
+-- Pending notifications in users.notification only carry their text, so they
+-- move into the inbox unread and without a link.
+INSERT INTO notifications (user_id, type, message, created_at)
+SELECT u.id, 'new_post', rtrim(n.value, char(10)), CURRENT_TIMESTAMP
+FROM users u, json_each(u.notification) n
+ORDER BY u.id, n.key;
+
+UPDATE users SET notification = '[]';
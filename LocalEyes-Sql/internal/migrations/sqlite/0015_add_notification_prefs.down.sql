@@ -0,0 +1,6 @@
+-- Dropping held hands any notification waiting for a digest over as it is.
+ALTER TABLE notifications DROP COLUMN held;
+
+DROP INDEX IF EXISTS idx_notification_subscriptions_target;
+DROP TABLE IF EXISTS notification_subscriptions;
+DROP TABLE IF EXISTS notification_prefs;
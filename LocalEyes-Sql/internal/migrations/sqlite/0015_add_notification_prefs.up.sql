@@ -0,0 +1,22 @@
+-- Users without a row here get every new post in their city right away.
+-- delivery is immediate, digest or off; posts is all, subscribed or none,
+-- where subscribed only counts posts in the categories and by the authors in
+-- notification_subscriptions. digest_at is when the last digest went out.
+CREATE TABLE IF NOT EXISTS notification_prefs (
+    user_id   INTEGER  PRIMARY KEY,
+    delivery  TEXT     NOT NULL DEFAULT 'immediate',
+    posts     TEXT     NOT NULL DEFAULT 'all',
+    digest_at DATETIME NOT NULL
+);
+
+-- kind is category or user, and target_id the category's or author's id.
+CREATE TABLE IF NOT EXISTS notification_subscriptions (
+    user_id   INTEGER NOT NULL,
+    kind      TEXT    NOT NULL,
+    target_id INTEGER NOT NULL,
+    PRIMARY KEY (user_id, kind, target_id)
+);
+CREATE INDEX IF NOT EXISTS idx_notification_subscriptions_target ON notification_subscriptions (kind, target_id);
+
+-- Held notifications wait for the recipient's next digest.
+ALTER TABLE notifications ADD COLUMN held BOOLEAN NOT NULL DEFAULT 0;
@@ -0,0 +1,3 @@
+-- Posts still waiting in the outbox are never announced.
+DROP INDEX IF EXISTS idx_notification_outbox_due;
+DROP TABLE IF EXISTS notification_outbox;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS notification_outbox (
+    id           INTEGER PRIMARY KEY AUTOINCREMENT,
+    post_id      INTEGER  NOT NULL,
+    attempts     INTEGER  NOT NULL DEFAULT 0,
+    available_at INTEGER  NOT NULL,
+    last_error   TEXT     NOT NULL DEFAULT '',
+    dead         BOOLEAN  NOT NULL DEFAULT 0,
+    created_at   DATETIME NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_notification_outbox_due ON notification_outbox (dead, available_at);
+-- A row is written with each new post, in the same transaction, and removed
+-- once the post's notifications have gone out. available_at holds the Unix
+-- second a worker may next pick the row up: claiming it pushes it out by a
+-- lease, and a failed attempt by a backoff. Rows that ran out of attempts are
+-- kept as dead with their last error.
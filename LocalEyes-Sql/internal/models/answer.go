@@ -0,0 +1,15 @@
+package models
+
+import (
+	"time"
+)
+
+type Answer struct {
+	AnswerId  int       `bson:"answer_id"`
+	QId       int       `bson:"q_id"`
+	UserId    int       `bson:"user_id"`
+	Username  string    `bson:"username"` //filled from users when listing
+	Text      string    `bson:"text"`
+	CreatedAt time.Time `bson:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at"`
+}
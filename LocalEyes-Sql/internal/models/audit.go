@@ -0,0 +1,58 @@
+package models
+
+import (
+	"time"
+)
+
+// Actions recorded in the audit log.
+const (
+	AuditDeleteUser     = "delete_user"
+	AuditReactivateUser = "reactivate_user"
+	AuditDeactivateUser = "deactivate_user"
+	AuditChangeRole     = "change_role"
+	AuditDeletePost     = "delete_post"
+	AuditDeleteQuestion = "delete_question"
+	AuditDeleteAnswer   = "delete_answer"
+	AuditDeleteComment  = "delete_comment"
+	AuditCreateCategory = "create_category"
+	AuditUpdateCategory = "update_category"
+	AuditDeleteCategory = "delete_category"
+	AuditCreateCity     = "create_city"
+)
+
+// Kinds of target an audit entry can point at.
+const (
+	TargetUser     = "user"
+	TargetPost     = "post"
+	TargetQuestion = "question"
+	TargetAnswer   = "answer"
+	TargetComment  = "comment"
+	TargetCategory = "category"
+	TargetCity     = "city"
+)
+
+// AuditEntry records who did what to which record. Before and After hold
+// JSON snapshots of the target and are empty when there is nothing to show,
+// such as After for a delete. The actor's username is copied in so entries
+// stay readable after the actor's account is gone.
+type AuditEntry struct {
+	Id         int       `bson:"id"`
+	ActorId    int       `bson:"actor_id"`
+	ActorName  string    `bson:"actor_name"`
+	Action     string    `bson:"action"`
+	TargetType string    `bson:"target_type"`
+	TargetId   int       `bson:"target_id"`
+	Before     string    `bson:"before_state"`
+	After      string    `bson:"after_state"`
+	CreatedAt  time.Time `bson:"created_at"`
+}
+
+// AuditFilter narrows an audit log query; zero fields match everything and
+// From and To are both inclusive.
+type AuditFilter struct {
+	ActorId    int
+	TargetType string
+	TargetId   int
+	From       time.Time
+	To         time.Time
+}
@@ -0,0 +1,17 @@
+package models
+
+// Category is what a post is about. Posts keep the category's name in their
+// Type, and only active categories take new posts.
+type Category struct {
+	Id          int    `bson:"id"`
+	Name        string `bson:"name"`
+	Description string `bson:"description"`
+	IsActive    bool   `bson:"is_active"`
+}
+
+// CategoryUpdate holds the changes to a category; nil fields stay as they are.
+type CategoryUpdate struct {
+	Name        *string
+	Description *string
+	IsActive    *bool
+}
@@ -0,0 +1,8 @@
+package models
+
+// City is a place LocalEyes runs in. Users and posts keep the city's name,
+// and every feed and notification stays within one city.
+type City struct {
+	Id   int    `bson:"id"`
+	Name string `bson:"name"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+)
+
+// Comment is one entry in a post's discussion. Replies nest without limit;
+// ParentId is 0 for a comment made on the post itself.
+type Comment struct {
+	CommentId int       `bson:"comment_id"`
+	PostId    int       `bson:"post_id"`
+	ParentId  int       `bson:"parent_id"`
+	UserId    int       `bson:"user_id"`
+	Username  string    `bson:"username"` //filled from users when listing
+	Text      string    `bson:"text"`
+	CreatedAt time.Time `bson:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at"` //zero until the comment is edited
+	// Deleted marks a placeholder for a comment removed while it still had
+	// replies; its text is gone.
+	Deleted bool       `bson:"deleted"`
+	Replies []*Comment `bson:"-"` //set when a discussion is threaded
+}
+
+// MaxCommentLength caps the text of a comment, in characters.
+const MaxCommentLength = 1000
+
+// DefaultCommentDepth is how many levels of replies the CLI shows under a
+// comment before folding the rest away.
+const DefaultCommentDepth = 4
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"errors"
+	"localEyes/config"
+)
+
+// Kinds of failure the user can do something about. Front ends tell them
+// apart with errors.Is; any other error is a fault on our side.
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrForbidden    = errors.New("forbidden")
+	ErrInvalid      = errors.New("invalid")
+	ErrUnauthorized = errors.New("unauthorized")
+)
+
+// Error is a failure of one of the kinds above, with the message to show
+// for it.
+type Error struct {
+	Kind    error
+	Message string
+}
+
+// NewError returns an error of kind with message.
+func NewError(kind error, message string) error {
+	return &Error{Kind: kind, Message: message}
+}
+
+func (e *Error) Error() string {
+	return config.Red + e.Message + config.Reset
+}
+
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"time"
+)
+
+// Kinds of notification.
+const (
+	NotifyNewPost     = "new_post"     // someone in the user's city posted
+	NotifyNewQuestion = "new_question" // someone asked about the user's post
+	NotifyNewAnswer   = "new_answer"   // someone answered the user's question
+	NotifyAdminAction = "admin_action" // a moderator or admin acted on the user's content or account
+	NotifyDigest      = "digest"       // the notifications held back for a daily digest
+)
+
+// How a user wants to receive notifications.
+const (
+	DeliverImmediately = "immediate" // straight into the inbox
+	DeliverDigest      = "digest"    // held back and summed up once a day
+	DeliverOff         = "off"       // not at all, apart from admin actions
+)
+
+// Which new posts in their city a user hears about.
+const (
+	PostsAll        = "all"
+	PostsSubscribed = "subscribed" // only those in a subscribed category or by a subscribed author
+	PostsNone       = "none"
+)
+
+var (
+	Deliveries = []string{DeliverImmediately, DeliverDigest, DeliverOff}
+	PostScopes = []string{PostsAll, PostsSubscribed, PostsNone}
+)
+
+// DigestInterval is how long a digest user waits between digests.
+const DigestInterval = 24 * time.Hour
+
+// Notification is one entry in a user's inbox. SourceType is one of the
+// audit Target kinds and, with SourceId, links to what the notification is
+// about; both are empty when there is nothing to link to. Held notifications
+// wait, out of sight, for the user's next digest.
+type Notification struct {
+	Id         int       `bson:"id"`
+	UserId     int       `bson:"user_id"`
+	Type       string    `bson:"type"`
+	SourceType string    `bson:"source_type"`
+	SourceId   int       `bson:"source_id"`
+	Message    string    `bson:"message"`
+	IsRead     bool      `bson:"is_read"`
+	Held       bool      `bson:"held"`
+	CreatedAt  time.Time `bson:"created_at"`
+}
+
+// NotificationPrefs is how a user wants to be notified. Subscriptions only
+// matter when Posts is PostsSubscribed.
+type NotificationPrefs struct {
+	UserId        int             `bson:"user_id"`
+	Delivery      string          `bson:"delivery"`
+	Posts         string          `bson:"posts"`
+	DigestAt      time.Time       `bson:"digest_at"`
+	Subscriptions []*Subscription `bson:"subscriptions"`
+}
+
+// Subscription follows a category (Kind TargetCategory) or an author (Kind
+// TargetUser). Name is the category's name or the author's username.
+type Subscription struct {
+	UserId   int    `bson:"user_id"`
+	Kind     string `bson:"kind"`
+	TargetId int    `bson:"target_id"`
+	Name     string `bson:"name"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+)
+
+// OutboxEntry is a new post whose notifications have yet to go out. Attempts
+// counts the times a worker has claimed it, and AvailableAt is when it may be
+// claimed again. Dead entries ran out of attempts and are kept, with the last
+// error, for someone to look into.
+type OutboxEntry struct {
+	Id          int       `bson:"id"`
+	PostId      int       `bson:"post_id"`
+	Attempts    int       `bson:"attempts"`
+	AvailableAt time.Time `bson:"available_at"`
+	LastError   string    `bson:"last_error"`
+	Dead        bool      `bson:"dead"`
+	CreatedAt   time.Time `bson:"created_at"`
+}
@@ -0,0 +1,61 @@
+package models
+
+// Orders a paged listing can be sorted in. Posts take the first four, users
+// and questions only newest and oldest, search results come by relevance and
+// near-me results by distance.
+const (
+	SortNewest        = "newest"
+	SortOldest        = "oldest"
+	SortMostLiked     = "most-liked"
+	SortMostQuestions = "most-questions"
+	SortRelevance     = "relevance"
+	SortNearest       = "nearest"
+)
+
+// PostSorts, RecordSorts, SearchSorts and NearSorts list the orders each
+// listing supports; the first is the default.
+var (
+	PostSorts   = []string{SortNewest, SortOldest, SortMostLiked, SortMostQuestions}
+	RecordSorts = []string{SortNewest, SortOldest}
+	SearchSorts = []string{SortRelevance}
+	NearSorts   = []string{SortNearest}
+)
+
+const (
+	DefaultPageSize = 10
+	MaxPageSize     = 100
+)
+
+// PageRequest asks for one page of a listing. Pages are numbered from 1.
+type PageRequest struct {
+	Number int
+	Size   int
+	Sort   string
+}
+
+func (p PageRequest) Offset() int {
+	return (p.Number - 1) * p.Size
+}
+
+func (p PageRequest) Next() PageRequest {
+	p.Number++
+	return p
+}
+
+func (p PageRequest) Prev() PageRequest {
+	if p.Number > 1 {
+		p.Number--
+	}
+	return p
+}
+
+// Page is one page of a listing along with the request that produced it.
+type Page[T any] struct {
+	Items   []T
+	Request PageRequest
+	HasNext bool
+}
+
+func (p *Page[T]) HasPrev() bool {
+	return p.Request.Number > 1
+}
@@ -12,4 +12,41 @@ type Post struct {
 	Content   string    `bson:"content"`
 	Likes     int       `bson:"likes"`
 	CreatedAt time.Time `bson:"created_at"`
+	City      string    `bson:"city"` //the author's city when posting
+	Location  `bson:",inline"`
+	LikedByMe bool     `bson:"-"` //set for the viewing user, not stored
+	Distance  *float64 `bson:"-"` //km from the viewer, set by near-me queries
+}
+
+// Location is where a post is about. Every field is optional, but the
+// coordinates come as a pair.
+type Location struct {
+	Neighbourhood string   `bson:"neighbourhood"`
+	Latitude      *float64 `bson:"latitude"`
+	Longitude     *float64 `bson:"longitude"`
+}
+
+func (l Location) HasCoordinates() bool {
+	return l.Latitude != nil && l.Longitude != nil
+}
+
+// Near-me queries look this far around the viewer unless told otherwise,
+// and never further than MaxNearRadiusKm.
+const (
+	DefaultNearRadiusKm = 5.0
+	MaxNearRadiusKm     = 50.0
+)
+
+// Area is a latitude/longitude box, the coarse first cut of a near-me query.
+type Area struct {
+	MinLatitude, MaxLatitude   float64
+	MinLongitude, MaxLongitude float64
+}
+
+// PostFilter narrows a post listing; zero fields match every post.
+type PostFilter struct {
+	Type          string
+	UId           int
+	City          string
+	Neighbourhood string
 }
@@ -9,6 +9,7 @@ type Question struct {
 	PostId    int       `bson:"post_id"`
 	UserId    int       `bson:"user_id"`
 	Text      string    `bson:"text"`
-	Replies   []string  `bson:"replies"`
+	Replies   []string  `bson:"replies"` //legacy, answers now live in their own table
 	CreatedAt time.Time `bson:"created_at"`
+	Answers   []*Answer `bson:"-"`
 }
@@ -0,0 +1,47 @@
+package models
+
+// Roles a user can hold. Everyone starts as a plain user; moderators look
+// after content and admins additionally manage accounts and roles.
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+type Permission string
+
+const (
+	PermViewContent      Permission = "view_content"      // list every post and question
+	PermModerateContent  Permission = "moderate_content"  // delete anyone's posts, questions, answers and comments
+	PermViewUsers        Permission = "view_users"        // list every user
+	PermManageUsers      Permission = "manage_users"      // delete and reactivate users
+	PermManageRoles      Permission = "manage_roles"      // grant and revoke roles
+	PermViewAudit        Permission = "view_audit"        // read the audit log
+	PermManageCategories Permission = "manage_categories" // add, change and remove post categories
+	PermManageCities     Permission = "manage_cities"     // open LocalEyes in new cities
+)
+
+var rolePermissions = map[string][]Permission{
+	RoleUser:      {},
+	RoleModerator: {PermViewContent, PermModerateContent},
+	RoleAdmin:     {PermViewContent, PermModerateContent, PermViewUsers, PermManageUsers, PermManageRoles, PermViewAudit, PermManageCategories, PermManageCities},
+}
+
+func ValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// IsStaff reports whether the user holds a role above a plain user.
+func (u *User) IsStaff() bool {
+	return u.Role == RoleModerator || u.Role == RoleAdmin
+}
+
+func (u *User) Can(permission Permission) bool {
+	for _, granted := range rolePermissions[u.Role] {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,14 @@
+package models
+
+import (
+	"time"
+)
+
+// Session is a login that outlives a single command. The token itself is only
+// ever handed to the client; the database keeps its SHA-256 hash.
+type Session struct {
+	TokenHash string    `bson:"token_hash"`
+	UserId    int       `bson:"user_id"`
+	CreatedAt time.Time `bson:"created_at"`
+	ExpiresAt time.Time `bson:"expires_at"`
+}
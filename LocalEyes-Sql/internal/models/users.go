@@ -1,14 +1,14 @@
 package models
 
 type User struct {
-	UId           int         `bson:"id"`
-	Username      string      `bson:"username"`
-	Password      string      `bson:"password"`
-	City          string      `bson:"city"`
-	DwellingAge   int         `bson:"dwelling_age"`
-	IsActive      bool        `bson:"is_active"`
-	Notification  []string    `bson:"notification"`
-	Tag           string      `bson:"tag"`
-	NotifyChannel chan string `bson:"-"` //ignore
+	UId          int      `bson:"id"`
+	Username     string   `bson:"username"`
+	Password     string   `bson:"password"`
+	City         string   `bson:"city"`
+	DwellingAge  int      `bson:"dwelling_age"`
+	IsActive     bool     `bson:"is_active"`
+	Notification []string `bson:"notification"` //legacy, notifications now live in their own table
+	Tag          string   `bson:"tag"`
+	Role         string   `bson:"role"`
 	//IsAdmin       bool        `bson:"is_admin"`
 }
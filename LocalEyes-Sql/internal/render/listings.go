@@ -0,0 +1,367 @@
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"localEyes/internal/models"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const timeLayout = "2006-01-02 15:04:05"
+
+// The record types fix the JSON field names and leave out what must not be
+// exported, such as password hashes.
+
+type userRecord struct {
+	Id          int    `json:"id"`
+	Username    string `json:"username"`
+	City        string `json:"city"`
+	DwellingAge int    `json:"dwelling_age"`
+	Active      bool   `json:"active"`
+	Tag         string `json:"tag"`
+}
+
+type postRecord struct {
+	Id            int       `json:"id"`
+	UserId        int       `json:"user_id"`
+	Title         string    `json:"title"`
+	Type          string    `json:"type"`
+	Content       string    `json:"content"`
+	Neighbourhood string    `json:"neighbourhood,omitempty"`
+	Latitude      *float64  `json:"latitude,omitempty"`
+	Longitude     *float64  `json:"longitude,omitempty"`
+	DistanceKm    *float64  `json:"distance_km,omitempty"`
+	Likes         int       `json:"likes"`
+	LikedByMe     bool      `json:"liked_by_me"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type answerRecord struct {
+	Id        int        `json:"id"`
+	UserId    int        `json:"user_id"`
+	Username  string     `json:"username"`
+	Text      string     `json:"text"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+type questionRecord struct {
+	Id        int            `json:"id"`
+	PostId    int            `json:"post_id"`
+	UserId    int            `json:"user_id"`
+	Text      string         `json:"text"`
+	CreatedAt time.Time      `json:"created_at"`
+	Answers   []answerRecord `json:"answers"`
+}
+
+type commentRecord struct {
+	Id          int             `json:"id"`
+	PostId      int             `json:"post_id"`
+	ParentId    int             `json:"parent_id"`
+	UserId      int             `json:"user_id"`
+	Username    string          `json:"username"`
+	Text        string          `json:"text"`
+	Deleted     bool            `json:"deleted"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   *time.Time      `json:"updated_at,omitempty"`
+	Replies     []commentRecord `json:"replies"`
+	MoreReplies int             `json:"more_replies,omitempty"`
+}
+
+type notificationRecord struct {
+	Id         int       `json:"id"`
+	Type       string    `json:"type"`
+	SourceType string    `json:"source_type,omitempty"`
+	SourceId   int       `json:"source_id,omitempty"`
+	Message    string    `json:"message"`
+	Read       bool      `json:"read"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type subscriptionRecord struct {
+	Kind string `json:"kind"`
+	Id   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type auditRecord struct {
+	Id         int             `json:"id"`
+	ActorId    int             `json:"actor_id"`
+	ActorName  string          `json:"actor_name"`
+	Action     string          `json:"action"`
+	TargetType string          `json:"target_type"`
+	TargetId   int             `json:"target_id"`
+	Before     json.RawMessage `json:"before,omitempty"`
+	After      json.RawMessage `json:"after,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+type categoryRecord struct {
+	Id          int    `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Active      bool   `json:"active"`
+}
+
+type cityRecord struct {
+	Id   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func Users(users []*models.User) Listing {
+	listing := Listing{
+		Columns: []string{"UserId", "UserName", "City", "Resident Till", "ActiveStatus", "Tag"},
+		Records: make([]any, 0, len(users)),
+	}
+	for _, user := range users {
+		listing.Rows = append(listing.Rows, []string{strconv.Itoa(user.UId), user.Username, user.City,
+			strconv.Itoa(user.DwellingAge), yesNo(user.IsActive), user.Tag})
+		listing.Records = append(listing.Records, userRecord{Id: user.UId, Username: user.Username, City: user.City,
+			DwellingAge: user.DwellingAge, Active: user.IsActive, Tag: user.Tag})
+	}
+	return listing
+}
+
+// Posts adds a Distance column when the posts came from a near-me query.
+func Posts(posts []*models.Post) Listing {
+	listing := Listing{
+		Columns: []string{"PostId", "Title", "Type", "Content", "Location", "Likes", "Liked By Me", "Created At"},
+		Records: make([]any, 0, len(posts)),
+	}
+	near := len(posts) > 0 && posts[0].Distance != nil
+	if near {
+		listing.Columns = append(listing.Columns, "Distance")
+	}
+	for _, post := range posts {
+		row := []string{strconv.Itoa(post.PostId), post.Title, post.Type, post.Content, location(post.Location),
+			strconv.Itoa(post.Likes), yesNo(post.LikedByMe), post.CreatedAt.Format(timeLayout)}
+		if near {
+			row = append(row, fmt.Sprintf("%.1f km", *post.Distance))
+		}
+		listing.Rows = append(listing.Rows, row)
+		listing.Records = append(listing.Records, postRecord{Id: post.PostId, UserId: post.UId, Title: post.Title,
+			Type: post.Type, Content: post.Content, Neighbourhood: post.Neighbourhood, Latitude: post.Latitude,
+			Longitude: post.Longitude, DistanceKm: post.Distance, Likes: post.Likes, LikedByMe: post.LikedByMe,
+			CreatedAt: post.CreatedAt})
+	}
+	return listing
+}
+
+func Categories(categories []*models.Category) Listing {
+	listing := Listing{
+		Columns: []string{"Id", "Name", "Description", "Active"},
+		Records: make([]any, 0, len(categories)),
+	}
+	for _, category := range categories {
+		listing.Rows = append(listing.Rows, []string{strconv.Itoa(category.Id), category.Name, category.Description,
+			yesNo(category.IsActive)})
+		listing.Records = append(listing.Records, categoryRecord{Id: category.Id, Name: category.Name,
+			Description: category.Description, Active: category.IsActive})
+	}
+	return listing
+}
+
+func Cities(cities []*models.City) Listing {
+	listing := Listing{
+		Columns: []string{"Id", "Name"},
+		Records: make([]any, 0, len(cities)),
+	}
+	for _, city := range cities {
+		listing.Rows = append(listing.Rows, []string{strconv.Itoa(city.Id), city.Name})
+		listing.Records = append(listing.Records, cityRecord{Id: city.Id, Name: city.Name})
+	}
+	return listing
+}
+
+// Questions puts all of a question's answers in one cell of the tabular
+// formats, one answer per line, and nests them in the JSON formats.
+func Questions(questions []*models.Question) Listing {
+	listing := Listing{
+		Columns: []string{"QID", "Question", "Answers", "Created At"},
+		Records: make([]any, 0, len(questions)),
+	}
+	for _, question := range questions {
+		lines := make([]string, 0, len(question.Answers))
+		answers := make([]answerRecord, 0, len(question.Answers))
+		for _, answer := range question.Answers {
+			lines = append(lines, formatAnswer(answer))
+			record := answerRecord{Id: answer.AnswerId, UserId: answer.UserId, Username: answer.Username,
+				Text: answer.Text, CreatedAt: answer.CreatedAt}
+			if !answer.UpdatedAt.IsZero() {
+				updatedAt := answer.UpdatedAt
+				record.UpdatedAt = &updatedAt
+			}
+			answers = append(answers, record)
+		}
+		listing.Rows = append(listing.Rows, []string{strconv.Itoa(question.QId), question.Text,
+			strings.Join(lines, "\n"), question.CreatedAt.Format(timeLayout)})
+		listing.Records = append(listing.Records, questionRecord{Id: question.QId, PostId: question.PostId,
+			UserId: question.UserId, Text: question.Text, CreatedAt: question.CreatedAt, Answers: answers})
+	}
+	return listing
+}
+
+// Comments lists threads in the tabular formats one comment per row, each
+// reply indented under the comment it answers, and nests the replies in the
+// JSON formats. Replies more than depth levels down are folded into a count
+// on their ancestor; a depth of 0 or less shows everything.
+func Comments(threads []*models.Comment, depth int) Listing {
+	listing := Listing{
+		Columns: []string{"Id", "Reply To", "Author", "Comment", "Created At", "Edited At"},
+		Records: make([]any, 0, len(threads)),
+	}
+	var walk func(comment *models.Comment, level int) commentRecord
+	walk = func(comment *models.Comment, level int) commentRecord {
+		record := commentRecord{Id: comment.CommentId, PostId: comment.PostId, ParentId: comment.ParentId,
+			UserId: comment.UserId, Username: comment.Username, Text: comment.Text, Deleted: comment.Deleted,
+			CreatedAt: comment.CreatedAt, Replies: make([]commentRecord, 0, len(comment.Replies))}
+		author, text, edited := comment.Username, comment.Text, ""
+		if comment.Deleted {
+			author, text = "", "[deleted]"
+		} else if !comment.UpdatedAt.IsZero() {
+			updatedAt := comment.UpdatedAt
+			record.UpdatedAt = &updatedAt
+			edited = updatedAt.Format(timeLayout)
+		}
+		replyTo := ""
+		if comment.ParentId != 0 {
+			replyTo = strconv.Itoa(comment.ParentId)
+		}
+		listing.Rows = append(listing.Rows, []string{strconv.Itoa(comment.CommentId), replyTo, author,
+			indent(level) + text, comment.CreatedAt.Format(timeLayout), edited})
+		if depth > 0 && level >= depth {
+			if record.MoreReplies = countReplies(comment); record.MoreReplies > 0 {
+				listing.Rows = append(listing.Rows, []string{"", strconv.Itoa(comment.CommentId), "",
+					indent(level+1) + fmt.Sprintf("(%d more %s)", record.MoreReplies, plural(record.MoreReplies, "reply", "replies")), "", ""})
+			}
+			return record
+		}
+		for _, reply := range comment.Replies {
+			record.Replies = append(record.Replies, walk(reply, level+1))
+		}
+		return record
+	}
+	for _, thread := range threads {
+		listing.Records = append(listing.Records, walk(thread, 0))
+	}
+	return listing
+}
+
+// indent marks how deep a comment sits in its thread.
+func indent(level int) string {
+	if level == 0 {
+		return ""
+	}
+	return strings.Repeat("  ", level-1) + "↳ "
+}
+
+func countReplies(comment *models.Comment) int {
+	count := len(comment.Replies)
+	for _, reply := range comment.Replies {
+		count += countReplies(reply)
+	}
+	return count
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
+
+// Notifications shows what each notification links to as "post #3".
+func Notifications(notifications []*models.Notification) Listing {
+	listing := Listing{
+		Columns: []string{"Id", "Type", "About", "Message", "Read", "Created At"},
+		Records: make([]any, 0, len(notifications)),
+	}
+	for _, notification := range notifications {
+		about := ""
+		if notification.SourceType != "" {
+			about = fmt.Sprintf("%s #%d", notification.SourceType, notification.SourceId)
+		}
+		listing.Rows = append(listing.Rows, []string{strconv.Itoa(notification.Id), notification.Type, about,
+			notification.Message, yesNo(notification.IsRead), notification.CreatedAt.Format(timeLayout)})
+		listing.Records = append(listing.Records, notificationRecord{Id: notification.Id, Type: notification.Type,
+			SourceType: notification.SourceType, SourceId: notification.SourceId, Message: notification.Message,
+			Read: notification.IsRead, CreatedAt: notification.CreatedAt})
+	}
+	return listing
+}
+
+// Subscriptions calls a subscription to a user one to an "author".
+func Subscriptions(subscriptions []*models.Subscription) Listing {
+	listing := Listing{
+		Columns: []string{"Kind", "Name"},
+		Records: make([]any, 0, len(subscriptions)),
+	}
+	for _, subscription := range subscriptions {
+		kind := subscription.Kind
+		if kind == models.TargetUser {
+			kind = "author"
+		}
+		listing.Rows = append(listing.Rows, []string{kind, subscription.Name})
+		listing.Records = append(listing.Records, subscriptionRecord{Kind: kind, Id: subscription.TargetId, Name: subscription.Name})
+	}
+	return listing
+}
+
+// AuditLog shows the snapshots as they are stored in the tabular formats and
+// nests them as JSON objects in the JSON formats.
+func AuditLog(entries []*models.AuditEntry) Listing {
+	listing := Listing{
+		Columns: []string{"Id", "Time", "Actor", "Action", "Target", "Before", "After"},
+		Records: make([]any, 0, len(entries)),
+	}
+	for _, entry := range entries {
+		listing.Rows = append(listing.Rows, []string{strconv.Itoa(entry.Id), entry.CreatedAt.Format(timeLayout),
+			fmt.Sprintf("%s (#%d)", entry.ActorName, entry.ActorId), entry.Action,
+			fmt.Sprintf("%s #%d", entry.TargetType, entry.TargetId), entry.Before, entry.After})
+		record := auditRecord{Id: entry.Id, ActorId: entry.ActorId, ActorName: entry.ActorName, Action: entry.Action,
+			TargetType: entry.TargetType, TargetId: entry.TargetId, CreatedAt: entry.CreatedAt}
+		if entry.Before != "" {
+			record.Before = json.RawMessage(entry.Before)
+		}
+		if entry.After != "" {
+			record.After = json.RawMessage(entry.After)
+		}
+		listing.Records = append(listing.Records, record)
+	}
+	return listing
+}
+
+// formatAnswer renders one answer as "#id author (time): text"; answers moved
+// over from the old replies column have no recorded author.
+func formatAnswer(answer *models.Answer) string {
+	author := answer.Username
+	if author == "" {
+		author = "anonymous"
+	}
+	line := fmt.Sprintf("#%d %s (%s): %s", answer.AnswerId, author, answer.CreatedAt.Format("2006-01-02 15:04"), answer.Text)
+	if !answer.UpdatedAt.IsZero() {
+		line += " (edited)"
+	}
+	return line
+}
+
+// location shows a post's neighbourhood and coordinates, whichever it has.
+func location(location models.Location) string {
+	var parts []string
+	if location.Neighbourhood != "" {
+		parts = append(parts, location.Neighbourhood)
+	}
+	if location.HasCoordinates() {
+		parts = append(parts, fmt.Sprintf("(%.5f, %.5f)", *location.Latitude, *location.Longitude))
+	}
+	return strings.Join(parts, " ")
+}
+
+func yesNo(value bool) string {
+	if value {
+		return "Yes"
+	}
+	return "No"
+}
@@ -0,0 +1,124 @@
+// Package render prints listings of users, posts and questions in one of
+// several output formats, so the same listing can go to a terminal, another
+// program or a spreadsheet.
+package render
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/olekukonko/tablewriter"
+	"io"
+	"strings"
+)
+
+type Format string
+
+const (
+	Table  Format = "table"
+	JSON   Format = "json"
+	NDJSON Format = "ndjson"
+	CSV    Format = "csv"
+)
+
+var Formats = []Format{Table, JSON, NDJSON, CSV}
+
+func ParseFormat(name string) (Format, error) {
+	for _, format := range Formats {
+		if strings.EqualFold(name, string(format)) {
+			return format, nil
+		}
+	}
+	return "", fmt.Errorf("unknown output format %q, use one of table, json, ndjson or csv", name)
+}
+
+// String and Set make *Format usable as a flag.Value.
+func (f *Format) String() string {
+	return string(*f)
+}
+
+func (f *Format) Set(value string) error {
+	format, err := ParseFormat(value)
+	if err != nil {
+		return err
+	}
+	*f = format
+	return nil
+}
+
+// Listing is what gets rendered: the tabular formats print Columns and Rows,
+// the JSON formats print Records, which keep nested data such as answers.
+type Listing struct {
+	Columns []string
+	Rows    [][]string
+	Records []any
+}
+
+type Renderer interface {
+	Render(w io.Writer, listing Listing) error
+}
+
+// New returns the renderer for format, falling back to a table.
+func New(format Format) Renderer {
+	switch format {
+	case JSON:
+		return JSONRenderer{}
+	case NDJSON:
+		return NDJSONRenderer{}
+	case CSV:
+		return CSVRenderer{}
+	default:
+		return TableRenderer{}
+	}
+}
+
+// TableRenderer draws a bordered ASCII table for people to read.
+type TableRenderer struct{}
+
+func (TableRenderer) Render(w io.Writer, listing Listing) error {
+	table := tablewriter.NewWriter(w)
+	table.SetHeader(listing.Columns)
+	table.AppendBulk(listing.Rows)
+	table.Render()
+	return nil
+}
+
+// JSONRenderer writes the records as one indented JSON array.
+type JSONRenderer struct{}
+
+func (JSONRenderer) Render(w io.Writer, listing Listing) error {
+	records := listing.Records
+	if records == nil {
+		records = []any{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(records)
+}
+
+// NDJSONRenderer writes one compact JSON record per line.
+type NDJSONRenderer struct{}
+
+func (NDJSONRenderer) Render(w io.Writer, listing Listing) error {
+	encoder := json.NewEncoder(w)
+	for _, record := range listing.Records {
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CSVRenderer writes a header line followed by one line per row.
+type CSVRenderer struct{}
+
+func (CSVRenderer) Render(w io.Writer, listing Listing) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(listing.Columns); err != nil {
+		return err
+	}
+	if err := writer.WriteAll(listing.Rows); err != nil {
+		return err
+	}
+	return writer.Error()
+}
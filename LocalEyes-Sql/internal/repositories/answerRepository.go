@@ -0,0 +1,195 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	_ "github.com/go-sql-driver/mysql"
+	"localEyes/config"
+	"localEyes/internal/models"
+	"localEyes/utils"
+	"strings"
+	"time"
+)
+
+type MySQLAnswerRepository struct {
+	DB DBTX
+}
+
+func NewMySQLAnswerRepository(Db DBTX) *MySQLAnswerRepository {
+	return &MySQLAnswerRepository{
+		DB: Db,
+	}
+}
+
+// answerColumns are selected with the author's username joined in; answers
+// copied from the old replies column have no author and come back with "".
+var answerColumns = []string{"a.answer_id", "a.q_id", "a.user_id", "COALESCE(u.username, '')", "a.text", "a.created_at", "a.updated_at"}
+
+func answerSelect(condition string) string {
+	query := fmt.Sprintf("SELECT %s FROM %s a LEFT JOIN %s u ON u.id = a.user_id", strings.Join(answerColumns, ", "), config.AnswerTable, config.UserTable)
+	if condition != "" {
+		query += " WHERE " + condition
+	}
+	return query + " ORDER BY a.answer_id"
+}
+
+// Create stores the answer only if its question exists, so a stale QId is
+// reported instead of leaving an orphaned answer behind.
+func (r *MySQLAnswerRepository) Create(answer *models.Answer) error {
+	query := fmt.Sprintf("INSERT INTO %s (q_id, user_id, text, created_at) SELECT q_id, ?, ?, ? FROM %s WHERE q_id = ?", config.AnswerTable, config.QuestionTable)
+	//query := "INSERT INTO answers (q_id, user_id, text, created_at) SELECT q_id, ?, ?, ? FROM questions WHERE q_id = ?"
+	result, err := r.DB.Exec(query, answer.UserId, answer.Text, answer.CreatedAt, answer.QId)
+	if err != nil {
+		return err
+	}
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affectedRows == 0 {
+		return models.NewError(models.ErrNotFound, "No Question exist with this id")
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	answer.AnswerId = int(id)
+	return nil
+}
+
+func (r *MySQLAnswerRepository) GetAnswersByQId(QId int) ([]*models.Answer, error) {
+	query := answerSelect("a.q_id = ?")
+	//query := "SELECT a.answer_id, ... FROM answers a LEFT JOIN users u ON u.id = a.user_id WHERE a.q_id = ? ORDER BY a.answer_id"
+	return r.queryAnswers(query, QId)
+}
+
+func (r *MySQLAnswerRepository) GetAnswersByPId(PId int) ([]*models.Answer, error) {
+	query := answerSelect(fmt.Sprintf("a.q_id IN (SELECT q_id FROM %s WHERE post_id = ?)", config.QuestionTable))
+	//query := "SELECT a.answer_id, ... FROM answers a LEFT JOIN users u ON u.id = a.user_id WHERE a.q_id IN (SELECT q_id FROM questions WHERE post_id = ?) ORDER BY a.answer_id"
+	return r.queryAnswers(query, PId)
+}
+
+func (r *MySQLAnswerRepository) GetAllAnswers() ([]*models.Answer, error) {
+	query := answerSelect("")
+	//query := "SELECT a.answer_id, ... FROM answers a LEFT JOIN users u ON u.id = a.user_id ORDER BY a.answer_id"
+	return r.queryAnswers(query)
+}
+
+// FindByAnswerId returns sql.ErrNoRows when there is no such answer.
+func (r *MySQLAnswerRepository) FindByAnswerId(AnswerId int) (*models.Answer, error) {
+	query := answerSelect("a.answer_id = ?")
+	//query := "SELECT a.answer_id, ... FROM answers a LEFT JOIN users u ON u.id = a.user_id WHERE a.answer_id = ? ORDER BY a.answer_id"
+	answers, err := r.queryAnswers(query, AnswerId)
+	if err != nil {
+		return nil, err
+	}
+	if len(answers) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return answers[0], nil
+}
+
+func (r *MySQLAnswerRepository) queryAnswers(query string, args ...any) ([]*models.Answer, error) {
+	rows, err := r.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			utils.Logger.Println("ERROR: Error closing rows:", err)
+		}
+	}(rows)
+
+	var answers []*models.Answer
+	for rows.Next() {
+		var answer models.Answer
+		var createdAt string
+		var updatedAt sql.NullString
+		if err := rows.Scan(&answer.AnswerId, &answer.QId, &answer.UserId, &answer.Username, &answer.Text, &createdAt, &updatedAt); err != nil {
+			return nil, err
+		}
+		if answer.CreatedAt, err = parseTimestamp(createdAt); err != nil {
+			return nil, err
+		}
+		if updatedAt.Valid && updatedAt.String != "" {
+			if answer.UpdatedAt, err = parseTimestamp(updatedAt.String); err != nil {
+				return nil, err
+			}
+		}
+		answers = append(answers, &answer)
+	}
+	return answers, rows.Err()
+}
+
+func (r *MySQLAnswerRepository) UpdateUserAnswer(AnswerId, UId int, text string) error {
+	columns := []string{"text", "updated_at"}
+	query := config.UpdateQuery(config.AnswerTable, "answer_id", "user_id", columns)
+	//query := "UPDATE answers SET text = ?, updated_at = ? WHERE answer_id = ? AND user_id = ?"
+	result, err := r.DB.Exec(query, text, time.Now(), AnswerId, UId)
+	if err != nil {
+		return err
+	}
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affectedRows == 0 {
+		return models.NewError(models.ErrForbidden, "You can only update your answer")
+	}
+	return nil
+}
+
+func (r *MySQLAnswerRepository) DeleteByAnswerIdUId(AnswerId, UId int) error {
+	query := config.DeleteQuery(config.AnswerTable, "answer_id", "user_id")
+	//query := "DELETE FROM answers WHERE answer_id = ? AND user_id = ?"
+	return r.deleteOne(query, AnswerId, UId)
+}
+
+func (r *MySQLAnswerRepository) DeleteByAnswerId(AnswerId int) error {
+	query := config.DeleteQuery(config.AnswerTable, "answer_id", "")
+	//query := "DELETE FROM answers WHERE answer_id = ?"
+	return r.deleteOne(query, AnswerId)
+}
+
+func (r *MySQLAnswerRepository) deleteOne(query string, args ...any) error {
+	result, err := r.DB.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affectedRows == 0 {
+		return models.NewError(models.ErrNotFound, "No Answer exist with this id")
+	}
+	return nil
+}
+
+// DeleteByQId removes every answer to a question; none is not an error.
+func (r *MySQLAnswerRepository) DeleteByQId(QId int) error {
+	query := config.DeleteQuery(config.AnswerTable, "q_id", "")
+	//query := "DELETE FROM answers WHERE q_id = ?"
+	_, err := r.DB.Exec(query, QId)
+	return err
+}
+
+// DeleteByPId removes the answers to every question asked on a post.
+func (r *MySQLAnswerRepository) DeleteByPId(PId int) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE q_id IN (SELECT q_id FROM %s WHERE post_id = ?)", config.AnswerTable, config.QuestionTable)
+	//query := "DELETE FROM answers WHERE q_id IN (SELECT q_id FROM questions WHERE post_id = ?)"
+	_, err := r.DB.Exec(query, PId)
+	return err
+}
+
+// DeleteForUser removes everything answer-related that goes away with a user:
+// the answers they wrote, answers to their questions and answers to questions
+// on their posts.
+func (r *MySQLAnswerRepository) DeleteForUser(UId int) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE user_id = ? OR q_id IN (SELECT q_id FROM %s WHERE user_id = ? OR post_id IN (SELECT post_id FROM %s WHERE user_id = ?))",
+		config.AnswerTable, config.QuestionTable, config.PostTable)
+	//query := "DELETE FROM answers WHERE user_id = ? OR q_id IN (SELECT q_id FROM questions WHERE user_id = ? OR post_id IN (SELECT post_id FROM posts WHERE user_id = ?))"
+	_, err := r.DB.Exec(query, UId, UId, UId)
+	return err
+}
@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	_ "github.com/go-sql-driver/mysql"
+	"localEyes/config"
+	"localEyes/internal/models"
+	"localEyes/utils"
+	"strings"
+	"time"
+)
+
+type MySQLAuditRepository struct {
+	DB DBTX
+}
+
+func NewMySQLAuditRepository(Db DBTX) *MySQLAuditRepository {
+	return &MySQLAuditRepository{
+		DB: Db,
+	}
+}
+
+var auditColumns = []string{"id", "actor_id", "actor_name", "action", "target_type", "target_id", "before_state", "after_state", "created_at"}
+
+func (r *MySQLAuditRepository) Create(entry *models.AuditEntry) error {
+	query := config.InsertQuery(config.AuditTable, auditColumns[1:])
+	//query := "INSERT INTO audit_log (actor_id, actor_name, action, target_type, target_id, before_state, after_state, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)"
+	result, err := r.DB.Exec(query, entry.ActorId, entry.ActorName, entry.Action, entry.TargetType, entry.TargetId,
+		entry.Before, entry.After, entry.CreatedAt.Unix())
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	entry.Id = int(id)
+	return nil
+}
+
+// Find returns the matching entries, oldest first.
+func (r *MySQLAuditRepository) Find(filter models.AuditFilter) ([]*models.AuditEntry, error) {
+	var conditions []string
+	var args []any
+	if filter.ActorId != 0 {
+		conditions = append(conditions, "actor_id = ?")
+		args = append(args, filter.ActorId)
+	}
+	if filter.TargetType != "" {
+		conditions = append(conditions, "target_type = ?")
+		args = append(args, filter.TargetType)
+	}
+	if filter.TargetId != 0 {
+		conditions = append(conditions, "target_id = ?")
+		args = append(args, filter.TargetId)
+	}
+	if !filter.From.IsZero() {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, filter.From.Unix())
+	}
+	if !filter.To.IsZero() {
+		conditions = append(conditions, "created_at <= ?")
+		args = append(args, filter.To.Unix())
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s", strings.Join(auditColumns, ", "), config.AuditTable)
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY id"
+	//query := "SELECT id, actor_id, ... FROM audit_log WHERE actor_id = ? AND ... ORDER BY id"
+	rows, err := r.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			utils.Logger.Println("ERROR: Error closing rows:", err)
+		}
+	}(rows)
+
+	var entries []*models.AuditEntry
+	for rows.Next() {
+		var entry models.AuditEntry
+		var createdAt int64
+		if err := rows.Scan(&entry.Id, &entry.ActorId, &entry.ActorName, &entry.Action, &entry.TargetType, &entry.TargetId,
+			&entry.Before, &entry.After, &createdAt); err != nil {
+			return nil, err
+		}
+		entry.CreatedAt = time.Unix(createdAt, 0)
+		entries = append(entries, &entry)
+	}
+	return entries, rows.Err()
+}
@@ -0,0 +1,117 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	_ "github.com/go-sql-driver/mysql"
+	"localEyes/config"
+	"localEyes/internal/models"
+	"localEyes/utils"
+	"strings"
+)
+
+type MySQLCategoryRepository struct {
+	DB DBTX
+}
+
+func NewMySQLCategoryRepository(Db DBTX) *MySQLCategoryRepository {
+	return &MySQLCategoryRepository{
+		DB: Db,
+	}
+}
+
+var categoryColumns = []string{"id", "name", "description", "is_active"}
+
+func (r *MySQLCategoryRepository) Create(category *models.Category) error {
+	query := config.InsertQuery(config.CategoryTable, categoryColumns[1:])
+	//query := "INSERT INTO categories (name, description, is_active) VALUES (?, ?, ?)"
+	result, err := r.DB.Exec(query, category.Name, category.Description, category.IsActive)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	category.Id = int(id)
+	return nil
+}
+
+// FindById returns sql.ErrNoRows when there is no such category.
+func (r *MySQLCategoryRepository) FindById(id int) (*models.Category, error) {
+	query := config.SelectQuery(config.CategoryTable, "id", "", categoryColumns)
+	//query := "SELECT id, name, description, is_active FROM categories WHERE id = ?"
+	return r.scanCategory(r.DB.QueryRow(query, id))
+}
+
+// FindByName returns sql.ErrNoRows when there is no such category.
+func (r *MySQLCategoryRepository) FindByName(name string) (*models.Category, error) {
+	query := config.SelectQuery(config.CategoryTable, "name", "", categoryColumns)
+	//query := "SELECT id, name, description, is_active FROM categories WHERE name = ?"
+	return r.scanCategory(r.DB.QueryRow(query, name))
+}
+
+func (r *MySQLCategoryRepository) scanCategory(row *sql.Row) (*models.Category, error) {
+	var category models.Category
+	err := row.Scan(&category.Id, &category.Name, &category.Description, &category.IsActive)
+	if err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// GetAll returns every category, active or not, by name.
+func (r *MySQLCategoryRepository) GetAll() ([]*models.Category, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s ORDER BY name", strings.Join(categoryColumns, ", "), config.CategoryTable)
+	//query := "SELECT id, name, description, is_active FROM categories ORDER BY name"
+	rows, err := r.DB.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			utils.Logger.Println("ERROR: Error closing rows:", err)
+		}
+	}(rows)
+	var categories []*models.Category
+	for rows.Next() {
+		var category models.Category
+		if err := rows.Scan(&category.Id, &category.Name, &category.Description, &category.IsActive); err != nil {
+			return nil, err
+		}
+		categories = append(categories, &category)
+	}
+	return categories, rows.Err()
+}
+
+func (r *MySQLCategoryRepository) Update(category *models.Category) error {
+	query := config.UpdateQuery(config.CategoryTable, "id", "", categoryColumns[1:])
+	//query := "UPDATE categories SET name = ?, description = ?, is_active = ? WHERE id = ?"
+	result, err := r.DB.Exec(query, category.Name, category.Description, category.IsActive, category.Id)
+	if err != nil {
+		return err
+	}
+	return categoryAffected(result)
+}
+
+func (r *MySQLCategoryRepository) DeleteById(id int) error {
+	query := config.DeleteQuery(config.CategoryTable, "id", "")
+	//query := "DELETE FROM categories WHERE id = ?"
+	result, err := r.DB.Exec(query, id)
+	if err != nil {
+		return err
+	}
+	return categoryAffected(result)
+}
+
+func categoryAffected(result sql.Result) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return models.NewError(models.ErrNotFound, "No category exist with this id")
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	_ "github.com/go-sql-driver/mysql"
+	"localEyes/config"
+	"localEyes/internal/models"
+	"localEyes/utils"
+)
+
+type MySQLCityRepository struct {
+	DB DBTX
+}
+
+func NewMySQLCityRepository(Db DBTX) *MySQLCityRepository {
+	return &MySQLCityRepository{
+		DB: Db,
+	}
+}
+
+func (r *MySQLCityRepository) Create(city *models.City) error {
+	query := config.InsertQuery(config.CityTable, []string{"name"})
+	//query := "INSERT INTO cities (name) VALUES (?)"
+	result, err := r.DB.Exec(query, city.Name)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	city.Id = int(id)
+	return nil
+}
+
+// FindByName returns sql.ErrNoRows when there is no such city.
+func (r *MySQLCityRepository) FindByName(name string) (*models.City, error) {
+	query := config.SelectQuery(config.CityTable, "name", "", []string{"id", "name"})
+	//query := "SELECT id, name FROM cities WHERE name = ?"
+	var city models.City
+	if err := r.DB.QueryRow(query, name).Scan(&city.Id, &city.Name); err != nil {
+		return nil, err
+	}
+	return &city, nil
+}
+
+// GetAll returns every city by name.
+func (r *MySQLCityRepository) GetAll() ([]*models.City, error) {
+	query := fmt.Sprintf("SELECT id, name FROM %s ORDER BY name", config.CityTable)
+	//query := "SELECT id, name FROM cities ORDER BY name"
+	rows, err := r.DB.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			utils.Logger.Println("ERROR: Error closing rows:", err)
+		}
+	}(rows)
+	var cities []*models.City
+	for rows.Next() {
+		var city models.City
+		if err := rows.Scan(&city.Id, &city.Name); err != nil {
+			return nil, err
+		}
+		cities = append(cities, &city)
+	}
+	return cities, rows.Err()
+}
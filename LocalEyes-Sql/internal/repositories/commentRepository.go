@@ -0,0 +1,202 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	_ "github.com/go-sql-driver/mysql"
+	"localEyes/config"
+	"localEyes/internal/models"
+	"localEyes/utils"
+	"strings"
+	"time"
+)
+
+type MySQLCommentRepository struct {
+	DB DBTX
+}
+
+func NewMySQLCommentRepository(Db DBTX) *MySQLCommentRepository {
+	return &MySQLCommentRepository{
+		DB: Db,
+	}
+}
+
+// commentColumns are selected with the author's username joined in, which
+// is "" once the author's account is gone.
+var commentColumns = []string{"c.comment_id", "c.post_id", "c.parent_id", "c.user_id", "COALESCE(u.username, '')", "c.text",
+	"c.created_at", "c.updated_at", "c.deleted"}
+
+func commentSelect(condition string) string {
+	return fmt.Sprintf("SELECT %s FROM %s c LEFT JOIN %s u ON u.id = c.user_id WHERE %s ORDER BY c.comment_id",
+		strings.Join(commentColumns, ", "), config.CommentTable, config.UserTable, condition)
+}
+
+// Create stores the comment only if its post exists, so a stale PId is
+// reported instead of leaving an orphaned comment behind. Checking the
+// parent is up to the caller.
+func (r *MySQLCommentRepository) Create(comment *models.Comment) error {
+	query := fmt.Sprintf("INSERT INTO %s (post_id, parent_id, user_id, text, created_at) SELECT post_id, ?, ?, ?, ? FROM %s WHERE post_id = ?",
+		config.CommentTable, config.PostTable)
+	//query := "INSERT INTO comments (post_id, parent_id, user_id, text, created_at) SELECT post_id, ?, ?, ?, ? FROM posts WHERE post_id = ?"
+	result, err := r.DB.Exec(query, comment.ParentId, comment.UserId, comment.Text, comment.CreatedAt, comment.PostId)
+	if err != nil {
+		return err
+	}
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affectedRows == 0 {
+		return models.NewError(models.ErrNotFound, "No Post exist with this id")
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	comment.CommentId = int(id)
+	return nil
+}
+
+// FindByCommentId returns sql.ErrNoRows when there is no such comment.
+func (r *MySQLCommentRepository) FindByCommentId(CommentId int) (*models.Comment, error) {
+	query := commentSelect("c.comment_id = ?")
+	//query := "SELECT c.comment_id, ... FROM comments c LEFT JOIN users u ON u.id = c.user_id WHERE c.comment_id = ? ORDER BY c.comment_id"
+	comments, err := r.queryComments(query, CommentId)
+	if err != nil {
+		return nil, err
+	}
+	if len(comments) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return comments[0], nil
+}
+
+// GetCommentsByPId returns every comment on the post, flat and oldest first,
+// so each reply comes after the comment it answers.
+func (r *MySQLCommentRepository) GetCommentsByPId(PId int) ([]*models.Comment, error) {
+	query := commentSelect("c.post_id = ?")
+	//query := "SELECT c.comment_id, ... FROM comments c LEFT JOIN users u ON u.id = c.user_id WHERE c.post_id = ? ORDER BY c.comment_id"
+	return r.queryComments(query, PId)
+}
+
+func (r *MySQLCommentRepository) queryComments(query string, args ...any) ([]*models.Comment, error) {
+	rows, err := r.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			utils.Logger.Println("ERROR: Error closing rows:", err)
+		}
+	}(rows)
+
+	var comments []*models.Comment
+	for rows.Next() {
+		var comment models.Comment
+		var createdAt string
+		var updatedAt sql.NullString
+		if err := rows.Scan(&comment.CommentId, &comment.PostId, &comment.ParentId, &comment.UserId, &comment.Username, &comment.Text,
+			&createdAt, &updatedAt, &comment.Deleted); err != nil {
+			return nil, err
+		}
+		if comment.CreatedAt, err = parseTimestamp(createdAt); err != nil {
+			return nil, err
+		}
+		if updatedAt.Valid && updatedAt.String != "" {
+			if comment.UpdatedAt, err = parseTimestamp(updatedAt.String); err != nil {
+				return nil, err
+			}
+		}
+		comments = append(comments, &comment)
+	}
+	return comments, rows.Err()
+}
+
+func (r *MySQLCommentRepository) CountReplies(CommentId int) (int, error) {
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE parent_id = ?", config.CommentTable)
+	//query := "SELECT COUNT(*) FROM comments WHERE parent_id = ?"
+	var count int
+	err := r.DB.QueryRow(query, CommentId).Scan(&count)
+	return count, err
+}
+
+// UpdateUserComment edits the text of one of the user's comments that has
+// not been deleted.
+func (r *MySQLCommentRepository) UpdateUserComment(CommentId, UId int, text string) error {
+	query := fmt.Sprintf("UPDATE %s SET text = ?, updated_at = ? WHERE comment_id = ? AND user_id = ? AND deleted = ?", config.CommentTable)
+	//query := "UPDATE comments SET text = ?, updated_at = ? WHERE comment_id = ? AND user_id = ? AND deleted = ?"
+	result, err := r.DB.Exec(query, text, time.Now(), CommentId, UId, false)
+	if err != nil {
+		return err
+	}
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affectedRows == 0 {
+		return models.NewError(models.ErrForbidden, "You can only update your comment")
+	}
+	return nil
+}
+
+// MarkDeleted turns the comment into a placeholder, keeping its place in the
+// thread but not its text.
+func (r *MySQLCommentRepository) MarkDeleted(CommentId int) error {
+	columns := []string{"text", "deleted", "updated_at"}
+	query := config.UpdateQuery(config.CommentTable, "comment_id", "", columns)
+	//query := "UPDATE comments SET text = ?, deleted = ?, updated_at = ? WHERE comment_id = ?"
+	return r.changeOne(query, "", true, time.Now(), CommentId)
+}
+
+func (r *MySQLCommentRepository) DeleteByCommentId(CommentId int) error {
+	query := config.DeleteQuery(config.CommentTable, "comment_id", "")
+	//query := "DELETE FROM comments WHERE comment_id = ?"
+	return r.changeOne(query, CommentId)
+}
+
+func (r *MySQLCommentRepository) changeOne(query string, args ...any) error {
+	result, err := r.DB.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affectedRows == 0 {
+		return models.NewError(models.ErrNotFound, "No Comment exist with this id")
+	}
+	return nil
+}
+
+// DeleteByPId removes a post's whole discussion; none is not an error.
+func (r *MySQLCommentRepository) DeleteByPId(PId int) error {
+	query := config.DeleteQuery(config.CommentTable, "post_id", "")
+	//query := "DELETE FROM comments WHERE post_id = ?"
+	_, err := r.DB.Exec(query, PId)
+	return err
+}
+
+// DeleteForUser removes the discussions on the user's posts and the user's
+// comments elsewhere. Comments others have replied to are only marked
+// deleted, so those replies stay in their threads.
+func (r *MySQLCommentRepository) DeleteForUser(UId int) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE post_id IN (SELECT post_id FROM %s WHERE user_id = ?)", config.CommentTable, config.PostTable)
+	//query := "DELETE FROM comments WHERE post_id IN (SELECT post_id FROM posts WHERE user_id = ?)"
+	if _, err := r.DB.Exec(query, UId); err != nil {
+		return err
+	}
+	// MySQL cannot read the table it deletes from, hence the derived table.
+	query = fmt.Sprintf("DELETE FROM %s WHERE user_id = ? AND comment_id NOT IN (SELECT parent_id FROM (SELECT parent_id FROM %s) AS replied)",
+		config.CommentTable, config.CommentTable)
+	//query := "DELETE FROM comments WHERE user_id = ? AND comment_id NOT IN (SELECT parent_id FROM (SELECT parent_id FROM comments) AS replied)"
+	if _, err := r.DB.Exec(query, UId); err != nil {
+		return err
+	}
+	columns := []string{"text", "deleted"}
+	query = config.UpdateQuery(config.CommentTable, "user_id", "", columns)
+	//query := "UPDATE comments SET text = ?, deleted = ? WHERE user_id = ?"
+	_, err := r.DB.Exec(query, "", true, UId)
+	return err
+}
@@ -0,0 +1,196 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"localEyes/internal/models"
+	"sort"
+	"sync"
+	"time"
+)
+
+// InMemoryAnswerRepository is the map-backed counterpart of MySQLAnswerRepository.
+// Checks that span tables (question existence, authors' usernames, post
+// ownership) only work once NewInMemoryUnitOfWork has linked it to the
+// question and user repositories.
+type InMemoryAnswerRepository struct {
+	undoable
+	mu        sync.RWMutex
+	answers   map[int]*models.Answer
+	nextId    int
+	questions *InMemoryQuestionRepository
+	users     *InMemoryUserRepository
+}
+
+func NewInMemoryAnswerRepository() *InMemoryAnswerRepository {
+	return &InMemoryAnswerRepository{
+		answers: make(map[int]*models.Answer),
+		nextId:  1,
+	}
+}
+
+func (r *InMemoryAnswerRepository) Create(answer *models.Answer) error {
+	if r.questions != nil && !r.questions.exists(answer.QId) {
+		return models.NewError(models.ErrNotFound, "No Question exist with this id")
+	}
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	answer.AnswerId = r.nextId
+	r.nextId++
+	clone := *answer
+	clone.Username = ""
+	r.answers[answer.AnswerId] = &clone
+	return nil
+}
+
+func (r *InMemoryAnswerRepository) collect(keep func(answer *models.Answer) bool) []*models.Answer {
+	r.mu.RLock()
+	var answers []*models.Answer
+	for _, answer := range r.answers {
+		if keep(answer) {
+			clone := *answer
+			answers = append(answers, &clone)
+		}
+	}
+	r.mu.RUnlock()
+	sort.Slice(answers, func(i, j int) bool { return answers[i].AnswerId < answers[j].AnswerId })
+	for _, answer := range answers {
+		if r.users == nil {
+			continue
+		}
+		if user, err := r.users.FindByUId(answer.UserId); err == nil {
+			answer.Username = user.Username
+		}
+	}
+	return answers
+}
+
+func (r *InMemoryAnswerRepository) GetAnswersByQId(QId int) ([]*models.Answer, error) {
+	return r.collect(func(answer *models.Answer) bool { return answer.QId == QId }), nil
+}
+
+func (r *InMemoryAnswerRepository) GetAnswersByPId(PId int) ([]*models.Answer, error) {
+	qIds, err := r.questionIds(func(question *models.Question) bool { return question.PostId == PId })
+	if err != nil {
+		return nil, err
+	}
+	return r.collect(func(answer *models.Answer) bool { return qIds[answer.QId] }), nil
+}
+
+func (r *InMemoryAnswerRepository) GetAllAnswers() ([]*models.Answer, error) {
+	return r.collect(func(answer *models.Answer) bool { return true }), nil
+}
+
+func (r *InMemoryAnswerRepository) FindByAnswerId(AnswerId int) (*models.Answer, error) {
+	answers := r.collect(func(answer *models.Answer) bool { return answer.AnswerId == AnswerId })
+	if len(answers) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return answers[0], nil
+}
+
+func (r *InMemoryAnswerRepository) UpdateUserAnswer(AnswerId, UId int, text string) error {
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	answer, ok := r.answers[AnswerId]
+	if !ok || answer.UserId != UId {
+		return models.NewError(models.ErrForbidden, "You can only update your answer")
+	}
+	answer.Text = text
+	answer.UpdatedAt = time.Now()
+	return nil
+}
+
+func (r *InMemoryAnswerRepository) DeleteByAnswerIdUId(AnswerId, UId int) error {
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	answer, ok := r.answers[AnswerId]
+	if !ok || answer.UserId != UId {
+		return models.NewError(models.ErrNotFound, "No Answer exist with this id")
+	}
+	delete(r.answers, AnswerId)
+	return nil
+}
+
+func (r *InMemoryAnswerRepository) DeleteByAnswerId(AnswerId int) error {
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.answers[AnswerId]; !ok {
+		return models.NewError(models.ErrNotFound, "No Answer exist with this id")
+	}
+	delete(r.answers, AnswerId)
+	return nil
+}
+
+func (r *InMemoryAnswerRepository) deleteWhere(match func(answer *models.Answer) bool) {
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, answer := range r.answers {
+		if match(answer) {
+			delete(r.answers, id)
+		}
+	}
+}
+
+func (r *InMemoryAnswerRepository) DeleteByQId(QId int) error {
+	r.deleteWhere(func(answer *models.Answer) bool { return answer.QId == QId })
+	return nil
+}
+
+func (r *InMemoryAnswerRepository) DeleteByPId(PId int) error {
+	qIds, err := r.questionIds(func(question *models.Question) bool { return question.PostId == PId })
+	if err != nil {
+		return err
+	}
+	r.deleteWhere(func(answer *models.Answer) bool { return qIds[answer.QId] })
+	return nil
+}
+
+func (r *InMemoryAnswerRepository) DeleteForUser(UId int) error {
+	if r.questions == nil || r.questions.posts == nil {
+		return errors.New("in-memory answer repository is not linked to the question and post repositories")
+	}
+	owned, err := r.questions.posts.GetPostsByUId(UId)
+	if err != nil {
+		return err
+	}
+	postIds := make(map[int]bool, len(owned))
+	for _, post := range owned {
+		postIds[post.PostId] = true
+	}
+	qIds, err := r.questionIds(func(question *models.Question) bool {
+		return question.UserId == UId || postIds[question.PostId]
+	})
+	if err != nil {
+		return err
+	}
+	r.deleteWhere(func(answer *models.Answer) bool { return answer.UserId == UId || qIds[answer.QId] })
+	return nil
+}
+
+func (r *InMemoryAnswerRepository) questionIds(match func(question *models.Question) bool) (map[int]bool, error) {
+	if r.questions == nil {
+		return nil, errors.New("in-memory answer repository is not linked to a question repository")
+	}
+	r.questions.mu.RLock()
+	defer r.questions.mu.RUnlock()
+	qIds := make(map[int]bool)
+	for id, question := range r.questions.questions {
+		if match(question) {
+			qIds[id] = true
+		}
+	}
+	return qIds, nil
+}
+
+func (r *InMemoryQuestionRepository) exists(QId int) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.questions[QId]
+	return ok
+}
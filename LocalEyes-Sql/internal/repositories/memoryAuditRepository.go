@@ -0,0 +1,53 @@
+package repositories
+
+import (
+	"localEyes/internal/models"
+	"sync"
+	"time"
+)
+
+// InMemoryAuditRepository is the slice-backed counterpart of
+// MySQLAuditRepository; entries are only ever appended.
+type InMemoryAuditRepository struct {
+	undoable
+	mu      sync.RWMutex
+	entries []models.AuditEntry
+	nextId  int
+}
+
+func NewInMemoryAuditRepository() *InMemoryAuditRepository {
+	return &InMemoryAuditRepository{
+		nextId: 1,
+	}
+}
+
+func (r *InMemoryAuditRepository) Create(entry *models.AuditEntry) error {
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entry.Id = r.nextId
+	r.nextId++
+	clone := *entry
+	// Match the SQL repositories, which keep whole seconds.
+	clone.CreatedAt = clone.CreatedAt.Truncate(time.Second)
+	r.entries = append(r.entries, clone)
+	return nil
+}
+
+func (r *InMemoryAuditRepository) Find(filter models.AuditFilter) ([]*models.AuditEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var entries []*models.AuditEntry
+	for _, entry := range r.entries {
+		if filter.ActorId != 0 && entry.ActorId != filter.ActorId ||
+			filter.TargetType != "" && entry.TargetType != filter.TargetType ||
+			filter.TargetId != 0 && entry.TargetId != filter.TargetId ||
+			!filter.From.IsZero() && entry.CreatedAt.Before(filter.From.Truncate(time.Second)) ||
+			!filter.To.IsZero() && entry.CreatedAt.After(filter.To) {
+			continue
+		}
+		clone := entry
+		entries = append(entries, &clone)
+	}
+	return entries, nil
+}
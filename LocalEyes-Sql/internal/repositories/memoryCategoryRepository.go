@@ -0,0 +1,117 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"localEyes/internal/models"
+	"sort"
+	"sync"
+)
+
+// DefaultCategories are the categories the migrations seed, so the in-memory
+// store starts out the same as a fresh database.
+var DefaultCategories = []models.Category{
+	{Name: "food", Description: "Places to eat and dishes to try", IsActive: true},
+	{Name: "travel", Description: "Getting around and places to visit", IsActive: true},
+	{Name: "shopping", Description: "Markets, shops and deals", IsActive: true},
+	{Name: "other", Description: "Everything else", IsActive: true},
+}
+
+// InMemoryCategoryRepository is the map-backed counterpart of
+// MySQLCategoryRepository.
+type InMemoryCategoryRepository struct {
+	undoable
+	mu         sync.RWMutex
+	categories map[int]*models.Category
+	nextId     int
+}
+
+func NewInMemoryCategoryRepository() *InMemoryCategoryRepository {
+	r := &InMemoryCategoryRepository{
+		categories: make(map[int]*models.Category),
+		nextId:     1,
+	}
+	for _, category := range DefaultCategories {
+		_ = r.Create(&category)
+	}
+	return r
+}
+
+func (r *InMemoryCategoryRepository) Create(category *models.Category) error {
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.categories {
+		if existing.Name == category.Name {
+			return errors.New("duplicate category name " + category.Name)
+		}
+	}
+	category.Id = r.nextId
+	r.nextId++
+	clone := *category
+	r.categories[category.Id] = &clone
+	return nil
+}
+
+func (r *InMemoryCategoryRepository) FindById(id int) (*models.Category, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	category, ok := r.categories[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	clone := *category
+	return &clone, nil
+}
+
+func (r *InMemoryCategoryRepository) FindByName(name string) (*models.Category, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, category := range r.categories {
+		if category.Name == name {
+			clone := *category
+			return &clone, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *InMemoryCategoryRepository) GetAll() ([]*models.Category, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	categories := make([]*models.Category, 0, len(r.categories))
+	for _, category := range r.categories {
+		clone := *category
+		categories = append(categories, &clone)
+	}
+	sort.Slice(categories, func(i, j int) bool { return categories[i].Name < categories[j].Name })
+	return categories, nil
+}
+
+func (r *InMemoryCategoryRepository) Update(category *models.Category) error {
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.categories[category.Id]; !ok {
+		return models.NewError(models.ErrNotFound, "No category exist with this id")
+	}
+	for id, existing := range r.categories {
+		if id != category.Id && existing.Name == category.Name {
+			return errors.New("duplicate category name " + category.Name)
+		}
+	}
+	clone := *category
+	r.categories[category.Id] = &clone
+	return nil
+}
+
+func (r *InMemoryCategoryRepository) DeleteById(id int) error {
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.categories[id]; !ok {
+		return models.NewError(models.ErrNotFound, "No category exist with this id")
+	}
+	delete(r.categories, id)
+	return nil
+}
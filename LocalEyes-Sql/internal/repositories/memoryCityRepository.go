@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"localEyes/internal/models"
+	"sort"
+	"sync"
+)
+
+// DefaultCity is the city the migrations seed, the one LocalEyes started in.
+const DefaultCity = "delhi"
+
+// InMemoryCityRepository is the map-backed counterpart of
+// MySQLCityRepository, starting out with DefaultCity like a fresh database.
+type InMemoryCityRepository struct {
+	undoable
+	mu     sync.RWMutex
+	cities map[int]*models.City
+	nextId int
+}
+
+func NewInMemoryCityRepository() *InMemoryCityRepository {
+	r := &InMemoryCityRepository{
+		cities: make(map[int]*models.City),
+		nextId: 1,
+	}
+	_ = r.Create(&models.City{Name: DefaultCity})
+	return r
+}
+
+func (r *InMemoryCityRepository) Create(city *models.City) error {
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.cities {
+		if existing.Name == city.Name {
+			return errors.New("duplicate city name " + city.Name)
+		}
+	}
+	city.Id = r.nextId
+	r.nextId++
+	clone := *city
+	r.cities[city.Id] = &clone
+	return nil
+}
+
+func (r *InMemoryCityRepository) FindByName(name string) (*models.City, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, city := range r.cities {
+		if city.Name == name {
+			clone := *city
+			return &clone, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *InMemoryCityRepository) GetAll() ([]*models.City, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	cities := make([]*models.City, 0, len(r.cities))
+	for _, city := range r.cities {
+		clone := *city
+		cities = append(cities, &clone)
+	}
+	sort.Slice(cities, func(i, j int) bool { return cities[i].Name < cities[j].Name })
+	return cities, nil
+}
@@ -0,0 +1,184 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"localEyes/internal/models"
+	"sort"
+	"sync"
+	"time"
+)
+
+// InMemoryCommentRepository is the map-backed counterpart of
+// MySQLCommentRepository. Checking the post exists, filling in usernames and
+// finding a user's posts need the post and user repositories, which
+// NewInMemoryUnitOfWork links.
+type InMemoryCommentRepository struct {
+	undoable
+	mu       sync.RWMutex
+	comments map[int]*models.Comment
+	nextId   int
+	posts    *InMemoryPostRepository
+	users    *InMemoryUserRepository
+}
+
+func NewInMemoryCommentRepository() *InMemoryCommentRepository {
+	return &InMemoryCommentRepository{
+		comments: make(map[int]*models.Comment),
+		nextId:   1,
+	}
+}
+
+func (r *InMemoryCommentRepository) Create(comment *models.Comment) error {
+	if r.posts != nil {
+		if posts, _ := r.posts.GetPostsByPId(comment.PostId); len(posts) == 0 {
+			return models.NewError(models.ErrNotFound, "No Post exist with this id")
+		}
+	}
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	comment.CommentId = r.nextId
+	r.nextId++
+	clone := *comment
+	clone.Username = ""
+	clone.Replies = nil
+	r.comments[comment.CommentId] = &clone
+	return nil
+}
+
+// collect returns copies of the comments matching keep in id order, with
+// their authors' usernames.
+func (r *InMemoryCommentRepository) collect(keep func(comment *models.Comment) bool) []*models.Comment {
+	r.mu.RLock()
+	var comments []*models.Comment
+	for _, comment := range r.comments {
+		if keep(comment) {
+			clone := *comment
+			comments = append(comments, &clone)
+		}
+	}
+	r.mu.RUnlock()
+	sort.Slice(comments, func(i, j int) bool { return comments[i].CommentId < comments[j].CommentId })
+	for _, comment := range comments {
+		if r.users == nil {
+			continue
+		}
+		if user, err := r.users.FindByUId(comment.UserId); err == nil {
+			comment.Username = user.Username
+		}
+	}
+	return comments
+}
+
+func (r *InMemoryCommentRepository) FindByCommentId(CommentId int) (*models.Comment, error) {
+	comments := r.collect(func(comment *models.Comment) bool { return comment.CommentId == CommentId })
+	if len(comments) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return comments[0], nil
+}
+
+func (r *InMemoryCommentRepository) GetCommentsByPId(PId int) ([]*models.Comment, error) {
+	return r.collect(func(comment *models.Comment) bool { return comment.PostId == PId }), nil
+}
+
+func (r *InMemoryCommentRepository) CountReplies(CommentId int) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	count := 0
+	for _, comment := range r.comments {
+		if comment.ParentId == CommentId {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *InMemoryCommentRepository) UpdateUserComment(CommentId, UId int, text string) error {
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	comment, ok := r.comments[CommentId]
+	if !ok || comment.UserId != UId || comment.Deleted {
+		return models.NewError(models.ErrForbidden, "You can only update your comment")
+	}
+	comment.Text = text
+	comment.UpdatedAt = time.Now()
+	return nil
+}
+
+func (r *InMemoryCommentRepository) MarkDeleted(CommentId int) error {
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	comment, ok := r.comments[CommentId]
+	if !ok {
+		return models.NewError(models.ErrNotFound, "No Comment exist with this id")
+	}
+	comment.Text = ""
+	comment.Deleted = true
+	comment.UpdatedAt = time.Now()
+	return nil
+}
+
+func (r *InMemoryCommentRepository) DeleteByCommentId(CommentId int) error {
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.comments[CommentId]; !ok {
+		return models.NewError(models.ErrNotFound, "No Comment exist with this id")
+	}
+	delete(r.comments, CommentId)
+	return nil
+}
+
+func (r *InMemoryCommentRepository) DeleteByPId(PId int) error {
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, comment := range r.comments {
+		if comment.PostId == PId {
+			delete(r.comments, id)
+		}
+	}
+	return nil
+}
+
+func (r *InMemoryCommentRepository) DeleteForUser(UId int) error {
+	if r.posts == nil {
+		return errors.New("in-memory comment repository is not linked to a post repository")
+	}
+	owned, err := r.posts.GetPostsByUId(UId)
+	if err != nil {
+		return err
+	}
+	postIds := make(map[int]bool, len(owned))
+	for _, post := range owned {
+		postIds[post.PostId] = true
+	}
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, comment := range r.comments {
+		if postIds[comment.PostId] {
+			delete(r.comments, id)
+		}
+	}
+	replied := make(map[int]bool)
+	for _, comment := range r.comments {
+		replied[comment.ParentId] = true
+	}
+	for id, comment := range r.comments {
+		if comment.UserId != UId {
+			continue
+		}
+		if replied[id] {
+			comment.Text = ""
+			comment.Deleted = true
+		} else {
+			delete(r.comments, id)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package repositories
+
+import (
+	"localEyes/internal/models"
+	"sort"
+	"sync"
+)
+
+// InMemoryNotificationPrefsRepository is the map-backed counterpart of
+// MySQLNotificationPrefsRepository. Naming subscriptions needs the user and
+// category repositories, which NewInMemoryUnitOfWork links.
+type InMemoryNotificationPrefsRepository struct {
+	undoable
+	mu            sync.RWMutex
+	prefs         map[int]*models.NotificationPrefs
+	subscriptions map[models.Subscription]bool
+	users         *InMemoryUserRepository
+	categories    *InMemoryCategoryRepository
+}
+
+func NewInMemoryNotificationPrefsRepository() *InMemoryNotificationPrefsRepository {
+	return &InMemoryNotificationPrefsRepository{
+		prefs:         make(map[int]*models.NotificationPrefs),
+		subscriptions: make(map[models.Subscription]bool),
+	}
+}
+
+func (r *InMemoryNotificationPrefsRepository) FindByUId(UId int) (*models.NotificationPrefs, error) {
+	r.mu.RLock()
+	prefs := &models.NotificationPrefs{UserId: UId, Delivery: models.DeliverImmediately, Posts: models.PostsAll}
+	if saved, ok := r.prefs[UId]; ok {
+		*prefs = *saved
+	}
+	var subscriptions []*models.Subscription
+	for key := range r.subscriptions {
+		if key.UserId == UId {
+			clone := key
+			subscriptions = append(subscriptions, &clone)
+		}
+	}
+	r.mu.RUnlock()
+	for _, subscription := range subscriptions {
+		subscription.Name = r.name(subscription.Kind, subscription.TargetId)
+	}
+	sort.Slice(subscriptions, func(i, j int) bool {
+		if subscriptions[i].Kind != subscriptions[j].Kind {
+			return subscriptions[i].Kind < subscriptions[j].Kind
+		}
+		return subscriptions[i].Name < subscriptions[j].Name
+	})
+	prefs.Subscriptions = subscriptions
+	return prefs, nil
+}
+
+// name looks up the category or author a subscription follows, or "" when
+// it is gone or the repository is not linked.
+func (r *InMemoryNotificationPrefsRepository) name(kind string, targetId int) string {
+	if kind == models.TargetCategory && r.categories != nil {
+		if category, err := r.categories.FindById(targetId); err == nil {
+			return category.Name
+		}
+	}
+	if kind == models.TargetUser && r.users != nil {
+		if user, err := r.users.FindByUId(targetId); err == nil {
+			return user.Username
+		}
+	}
+	return ""
+}
+
+func (r *InMemoryNotificationPrefsRepository) Save(prefs *models.NotificationPrefs) error {
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	clone := *prefs
+	clone.Subscriptions = nil
+	r.prefs[prefs.UserId] = &clone
+	return nil
+}
+
+func (r *InMemoryNotificationPrefsRepository) Subscribe(subscription *models.Subscription) error {
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := models.Subscription{UserId: subscription.UserId, Kind: subscription.Kind, TargetId: subscription.TargetId}
+	if r.subscriptions[key] {
+		return models.NewError(models.ErrConflict, "You are already subscribed to that "+subscriptionNoun(subscription.Kind))
+	}
+	r.subscriptions[key] = true
+	return nil
+}
+
+func (r *InMemoryNotificationPrefsRepository) Unsubscribe(UId int, kind string, targetId int) error {
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := models.Subscription{UserId: UId, Kind: kind, TargetId: targetId}
+	if !r.subscriptions[key] {
+		return models.NewError(models.ErrConflict, "You are not subscribed to that "+subscriptionNoun(kind))
+	}
+	delete(r.subscriptions, key)
+	return nil
+}
+
+func (r *InMemoryNotificationPrefsRepository) DeleteForUser(UId int) error {
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.prefs, UId)
+	for key := range r.subscriptions {
+		if key.UserId == UId || key.Kind == models.TargetUser && key.TargetId == UId {
+			delete(r.subscriptions, key)
+		}
+	}
+	return nil
+}
+
+func (r *InMemoryNotificationPrefsRepository) DeleteSubscriptionsTo(kind string, targetId int) error {
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for key := range r.subscriptions {
+		if key.Kind == kind && key.TargetId == targetId {
+			delete(r.subscriptions, key)
+		}
+	}
+	return nil
+}
+
+// follows reports whether the user subscribes to the post's category or
+// author.
+func (r *InMemoryNotificationPrefsRepository) follows(UId int, post *models.Post) bool {
+	if r.categories != nil {
+		if category, err := r.categories.FindByName(post.Type); err == nil {
+			if r.subscribed(models.Subscription{UserId: UId, Kind: models.TargetCategory, TargetId: category.Id}) {
+				return true
+			}
+		}
+	}
+	return r.subscribed(models.Subscription{UserId: UId, Kind: models.TargetUser, TargetId: post.UId})
+}
+
+func (r *InMemoryNotificationPrefsRepository) subscribed(key models.Subscription) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.subscriptions[key]
+}
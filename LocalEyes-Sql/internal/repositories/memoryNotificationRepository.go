@@ -0,0 +1,211 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"localEyes/internal/models"
+	"sort"
+	"sync"
+)
+
+// InMemoryNotificationRepository is the map-backed counterpart of
+// MySQLNotificationRepository. Sending out a new post needs the user and
+// notification preference repositories, which NewInMemoryUnitOfWork links.
+type InMemoryNotificationRepository struct {
+	undoable
+	mu            sync.RWMutex
+	notifications map[int]*models.Notification
+	nextId        int
+	users         *InMemoryUserRepository
+	prefs         *InMemoryNotificationPrefsRepository
+}
+
+func NewInMemoryNotificationRepository() *InMemoryNotificationRepository {
+	return &InMemoryNotificationRepository{
+		notifications: make(map[int]*models.Notification),
+		nextId:        1,
+	}
+}
+
+func (r *InMemoryNotificationRepository) Create(notification *models.Notification) error {
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.add(notification)
+	return nil
+}
+
+// add stores a copy of notification under the next id; r.mu must be held.
+func (r *InMemoryNotificationRepository) add(notification *models.Notification) {
+	notification.Id = r.nextId
+	r.nextId++
+	clone := *notification
+	r.notifications[clone.Id] = &clone
+}
+
+func (r *InMemoryNotificationRepository) CreateForPost(notification *models.Notification, post *models.Post) error {
+	if r.users == nil || r.prefs == nil {
+		return errors.New("in-memory notification repository is not linked to the user and preference repositories")
+	}
+	users, err := r.users.GetAllUsers()
+	if err != nil {
+		return err
+	}
+	var recipients []*models.Notification
+	for _, user := range users {
+		if user.UId == post.UId || user.City != post.City {
+			continue
+		}
+		prefs, err := r.prefs.FindByUId(user.UId)
+		if err != nil {
+			return err
+		}
+		if prefs.Delivery == models.DeliverOff || prefs.Posts == models.PostsNone ||
+			prefs.Posts == models.PostsSubscribed && !r.prefs.follows(user.UId, post) {
+			continue
+		}
+		clone := *notification
+		clone.UserId = user.UId
+		clone.Held = prefs.Delivery == models.DeliverDigest
+		recipients = append(recipients, &clone)
+	}
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, recipient := range recipients {
+		r.add(recipient)
+	}
+	return nil
+}
+
+func (r *InMemoryNotificationRepository) FindByNotificationId(Id int) (*models.Notification, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	notification, ok := r.notifications[Id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	clone := *notification
+	return &clone, nil
+}
+
+func (r *InMemoryNotificationRepository) GetUserNotifications(UId int, unreadOnly bool, order string, limit, offset int) ([]*models.Notification, error) {
+	if _, ok := notificationOrders[order]; !ok {
+		return nil, unknownOrder(order)
+	}
+	r.mu.RLock()
+	var notifications []*models.Notification
+	for _, notification := range r.notifications {
+		if notification.UserId != UId || notification.Held || unreadOnly && notification.IsRead {
+			continue
+		}
+		clone := *notification
+		notifications = append(notifications, &clone)
+	}
+	r.mu.RUnlock()
+	sort.Slice(notifications, func(i, j int) bool {
+		if order == models.SortOldest {
+			return notifications[i].Id < notifications[j].Id
+		}
+		return notifications[i].Id > notifications[j].Id
+	})
+	return slicePage(notifications, limit, offset), nil
+}
+
+func (r *InMemoryNotificationRepository) CountUnread(UId int) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	count := 0
+	for _, notification := range r.notifications {
+		if notification.UserId == UId && !notification.IsRead && !notification.Held {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *InMemoryNotificationRepository) MarkRead(UId int, Ids []int) error {
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, id := range Ids {
+		if notification, ok := r.notifications[id]; ok && notification.UserId == UId {
+			notification.IsRead = true
+		}
+	}
+	return nil
+}
+
+func (r *InMemoryNotificationRepository) MarkAllRead(UId int) error {
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, notification := range r.notifications {
+		if notification.UserId == UId && !notification.Held {
+			notification.IsRead = true
+		}
+	}
+	return nil
+}
+
+func (r *InMemoryNotificationRepository) DeleteForUser(UId int) error {
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, notification := range r.notifications {
+		if notification.UserId == UId {
+			delete(r.notifications, id)
+		}
+	}
+	return nil
+}
+
+func (r *InMemoryNotificationRepository) GetHeld(UId int) ([]*models.Notification, error) {
+	r.mu.RLock()
+	var notifications []*models.Notification
+	for _, notification := range r.notifications {
+		if notification.UserId == UId && notification.Held {
+			clone := *notification
+			notifications = append(notifications, &clone)
+		}
+	}
+	r.mu.RUnlock()
+	sort.Slice(notifications, func(i, j int) bool { return notifications[i].Id < notifications[j].Id })
+	return notifications, nil
+}
+
+func (r *InMemoryNotificationRepository) Release(UId int) error {
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, notification := range r.notifications {
+		if notification.UserId == UId && notification.Held {
+			notification.Held = false
+			notification.IsRead = true
+		}
+	}
+	return nil
+}
+
+func (r *InMemoryNotificationRepository) GetSince(afterId, limit int) ([]*models.Notification, error) {
+	r.mu.RLock()
+	var notifications []*models.Notification
+	for _, notification := range r.notifications {
+		if notification.Id > afterId && !notification.Held {
+			clone := *notification
+			notifications = append(notifications, &clone)
+		}
+	}
+	r.mu.RUnlock()
+	sort.Slice(notifications, func(i, j int) bool { return notifications[i].Id < notifications[j].Id })
+	if len(notifications) > limit {
+		notifications = notifications[:limit]
+	}
+	return notifications, nil
+}
+
+func (r *InMemoryNotificationRepository) LastId() (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.nextId - 1, nil
+}
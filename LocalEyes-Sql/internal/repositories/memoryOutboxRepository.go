@@ -0,0 +1,151 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"localEyes/internal/models"
+	"sort"
+	"sync"
+	"time"
+)
+
+// InMemoryOutboxRepository is the map-backed counterpart of
+// MySQLOutboxRepository. Finding a user's posts needs the post repository,
+// which NewInMemoryUnitOfWork links.
+type InMemoryOutboxRepository struct {
+	undoable
+	mu      sync.RWMutex
+	entries map[int]*models.OutboxEntry
+	nextId  int
+	posts   *InMemoryPostRepository
+}
+
+func NewInMemoryOutboxRepository() *InMemoryOutboxRepository {
+	return &InMemoryOutboxRepository{
+		entries: make(map[int]*models.OutboxEntry),
+		nextId:  1,
+	}
+}
+
+func (r *InMemoryOutboxRepository) Create(entry *models.OutboxEntry) error {
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entry.Id = r.nextId
+	r.nextId++
+	clone := *entry
+	r.entries[clone.Id] = &clone
+	return nil
+}
+
+func (r *InMemoryOutboxRepository) FindById(Id int) (*models.OutboxEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	entry, ok := r.entries[Id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	clone := *entry
+	return &clone, nil
+}
+
+func (r *InMemoryOutboxRepository) GetDue(now time.Time, limit int) ([]*models.OutboxEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var due []*models.OutboxEntry
+	for _, entry := range r.entries {
+		if !entry.Dead && !entry.AvailableAt.After(now) {
+			clone := *entry
+			due = append(due, &clone)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].Id < due[j].Id })
+	if len(due) > limit {
+		due = due[:limit]
+	}
+	return due, nil
+}
+
+func (r *InMemoryOutboxRepository) Claim(entry *models.OutboxEntry, until time.Time) (bool, error) {
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.entries[entry.Id]
+	if !ok || stored.Dead || stored.Attempts != entry.Attempts {
+		return false, nil
+	}
+	stored.Attempts++
+	stored.AvailableAt = until
+	entry.Attempts = stored.Attempts
+	entry.AvailableAt = until
+	return true, nil
+}
+
+func (r *InMemoryOutboxRepository) Retry(Id int, at time.Time, lastError string) error {
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if entry, ok := r.entries[Id]; ok {
+		entry.AvailableAt = at
+		entry.LastError = lastError
+	}
+	return nil
+}
+
+func (r *InMemoryOutboxRepository) MarkDead(Id int, lastError string) error {
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if entry, ok := r.entries[Id]; ok {
+		entry.Dead = true
+		entry.LastError = lastError
+	}
+	return nil
+}
+
+func (r *InMemoryOutboxRepository) Complete(entry *models.OutboxEntry) (bool, error) {
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.entries[entry.Id]
+	if !ok || stored.Attempts != entry.Attempts {
+		return false, nil
+	}
+	delete(r.entries, entry.Id)
+	return true, nil
+}
+
+func (r *InMemoryOutboxRepository) DeleteByPId(PId int) error {
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, entry := range r.entries {
+		if entry.PostId == PId {
+			delete(r.entries, id)
+		}
+	}
+	return nil
+}
+
+func (r *InMemoryOutboxRepository) DeleteForUser(UId int) error {
+	if r.posts == nil {
+		return errors.New("in-memory outbox repository is not linked to a post repository")
+	}
+	owned, err := r.posts.GetPostsByUId(UId)
+	if err != nil {
+		return err
+	}
+	postIds := make(map[int]bool, len(owned))
+	for _, post := range owned {
+		postIds[post.PostId] = true
+	}
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, entry := range r.entries {
+		if postIds[entry.PostId] {
+			delete(r.entries, id)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package repositories
+
+import (
+	"errors"
+	"localEyes/internal/models"
+	"sort"
+	"sync"
+)
+
+type postLike struct {
+	UId int
+	PId int
+}
+
+// InMemoryPostLikeRepository is the map-backed counterpart of
+// MySQLPostLikeRepository. Like the SQL version it needs the post to exist,
+// which it can only check once NewInMemoryUnitOfWork has linked it to the
+// post repository.
+type InMemoryPostLikeRepository struct {
+	undoable
+	mu    sync.RWMutex
+	likes map[postLike]bool
+	posts *InMemoryPostRepository
+}
+
+func NewInMemoryPostLikeRepository() *InMemoryPostLikeRepository {
+	return &InMemoryPostLikeRepository{
+		likes: make(map[postLike]bool),
+	}
+}
+
+func (r *InMemoryPostLikeRepository) Create(UId, PId int) error {
+	if r.posts != nil {
+		posts, err := r.posts.GetPostsByPId(PId)
+		if err != nil {
+			return err
+		}
+		if len(posts) == 0 {
+			return models.NewError(models.ErrConflict, "You have already liked this post")
+		}
+	}
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := postLike{UId: UId, PId: PId}
+	if r.likes[key] {
+		return models.NewError(models.ErrConflict, "You have already liked this post")
+	}
+	r.likes[key] = true
+	return nil
+}
+
+func (r *InMemoryPostLikeRepository) Delete(UId, PId int) error {
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := postLike{UId: UId, PId: PId}
+	if !r.likes[key] {
+		return models.NewError(models.ErrConflict, "You have not liked this post")
+	}
+	delete(r.likes, key)
+	return nil
+}
+
+func (r *InMemoryPostLikeRepository) GetLikedPIds(UId int) ([]int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var pIds []int
+	for key := range r.likes {
+		if key.UId == UId {
+			pIds = append(pIds, key.PId)
+		}
+	}
+	sort.Ints(pIds)
+	return pIds, nil
+}
+
+func (r *InMemoryPostLikeRepository) DeleteByPId(PId int) error {
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for key := range r.likes {
+		if key.PId == PId {
+			delete(r.likes, key)
+		}
+	}
+	return nil
+}
+
+func (r *InMemoryPostLikeRepository) DeleteForUser(UId int) error {
+	if r.posts == nil {
+		return errors.New("in-memory like repository is not linked to a post repository")
+	}
+	owned, err := r.posts.GetPostsByUId(UId)
+	if err != nil {
+		return err
+	}
+	postIds := make(map[int]bool, len(owned))
+	for _, post := range owned {
+		postIds[post.PostId] = true
+	}
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for key := range r.likes {
+		if key.UId == UId || postIds[key.PId] {
+			delete(r.likes, key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,260 @@
+package repositories
+
+import (
+	"errors"
+	"localEyes/internal/models"
+	"sort"
+	"sync"
+)
+
+// InMemoryPostRepository is the map-backed counterpart of MySQLPostRepository.
+// RemoveUserLikes needs the like repository and sorting by most questions
+// the question repository, which NewInMemoryUnitOfWork links.
+type InMemoryPostRepository struct {
+	undoable
+	mu        sync.RWMutex
+	posts     map[int]*models.Post
+	nextId    int
+	likes     *InMemoryPostLikeRepository
+	questions *InMemoryQuestionRepository
+}
+
+func NewInMemoryPostRepository() *InMemoryPostRepository {
+	return &InMemoryPostRepository{
+		posts:  make(map[int]*models.Post),
+		nextId: 1,
+	}
+}
+
+func (r *InMemoryPostRepository) Create(post *models.Post) error {
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	post.PostId = r.nextId
+	r.nextId++
+	r.posts[post.PostId] = clonePost(post)
+	return nil
+}
+
+// clonePost copies post down to its coordinates, so neither side can move
+// the other's location.
+func clonePost(post *models.Post) *models.Post {
+	clone := *post
+	if post.Latitude != nil {
+		latitude := *post.Latitude
+		clone.Latitude = &latitude
+	}
+	if post.Longitude != nil {
+		longitude := *post.Longitude
+		clone.Longitude = &longitude
+	}
+	return &clone
+}
+
+// collect returns copies of the posts matching keep, ordered by id like the
+// insertion order MySQL returns them in.
+func (r *InMemoryPostRepository) collect(keep func(post *models.Post) bool) []*models.Post {
+	var posts []*models.Post
+	for _, post := range r.posts {
+		if keep(post) {
+			posts = append(posts, clonePost(post))
+		}
+	}
+	sort.Slice(posts, func(i, j int) bool { return posts[i].PostId < posts[j].PostId })
+	return posts
+}
+
+func (r *InMemoryPostRepository) GetAllPosts() ([]*models.Post, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.collect(func(post *models.Post) bool { return true }), nil
+}
+
+func (r *InMemoryPostRepository) ListPosts(filter models.PostFilter, order string, limit, offset int) ([]*models.Post, error) {
+	// Count before taking the lock, as the question repository reads posts.
+	var questionCounts map[int]int
+	if order == models.SortMostQuestions && r.questions != nil {
+		questionCounts = r.questions.countByPost()
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	posts := r.collect(func(post *models.Post) bool {
+		return (filter.Type == "" || post.Type == filter.Type) && (filter.UId == 0 || post.UId == filter.UId) &&
+			(filter.City == "" || post.City == filter.City) &&
+			(filter.Neighbourhood == "" || post.Neighbourhood == filter.Neighbourhood)
+	})
+	var before func(a, b *models.Post) bool
+	switch order {
+	case models.SortNewest:
+		before = func(a, b *models.Post) bool { return a.PostId > b.PostId }
+	case models.SortOldest:
+		before = func(a, b *models.Post) bool { return a.PostId < b.PostId }
+	case models.SortMostLiked:
+		before = func(a, b *models.Post) bool {
+			return a.Likes > b.Likes || a.Likes == b.Likes && a.PostId > b.PostId
+		}
+	case models.SortMostQuestions:
+		before = func(a, b *models.Post) bool {
+			countA, countB := questionCounts[a.PostId], questionCounts[b.PostId]
+			return countA > countB || countA == countB && a.PostId > b.PostId
+		}
+	default:
+		return nil, unknownOrder(order)
+	}
+	sort.Slice(posts, func(i, j int) bool { return before(posts[i], posts[j]) })
+	return slicePage(posts, limit, offset), nil
+}
+
+func (r *InMemoryPostRepository) PostsInArea(area models.Area) ([]*models.Post, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.collect(func(post *models.Post) bool {
+		return post.HasCoordinates() &&
+			*post.Latitude >= area.MinLatitude && *post.Latitude <= area.MaxLatitude &&
+			*post.Longitude >= area.MinLongitude && *post.Longitude <= area.MaxLongitude
+	}), nil
+}
+
+// SearchPosts scores posts like the SQLite LIKE fallback does.
+func (r *InMemoryPostRepository) SearchPosts(city, search string, limit, offset int) ([]*models.Post, error) {
+	terms := searchTerms(search)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	scores := make(map[int]int)
+	posts := r.collect(func(post *models.Post) bool {
+		if city != "" && post.City != city {
+			return false
+		}
+		scores[post.PostId] = containsScore(terms, 2, post.Title) + containsScore(terms, 1, post.Content)
+		return scores[post.PostId] > 0
+	})
+	sort.Slice(posts, func(i, j int) bool {
+		a, b := posts[i], posts[j]
+		return scores[a.PostId] > scores[b.PostId] || scores[a.PostId] == scores[b.PostId] && a.PostId > b.PostId
+	})
+	return slicePage(posts, limit, offset), nil
+}
+
+func (r *InMemoryPostRepository) DeleteByPId(PId int) error {
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.posts[PId]; !ok {
+		return models.NewError(models.ErrNotFound, "No Post exist with this id")
+	}
+	delete(r.posts, PId)
+	return nil
+}
+
+func (r *InMemoryPostRepository) DeleteByUIdPId(UId, PId int) error {
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	post, ok := r.posts[PId]
+	if !ok || post.UId != UId {
+		return models.NewError(models.ErrNotFound, "No Post exist with this id")
+	}
+	delete(r.posts, PId)
+	return nil
+}
+
+func (r *InMemoryPostRepository) DeleteByUId(UId int) error {
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, post := range r.posts {
+		if post.UId == UId {
+			delete(r.posts, id)
+		}
+	}
+	return nil
+}
+
+func (r *InMemoryPostRepository) GetPostsByFilter(filter string) ([]*models.Post, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.collect(func(post *models.Post) bool { return post.Type == filter }), nil
+}
+
+func (r *InMemoryPostRepository) GetPostsByUId(UId int) ([]*models.Post, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.collect(func(post *models.Post) bool { return post.UId == UId }), nil
+}
+
+func (r *InMemoryPostRepository) GetPostsByPId(PId int) ([]*models.Post, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.collect(func(post *models.Post) bool { return post.PostId == PId }), nil
+}
+
+func (r *InMemoryPostRepository) UpdateUserPost(PId, UId int, title, content string) error {
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	post, ok := r.posts[PId]
+	if !ok || post.UId != UId || (post.Title == title && post.Content == content) {
+		return models.NewError(models.ErrForbidden, "You can only update your post")
+	}
+	post.Title = title
+	post.Content = content
+	return nil
+}
+
+func (r *InMemoryPostRepository) UpdateType(from, to string) error {
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, post := range r.posts {
+		if post.Type == from {
+			post.Type = to
+		}
+	}
+	return nil
+}
+
+func (r *InMemoryPostRepository) UpdateLike(PId int) error {
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	post, ok := r.posts[PId]
+	if !ok {
+		return models.NewError(models.ErrNotFound, "No post exist with this id")
+	}
+	post.Likes++
+	return nil
+}
+
+func (r *InMemoryPostRepository) RemoveLike(PId int) error {
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	post, ok := r.posts[PId]
+	if !ok || post.Likes == 0 {
+		return models.NewError(models.ErrNotFound, "No post exist with this id")
+	}
+	post.Likes--
+	return nil
+}
+
+func (r *InMemoryPostRepository) RemoveUserLikes(UId int) error {
+	if r.likes == nil {
+		return errors.New("in-memory post repository is not linked to a like repository")
+	}
+	liked, err := r.likes.GetLikedPIds(UId)
+	if err != nil {
+		return err
+	}
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, pId := range liked {
+		if post, ok := r.posts[pId]; ok && post.Likes > 0 {
+			post.Likes--
+		}
+	}
+	return nil
+}
@@ -0,0 +1,215 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"localEyes/internal/models"
+	"sort"
+	"sync"
+)
+
+// InMemoryQuestionRepository is the map-backed counterpart of MySQLQuestionRepository.
+// Searching answers needs the answer repository, which NewInMemoryUnitOfWork links.
+type InMemoryQuestionRepository struct {
+	undoable
+	mu        sync.RWMutex
+	questions map[int]*models.Question
+	nextId    int
+	posts     *InMemoryPostRepository
+	answers   *InMemoryAnswerRepository
+}
+
+func NewInMemoryQuestionRepository() *InMemoryQuestionRepository {
+	return &InMemoryQuestionRepository{
+		questions: make(map[int]*models.Question),
+		nextId:    1,
+	}
+}
+
+func copyQuestion(question *models.Question) *models.Question {
+	clone := *question
+	clone.Replies = append([]string{}, question.Replies...)
+	return &clone
+}
+
+func (r *InMemoryQuestionRepository) Create(question *models.Question) error {
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	question.QId = r.nextId
+	r.nextId++
+	r.questions[question.QId] = copyQuestion(question)
+	return nil
+}
+
+func (r *InMemoryQuestionRepository) collect(keep func(question *models.Question) bool) []*models.Question {
+	var questions []*models.Question
+	for _, question := range r.questions {
+		if keep(question) {
+			questions = append(questions, copyQuestion(question))
+		}
+	}
+	sort.Slice(questions, func(i, j int) bool { return questions[i].QId < questions[j].QId })
+	return questions
+}
+
+func (r *InMemoryQuestionRepository) GetAllQuestions() ([]*models.Question, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.collect(func(question *models.Question) bool { return true }), nil
+}
+
+func (r *InMemoryQuestionRepository) ListQuestions(city, order string, limit, offset int) ([]*models.Question, error) {
+	// Read the posts before taking the lock, as the post repository reads questions.
+	var inCity map[int]bool
+	if city != "" {
+		if r.posts == nil {
+			return nil, errors.New("in-memory question repository is not linked to a post repository")
+		}
+		posts, err := r.posts.GetAllPosts()
+		if err != nil {
+			return nil, err
+		}
+		inCity = make(map[int]bool)
+		for _, post := range posts {
+			inCity[post.PostId] = post.City == city
+		}
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	questions := r.collect(func(question *models.Question) bool { return city == "" || inCity[question.PostId] })
+	switch order {
+	case models.SortNewest:
+		sort.Slice(questions, func(i, j int) bool { return questions[i].QId > questions[j].QId })
+	case models.SortOldest:
+	default:
+		return nil, unknownOrder(order)
+	}
+	return slicePage(questions, limit, offset), nil
+}
+
+// SearchQuestions scores questions like the SQLite LIKE fallback does.
+func (r *InMemoryQuestionRepository) SearchQuestions(search string, limit, offset int) ([]*models.Question, error) {
+	terms := searchTerms(search)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+	// Read the answers before taking the lock, as the answer repository reads questions.
+	answerTexts := make(map[int]string)
+	if r.answers != nil {
+		answers, err := r.answers.GetAllAnswers()
+		if err != nil {
+			return nil, err
+		}
+		for _, answer := range answers {
+			answerTexts[answer.QId] += answer.Text + "\n"
+		}
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	scores := make(map[int]int)
+	questions := r.collect(func(question *models.Question) bool {
+		scores[question.QId] = containsScore(terms, 2, question.Text) + containsScore(terms, 1, answerTexts[question.QId])
+		return scores[question.QId] > 0
+	})
+	sort.Slice(questions, func(i, j int) bool {
+		a, b := questions[i], questions[j]
+		return scores[a.QId] > scores[b.QId] || scores[a.QId] == scores[b.QId] && a.QId > b.QId
+	})
+	return slicePage(questions, limit, offset), nil
+}
+
+// countByPost returns how many questions each post has.
+func (r *InMemoryQuestionRepository) countByPost() map[int]int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	counts := make(map[int]int)
+	for _, question := range r.questions {
+		counts[question.PostId]++
+	}
+	return counts
+}
+
+func (r *InMemoryQuestionRepository) GetQuestionsByPId(PId int) ([]*models.Question, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.collect(func(question *models.Question) bool { return question.PostId == PId }), nil
+}
+
+func (r *InMemoryQuestionRepository) FindByQId(QId int) (*models.Question, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	question, ok := r.questions[QId]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return copyQuestion(question), nil
+}
+
+func (r *InMemoryQuestionRepository) DeleteByQIdUId(QId, UId int) error {
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	question, ok := r.questions[QId]
+	if !ok || question.UserId != UId {
+		return models.NewError(models.ErrNotFound, "No Question exist with this id")
+	}
+	delete(r.questions, QId)
+	return nil
+}
+
+func (r *InMemoryQuestionRepository) deleteWhere(match func(question *models.Question) bool) {
+	for id, question := range r.questions {
+		if match(question) {
+			delete(r.questions, id)
+		}
+	}
+}
+
+func (r *InMemoryQuestionRepository) DeleteByPId(PId int) error {
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deleteWhere(func(question *models.Question) bool { return question.PostId == PId })
+	return nil
+}
+
+func (r *InMemoryQuestionRepository) DeleteByUId(UId int) error {
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deleteWhere(func(question *models.Question) bool { return question.UserId == UId })
+	return nil
+}
+
+// DeleteByPostOwner needs to know who owns each post, so it only works once
+// NewInMemoryUnitOfWork has linked this repository to the post repository.
+func (r *InMemoryQuestionRepository) DeleteByPostOwner(UId int) error {
+	if r.posts == nil {
+		return errors.New("in-memory question repository is not linked to a post repository")
+	}
+	owned, err := r.posts.GetPostsByUId(UId)
+	if err != nil {
+		return err
+	}
+	postIds := make(map[int]bool, len(owned))
+	for _, post := range owned {
+		postIds[post.PostId] = true
+	}
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deleteWhere(func(question *models.Question) bool { return postIds[question.PostId] })
+	return nil
+}
+
+func (r *InMemoryQuestionRepository) DeleteByQId(QId int) error {
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.questions[QId]; !ok {
+		return models.NewError(models.ErrNotFound, "No Question exist with this id")
+	}
+	delete(r.questions, QId)
+	return nil
+}
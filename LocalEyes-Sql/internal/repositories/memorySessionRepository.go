@@ -0,0 +1,76 @@
+package repositories
+
+import (
+	"database/sql"
+	"localEyes/internal/models"
+	"sync"
+	"time"
+)
+
+// InMemorySessionRepository is the map-backed counterpart of
+// MySQLSessionRepository, keyed by token hash.
+type InMemorySessionRepository struct {
+	undoable
+	mu       sync.RWMutex
+	sessions map[string]*models.Session
+}
+
+func NewInMemorySessionRepository() *InMemorySessionRepository {
+	return &InMemorySessionRepository{
+		sessions: make(map[string]*models.Session),
+	}
+}
+
+func (r *InMemorySessionRepository) Create(session *models.Session) error {
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	clone := *session
+	r.sessions[session.TokenHash] = &clone
+	return nil
+}
+
+func (r *InMemorySessionRepository) FindByTokenHash(tokenHash string) (*models.Session, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	session, ok := r.sessions[tokenHash]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	clone := *session
+	return &clone, nil
+}
+
+func (r *InMemorySessionRepository) DeleteByTokenHash(tokenHash string) error {
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.sessions, tokenHash)
+	return nil
+}
+
+func (r *InMemorySessionRepository) DeleteForUser(UId int) error {
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for tokenHash, session := range r.sessions {
+		if session.UserId == UId {
+			delete(r.sessions, tokenHash)
+		}
+	}
+	return nil
+}
+
+func (r *InMemorySessionRepository) DeleteExpired(now time.Time) (int64, error) {
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var deleted int64
+	for tokenHash, session := range r.sessions {
+		if !session.ExpiresAt.After(now) {
+			delete(r.sessions, tokenHash)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,323 @@
+package repositories
+
+import (
+	"localEyes/internal/interfaces"
+	"localEyes/internal/models"
+	"sync"
+)
+
+// InMemoryUnitOfWork gives the in-memory repositories all-or-nothing
+// semantics: each repository the work writes to is snapshot just before its
+// first write, and those snapshots are restored if the work fails. Units of
+// work are serialised, but plain repository calls made meanwhile are not
+// isolated from them.
+type InMemoryUnitOfWork struct {
+	mu            sync.Mutex
+	tracked       []*undoable
+	users         *InMemoryUserRepository
+	posts         *InMemoryPostRepository
+	questions     *InMemoryQuestionRepository
+	answers       *InMemoryAnswerRepository
+	likes         *InMemoryPostLikeRepository
+	sessions      *InMemorySessionRepository
+	audit         *InMemoryAuditRepository
+	categories    *InMemoryCategoryRepository
+	cities        *InMemoryCityRepository
+	comments      *InMemoryCommentRepository
+	notifications *InMemoryNotificationRepository
+	prefs         *InMemoryNotificationPrefsRepository
+	outbox        *InMemoryOutboxRepository
+}
+
+func NewInMemoryUnitOfWork(users *InMemoryUserRepository, posts *InMemoryPostRepository, questions *InMemoryQuestionRepository, answers *InMemoryAnswerRepository, likes *InMemoryPostLikeRepository, sessions *InMemorySessionRepository, audit *InMemoryAuditRepository, categories *InMemoryCategoryRepository, cities *InMemoryCityRepository, comments *InMemoryCommentRepository, notifications *InMemoryNotificationRepository, prefs *InMemoryNotificationPrefsRepository, outbox *InMemoryOutboxRepository) *InMemoryUnitOfWork {
+	questions.posts = posts
+	questions.answers = answers
+	answers.questions = questions
+	answers.users = users
+	likes.posts = posts
+	posts.likes = likes
+	posts.questions = questions
+	comments.posts = posts
+	comments.users = users
+	notifications.users = users
+	notifications.prefs = prefs
+	prefs.users = users
+	prefs.categories = categories
+	outbox.posts = posts
+	users.save = users.snapshot
+	posts.save = posts.snapshot
+	questions.save = questions.snapshot
+	answers.save = answers.snapshot
+	likes.save = likes.snapshot
+	sessions.save = sessions.snapshot
+	audit.save = audit.snapshot
+	categories.save = categories.snapshot
+	cities.save = cities.snapshot
+	comments.save = comments.snapshot
+	notifications.save = notifications.snapshot
+	prefs.save = prefs.snapshot
+	outbox.save = outbox.snapshot
+	return &InMemoryUnitOfWork{
+		tracked: []*undoable{&users.undoable, &posts.undoable, &questions.undoable, &answers.undoable, &likes.undoable, &sessions.undoable,
+			&audit.undoable, &categories.undoable, &cities.undoable, &comments.undoable, &notifications.undoable, &prefs.undoable, &outbox.undoable},
+		users:         users,
+		posts:         posts,
+		questions:     questions,
+		answers:       answers,
+		likes:         likes,
+		sessions:      sessions,
+		audit:         audit,
+		categories:    categories,
+		cities:        cities,
+		comments:      comments,
+		notifications: notifications,
+		prefs:         prefs,
+		outbox:        outbox,
+	}
+}
+
+func (u *InMemoryUnitOfWork) Do(fn func(repos interfaces.Repositories) error) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for _, repo := range u.tracked {
+		repo.track()
+	}
+	err := fn(interfaces.Repositories{Users: u.users, Posts: u.posts, Questions: u.questions, Answers: u.answers, Likes: u.likes, Sessions: u.sessions, Audit: u.audit, Categories: u.categories, Cities: u.cities, Comments: u.comments, Notifications: u.notifications, NotificationPrefs: u.prefs, Outbox: u.outbox})
+	for _, repo := range u.tracked {
+		if restore := repo.untrack(); restore != nil && err != nil {
+			restore()
+		}
+	}
+	return err
+}
+
+// undoable lets a unit of work snapshot an in-memory repository lazily:
+// while the work runs, the repository's first write calls save, and the
+// restore it returns is kept until the work ends.
+type undoable struct {
+	guard    sync.Mutex
+	save     func() (restore func())
+	tracking bool
+	restore  func()
+}
+
+// beforeWrite is called by every write before it takes the repository's lock.
+func (u *undoable) beforeWrite() {
+	u.guard.Lock()
+	defer u.guard.Unlock()
+	if u.tracking && u.restore == nil && u.save != nil {
+		u.restore = u.save()
+	}
+}
+
+func (u *undoable) track() {
+	u.guard.Lock()
+	defer u.guard.Unlock()
+	u.tracking = true
+	u.restore = nil
+}
+
+// untrack stops tracking and returns the restore for the snapshot taken, or
+// nil when the repository was not written to.
+func (u *undoable) untrack() func() {
+	u.guard.Lock()
+	defer u.guard.Unlock()
+	restore := u.restore
+	u.tracking = false
+	u.restore = nil
+	return restore
+}
+
+func (r *InMemoryUserRepository) snapshot() (restore func()) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	users := make(map[int]*models.User, len(r.users))
+	for id, user := range r.users {
+		users[id] = copyUser(user)
+	}
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.users = users
+	}
+}
+
+func (r *InMemoryPostRepository) snapshot() (restore func()) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	posts := make(map[int]*models.Post, len(r.posts))
+	for id, post := range r.posts {
+		clone := *post
+		posts[id] = &clone
+	}
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.posts = posts
+	}
+}
+
+func (r *InMemoryQuestionRepository) snapshot() (restore func()) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	questions := make(map[int]*models.Question, len(r.questions))
+	for id, question := range r.questions {
+		questions[id] = copyQuestion(question)
+	}
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.questions = questions
+	}
+}
+
+func (r *InMemoryAnswerRepository) snapshot() (restore func()) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	answers := make(map[int]*models.Answer, len(r.answers))
+	for id, answer := range r.answers {
+		clone := *answer
+		answers[id] = &clone
+	}
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.answers = answers
+	}
+}
+
+func (r *InMemoryPostLikeRepository) snapshot() (restore func()) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	likes := make(map[postLike]bool, len(r.likes))
+	for key := range r.likes {
+		likes[key] = true
+	}
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.likes = likes
+	}
+}
+
+func (r *InMemorySessionRepository) snapshot() (restore func()) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	sessions := make(map[string]*models.Session, len(r.sessions))
+	for tokenHash, session := range r.sessions {
+		clone := *session
+		sessions[tokenHash] = &clone
+	}
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.sessions = sessions
+	}
+}
+
+// The audit log is append-only, so its length is snapshot enough.
+func (r *InMemoryAuditRepository) snapshot() (restore func()) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	length := len(r.entries)
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.entries = r.entries[:length]
+	}
+}
+
+func (r *InMemoryCategoryRepository) snapshot() (restore func()) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	categories := make(map[int]*models.Category, len(r.categories))
+	for id, category := range r.categories {
+		clone := *category
+		categories[id] = &clone
+	}
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.categories = categories
+	}
+}
+
+func (r *InMemoryCityRepository) snapshot() (restore func()) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	cities := make(map[int]*models.City, len(r.cities))
+	for id, city := range r.cities {
+		clone := *city
+		cities[id] = &clone
+	}
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.cities = cities
+	}
+}
+
+func (r *InMemoryCommentRepository) snapshot() (restore func()) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	comments := make(map[int]*models.Comment, len(r.comments))
+	for id, comment := range r.comments {
+		clone := *comment
+		comments[id] = &clone
+	}
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.comments = comments
+	}
+}
+
+func (r *InMemoryNotificationRepository) snapshot() (restore func()) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	notifications := make(map[int]*models.Notification, len(r.notifications))
+	for id, notification := range r.notifications {
+		clone := *notification
+		notifications[id] = &clone
+	}
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.notifications = notifications
+	}
+}
+
+func (r *InMemoryNotificationPrefsRepository) snapshot() (restore func()) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	prefs := make(map[int]*models.NotificationPrefs, len(r.prefs))
+	for id, saved := range r.prefs {
+		clone := *saved
+		prefs[id] = &clone
+	}
+	subscriptions := make(map[models.Subscription]bool, len(r.subscriptions))
+	for key := range r.subscriptions {
+		subscriptions[key] = true
+	}
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.prefs = prefs
+		r.subscriptions = subscriptions
+	}
+}
+
+func (r *InMemoryOutboxRepository) snapshot() (restore func()) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	entries := make(map[int]*models.OutboxEntry, len(r.entries))
+	for id, entry := range r.entries {
+		clone := *entry
+		entries[id] = &clone
+	}
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.entries = entries
+	}
+}
@@ -0,0 +1,173 @@
+package repositories
+
+import (
+	"database/sql"
+	"localEyes/internal/models"
+	"sort"
+	"sync"
+)
+
+// InMemoryUserRepository keeps users in a map guarded by a mutex. It mirrors
+// the MySQL repository's behaviour, including its "no rows affected" errors,
+// so it can stand in for a database in demos and tests.
+type InMemoryUserRepository struct {
+	undoable
+	mu     sync.RWMutex
+	users  map[int]*models.User
+	nextId int
+}
+
+func NewInMemoryUserRepository() *InMemoryUserRepository {
+	return &InMemoryUserRepository{
+		users:  make(map[int]*models.User),
+		nextId: 1,
+	}
+}
+
+func copyUser(user *models.User) *models.User {
+	clone := *user
+	clone.Notification = append([]string{}, user.Notification...)
+	return &clone
+}
+
+func (r *InMemoryUserRepository) Create(user *models.User) error {
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.users {
+		if existing.Username == user.Username {
+			return models.NewError(models.ErrConflict, "Username already taken")
+		}
+	}
+	user.UId = r.nextId
+	r.nextId++
+	if user.Role == "" {
+		user.Role = models.RoleUser
+	}
+	r.users[user.UId] = copyUser(user)
+	return nil
+}
+
+func (r *InMemoryUserRepository) FindByUId(UId int) (*models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	user, ok := r.users[UId]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return copyUser(user), nil
+}
+
+func (r *InMemoryUserRepository) findByUsername(username string) *models.User {
+	for _, user := range r.users {
+		if user.Username == username {
+			return user
+		}
+	}
+	return nil
+}
+
+func (r *InMemoryUserRepository) FindByUsername(username string) (*models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	user := r.findByUsername(username)
+	if user == nil {
+		return nil, sql.ErrNoRows
+	}
+	return copyUser(user), nil
+}
+
+func (r *InMemoryUserRepository) GetAllUsers() ([]*models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var users []*models.User
+	for _, user := range r.users {
+		users = append(users, copyUser(user))
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].UId < users[j].UId })
+	return users, nil
+}
+
+func (r *InMemoryUserRepository) ListUsers(order string, limit, offset int) ([]*models.User, error) {
+	users, _ := r.GetAllUsers()
+	switch order {
+	case models.SortNewest:
+		sort.Slice(users, func(i, j int) bool { return users[i].UId > users[j].UId })
+	case models.SortOldest:
+	default:
+		return nil, unknownOrder(order)
+	}
+	return slicePage(users, limit, offset), nil
+}
+
+func (r *InMemoryUserRepository) DeleteByUId(UId int) error {
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.users[UId]; !ok {
+		return models.NewError(models.ErrNotFound, "No user exist with this id")
+	}
+	delete(r.users, UId)
+	return nil
+}
+
+func (r *InMemoryUserRepository) UpdateActiveStatus(UId int, status bool) error {
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	user, ok := r.users[UId]
+	// MySQL reports zero affected rows when the value is unchanged, too.
+	if !ok || user.IsActive == status {
+		return models.NewError(models.ErrNotFound, "No inActive user exist with this id")
+	}
+	user.IsActive = status
+	return nil
+}
+
+func (r *InMemoryUserRepository) UpdatePassword(UId int, password string) error {
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	user, ok := r.users[UId]
+	if !ok || user.Password == password {
+		return models.NewError(models.ErrNotFound, "No user exist with this id")
+	}
+	user.Password = password
+	return nil
+}
+
+func (r *InMemoryUserRepository) UpdateRole(UId int, role string) error {
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	user, ok := r.users[UId]
+	if !ok || user.Role == role {
+		return models.NewError(models.ErrNotFound, "No user exist with this id")
+	}
+	user.Role = role
+	return nil
+}
+
+func (r *InMemoryUserRepository) CountByRole(role string) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	count := 0
+	for _, user := range r.users {
+		if user.Role == role {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *InMemoryUserRepository) UpdateCity(UId int, city string) error {
+	r.beforeWrite()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	user, ok := r.users[UId]
+	if !ok || user.City == city {
+		return models.NewError(models.ErrNotFound, "No user exist with this id")
+	}
+	user.City = city
+	return nil
+}
@@ -0,0 +1,163 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	_ "github.com/go-sql-driver/mysql"
+	"localEyes/config"
+	"localEyes/internal/models"
+	"localEyes/utils"
+)
+
+type MySQLNotificationPrefsRepository struct {
+	DB DBTX
+}
+
+func NewMySQLNotificationPrefsRepository(Db DBTX) *MySQLNotificationPrefsRepository {
+	return &MySQLNotificationPrefsRepository{
+		DB: Db,
+	}
+}
+
+// FindByUId returns the user's preferences with their subscriptions. A user
+// who never changed them gets the defaults: every post, right away.
+func (r *MySQLNotificationPrefsRepository) FindByUId(UId int) (*models.NotificationPrefs, error) {
+	query := config.SelectQuery(config.NotificationPrefsTable, "user_id", "", []string{"delivery", "posts", "digest_at"})
+	//query := "SELECT delivery, posts, digest_at FROM notification_prefs WHERE user_id = ?"
+	prefs := &models.NotificationPrefs{UserId: UId, Delivery: models.DeliverImmediately, Posts: models.PostsAll}
+	var digestAt string
+	err := r.DB.QueryRow(query, UId).Scan(&prefs.Delivery, &prefs.Posts, &digestAt)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if err == nil {
+		if prefs.DigestAt, err = parseTimestamp(digestAt); err != nil {
+			return nil, err
+		}
+	}
+	prefs.Subscriptions, err = r.subscriptions(UId)
+	if err != nil {
+		return nil, err
+	}
+	return prefs, nil
+}
+
+// subscriptions lists the user's subscriptions with the names of the
+// categories and authors they follow, categories first.
+func (r *MySQLNotificationPrefsRepository) subscriptions(UId int) ([]*models.Subscription, error) {
+	query := fmt.Sprintf(`SELECT s.user_id, s.kind, s.target_id, COALESCE(c.name, u.username, '') AS name FROM %s s
+LEFT JOIN %s c ON s.kind = ? AND c.id = s.target_id LEFT JOIN %s u ON s.kind = ? AND u.id = s.target_id
+WHERE s.user_id = ? ORDER BY s.kind, name`, config.SubscriptionTable, config.CategoryTable, config.UserTable)
+	//query := "SELECT s.user_id, s.kind, s.target_id, COALESCE(c.name, u.username, '') AS name FROM notification_subscriptions s LEFT JOIN categories c ON s.kind = 'category' AND c.id = s.target_id LEFT JOIN users u ON s.kind = 'user' AND u.id = s.target_id WHERE s.user_id = ? ORDER BY s.kind, name"
+	rows, err := r.DB.Query(query, models.TargetCategory, models.TargetUser, UId)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			utils.Logger.Println("ERROR: Error closing rows:", err)
+		}
+	}(rows)
+
+	var subscriptions []*models.Subscription
+	for rows.Next() {
+		var subscription models.Subscription
+		if err := rows.Scan(&subscription.UserId, &subscription.Kind, &subscription.TargetId, &subscription.Name); err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, &subscription)
+	}
+	return subscriptions, rows.Err()
+}
+
+// Save stores the delivery, posts and digest time; subscriptions are kept
+// through Subscribe and Unsubscribe. The row is added the first time, as long
+// as the user still exists.
+func (r *MySQLNotificationPrefsRepository) Save(prefs *models.NotificationPrefs) error {
+	query := fmt.Sprintf("INSERT INTO %s (user_id, delivery, posts, digest_at) SELECT id, ?, ?, ? FROM %s WHERE id = ? AND NOT EXISTS (SELECT 1 FROM %s WHERE user_id = ?)",
+		config.NotificationPrefsTable, config.UserTable, config.NotificationPrefsTable)
+	//query := "INSERT INTO notification_prefs (user_id, delivery, posts, digest_at) SELECT id, ?, ?, ? FROM users WHERE id = ? AND NOT EXISTS (SELECT 1 FROM notification_prefs WHERE user_id = ?)"
+	result, err := r.DB.Exec(query, prefs.Delivery, prefs.Posts, prefs.DigestAt, prefs.UserId, prefs.UserId)
+	if err != nil {
+		return err
+	}
+	affectedRows, err := result.RowsAffected()
+	if err != nil || affectedRows > 0 {
+		return err
+	}
+	query = config.UpdateQuery(config.NotificationPrefsTable, "user_id", "", []string{"delivery", "posts", "digest_at"})
+	//query := "UPDATE notification_prefs SET delivery = ?, posts = ?, digest_at = ? WHERE user_id = ?"
+	_, err = r.DB.Exec(query, prefs.Delivery, prefs.Posts, prefs.DigestAt, prefs.UserId)
+	return err
+}
+
+// Subscribe adds the subscription unless the user already has it. Callers
+// check that the category or author exists beforehand.
+func (r *MySQLNotificationPrefsRepository) Subscribe(subscription *models.Subscription) error {
+	query := fmt.Sprintf("INSERT INTO %s (user_id, kind, target_id) SELECT id, ?, ? FROM %s WHERE id = ? AND NOT EXISTS (SELECT 1 FROM %s WHERE user_id = ? AND kind = ? AND target_id = ?)",
+		config.SubscriptionTable, config.UserTable, config.SubscriptionTable)
+	//query := "INSERT INTO notification_subscriptions (user_id, kind, target_id) SELECT id, ?, ? FROM users WHERE id = ? AND NOT EXISTS (SELECT 1 FROM notification_subscriptions WHERE user_id = ? AND kind = ? AND target_id = ?)"
+	result, err := r.DB.Exec(query, subscription.Kind, subscription.TargetId, subscription.UserId,
+		subscription.UserId, subscription.Kind, subscription.TargetId)
+	if err != nil {
+		return err
+	}
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affectedRows == 0 {
+		return models.NewError(models.ErrConflict, "You are already subscribed to that "+subscriptionNoun(subscription.Kind))
+	}
+	return nil
+}
+
+func (r *MySQLNotificationPrefsRepository) Unsubscribe(UId int, kind string, targetId int) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE user_id = ? AND kind = ? AND target_id = ?", config.SubscriptionTable)
+	//query := "DELETE FROM notification_subscriptions WHERE user_id = ? AND kind = ? AND target_id = ?"
+	result, err := r.DB.Exec(query, UId, kind, targetId)
+	if err != nil {
+		return err
+	}
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affectedRows == 0 {
+		return models.NewError(models.ErrConflict, "You are not subscribed to that "+subscriptionNoun(kind))
+	}
+	return nil
+}
+
+// DeleteForUser removes the user's preferences and subscriptions, and
+// everyone's subscriptions to the user as an author.
+func (r *MySQLNotificationPrefsRepository) DeleteForUser(UId int) error {
+	query := config.DeleteQuery(config.NotificationPrefsTable, "user_id", "")
+	//query := "DELETE FROM notification_prefs WHERE user_id = ?"
+	if _, err := r.DB.Exec(query, UId); err != nil {
+		return err
+	}
+	query = fmt.Sprintf("DELETE FROM %s WHERE user_id = ? OR kind = ? AND target_id = ?", config.SubscriptionTable)
+	//query := "DELETE FROM notification_subscriptions WHERE user_id = ? OR kind = ? AND target_id = ?"
+	_, err := r.DB.Exec(query, UId, models.TargetUser, UId)
+	return err
+}
+
+// DeleteSubscriptionsTo removes every subscription to the category or
+// author; none is not an error.
+func (r *MySQLNotificationPrefsRepository) DeleteSubscriptionsTo(kind string, targetId int) error {
+	query := config.DeleteQuery(config.SubscriptionTable, "kind", "target_id")
+	//query := "DELETE FROM notification_subscriptions WHERE kind = ? AND target_id = ?"
+	_, err := r.DB.Exec(query, kind, targetId)
+	return err
+}
+
+// subscriptionNoun is what the messages call a subscription of kind.
+func subscriptionNoun(kind string) string {
+	if kind == models.TargetUser {
+		return "author"
+	}
+	return kind
+}
@@ -0,0 +1,197 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	_ "github.com/go-sql-driver/mysql"
+	"localEyes/config"
+	"localEyes/internal/models"
+	"localEyes/utils"
+	"strings"
+)
+
+type MySQLNotificationRepository struct {
+	DB DBTX
+}
+
+func NewMySQLNotificationRepository(Db DBTX) *MySQLNotificationRepository {
+	return &MySQLNotificationRepository{
+		DB: Db,
+	}
+}
+
+var notificationColumns = []string{"id", "user_id", "type", "source_type", "source_id", "message", "is_read", "held", "created_at"}
+
+func (r *MySQLNotificationRepository) Create(notification *models.Notification) error {
+	query := config.InsertQuery(config.NotificationTable, notificationColumns[1:])
+	//query := "INSERT INTO notifications (user_id, type, source_type, source_id, message, is_read, held, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)"
+	result, err := r.DB.Exec(query, notification.UserId, notification.Type, notification.SourceType, notification.SourceId,
+		notification.Message, notification.IsRead, notification.Held, notification.CreatedAt)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	notification.Id = int(id)
+	return nil
+}
+
+// CreateForPost sends a copy of the notification to everyone in the post's
+// city who wants to hear about it: not the author, nobody whose delivery is
+// off, and when their posts setting is subscribed only those following the
+// post's category or author. Digest users get it held.
+func (r *MySQLNotificationRepository) CreateForPost(notification *models.Notification, post *models.Post) error {
+	query := fmt.Sprintf(`INSERT INTO %[1]s (%[2]s)
+SELECT u.id, ?, ?, ?, ?, ?, COALESCE(p.delivery, ?) = ?, ?
+FROM %[3]s u LEFT JOIN %[4]s p ON p.user_id = u.id
+WHERE u.city = ? AND u.id != ? AND COALESCE(p.delivery, ?) != ?
+AND (COALESCE(p.posts, ?) = ? OR p.posts = ? AND EXISTS (
+    SELECT 1 FROM %[5]s s WHERE s.user_id = u.id AND (s.kind = ? AND s.target_id = ?
+        OR s.kind = ? AND s.target_id IN (SELECT id FROM %[6]s WHERE name = ?))))`,
+		config.NotificationTable, strings.Join(notificationColumns[1:], ", "), config.UserTable, config.NotificationPrefsTable,
+		config.SubscriptionTable, config.CategoryTable)
+	//query := "INSERT INTO notifications (user_id, ..., held, created_at) SELECT u.id, ?, ..., COALESCE(p.delivery, 'immediate') = 'digest', ? FROM users u LEFT JOIN notification_prefs p ON p.user_id = u.id WHERE u.city = ? AND u.id != ? AND COALESCE(p.delivery, 'immediate') != 'off' AND (COALESCE(p.posts, 'all') = 'all' OR p.posts = 'subscribed' AND EXISTS (SELECT 1 FROM notification_subscriptions s WHERE ...))"
+	_, err := r.DB.Exec(query, notification.Type, notification.SourceType, notification.SourceId, notification.Message,
+		notification.IsRead, models.DeliverImmediately, models.DeliverDigest, notification.CreatedAt,
+		post.City, post.UId, models.DeliverImmediately, models.DeliverOff,
+		models.PostsAll, models.PostsAll, models.PostsSubscribed,
+		models.TargetUser, post.UId, models.TargetCategory, post.Type)
+	return err
+}
+
+// FindByNotificationId returns sql.ErrNoRows when there is no such
+// notification; held ones are found like any other.
+func (r *MySQLNotificationRepository) FindByNotificationId(Id int) (*models.Notification, error) {
+	query := config.SelectQuery(config.NotificationTable, "id", "", notificationColumns)
+	//query := "SELECT id, user_id, type, source_type, source_id, message, is_read, created_at FROM notifications WHERE id = ?"
+	notifications, err := r.queryNotifications(query, Id)
+	if err != nil {
+		return nil, err
+	}
+	if len(notifications) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return notifications[0], nil
+}
+
+// GetUserNotifications returns one page of the user's inbox, or of only its
+// unread notifications, leaving out what is held for a digest.
+func (r *MySQLNotificationRepository) GetUserNotifications(UId int, unreadOnly bool, order string, limit, offset int) ([]*models.Notification, error) {
+	clause, err := pageClause(notificationOrders, order)
+	if err != nil {
+		return nil, err
+	}
+	query := config.SelectQuery(config.NotificationTable, "user_id", "held", notificationColumns)
+	args := []any{UId, false}
+	if unreadOnly {
+		query += " AND is_read = ?"
+		args = append(args, false)
+	}
+	//query := "SELECT id, user_id, ... FROM notifications WHERE user_id = ? AND held = ? AND is_read = ? ORDER BY id DESC LIMIT ? OFFSET ?"
+	return r.queryNotifications(query+clause, append(args, limit, offset)...)
+}
+
+func (r *MySQLNotificationRepository) queryNotifications(query string, args ...any) ([]*models.Notification, error) {
+	rows, err := r.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			utils.Logger.Println("ERROR: Error closing rows:", err)
+		}
+	}(rows)
+
+	var notifications []*models.Notification
+	for rows.Next() {
+		var notification models.Notification
+		var createdAt string
+		if err := rows.Scan(&notification.Id, &notification.UserId, &notification.Type, &notification.SourceType,
+			&notification.SourceId, &notification.Message, &notification.IsRead, &notification.Held, &createdAt); err != nil {
+			return nil, err
+		}
+		if notification.CreatedAt, err = parseTimestamp(createdAt); err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, &notification)
+	}
+	return notifications, rows.Err()
+}
+
+func (r *MySQLNotificationRepository) CountUnread(UId int) (int, error) {
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE user_id = ? AND is_read = ? AND held = ?", config.NotificationTable)
+	//query := "SELECT COUNT(*) FROM notifications WHERE user_id = ? AND is_read = ? AND held = ?"
+	var count int
+	err := r.DB.QueryRow(query, UId, false, false).Scan(&count)
+	return count, err
+}
+
+// MarkRead marks the listed notifications read, skipping any that are not
+// the user's.
+func (r *MySQLNotificationRepository) MarkRead(UId int, Ids []int) error {
+	if len(Ids) == 0 {
+		return nil
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(Ids)), ", ")
+	query := fmt.Sprintf("UPDATE %s SET is_read = ? WHERE user_id = ? AND id IN (%s)", config.NotificationTable, placeholders)
+	//query := "UPDATE notifications SET is_read = ? WHERE user_id = ? AND id IN (?, ?, ...)"
+	args := []any{true, UId}
+	for _, id := range Ids {
+		args = append(args, id)
+	}
+	_, err := r.DB.Exec(query, args...)
+	return err
+}
+
+// MarkAllRead marks the inbox read; what is held for a digest stays unread.
+func (r *MySQLNotificationRepository) MarkAllRead(UId int) error {
+	query := config.UpdateQuery(config.NotificationTable, "user_id", "held", []string{"is_read"})
+	//query := "UPDATE notifications SET is_read = ? WHERE user_id = ? AND held = ?"
+	_, err := r.DB.Exec(query, true, UId, false)
+	return err
+}
+
+// DeleteForUser empties the user's inbox; none is not an error.
+func (r *MySQLNotificationRepository) DeleteForUser(UId int) error {
+	query := config.DeleteQuery(config.NotificationTable, "user_id", "")
+	//query := "DELETE FROM notifications WHERE user_id = ?"
+	_, err := r.DB.Exec(query, UId)
+	return err
+}
+
+// GetHeld returns what is held for the user's next digest, oldest first.
+func (r *MySQLNotificationRepository) GetHeld(UId int) ([]*models.Notification, error) {
+	query := config.SelectQuery(config.NotificationTable, "user_id", "held", notificationColumns) + " ORDER BY id"
+	//query := "SELECT id, user_id, ... FROM notifications WHERE user_id = ? AND held = ? ORDER BY id"
+	return r.queryNotifications(query, UId, true)
+}
+
+// Release moves the held notifications into the inbox as read, the digest
+// having told the user about them.
+func (r *MySQLNotificationRepository) Release(UId int) error {
+	query := config.UpdateQuery(config.NotificationTable, "user_id", "held", []string{"held", "is_read"})
+	//query := "UPDATE notifications SET held = ?, is_read = ? WHERE user_id = ? AND held = ?"
+	_, err := r.DB.Exec(query, false, true, UId, true)
+	return err
+}
+
+// GetSince returns up to limit notifications added after afterId, for every
+// user, in the order they were added. Held ones are left out until their
+// digest.
+func (r *MySQLNotificationRepository) GetSince(afterId, limit int) ([]*models.Notification, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE id > ? AND held = ? ORDER BY id LIMIT ?", strings.Join(notificationColumns, ", "), config.NotificationTable)
+	//query := "SELECT id, user_id, ... FROM notifications WHERE id > ? AND held = ? ORDER BY id LIMIT ?"
+	return r.queryNotifications(query, afterId, false, limit)
+}
+
+// LastId returns the id of the newest notification, or 0 when there are none.
+func (r *MySQLNotificationRepository) LastId() (int, error) {
+	query := fmt.Sprintf("SELECT COALESCE(MAX(id), 0) FROM %s", config.NotificationTable)
+	//query := "SELECT COALESCE(MAX(id), 0) FROM notifications"
+	var id int
+	err := r.DB.QueryRow(query).Scan(&id)
+	return id, err
+}
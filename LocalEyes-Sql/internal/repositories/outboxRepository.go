@@ -0,0 +1,162 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	_ "github.com/go-sql-driver/mysql"
+	"localEyes/config"
+	"localEyes/internal/models"
+	"localEyes/utils"
+	"time"
+)
+
+type MySQLOutboxRepository struct {
+	DB DBTX
+}
+
+func NewMySQLOutboxRepository(Db DBTX) *MySQLOutboxRepository {
+	return &MySQLOutboxRepository{
+		DB: Db,
+	}
+}
+
+var outboxColumns = []string{"id", "post_id", "attempts", "available_at", "last_error", "dead", "created_at"}
+
+func (r *MySQLOutboxRepository) Create(entry *models.OutboxEntry) error {
+	columns := []string{"post_id", "attempts", "available_at", "last_error", "dead", "created_at"}
+	query := config.InsertQuery(config.OutboxTable, columns)
+	//query := "INSERT INTO notification_outbox (post_id, attempts, available_at, last_error, dead, created_at) VALUES (?, ?, ?, ?, ?, ?)"
+	result, err := r.DB.Exec(query, entry.PostId, entry.Attempts, entry.AvailableAt.Unix(), entry.LastError, entry.Dead, entry.CreatedAt)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	entry.Id = int(id)
+	return nil
+}
+
+// FindById returns sql.ErrNoRows once the entry has been delivered.
+func (r *MySQLOutboxRepository) FindById(Id int) (*models.OutboxEntry, error) {
+	query := config.SelectQuery(config.OutboxTable, "id", "", outboxColumns)
+	//query := "SELECT id, post_id, attempts, available_at, last_error, dead, created_at FROM notification_outbox WHERE id = ?"
+	entries, err := r.queryEntries(query, Id)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return entries[0], nil
+}
+
+// GetDue returns up to limit live entries that may be claimed at now, oldest
+// first.
+func (r *MySQLOutboxRepository) GetDue(now time.Time, limit int) ([]*models.OutboxEntry, error) {
+	query := fmt.Sprintf("SELECT id, post_id, attempts, available_at, last_error, dead, created_at FROM %s WHERE dead = ? AND available_at <= ? ORDER BY id LIMIT ?",
+		config.OutboxTable)
+	//query := "SELECT id, post_id, attempts, available_at, last_error, dead, created_at FROM notification_outbox WHERE dead = ? AND available_at <= ? ORDER BY id LIMIT ?"
+	return r.queryEntries(query, false, now.Unix(), limit)
+}
+
+func (r *MySQLOutboxRepository) queryEntries(query string, args ...any) ([]*models.OutboxEntry, error) {
+	rows, err := r.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			utils.Logger.Println("ERROR: Error closing rows:", err)
+		}
+	}(rows)
+
+	var entries []*models.OutboxEntry
+	for rows.Next() {
+		var entry models.OutboxEntry
+		var availableAt int64
+		var createdAt string
+		if err := rows.Scan(&entry.Id, &entry.PostId, &entry.Attempts, &availableAt, &entry.LastError, &entry.Dead, &createdAt); err != nil {
+			return nil, err
+		}
+		if entry.CreatedAt, err = parseTimestamp(createdAt); err != nil {
+			return nil, err
+		}
+		entry.AvailableAt = time.Unix(availableAt, 0)
+		entries = append(entries, &entry)
+	}
+	return entries, rows.Err()
+}
+
+// Claim takes the entry for one more attempt, hiding it from other workers
+// until until. It reports false when another worker got there first, which
+// shows as the attempts count having moved on since the entry was read.
+func (r *MySQLOutboxRepository) Claim(entry *models.OutboxEntry, until time.Time) (bool, error) {
+	query := fmt.Sprintf("UPDATE %s SET attempts = attempts + 1, available_at = ? WHERE id = ? AND attempts = ? AND dead = ?", config.OutboxTable)
+	//query := "UPDATE notification_outbox SET attempts = attempts + 1, available_at = ? WHERE id = ? AND attempts = ? AND dead = ?"
+	result, err := r.DB.Exec(query, until.Unix(), entry.Id, entry.Attempts, false)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if affected == 0 {
+		return false, nil
+	}
+	entry.Attempts++
+	entry.AvailableAt = time.Unix(until.Unix(), 0)
+	return true, nil
+}
+
+// Retry puts the entry back for another attempt at at.
+func (r *MySQLOutboxRepository) Retry(Id int, at time.Time, lastError string) error {
+	query := config.UpdateQuery(config.OutboxTable, "id", "", []string{"available_at", "last_error"})
+	//query := "UPDATE notification_outbox SET available_at = ?, last_error = ? WHERE id = ?"
+	_, err := r.DB.Exec(query, at.Unix(), lastError, Id)
+	return err
+}
+
+// MarkDead gives up on the entry; it stays in the table but is never due.
+func (r *MySQLOutboxRepository) MarkDead(Id int, lastError string) error {
+	query := config.UpdateQuery(config.OutboxTable, "id", "", []string{"dead", "last_error"})
+	//query := "UPDATE notification_outbox SET dead = ?, last_error = ? WHERE id = ?"
+	_, err := r.DB.Exec(query, true, lastError, Id)
+	return err
+}
+
+// Complete removes a delivered entry, provided the caller's claim on it still
+// stands: an entry claimed again after the caller's lease ran out, or already
+// gone, is left alone and Complete reports false.
+func (r *MySQLOutboxRepository) Complete(entry *models.OutboxEntry) (bool, error) {
+	query := config.DeleteQuery(config.OutboxTable, "id", "attempts")
+	//query := "DELETE FROM notification_outbox WHERE id = ? AND attempts = ?"
+	result, err := r.DB.Exec(query, entry.Id, entry.Attempts)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// DeleteByPId removes the post's entry, live or dead, when the post goes.
+func (r *MySQLOutboxRepository) DeleteByPId(PId int) error {
+	query := config.DeleteQuery(config.OutboxTable, "post_id", "")
+	//query := "DELETE FROM notification_outbox WHERE post_id = ?"
+	_, err := r.DB.Exec(query, PId)
+	return err
+}
+
+// DeleteForUser removes the entries of every post written by UId.
+func (r *MySQLOutboxRepository) DeleteForUser(UId int) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE post_id IN (SELECT post_id FROM %s WHERE user_id = ?)", config.OutboxTable, config.PostTable)
+	//query := "DELETE FROM notification_outbox WHERE post_id IN (SELECT post_id FROM posts WHERE user_id = ?)"
+	_, err := r.DB.Exec(query, UId)
+	return err
+}
@@ -0,0 +1,54 @@
+package repositories
+
+import (
+	"fmt"
+	"localEyes/config"
+	"localEyes/internal/models"
+)
+
+// The ORDER BY clauses behind each sort order. Ids grow with creation time,
+// so they stand in for it, and they break ties so that pages never overlap.
+var (
+	postOrders = map[string]string{
+		models.SortNewest:    "post_id DESC",
+		models.SortOldest:    "post_id",
+		models.SortMostLiked: "likes DESC, post_id DESC",
+		models.SortMostQuestions: fmt.Sprintf("(SELECT COUNT(*) FROM %[1]s WHERE %[1]s.post_id = %[2]s.post_id) DESC, post_id DESC",
+			config.QuestionTable, config.PostTable),
+	}
+	questionOrders = map[string]string{
+		models.SortNewest: "q_id DESC",
+		models.SortOldest: "q_id",
+	}
+	userOrders = map[string]string{
+		models.SortNewest: "id DESC",
+		models.SortOldest: "id",
+	}
+	notificationOrders = userOrders
+)
+
+// pageClause returns the ORDER BY and LIMIT clause for one page.
+func pageClause(orders map[string]string, order string) (string, error) {
+	orderBy, ok := orders[order]
+	if !ok {
+		return "", unknownOrder(order)
+	}
+	return " ORDER BY " + orderBy + " LIMIT ? OFFSET ?", nil
+}
+
+func unknownOrder(order string) error {
+	return fmt.Errorf("unknown sort order %q", order)
+}
+
+// slicePage cuts one page out of items the in-memory repositories have
+// already filtered and sorted.
+func slicePage[T any](items []T, limit, offset int) []T {
+	if offset >= len(items) {
+		return nil
+	}
+	items = items[offset:]
+	if limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
@@ -0,0 +1,110 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	_ "github.com/go-sql-driver/mysql"
+	"localEyes/config"
+	"localEyes/internal/models"
+	"localEyes/utils"
+	"time"
+)
+
+type MySQLPostLikeRepository struct {
+	DB DBTX
+}
+
+func NewMySQLPostLikeRepository(Db DBTX) *MySQLPostLikeRepository {
+	return &MySQLPostLikeRepository{
+		DB: Db,
+	}
+}
+
+// Create records that UId likes PId. The insert is skipped when the like is
+// already there (or the post is gone, which callers check beforehand), and
+// the primary key on (user_id, post_id) rejects the rare concurrent duplicate
+// that slips past that check; either way the caller is told the post is
+// already liked.
+func (r *MySQLPostLikeRepository) Create(UId, PId int) error {
+	alreadyLiked := models.NewError(models.ErrConflict, "You have already liked this post")
+	query := fmt.Sprintf("INSERT INTO %s (user_id, post_id, created_at) SELECT ?, post_id, ? FROM %s WHERE post_id = ? AND NOT EXISTS (SELECT 1 FROM %s WHERE user_id = ? AND post_id = ?)",
+		config.PostLikeTable, config.PostTable, config.PostLikeTable)
+	//query := "INSERT INTO post_likes (user_id, post_id, created_at) SELECT ?, post_id, ? FROM posts WHERE post_id = ? AND NOT EXISTS (SELECT 1 FROM post_likes WHERE user_id = ? AND post_id = ?)"
+	result, err := r.DB.Exec(query, UId, time.Now(), PId, UId, PId)
+	if isDuplicateKey(err) {
+		return alreadyLiked
+	}
+	if err != nil {
+		return err
+	}
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affectedRows == 0 {
+		return alreadyLiked
+	}
+	return nil
+}
+
+func (r *MySQLPostLikeRepository) Delete(UId, PId int) error {
+	condition1 := "user_id"
+	condition2 := "post_id"
+	query := config.DeleteQuery(config.PostLikeTable, condition1, condition2)
+	//query := "DELETE FROM post_likes WHERE user_id = ? AND post_id = ?"
+	result, err := r.DB.Exec(query, UId, PId)
+	if err != nil {
+		return err
+	}
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affectedRows == 0 {
+		return models.NewError(models.ErrConflict, "You have not liked this post")
+	}
+	return nil
+}
+
+func (r *MySQLPostLikeRepository) GetLikedPIds(UId int) ([]int, error) {
+	columns := []string{"post_id"}
+	condition1 := "user_id"
+	query := config.SelectQuery(config.PostLikeTable, condition1, "", columns)
+	//query := "SELECT post_id FROM post_likes WHERE user_id = ?"
+	rows, err := r.DB.Query(query, UId)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			utils.Logger.Println("ERROR: Error closing rows:", err)
+		}
+	}(rows)
+
+	var pIds []int
+	for rows.Next() {
+		var pId int
+		if err := rows.Scan(&pId); err != nil {
+			return nil, err
+		}
+		pIds = append(pIds, pId)
+	}
+	return pIds, rows.Err()
+}
+
+func (r *MySQLPostLikeRepository) DeleteByPId(PId int) error {
+	condition1 := "post_id"
+	query := config.DeleteQuery(config.PostLikeTable, condition1, "")
+	//query := "DELETE FROM post_likes WHERE post_id = ?"
+	_, err := r.DB.Exec(query, PId)
+	return err
+}
+
+// DeleteForUser removes the likes UId gave and the likes on UId's posts.
+func (r *MySQLPostLikeRepository) DeleteForUser(UId int) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE user_id = ? OR post_id IN (SELECT post_id FROM %s WHERE user_id = ?)", config.PostLikeTable, config.PostTable)
+	//query := "DELETE FROM post_likes WHERE user_id = ? OR post_id IN (SELECT post_id FROM posts WHERE user_id = ?)"
+	_, err := r.DB.Exec(query, UId, UId)
+	return err
+}
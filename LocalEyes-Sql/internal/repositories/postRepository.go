@@ -2,36 +2,57 @@ package repositories
 
 import (
 	"database/sql"
-	"errors"
+	"fmt"
 	_ "github.com/go-sql-driver/mysql"
 	"localEyes/config"
 	"localEyes/internal/models"
 	"localEyes/utils"
-	"time"
+	"strings"
 )
 
 type MySQLPostRepository struct {
-	DB *sql.DB
+	DB DBTX
 }
 
-func NewMySQLPostRepository(Db *sql.DB) *MySQLPostRepository {
+func NewMySQLPostRepository(Db DBTX) *MySQLPostRepository {
 	return &MySQLPostRepository{
 		DB: Db,
 	}
 }
 
 func (r *MySQLPostRepository) Create(post *models.Post) error {
-	columns := []string{"user_id", "title", "type", "content", "likes", "created_at"}
+	columns := []string{"user_id", "title", "type", "content", "likes", "created_at", "city", "neighbourhood", "latitude", "longitude"}
 	query := config.InsertQuery(config.PostTable, columns)
-	//query := "INSERT INTO posts (user_id, title,type, content, likes,created_at) VALUES (?, ?, ?, ?, ?,?)"
-	_, err := r.DB.Exec(query, post.UId, post.Title, post.Type, post.Content, post.Likes, post.CreatedAt)
-	return err
+	//query := "INSERT INTO posts (user_id, title,type, content, likes,created_at, city, neighbourhood, latitude, longitude) VALUES (?, ?, ?, ?, ?,?, ?, ?, ?, ?)"
+	result, err := r.DB.Exec(query, post.UId, post.Title, post.Type, post.Content, post.Likes, post.CreatedAt, post.City,
+		post.Neighbourhood, post.Latitude, post.Longitude)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	post.PostId = int(id)
+	return nil
+}
+
+// nullLocation holds the nullable coordinate columns while a post row is
+// scanned.
+type nullLocation struct {
+	latitude, longitude sql.NullFloat64
+}
+
+func (l nullLocation) apply(post *models.Post) {
+	if l.latitude.Valid && l.longitude.Valid {
+		post.Latitude, post.Longitude = &l.latitude.Float64, &l.longitude.Float64
+	}
 }
 
 func (r *MySQLPostRepository) GetAllPosts() ([]*models.Post, error) {
-	columns := []string{"post_id", "user_id", "title", "type", "content", "likes", "created_at"}
+	columns := []string{"post_id", "user_id", "title", "type", "content", "likes", "created_at", "city", "neighbourhood", "latitude", "longitude"}
 	query := config.SelectQuery(config.PostTable, "", "", columns)
-	//query := "SELECT post_id, user_id, title, type, content, likes, created_at FROM posts"
+	//query := "SELECT post_id, user_id, title, type, content, likes, created_at, city, neighbourhood, latitude, longitude FROM posts"
 	rows, err := r.DB.Query(query)
 	if err != nil {
 		return nil, err
@@ -47,11 +68,14 @@ func (r *MySQLPostRepository) GetAllPosts() ([]*models.Post, error) {
 	for rows.Next() {
 		var post models.Post
 		var createdAt string
-		if err := rows.Scan(&post.PostId, &post.UId, &post.Title, &post.Type, &post.Content, &post.Likes, &createdAt); err != nil {
+		var location nullLocation
+		if err := rows.Scan(&post.PostId, &post.UId, &post.Title, &post.Type, &post.Content, &post.Likes, &createdAt, &post.City,
+			&post.Neighbourhood, &location.latitude, &location.longitude); err != nil {
 			return nil, err
 		}
+		location.apply(&post)
 		if createdAt != "" {
-			parsedTime, err := time.Parse("2006-01-02T15:04:05Z", createdAt)
+			parsedTime, err := parseTimestamp(createdAt)
 			if err != nil {
 				return nil, err
 			}
@@ -63,6 +87,101 @@ func (r *MySQLPostRepository) GetAllPosts() ([]*models.Post, error) {
 	return posts, nil
 }
 
+var postColumns = []string{"post_id", "user_id", "title", "type", "content", "likes", "created_at", "city", "neighbourhood", "latitude", "longitude"}
+
+// ListPosts returns one page of the posts matching filter, limit rows from
+// offset on in the given order.
+func (r *MySQLPostRepository) ListPosts(filter models.PostFilter, order string, limit, offset int) ([]*models.Post, error) {
+	clause, err := pageClause(postOrders, order)
+	if err != nil {
+		return nil, err
+	}
+	var conditions []string
+	var args []any
+	if filter.Type != "" {
+		conditions = append(conditions, "type = ?")
+		args = append(args, filter.Type)
+	}
+	if filter.UId != 0 {
+		conditions = append(conditions, "user_id = ?")
+		args = append(args, filter.UId)
+	}
+	if filter.City != "" {
+		conditions = append(conditions, "city = ?")
+		args = append(args, filter.City)
+	}
+	if filter.Neighbourhood != "" {
+		conditions = append(conditions, "neighbourhood = ?")
+		args = append(args, filter.Neighbourhood)
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s", strings.Join(postColumns, ", "), config.PostTable)
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += clause
+	//query := "SELECT post_id, user_id, title, type, content, likes, created_at, city, neighbourhood, latitude, longitude FROM posts WHERE type = ? ORDER BY likes DESC, post_id DESC LIMIT ? OFFSET ?"
+	return r.queryPosts(query, append(args, limit, offset)...)
+}
+
+// SearchPosts returns the posts in city whose title or content match the
+// search, most relevant first, ranked by the FULLTEXT index. An empty city
+// searches every city.
+func (r *MySQLPostRepository) SearchPosts(city, search string, limit, offset int) ([]*models.Post, error) {
+	match := "MATCH (title, content) AGAINST (? IN NATURAL LANGUAGE MODE)"
+	where, args := match, []any{search}
+	if city != "" {
+		where, args = "city = ? AND "+match, []any{city, search}
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s ORDER BY %s DESC, post_id DESC LIMIT ? OFFSET ?",
+		strings.Join(postColumns, ", "), config.PostTable, where, match)
+	//query := "SELECT post_id, ... FROM posts WHERE city = ? AND MATCH (title, content) AGAINST (?) ORDER BY MATCH (title, content) AGAINST (?) DESC, post_id DESC LIMIT ? OFFSET ?"
+	return r.queryPosts(query, append(args, search, limit, offset)...)
+}
+
+// PostsInArea returns the posts with coordinates inside area, for the caller
+// to measure and sort by distance.
+func (r *MySQLPostRepository) PostsInArea(area models.Area) ([]*models.Post, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE latitude BETWEEN ? AND ? AND longitude BETWEEN ? AND ?",
+		strings.Join(postColumns, ", "), config.PostTable)
+	//query := "SELECT post_id, ... FROM posts WHERE latitude BETWEEN ? AND ? AND longitude BETWEEN ? AND ?"
+	return r.queryPosts(query, area.MinLatitude, area.MaxLatitude, area.MinLongitude, area.MaxLongitude)
+}
+
+// queryPosts runs a query selecting postColumns.
+func (r *MySQLPostRepository) queryPosts(query string, args ...any) ([]*models.Post, error) {
+	rows, err := r.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			utils.Logger.Println("ERROR: Error closing rows:", err)
+		}
+	}(rows)
+
+	var posts []*models.Post
+	for rows.Next() {
+		var post models.Post
+		var createdAt string
+		var location nullLocation
+		if err := rows.Scan(&post.PostId, &post.UId, &post.Title, &post.Type, &post.Content, &post.Likes, &createdAt, &post.City,
+			&post.Neighbourhood, &location.latitude, &location.longitude); err != nil {
+			return nil, err
+		}
+		location.apply(&post)
+		if createdAt != "" {
+			parsedTime, err := parseTimestamp(createdAt)
+			if err != nil {
+				return nil, err
+			}
+			post.CreatedAt = parsedTime
+		}
+		posts = append(posts, &post)
+	}
+	return posts, rows.Err()
+}
+
 func (r *MySQLPostRepository) DeleteByPId(PId int) error {
 	condition1 := "post_id"
 	query := config.DeleteQuery(config.PostTable, condition1, "")
@@ -74,7 +193,7 @@ func (r *MySQLPostRepository) DeleteByPId(PId int) error {
 			return err
 		}
 		if affectedRows == 0 {
-			return errors.New(config.Red + "No Post exist with this id" + config.Reset)
+			return models.NewError(models.ErrNotFound, "No Post exist with this id")
 		}
 	}
 	return err
@@ -92,7 +211,7 @@ func (r *MySQLPostRepository) DeleteByUIdPId(UId, PId int) error {
 			return err
 		}
 		if affectedRows == 0 {
-			return errors.New(config.Red + "No Post exist with this id" + config.Reset)
+			return models.NewError(models.ErrNotFound, "No Post exist with this id")
 		}
 	}
 	return err
@@ -107,10 +226,10 @@ func (r *MySQLPostRepository) DeleteByUId(UId int) error {
 }
 
 func (r *MySQLPostRepository) GetPostsByFilter(filter string) ([]*models.Post, error) {
-	columns := []string{"post_id", "user_id", "title", "type", "content", "likes", "created_at"}
+	columns := []string{"post_id", "user_id", "title", "type", "content", "likes", "created_at", "city", "neighbourhood", "latitude", "longitude"}
 	condition1 := "type"
 	query := config.SelectQuery(config.PostTable, condition1, "", columns)
-	//query := "SELECT post_id, user_id, title,type, content, likes,created_at FROM posts WHERE type = ?"
+	//query := "SELECT post_id, user_id, title,type, content, likes,created_at, city, neighbourhood, latitude, longitude FROM posts WHERE type = ?"
 	rows, err := r.DB.Query(query, filter)
 	if err != nil {
 		return nil, err
@@ -126,11 +245,14 @@ func (r *MySQLPostRepository) GetPostsByFilter(filter string) ([]*models.Post, e
 	for rows.Next() {
 		var post models.Post
 		var createdAt string
-		if err := rows.Scan(&post.PostId, &post.UId, &post.Title, &post.Type, &post.Content, &post.Likes, &createdAt); err != nil {
+		var location nullLocation
+		if err := rows.Scan(&post.PostId, &post.UId, &post.Title, &post.Type, &post.Content, &post.Likes, &createdAt, &post.City,
+			&post.Neighbourhood, &location.latitude, &location.longitude); err != nil {
 			return nil, err
 		}
+		location.apply(&post)
 		if createdAt != "" {
-			parsedTime, err := time.Parse("2006-01-02 15:04:05", createdAt)
+			parsedTime, err := parseTimestamp(createdAt)
 			if err != nil {
 				return nil, err
 			}
@@ -144,9 +266,9 @@ func (r *MySQLPostRepository) GetPostsByFilter(filter string) ([]*models.Post, e
 
 func (r *MySQLPostRepository) GetPostsByUId(UId int) ([]*models.Post, error) {
 	condition1 := "user_id"
-	columns := []string{"post_id", "user_id", "title", "type", "content", "likes", "created_at"}
+	columns := []string{"post_id", "user_id", "title", "type", "content", "likes", "created_at", "city", "neighbourhood", "latitude", "longitude"}
 	query := config.SelectQuery(config.PostTable, condition1, "", columns)
-	//query := "SELECT post_id, user_id, title,type, content, likes,created_at FROM posts WHERE user_id = ?"
+	//query := "SELECT post_id, user_id, title,type, content, likes,created_at, city, neighbourhood, latitude, longitude FROM posts WHERE user_id = ?"
 	rows, err := r.DB.Query(query, UId)
 	if err != nil {
 		return nil, err
@@ -161,9 +283,12 @@ func (r *MySQLPostRepository) GetPostsByUId(UId int) ([]*models.Post, error) {
 	var posts []*models.Post
 	for rows.Next() {
 		var post models.Post
-		if err := rows.Scan(&post.PostId, &post.UId, &post.Title, &post.Type, &post.Content, &post.Likes, &post.CreatedAt); err != nil {
+		var location nullLocation
+		if err := rows.Scan(&post.PostId, &post.UId, &post.Title, &post.Type, &post.Content, &post.Likes, &post.CreatedAt, &post.City,
+			&post.Neighbourhood, &location.latitude, &location.longitude); err != nil {
 			return nil, err
 		}
+		location.apply(&post)
 		posts = append(posts, &post)
 	}
 
@@ -171,10 +296,10 @@ func (r *MySQLPostRepository) GetPostsByUId(UId int) ([]*models.Post, error) {
 }
 
 func (r *MySQLPostRepository) GetPostsByPId(PId int) ([]*models.Post, error) {
-	columns := []string{"post_id", "user_id", "title", "type", "content", "likes", "created_at"}
+	columns := []string{"post_id", "user_id", "title", "type", "content", "likes", "created_at", "city", "neighbourhood", "latitude", "longitude"}
 	condition1 := "post_id"
 	query := config.SelectQuery(config.PostTable, condition1, "", columns)
-	//query := "SELECT post_id, user_id, title,type, content, likes,created_at FROM posts WHERE post_id = ?"
+	//query := "SELECT post_id, user_id, title,type, content, likes,created_at, city, neighbourhood, latitude, longitude FROM posts WHERE post_id = ?"
 	rows, err := r.DB.Query(query, PId)
 	if err != nil {
 		return nil, err
@@ -189,9 +314,12 @@ func (r *MySQLPostRepository) GetPostsByPId(PId int) ([]*models.Post, error) {
 	var posts []*models.Post
 	for rows.Next() {
 		var post models.Post
-		if err := rows.Scan(&post.PostId, &post.UId, &post.Title, &post.Type, &post.Content, &post.Likes, &post.CreatedAt); err != nil {
+		var location nullLocation
+		if err := rows.Scan(&post.PostId, &post.UId, &post.Title, &post.Type, &post.Content, &post.Likes, &post.CreatedAt, &post.City,
+			&post.Neighbourhood, &location.latitude, &location.longitude); err != nil {
 			return nil, err
 		}
+		location.apply(&post)
 		posts = append(posts, &post)
 	}
 
@@ -211,12 +339,21 @@ func (r *MySQLPostRepository) UpdateUserPost(PId, UId int, title, content string
 			return err
 		}
 		if rowsAffected == 0 {
-			return errors.New(config.Red + "You can only update your post" + config.Reset)
+			return models.NewError(models.ErrForbidden, "You can only update your post")
 		}
 	}
 	return err
 }
 
+// UpdateType moves every post of type from over to type to, which is how a
+// category rename reaches the posts filed under it.
+func (r *MySQLPostRepository) UpdateType(from, to string) error {
+	query := config.UpdateQuery(config.PostTable, "type", "", []string{"type"})
+	//query := "UPDATE posts SET type = ? WHERE type = ?"
+	_, err := r.DB.Exec(query, to, from)
+	return err
+}
+
 func (r *MySQLPostRepository) UpdateLike(PId int) error {
 	columns := "likes=likes+1"
 	condition1 := "post_id=?"
@@ -229,8 +366,40 @@ func (r *MySQLPostRepository) UpdateLike(PId int) error {
 			return err
 		}
 		if rowsAffected == 0 {
-			return errors.New(config.Red + "No post exist with this id" + config.Reset)
+			return models.NewError(models.ErrNotFound, "No post exist with this id")
+		}
+	}
+	return err
+}
+
+// RemoveLike takes back one like; the counter never drops below zero.
+func (r *MySQLPostRepository) RemoveLike(PId int) error {
+	columns := "likes=likes-1"
+	condition1 := "post_id=?"
+	condition2 := "likes>0"
+	query := config.UpdateQueryWithValue(config.PostTable, condition1, condition2, columns)
+	//query := "UPDATE posts SET likes=likes-1 WHERE post_id=? AND likes>0"
+	result, err := r.DB.Exec(query, PId)
+	if result != nil {
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			return models.NewError(models.ErrNotFound, "No post exist with this id")
 		}
 	}
 	return err
 }
+
+// RemoveUserLikes takes back every like UId has given, ahead of deleting
+// their rows from post_likes.
+func (r *MySQLPostRepository) RemoveUserLikes(UId int) error {
+	columns := "likes=likes-1"
+	condition1 := fmt.Sprintf("post_id IN (SELECT post_id FROM %s WHERE user_id = ?)", config.PostLikeTable)
+	condition2 := "likes>0"
+	query := config.UpdateQueryWithValue(config.PostTable, condition1, condition2, columns)
+	//query := "UPDATE posts SET likes=likes-1 WHERE post_id IN (SELECT post_id FROM post_likes WHERE user_id = ?) AND likes>0"
+	_, err := r.DB.Exec(query, UId)
+	return err
+}
@@ -3,27 +3,34 @@ package repositories
 import (
 	"database/sql"
 	"encoding/json"
-	"errors"
 	"fmt"
 	_ "github.com/go-sql-driver/mysql"
 	"localEyes/config"
 	"localEyes/internal/models"
 	"localEyes/utils"
-	"time"
+	"strings"
 )
 
 type MySQLQuestionRepository struct {
-	DB *sql.DB
+	DB DBTX
 }
 
-func NewMySQLQuestionRepository(Db *sql.DB) *MySQLQuestionRepository {
+func NewMySQLQuestionRepository(Db DBTX) *MySQLQuestionRepository {
 	return &MySQLQuestionRepository{
 		DB: Db,
 	}
 }
 
 func (r *MySQLQuestionRepository) Create(question *models.Question) error {
+	// Keep the replies column a JSON array, as older rows have it, rather
+	// than storing a nil slice as null.
+	if question.Replies == nil {
+		question.Replies = []string{}
+	}
 	replies, err := json.Marshal(question.Replies)
+	if err != nil {
+		return err
+	}
 	columns:=[]string{"post_id","user_id", "text", "replies","created_at"}
 	query:=config.InsertQuery(config.QuestionTable,columns)
 	//query := "INSERT INTO questions (post_id,user_id, text, replies,created_at) VALUES (?, ?, ?, ?,?)"
@@ -63,7 +70,7 @@ func (r *MySQLQuestionRepository) GetAllQuestions() ([]*models.Question, error)
 		}
 
 		if createdAt != "" {
-			parsedTime, err := time.Parse("2006-01-02T15:04:05Z", createdAt)
+			parsedTime, err := parseTimestamp(createdAt)
 			if err != nil {
 				return nil, err
 			}
@@ -74,6 +81,76 @@ func (r *MySQLQuestionRepository) GetAllQuestions() ([]*models.Question, error)
 	}
 	return questions, nil
 }
+var questionColumns = []string{"q_id", "post_id", "user_id", "text", "replies", "created_at"}
+
+// ListQuestions returns limit questions from offset on in the given order,
+// only those asked on posts in city unless city is empty.
+func (r *MySQLQuestionRepository) ListQuestions(city, order string, limit, offset int) ([]*models.Question, error) {
+	clause, err := pageClause(questionOrders, order)
+	if err != nil {
+		return nil, err
+	}
+	query := config.SelectQuery(config.QuestionTable, "", "", questionColumns)
+	var args []any
+	if city != "" {
+		query += fmt.Sprintf(" WHERE post_id IN (SELECT post_id FROM %s WHERE city = ?)", config.PostTable)
+		args = append(args, city)
+	}
+	query += clause
+	//query := "SELECT q_id, post_id, user_id, text, replies, created_at FROM questions WHERE post_id IN (SELECT post_id FROM posts WHERE city = ?) ORDER BY q_id DESC LIMIT ? OFFSET ?"
+	return r.queryQuestions(query, append(args, limit, offset)...)
+}
+
+// SearchQuestions returns the questions whose text or answers match the
+// search, most relevant first, ranked by the FULLTEXT indexes.
+func (r *MySQLQuestionRepository) SearchQuestions(search string, limit, offset int) ([]*models.Question, error) {
+	questionMatch := "MATCH (text) AGAINST (? IN NATURAL LANGUAGE MODE)"
+	answerMatch := "MATCH (a.text) AGAINST (? IN NATURAL LANGUAGE MODE)"
+	answered := fmt.Sprintf("q_id IN (SELECT a.q_id FROM %s a WHERE %s)", config.AnswerTable, answerMatch)
+	answerScore := fmt.Sprintf("(SELECT COALESCE(SUM(%s), 0) FROM %s a WHERE a.q_id = %s.q_id)", answerMatch, config.AnswerTable, config.QuestionTable)
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s OR %s ORDER BY %s + %s DESC, q_id DESC LIMIT ? OFFSET ?",
+		strings.Join(questionColumns, ", "), config.QuestionTable, questionMatch, answered, questionMatch, answerScore)
+	//query := "SELECT q_id, ... FROM questions WHERE MATCH (text) AGAINST (?) OR q_id IN (SELECT a.q_id FROM answers a WHERE MATCH (a.text) AGAINST (?)) ORDER BY MATCH (text) AGAINST (?) + (SELECT COALESCE(SUM(MATCH (a.text) AGAINST (?)), 0) FROM answers a WHERE a.q_id = questions.q_id) DESC, q_id DESC LIMIT ? OFFSET ?"
+	return r.queryQuestions(query, search, search, search, search, limit, offset)
+}
+
+// queryQuestions runs a query selecting questionColumns.
+func (r *MySQLQuestionRepository) queryQuestions(query string, args ...any) ([]*models.Question, error) {
+	rows, err := r.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			utils.Logger.Println("ERROR: Error closing rows:", err)
+		}
+	}(rows)
+
+	var questions []*models.Question
+	for rows.Next() {
+		var question models.Question
+		var replies string
+		var createdAt string
+		if err := rows.Scan(&question.QId, &question.PostId, &question.UserId, &question.Text, &replies, &createdAt); err != nil {
+			return nil, err
+		}
+		if replies != "" {
+			if err := json.Unmarshal([]byte(replies), &question.Replies); err != nil {
+				return nil, err
+			}
+		}
+		if createdAt != "" {
+			parsedTime, err := parseTimestamp(createdAt)
+			if err != nil {
+				return nil, err
+			}
+			question.CreatedAt = parsedTime
+		}
+		questions = append(questions, &question)
+	}
+	return questions, rows.Err()
+}
 func (r *MySQLQuestionRepository) DeleteByQIdUId(QId, UId int) error {
 	condition1:="q_id"
 	condition2:="user_id"
@@ -86,25 +163,32 @@ func (r *MySQLQuestionRepository) DeleteByQIdUId(QId, UId int) error {
 			return err
 		}
 		if affectedRows == 0 {
-			return errors.New(config.Red + "No Question exist with this id" + config.Reset)
+			return models.NewError(models.ErrNotFound, "No Question exist with this id")
 		}
 	}
 	return err
 }
+// DeleteByPId removes every question on a post; a post without questions is not an error.
 func (r *MySQLQuestionRepository) DeleteByPId(PId int) error {
 	condition1:="post_id"
 	query:=config.DeleteQuery(config.QuestionTable,condition1,"")
 	//query := "DELETE FROM questions WHERE post_id = ?"
-	result, err := r.DB.Exec(query, PId)
-	if result != nil {
-		affectedRows, err := result.RowsAffected()
-		if err != nil {
-			return err
-		}
-		if affectedRows == 0 {
-			return errors.New(config.Red + "No Question exist with this id" + config.Reset)
-		}
-	}
+	_, err := r.DB.Exec(query, PId)
+	return err
+}
+func (r *MySQLQuestionRepository) DeleteByUId(UId int) error {
+	condition1:="user_id"
+	query:=config.DeleteQuery(config.QuestionTable,condition1,"")
+	//query := "DELETE FROM questions WHERE user_id = ?"
+	_, err := r.DB.Exec(query, UId)
+	return err
+}
+// DeleteByPostOwner removes the questions asked on any post written by UId.
+func (r *MySQLQuestionRepository) DeleteByPostOwner(UId int) error {
+	condition1:=fmt.Sprintf("post_id IN (SELECT post_id FROM %s WHERE user_id = ?)",config.PostTable)
+	query:=fmt.Sprintf("DELETE FROM %s WHERE %s",config.QuestionTable,condition1)
+	//query := "DELETE FROM questions WHERE post_id IN (SELECT post_id FROM posts WHERE user_id = ?)"
+	_, err := r.DB.Exec(query, UId)
 	return err
 }
 func (r *MySQLQuestionRepository) DeleteByQId(QId int) error {
@@ -118,7 +202,7 @@ func (r *MySQLQuestionRepository) DeleteByQId(QId int) error {
 			return err
 		}
 		if affectedRows == 0 {
-			return errors.New(config.Red + "No Question exist with this id" + config.Reset)
+			return models.NewError(models.ErrNotFound, "No Question exist with this id")
 		}
 	}
 	return err
@@ -149,20 +233,26 @@ func (r *MySQLQuestionRepository) GetQuestionsByPId(PId int) ([]*models.Question
 	}
 	return questions, nil
 }
-func (r *MySQLQuestionRepository) UpdateQuestion(QId int, answer string) error {
-	columns:=[]string{"replies"}
+// FindByQId returns sql.ErrNoRows when there is no such question.
+func (r *MySQLQuestionRepository) FindByQId(QId int) (*models.Question, error) {
+	columns:=[]string{"q_id","post_id","user_id","text","replies","created_at"}
 	condition1:="q_id"
-	query:=config.UpdateQuery(config.QuestionTable,condition1,"",columns)
-	//query := "UPDATE questions SET replies= JSON_ARRAY_APPEND(replies, '$' ,?) WHERE q_id = ?"
-	result, err := r.DB.Exec(query, fmt.Sprintf("JSON_ARRAY_APPEND(replies, '$' ,%s)",answer), QId)
-	if result != nil {
-		affectedRows, err := result.RowsAffected()
-		if err != nil {
-			return err
-		}
-		if affectedRows == 0 {
-			return errors.New(config.Red + "No Question exist with this id" + config.Reset)
+	query:=config.SelectQuery(config.QuestionTable,condition1,"",columns)
+	//query := "SELECT q_id, post_id, user_id, text, replies, created_at FROM questions WHERE q_id = ?"
+	var question models.Question
+	var replies string
+	var createdAt string
+	err := r.DB.QueryRow(query, QId).Scan(&question.QId, &question.PostId, &question.UserId, &question.Text, &replies, &createdAt)
+	if err != nil {
+		return nil, err
+	}
+	if replies != "" {
+		if err := json.Unmarshal([]byte(replies), &question.Replies); err != nil {
+			return nil, err
 		}
 	}
-	return err
+	if question.CreatedAt, err = parseTimestamp(createdAt); err != nil {
+		return nil, err
+	}
+	return &question, nil
 }
@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxSearchTerms bounds how many words of a search the LIKE fallback looks
+// for, as every word adds conditions to the query.
+const maxSearchTerms = 8
+
+// searchTerms splits a search into the distinct lower-case words the LIKE
+// fallback and the in-memory repositories look for.
+func searchTerms(search string) []string {
+	seen := make(map[string]bool)
+	var terms []string
+	for _, term := range strings.Fields(strings.ToLower(search)) {
+		if seen[term] || len(terms) == maxSearchTerms {
+			continue
+		}
+		seen[term] = true
+		terms = append(terms, term)
+	}
+	return terms
+}
+
+// likeMatch is an expression a term is looked for in with LIKE, and what
+// finding it there adds to the score.
+type likeMatch struct {
+	expression string
+	weight     int
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// likeScore stands in for a FULLTEXT relevance score: it adds up the weight
+// of every match each term is found in. It returns the SQL expression and
+// its arguments.
+func likeScore(terms []string, matches []likeMatch) (string, []any) {
+	var parts []string
+	var args []any
+	for _, term := range terms {
+		pattern := "%" + likeEscaper.Replace(term) + "%"
+		for _, match := range matches {
+			parts = append(parts, fmt.Sprintf("(CASE WHEN %s LIKE ? ESCAPE '\\' THEN %d ELSE 0 END)", match.expression, match.weight))
+			args = append(args, pattern)
+		}
+	}
+	return strings.Join(parts, " + "), args
+}
+
+// containsScore is likeScore for the in-memory repositories: every term
+// found in text adds weight.
+func containsScore(terms []string, weight int, text string) int {
+	text = strings.ToLower(text)
+	score := 0
+	for _, term := range terms {
+		if strings.Contains(text, term) {
+			score += weight
+		}
+	}
+	return score
+}
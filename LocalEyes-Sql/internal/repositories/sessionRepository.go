@@ -0,0 +1,76 @@
+package repositories
+
+import (
+	"fmt"
+	_ "github.com/go-sql-driver/mysql"
+	"localEyes/config"
+	"localEyes/internal/models"
+	"time"
+)
+
+type MySQLSessionRepository struct {
+	DB DBTX
+}
+
+func NewMySQLSessionRepository(Db DBTX) *MySQLSessionRepository {
+	return &MySQLSessionRepository{
+		DB: Db,
+	}
+}
+
+func (r *MySQLSessionRepository) Create(session *models.Session) error {
+	columns := []string{"token_hash", "user_id", "created_at", "expires_at"}
+	query := config.InsertQuery(config.SessionTable, columns)
+	//query := "INSERT INTO sessions (token_hash, user_id, created_at, expires_at) VALUES (?, ?, ?, ?)"
+	_, err := r.DB.Exec(query, session.TokenHash, session.UserId, session.CreatedAt, session.ExpiresAt.Unix())
+	return err
+}
+
+// FindByTokenHash returns sql.ErrNoRows when there is no such session.
+func (r *MySQLSessionRepository) FindByTokenHash(tokenHash string) (*models.Session, error) {
+	columns := []string{"token_hash", "user_id", "created_at", "expires_at"}
+	condition1 := "token_hash"
+	query := config.SelectQuery(config.SessionTable, condition1, "", columns)
+	//query := "SELECT token_hash, user_id, created_at, expires_at FROM sessions WHERE token_hash = ?"
+	var session models.Session
+	var createdAt string
+	var expiresAt int64
+	err := r.DB.QueryRow(query, tokenHash).Scan(&session.TokenHash, &session.UserId, &createdAt, &expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	session.CreatedAt, err = parseTimestamp(createdAt)
+	if err != nil {
+		return nil, err
+	}
+	session.ExpiresAt = time.Unix(expiresAt, 0)
+	return &session, nil
+}
+
+// DeleteByTokenHash ends one session; ending a session that is already gone
+// is not an error.
+func (r *MySQLSessionRepository) DeleteByTokenHash(tokenHash string) error {
+	condition1 := "token_hash"
+	query := config.DeleteQuery(config.SessionTable, condition1, "")
+	//query := "DELETE FROM sessions WHERE token_hash = ?"
+	_, err := r.DB.Exec(query, tokenHash)
+	return err
+}
+
+func (r *MySQLSessionRepository) DeleteForUser(UId int) error {
+	condition1 := "user_id"
+	query := config.DeleteQuery(config.SessionTable, condition1, "")
+	//query := "DELETE FROM sessions WHERE user_id = ?"
+	_, err := r.DB.Exec(query, UId)
+	return err
+}
+
+func (r *MySQLSessionRepository) DeleteExpired(now time.Time) (int64, error) {
+	query := fmt.Sprintf("DELETE FROM %s WHERE expires_at <= ?", config.SessionTable)
+	//query := "DELETE FROM sessions WHERE expires_at <= ?"
+	result, err := r.DB.Exec(query, now.Unix())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
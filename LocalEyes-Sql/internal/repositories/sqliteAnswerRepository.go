@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	_ "modernc.org/sqlite"
+)
+
+// SQLiteAnswerRepository runs the MySQL answer queries unchanged; none of
+// them depend on MySQL-only syntax.
+type SQLiteAnswerRepository struct {
+	*MySQLAnswerRepository
+}
+
+func NewSQLiteAnswerRepository(Db DBTX) *SQLiteAnswerRepository {
+	return &SQLiteAnswerRepository{
+		MySQLAnswerRepository: NewMySQLAnswerRepository(Db),
+	}
+}
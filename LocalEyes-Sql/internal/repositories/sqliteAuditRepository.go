@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	_ "modernc.org/sqlite"
+)
+
+// SQLiteAuditRepository runs the MySQL audit queries unchanged; none of them
+// depend on MySQL-only syntax.
+type SQLiteAuditRepository struct {
+	*MySQLAuditRepository
+}
+
+func NewSQLiteAuditRepository(Db DBTX) *SQLiteAuditRepository {
+	return &SQLiteAuditRepository{
+		MySQLAuditRepository: NewMySQLAuditRepository(Db),
+	}
+}
@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	_ "modernc.org/sqlite"
+)
+
+// SQLiteCategoryRepository runs the MySQL category queries unchanged; none
+// of them depend on MySQL-only syntax.
+type SQLiteCategoryRepository struct {
+	*MySQLCategoryRepository
+}
+
+func NewSQLiteCategoryRepository(Db DBTX) *SQLiteCategoryRepository {
+	return &SQLiteCategoryRepository{
+		MySQLCategoryRepository: NewMySQLCategoryRepository(Db),
+	}
+}
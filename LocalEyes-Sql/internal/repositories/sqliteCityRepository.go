@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	_ "modernc.org/sqlite"
+)
+
+// SQLiteCityRepository runs the MySQL city queries unchanged; none of them
+// depend on MySQL-only syntax.
+type SQLiteCityRepository struct {
+	*MySQLCityRepository
+}
+
+func NewSQLiteCityRepository(Db DBTX) *SQLiteCityRepository {
+	return &SQLiteCityRepository{
+		MySQLCityRepository: NewMySQLCityRepository(Db),
+	}
+}
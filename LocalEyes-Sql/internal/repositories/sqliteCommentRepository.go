@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	_ "modernc.org/sqlite"
+)
+
+// SQLiteCommentRepository runs the MySQL comment queries unchanged; none of
+// them depend on MySQL-only syntax.
+type SQLiteCommentRepository struct {
+	*MySQLCommentRepository
+}
+
+func NewSQLiteCommentRepository(Db DBTX) *SQLiteCommentRepository {
+	return &SQLiteCommentRepository{
+		MySQLCommentRepository: NewMySQLCommentRepository(Db),
+	}
+}
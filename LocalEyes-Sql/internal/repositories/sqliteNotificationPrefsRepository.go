@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	_ "modernc.org/sqlite"
+)
+
+// SQLiteNotificationPrefsRepository runs the MySQL notification preference
+// queries unchanged; none of them depend on MySQL-only syntax.
+type SQLiteNotificationPrefsRepository struct {
+	*MySQLNotificationPrefsRepository
+}
+
+func NewSQLiteNotificationPrefsRepository(Db DBTX) *SQLiteNotificationPrefsRepository {
+	return &SQLiteNotificationPrefsRepository{
+		MySQLNotificationPrefsRepository: NewMySQLNotificationPrefsRepository(Db),
+	}
+}
@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	_ "modernc.org/sqlite"
+)
+
+// SQLiteNotificationRepository runs the MySQL notification queries
+// unchanged; none of them depend on MySQL-only syntax.
+type SQLiteNotificationRepository struct {
+	*MySQLNotificationRepository
+}
+
+func NewSQLiteNotificationRepository(Db DBTX) *SQLiteNotificationRepository {
+	return &SQLiteNotificationRepository{
+		MySQLNotificationRepository: NewMySQLNotificationRepository(Db),
+	}
+}
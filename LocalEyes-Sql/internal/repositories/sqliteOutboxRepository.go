@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	_ "modernc.org/sqlite"
+)
+
+// SQLiteOutboxRepository runs the MySQL outbox queries unchanged; none of
+// them depend on MySQL-only syntax.
+type SQLiteOutboxRepository struct {
+	*MySQLOutboxRepository
+}
+
+func NewSQLiteOutboxRepository(Db DBTX) *SQLiteOutboxRepository {
+	return &SQLiteOutboxRepository{
+		MySQLOutboxRepository: NewMySQLOutboxRepository(Db),
+	}
+}
@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	_ "modernc.org/sqlite"
+)
+
+// SQLitePostLikeRepository runs the MySQL like queries unchanged; none of
+// them depend on MySQL-only syntax.
+type SQLitePostLikeRepository struct {
+	*MySQLPostLikeRepository
+}
+
+func NewSQLitePostLikeRepository(Db DBTX) *SQLitePostLikeRepository {
+	return &SQLitePostLikeRepository{
+		MySQLPostLikeRepository: NewMySQLPostLikeRepository(Db),
+	}
+}
@@ -0,0 +1,41 @@
+package repositories
+
+import (
+	"fmt"
+	"localEyes/config"
+	"localEyes/internal/models"
+	_ "modernc.org/sqlite"
+	"strings"
+)
+
+// SQLitePostRepository runs the MySQL post queries unchanged, except for the
+// search, as SQLite has no FULLTEXT indexes.
+type SQLitePostRepository struct {
+	*MySQLPostRepository
+}
+
+func NewSQLitePostRepository(Db DBTX) *SQLitePostRepository {
+	return &SQLitePostRepository{
+		MySQLPostRepository: NewMySQLPostRepository(Db),
+	}
+}
+
+// SearchPosts falls back to LIKE: each word found in the title scores two,
+// each word found in the content one.
+func (r *SQLitePostRepository) SearchPosts(city, search string, limit, offset int) ([]*models.Post, error) {
+	terms := searchTerms(search)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+	score, args := likeScore(terms, []likeMatch{{"title", 2}, {"content", 1}})
+	where := "score > 0"
+	if city != "" {
+		where += " AND city = ?"
+		args = append(args, city)
+	}
+	columns := strings.Join(postColumns, ", ")
+	query := fmt.Sprintf("SELECT %[1]s FROM (SELECT %[1]s, %[2]s AS score FROM %[3]s) WHERE %[4]s ORDER BY score DESC, post_id DESC LIMIT ? OFFSET ?",
+		columns, score, config.PostTable, where)
+	//query := "SELECT post_id, ... FROM (SELECT post_id, ..., (CASE WHEN title LIKE ? ESCAPE '\\' THEN 2 ELSE 0 END) + ... AS score FROM posts) WHERE score > 0 AND city = ? ORDER BY score DESC, post_id DESC LIMIT ? OFFSET ?"
+	return r.queryPosts(query, append(args, limit, offset)...)
+}
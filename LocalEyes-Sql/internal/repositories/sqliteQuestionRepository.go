@@ -0,0 +1,37 @@
+package repositories
+
+import (
+	"fmt"
+	"localEyes/config"
+	"localEyes/internal/models"
+	_ "modernc.org/sqlite"
+	"strings"
+)
+
+// SQLiteQuestionRepository reuses the MySQL queries, swapping the FULLTEXT
+// search for LIKE.
+type SQLiteQuestionRepository struct {
+	*MySQLQuestionRepository
+}
+
+func NewSQLiteQuestionRepository(Db DBTX) *SQLiteQuestionRepository {
+	return &SQLiteQuestionRepository{
+		MySQLQuestionRepository: NewMySQLQuestionRepository(Db),
+	}
+}
+
+// SearchQuestions falls back to LIKE: each word found in the question scores
+// two, each word found in any of its answers one.
+func (r *SQLiteQuestionRepository) SearchQuestions(search string, limit, offset int) ([]*models.Question, error) {
+	terms := searchTerms(search)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+	answers := fmt.Sprintf("(SELECT group_concat(a.text, ' ') FROM %s a WHERE a.q_id = %s.q_id)", config.AnswerTable, config.QuestionTable)
+	score, args := likeScore(terms, []likeMatch{{"text", 2}, {answers, 1}})
+	columns := strings.Join(questionColumns, ", ")
+	query := fmt.Sprintf("SELECT %[1]s FROM (SELECT %[1]s, %[2]s AS score FROM %[3]s) WHERE score > 0 ORDER BY score DESC, q_id DESC LIMIT ? OFFSET ?",
+		columns, score, config.QuestionTable)
+	//query := "SELECT q_id, ... FROM (SELECT q_id, ..., (CASE WHEN text LIKE ? ESCAPE '\\' THEN 2 ELSE 0 END) + ... AS score FROM questions) WHERE score > 0 ORDER BY score DESC, q_id DESC LIMIT ? OFFSET ?"
+	return r.queryQuestions(query, append(args, limit, offset)...)
+}
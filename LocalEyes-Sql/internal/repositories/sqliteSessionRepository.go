@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	_ "modernc.org/sqlite"
+)
+
+// SQLiteSessionRepository runs the MySQL session queries unchanged; none of
+// them depend on MySQL-only syntax.
+type SQLiteSessionRepository struct {
+	*MySQLSessionRepository
+}
+
+func NewSQLiteSessionRepository(Db DBTX) *SQLiteSessionRepository {
+	return &SQLiteSessionRepository{
+		MySQLSessionRepository: NewMySQLSessionRepository(Db),
+	}
+}
@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	_ "modernc.org/sqlite"
+)
+
+// SQLiteUserRepository runs the MySQL user queries unchanged; none of them
+// depend on MySQL-only syntax.
+type SQLiteUserRepository struct {
+	*MySQLUserRepository
+}
+
+func NewSQLiteUserRepository(Db DBTX) *SQLiteUserRepository {
+	return &SQLiteUserRepository{
+		MySQLUserRepository: NewMySQLUserRepository(Db),
+	}
+}
@@ -0,0 +1,24 @@
+package repositories
+
+import (
+	"fmt"
+	"time"
+)
+
+// timestampLayouts covers how created_at comes back once scanned into a
+// string: RFC3339 from the MySQL driver with parseTime, plain DATETIME text,
+// and the format the SQLite driver stores time.Time values in.
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04:05.999999999-07:00",
+}
+
+func parseTimestamp(value string) (time.Time, error) {
+	for _, layout := range timestampLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognised timestamp %q", value)
+}
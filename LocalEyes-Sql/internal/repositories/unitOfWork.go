@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"database/sql"
+	"localEyes/config"
+	"localEyes/internal/interfaces"
+)
+
+// DBTX is the part of *sql.DB that the repositories use. *sql.Tx satisfies
+// it as well, which is how a repository joins a unit of work.
+type DBTX interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// NewSQLRepositories builds the repositories for driver on top of Db, which
+// may be the connection pool or an open transaction.
+func NewSQLRepositories(driver string, Db DBTX) interfaces.Repositories {
+	if driver == config.SQLiteDriver {
+		return interfaces.Repositories{
+			Users:             NewSQLiteUserRepository(Db),
+			Posts:             NewSQLitePostRepository(Db),
+			Questions:         NewSQLiteQuestionRepository(Db),
+			Answers:           NewSQLiteAnswerRepository(Db),
+			Likes:             NewSQLitePostLikeRepository(Db),
+			Sessions:          NewSQLiteSessionRepository(Db),
+			Audit:             NewSQLiteAuditRepository(Db),
+			Categories:        NewSQLiteCategoryRepository(Db),
+			Cities:            NewSQLiteCityRepository(Db),
+			Comments:          NewSQLiteCommentRepository(Db),
+			Notifications:     NewSQLiteNotificationRepository(Db),
+			NotificationPrefs: NewSQLiteNotificationPrefsRepository(Db),
+			Outbox:            NewSQLiteOutboxRepository(Db),
+		}
+	}
+	return interfaces.Repositories{
+		Users:             NewMySQLUserRepository(Db),
+		Posts:             NewMySQLPostRepository(Db),
+		Questions:         NewMySQLQuestionRepository(Db),
+		Answers:           NewMySQLAnswerRepository(Db),
+		Likes:             NewMySQLPostLikeRepository(Db),
+		Sessions:          NewMySQLSessionRepository(Db),
+		Audit:             NewMySQLAuditRepository(Db),
+		Categories:        NewMySQLCategoryRepository(Db),
+		Cities:            NewMySQLCityRepository(Db),
+		Comments:          NewMySQLCommentRepository(Db),
+		Notifications:     NewMySQLNotificationRepository(Db),
+		NotificationPrefs: NewMySQLNotificationPrefsRepository(Db),
+		Outbox:            NewMySQLOutboxRepository(Db),
+	}
+}
+
+type SQLUnitOfWork struct {
+	DB     *sql.DB
+	driver string
+}
+
+func NewSQLUnitOfWork(Db *sql.DB, driver string) *SQLUnitOfWork {
+	return &SQLUnitOfWork{
+		DB:     Db,
+		driver: driver,
+	}
+}
+
+// Do runs fn against repositories bound to a single transaction, committing
+// when fn returns nil and rolling back otherwise.
+func (u *SQLUnitOfWork) Do(fn func(repos interfaces.Repositories) error) error {
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+	if err := fn(NewSQLRepositories(u.driver, tx)); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
@@ -3,17 +3,17 @@ package repositories
 import (
 	"database/sql"
 	"encoding/json"
-	"errors"
+	"fmt"
 	"localEyes/config"
 	"localEyes/internal/models"
 	"localEyes/utils"
 )
 
 type MySQLUserRepository struct {
-	DB *sql.DB
+	DB DBTX
 }
 
-func NewMySQLUserRepository(Db *sql.DB) *MySQLUserRepository {
+func NewMySQLUserRepository(Db DBTX) *MySQLUserRepository {
 	return &MySQLUserRepository{
 		DB: Db,
 	}
@@ -21,78 +21,57 @@ func NewMySQLUserRepository(Db *sql.DB) *MySQLUserRepository {
 
 func (r *MySQLUserRepository) Create(user *models.User) error {
 	notification, err := json.Marshal(user.Notification)
-	columns := []string{"username", "password", "is_active", "city", "dwelling_age", "tag", "notification"}
+	role := user.Role
+	if role == "" {
+		role = models.RoleUser
+	}
+	columns := []string{"username", "password", "is_active", "city", "dwelling_age", "tag", "notification", "role"}
 	query := config.InsertQuery(config.UserTable, columns)
-	//query := "INSERT INTO users (username, password, is_active, city, dwelling_age, tag, notification) VALUES (?, ?, ?, ?, ?, ?, ?)"
-	_, err = r.DB.Exec(query, user.Username, user.Password, user.IsActive, user.City, user.DwellingAge, user.Tag, notification)
+	//query := "INSERT INTO users (username, password, is_active, city, dwelling_age, tag, notification, role) VALUES (?, ?, ?, ?, ?, ?, ?, ?)"
+	_, err = r.DB.Exec(query, user.Username, user.Password, user.IsActive, user.City, user.DwellingAge, user.Tag, notification, role)
 	return err
 }
 
+// FindByUId returns sql.ErrNoRows when there is no such user.
 func (r *MySQLUserRepository) FindByUId(UId int) (*models.User, error) {
 	var user models.User
-	columns := []string{"id", "username", "password", "is_active", "city", "dwelling_age", "tag", "notification"}
+	columns := []string{"id", "username", "password", "is_active", "city", "dwelling_age", "tag", "notification", "role"}
 	condition := "id"
 	query := config.SelectQuery(config.UserTable, condition, "", columns)
-	//query := "SELECT id, username, password, is_active, city, dwelling_age, tag, notification FROM users WHERE id = ?"
+	//query := "SELECT id, username, password, is_active, city, dwelling_age, tag, notification, role FROM users WHERE id = ?"
 	var notification []byte
-	err := r.DB.QueryRow(query, UId).Scan(&user.UId, &user.Username, &user.Password, &user.IsActive, &user.City, &user.DwellingAge, &user.Tag, &notification)
-	err = json.Unmarshal(notification, &user.Notification)
+	err := r.DB.QueryRow(query, UId).Scan(&user.UId, &user.Username, &user.Password, &user.IsActive, &user.City, &user.DwellingAge, &user.Tag, &notification, &user.Role)
 	if err != nil {
 		return nil, err
 	}
-	return &user, err
+	if err := json.Unmarshal(notification, &user.Notification); err != nil {
+		return nil, err
+	}
+	return &user, nil
 }
 
+// FindByUsername returns sql.ErrNoRows when there is no such user.
 func (r *MySQLUserRepository) FindByUsername(username string) (*models.User, error) {
 	var user models.User
-	columns := []string{"id", "username", "password", "is_active", "city", "dwelling_age", "tag", "notification"}
+	columns := []string{"id", "username", "password", "is_active", "city", "dwelling_age", "tag", "notification", "role"}
 	condition1 := "username"
 	query := config.SelectQuery(config.UserTable, condition1, "", columns)
-	//query := "SELECT id, username, password, is_active, city, dwelling_age, tag, notification FROM users WHERE username = ?"
+	//query := "SELECT id, username, password, is_active, city, dwelling_age, tag, notification, role FROM users WHERE username = ?"
 	var notification []byte
-	err := r.DB.QueryRow(query, username).Scan(&user.UId, &user.Username, &user.Password, &user.IsActive, &user.City, &user.DwellingAge, &user.Tag, &notification)
-	err = json.Unmarshal(notification, &user.Notification)
+	err := r.DB.QueryRow(query, username).Scan(&user.UId, &user.Username, &user.Password, &user.IsActive, &user.City, &user.DwellingAge, &user.Tag, &notification, &user.Role)
 	if err != nil {
 		return nil, err
 	}
-	return &user, err
-}
-
-func (r *MySQLUserRepository) FindByUsernamePassword(username, password string) (*models.User, error) {
-	var user models.User
-	columns := []string{"id", "username", "password", "is_active", "city", "dwelling_age", "tag", "notification"}
-	condition1 := "username"
-	condition2 := "password"
-	query := config.SelectQuery(config.UserTable, condition1, condition2, columns)
-	//query := "SELECT id, username, password, is_active, city, dwelling_age, tag, notification FROM users WHERE username = ? AND password = ?"
-	var notification []byte
-	err := r.DB.QueryRow(query, username, password).Scan(&user.UId, &user.Username, &user.Password, &user.IsActive, &user.City, &user.DwellingAge, &user.Tag, &notification)
-	err = json.Unmarshal(notification, &user.Notification)
-	if err != nil {
+	if err := json.Unmarshal(notification, &user.Notification); err != nil {
 		return nil, err
 	}
-	return &user, err
-}
-
-func (r *MySQLUserRepository) FindAdminByUsernamePassword(username, password string) (*models.Admin, error) {
-	var admin models.Admin
-	columns := []string{"id", "username", "password"}
-	condition1 := "username"
-	condition2 := "password"
-	query := config.SelectQuery(config.UserTable, condition1, condition2, columns)
-	//query := "SELECT id, username, password FROM users WHERE username = ? AND password = ?"
-	row := r.DB.QueryRow(query, username, password)
-	if row != nil {
-		err := row.Scan(&admin.User.UId, &admin.User.Username, &admin.User.Password)
-		return &admin, err
-	}
-	return nil, errors.New("not found")
+	return &user, nil
 }
 
 func (r *MySQLUserRepository) GetAllUsers() ([]*models.User, error) {
-	columns := []string{"id", "username", "password", "is_active", "city", "dwelling_age", "tag", "notification"}
+	columns := []string{"id", "username", "password", "is_active", "city", "dwelling_age", "tag", "notification", "role"}
 	query := config.SelectQuery(config.UserTable, "", "", columns)
-	//query := "SELECT id, username, password, is_active, city, dwelling_age, tag, notification FROM users"
+	//query := "SELECT id, username, password, is_active, city, dwelling_age, tag, notification, role FROM users"
 	rows, err := r.DB.Query(query)
 	if err != nil {
 		return nil, err
@@ -108,7 +87,7 @@ func (r *MySQLUserRepository) GetAllUsers() ([]*models.User, error) {
 	for rows.Next() {
 		var user models.User
 		var notification []byte
-		if err := rows.Scan(&user.UId, &user.Username, &user.Password, &user.IsActive, &user.City, &user.DwellingAge, &user.Tag, &notification); err != nil {
+		if err := rows.Scan(&user.UId, &user.Username, &user.Password, &user.IsActive, &user.City, &user.DwellingAge, &user.Tag, &notification, &user.Role); err != nil {
 			return nil, err
 		}
 		err = json.Unmarshal(notification, &user.Notification)
@@ -120,6 +99,41 @@ func (r *MySQLUserRepository) GetAllUsers() ([]*models.User, error) {
 	return users, nil
 }
 
+// ListUsers returns limit users from offset on in the given order.
+func (r *MySQLUserRepository) ListUsers(order string, limit, offset int) ([]*models.User, error) {
+	clause, err := pageClause(userOrders, order)
+	if err != nil {
+		return nil, err
+	}
+	columns := []string{"id", "username", "password", "is_active", "city", "dwelling_age", "tag", "notification", "role"}
+	query := config.SelectQuery(config.UserTable, "", "", columns) + clause
+	//query := "SELECT id, username, password, is_active, city, dwelling_age, tag, notification, role FROM users ORDER BY id DESC LIMIT ? OFFSET ?"
+	rows, err := r.DB.Query(query, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			utils.Logger.Println("ERROR: Error closing rows:", err)
+		}
+	}(rows)
+
+	var users []*models.User
+	for rows.Next() {
+		var user models.User
+		var notification []byte
+		if err := rows.Scan(&user.UId, &user.Username, &user.Password, &user.IsActive, &user.City, &user.DwellingAge, &user.Tag, &notification, &user.Role); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(notification, &user.Notification); err != nil {
+			return nil, err
+		}
+		users = append(users, &user)
+	}
+	return users, rows.Err()
+}
+
 func (r *MySQLUserRepository) DeleteByUId(UId int) error {
 	condition1 := "id"
 	query := config.DeleteQuery(config.UserTable, condition1, "")
@@ -131,7 +145,7 @@ func (r *MySQLUserRepository) DeleteByUId(UId int) error {
 			return err
 		}
 		if affectedRows == 0 {
-			return errors.New(config.Red + "No user exist with this id" + config.Reset)
+			return models.NewError(models.ErrNotFound, "No user exist with this id")
 		}
 	}
 	return err
@@ -149,28 +163,73 @@ func (r *MySQLUserRepository) UpdateActiveStatus(UId int, status bool) error {
 			return err
 		}
 		if affectedRows == 0 {
-			return errors.New(config.Red + "No inActive user exist with this id" + config.Reset)
+			return models.NewError(models.ErrNotFound, "No inActive user exist with this id")
 		}
 	}
 	return err
 }
 
-func (r *MySQLUserRepository) PushNotification(UId int, title string) error {
-	columns := "notification= JSON_ARRAY_APPEND(notification, '$' ,?)"
-	condition1 := "id!=?"
-	condition2 := "username!=?"
-	query := config.UpdateQueryWithValue(config.UserTable, condition1, condition2, columns)
-	//query := "UPDATE users SET notification= JSON_ARRAY_APPEND(notification, '$' ,?) WHERE id != ?"
-	notification := "New post: " + title + "\n"
-	_, err := r.DB.Exec(query, notification, UId, "admin")
+func (r *MySQLUserRepository) UpdatePassword(UId int, password string) error {
+	condition1 := "id"
+	columns := []string{"password"}
+	query := config.UpdateQuery(config.UserTable, condition1, "", columns)
+	//query := "UPDATE users SET password = ? WHERE id = ?"
+	result, err := r.DB.Exec(query, password, UId)
+	if result != nil {
+		affectedRows, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affectedRows == 0 {
+			return models.NewError(models.ErrNotFound, "No user exist with this id")
+		}
+	}
 	return err
 }
 
-func (r *MySQLUserRepository) ClearNotification(UId int) error {
-	columns := []string{"notification"}
+func (r *MySQLUserRepository) UpdateRole(UId int, role string) error {
 	condition1 := "id"
+	columns := []string{"role"}
 	query := config.UpdateQuery(config.UserTable, condition1, "", columns)
-	//query := "UPDATE users SET notification =?  WHERE id = ?"
-	_, err := r.DB.Exec(query, "[]", UId)
-	return err
+	//query := "UPDATE users SET role = ? WHERE id = ?"
+	result, err := r.DB.Exec(query, role, UId)
+	if err != nil {
+		return err
+	}
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affectedRows == 0 {
+		return models.NewError(models.ErrNotFound, "No user exist with this id")
+	}
+	return nil
+}
+
+func (r *MySQLUserRepository) CountByRole(role string) (int, error) {
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE role = ?", config.UserTable)
+	//query := "SELECT COUNT(*) FROM users WHERE role = ?"
+	var count int
+	err := r.DB.QueryRow(query, role).Scan(&count)
+	return count, err
+}
+
+// UpdateCity moves the user to another city.
+func (r *MySQLUserRepository) UpdateCity(UId int, city string) error {
+	condition1 := "id"
+	columns := []string{"city"}
+	query := config.UpdateQuery(config.UserTable, condition1, "", columns)
+	//query := "UPDATE users SET city = ? WHERE id = ?"
+	result, err := r.DB.Exec(query, city, UId)
+	if err != nil {
+		return err
+	}
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affectedRows == 0 {
+		return models.NewError(models.ErrNotFound, "No user exist with this id")
+	}
+	return nil
 }
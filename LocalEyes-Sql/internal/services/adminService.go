@@ -1,32 +1,74 @@
 package services
 
 import (
+	"database/sql"
 	"errors"
-	"localEyes/config"
+	"fmt"
 	"localEyes/internal/interfaces"
 	"localEyes/internal/models"
+	"localEyes/utils"
 )
 
 type AdminService struct {
-	UserRepo interfaces.UserRepository
-	PostRepo interfaces.PostRepository
-	QuesRepo interfaces.QuestionRepository
+	UserRepo   interfaces.UserRepository
+	PostRepo   interfaces.PostRepository
+	QuesRepo   interfaces.QuestionRepository
+	AnswerRepo interfaces.AnswerRepository
+	AuditRepo  interfaces.AuditRepository
+	uow        interfaces.UnitOfWork
 }
 
-func NewAdminService(userRepo interfaces.UserRepository, postRepo interfaces.PostRepository, quesRepo interfaces.QuestionRepository) *AdminService {
-	return &AdminService{UserRepo: userRepo, PostRepo: postRepo, QuesRepo: quesRepo}
+func NewAdminService(userRepo interfaces.UserRepository, postRepo interfaces.PostRepository, quesRepo interfaces.QuestionRepository, answerRepo interfaces.AnswerRepository, auditRepo interfaces.AuditRepository, uow interfaces.UnitOfWork) *AdminService {
+	return &AdminService{UserRepo: userRepo, PostRepo: postRepo, QuesRepo: quesRepo, AnswerRepo: answerRepo, AuditRepo: auditRepo, uow: uow}
 }
 
-func (s *AdminService) Login(password string) (*models.Admin, error) {
-	hashedPassword := HashPassword(password)
-	user, err := s.UserRepo.FindAdminByUsernamePassword("admin", hashedPassword)
-	if err != nil {
-		return nil, errors.New(config.Red + "Invalid username or password" + config.Reset)
+// Login lets a moderator or admin into the admin menus.
+func (s *AdminService) Login(username, password string) (*models.Admin, error) {
+	user, err := s.UserRepo.FindByUsername(username)
+	if err != nil || user == nil {
+		verifyMissingUser(password)
+		return nil, models.NewError(models.ErrUnauthorized, "Invalid username or password")
+	}
+	match, needsRehash := VerifyPassword(user.Password, password)
+	if !match {
+		return nil, models.NewError(models.ErrUnauthorized, "Invalid username or password")
+	}
+	if needsRehash {
+		rehashPassword(s.UserRepo, user, password)
+	}
+	return s.AdminFor(user)
+}
+
+// AdminFor gives a user who has already authenticated, with a password or
+// a session, access to the admin operations their role allows.
+func (s *AdminService) AdminFor(user *models.User) (*models.Admin, error) {
+	if !user.IsActive {
+		return nil, models.NewError(models.ErrForbidden, "InActive Account")
+	}
+	if !user.IsStaff() {
+		return nil, models.NewError(models.ErrForbidden, "You do not have permission to use admin commands")
+	}
+	return &models.Admin{User: *user}, nil
+}
+
+// authorize checks the permission against the admin's current role, so a
+// revoked role stops working at once rather than at the next login.
+func (s *AdminService) authorize(admin *models.Admin, permission models.Permission) error {
+	denied := models.NewError(models.ErrForbidden, "You do not have permission to do this")
+	if admin == nil {
+		return denied
 	}
-	return user, nil
+	user, err := s.UserRepo.FindByUId(admin.User.UId)
+	if err != nil || user == nil || !user.IsActive || !user.Can(permission) {
+		return denied
+	}
+	return nil
 }
 
-func (s *AdminService) GetAllUsers() ([]*models.User, error) {
+func (s *AdminService) GetAllUsers(admin *models.Admin) ([]*models.User, error) {
+	if err := s.authorize(admin, models.PermViewUsers); err != nil {
+		return nil, err
+	}
 	users, err := s.UserRepo.GetAllUsers()
 	if err != nil {
 		return nil, err
@@ -34,7 +76,10 @@ func (s *AdminService) GetAllUsers() ([]*models.User, error) {
 	return users, nil
 }
 
-func (s *AdminService) GetAllPosts() ([]*models.Post, error) {
+func (s *AdminService) GetAllPosts(admin *models.Admin) ([]*models.Post, error) {
+	if err := s.authorize(admin, models.PermViewContent); err != nil {
+		return nil, err
+	}
 	posts, err := s.PostRepo.GetAllPosts()
 	if err != nil {
 		return nil, err
@@ -42,48 +87,499 @@ func (s *AdminService) GetAllPosts() ([]*models.Post, error) {
 	return posts, nil
 }
 
-func (s *AdminService) GetAllQuestions() ([]*models.Question, error) {
+func (s *AdminService) GetAllQuestions(admin *models.Admin) ([]*models.Question, error) {
+	if err := s.authorize(admin, models.PermViewContent); err != nil {
+		return nil, err
+	}
 	questions, err := s.QuesRepo.GetAllQuestions()
 	if err != nil {
 		return nil, err
 	}
+	answers, err := s.AnswerRepo.GetAllAnswers()
+	if err != nil {
+		return nil, err
+	}
+	attachAnswers(questions, answers)
 	return questions, nil
 }
 
-func (s *AdminService) DeleteUser(UId int) error {
-	err1 := s.UserRepo.DeleteByUId(UId)
-	err2 := s.PostRepo.DeleteByUId(UId)
-	if err1 != nil {
-		return err1
-	} else if err2 != nil {
-		return err2
+func (s *AdminService) ListUsers(admin *models.Admin, page models.PageRequest) (*models.Page[*models.User], error) {
+	if err := s.authorize(admin, models.PermViewUsers); err != nil {
+		return nil, err
 	}
-	return nil
+	page, err := normalizePage(page, models.RecordSorts)
+	if err != nil {
+		return nil, err
+	}
+	users, err := s.UserRepo.ListUsers(page.Sort, page.Size+1, page.Offset())
+	if err != nil {
+		return nil, err
+	}
+	return pageOf(users, page), nil
 }
 
-func (s *AdminService) DeletePost(PId int) error {
-	err1 := s.PostRepo.DeleteByPId(PId)
-	err2 := s.QuesRepo.DeleteByPId(PId)
-	if err1 != nil {
-		return err1
-	} else if err2 != nil {
-		return err2
+// ListPosts returns one page of the posts made in city, or in every city
+// when city is empty.
+func (s *AdminService) ListPosts(admin *models.Admin, city string, page models.PageRequest) (*models.Page[*models.Post], error) {
+	if err := s.authorize(admin, models.PermViewContent); err != nil {
+		return nil, err
 	}
-	return nil
+	page, err := normalizePage(page, models.PostSorts)
+	if err != nil {
+		return nil, err
+	}
+	posts, err := s.PostRepo.ListPosts(models.PostFilter{City: CityName(city)}, page.Sort, page.Size+1, page.Offset())
+	if err != nil {
+		return nil, err
+	}
+	return pageOf(posts, page), nil
 }
 
-func (s *AdminService) DeleteQuestion(QId int) error {
-	err := s.QuesRepo.DeleteByQId(QId)
+// ListQuestions returns one page of questions on posts made in city, or in
+// every city when city is empty, with their answers. Only the answers to the
+// questions on the page are loaded.
+func (s *AdminService) ListQuestions(admin *models.Admin, city string, page models.PageRequest) (*models.Page[*models.Question], error) {
+	if err := s.authorize(admin, models.PermViewContent); err != nil {
+		return nil, err
+	}
+	page, err := normalizePage(page, models.RecordSorts)
 	if err != nil {
+		return nil, err
+	}
+	questions, err := s.QuesRepo.ListQuestions(CityName(city), page.Sort, page.Size+1, page.Offset())
+	if err != nil {
+		return nil, err
+	}
+	result := pageOf(questions, page)
+	for _, question := range result.Items {
+		answers, err := s.AnswerRepo.GetAnswersByQId(question.QId)
+		if err != nil {
+			return nil, err
+		}
+		question.Answers = answers
+	}
+	return result, nil
+}
+
+// DeleteUser removes the user together with their posts, the questions asked
+// on those posts, the questions they asked elsewhere, every answer tied to any
+// of these, the comments on their posts and their own comments, the likes they
+// gave or received, their sessions, their notifications and notification
+// preferences, the subscriptions to them as an author and the notifications
+// still to go out about their posts, all or nothing.
+func (s *AdminService) DeleteUser(admin *models.Admin, UId int) error {
+	if err := s.authorize(admin, models.PermManageUsers); err != nil {
 		return err
 	}
-	return nil
+	return s.uow.Do(func(repos interfaces.Repositories) error {
+		user, err := repos.Users.FindByUId(UId)
+		if err != nil {
+			return notFound(err, "No user exist with this id")
+		}
+		if err := repos.Sessions.DeleteForUser(UId); err != nil {
+			return err
+		}
+		if err := repos.Answers.DeleteForUser(UId); err != nil {
+			return err
+		}
+		if err := repos.Comments.DeleteForUser(UId); err != nil {
+			return err
+		}
+		if err := repos.Notifications.DeleteForUser(UId); err != nil {
+			return err
+		}
+		if err := repos.NotificationPrefs.DeleteForUser(UId); err != nil {
+			return err
+		}
+		if err := repos.Questions.DeleteByPostOwner(UId); err != nil {
+			return err
+		}
+		if err := repos.Questions.DeleteByUId(UId); err != nil {
+			return err
+		}
+		if err := repos.Posts.RemoveUserLikes(UId); err != nil {
+			return err
+		}
+		if err := repos.Likes.DeleteForUser(UId); err != nil {
+			return err
+		}
+		if err := repos.Outbox.DeleteForUser(UId); err != nil {
+			return err
+		}
+		if err := repos.Posts.DeleteByUId(UId); err != nil {
+			return err
+		}
+		if err := repos.Users.DeleteByUId(UId); err != nil {
+			return err
+		}
+		return audit(repos.Audit, &admin.User, models.AuditDeleteUser, models.TargetUser, UId, snapshotUser(user), nil)
+	})
+}
+
+func (s *AdminService) DeletePost(admin *models.Admin, PId int) error {
+	if err := s.authorize(admin, models.PermModerateContent); err != nil {
+		return err
+	}
+	return s.uow.Do(func(repos interfaces.Repositories) error {
+		posts, err := repos.Posts.GetPostsByPId(PId)
+		if err != nil {
+			return err
+		}
+		if len(posts) == 0 {
+			return models.NewError(models.ErrNotFound, "No Post exist with this id")
+		}
+		if err := repos.Posts.DeleteByPId(PId); err != nil {
+			return err
+		}
+		if err := repos.Likes.DeleteByPId(PId); err != nil {
+			return err
+		}
+		if err := repos.Answers.DeleteByPId(PId); err != nil {
+			return err
+		}
+		if err := repos.Questions.DeleteByPId(PId); err != nil {
+			return err
+		}
+		if err := repos.Comments.DeleteByPId(PId); err != nil {
+			return err
+		}
+		if err := repos.Outbox.DeleteByPId(PId); err != nil {
+			return err
+		}
+		if err := notify(repos, admin.User.UId, posts[0].UId, &models.Notification{Type: models.NotifyAdminAction,
+			SourceType: models.TargetPost, SourceId: PId, Message: fmt.Sprintf("A moderator removed your post %q", excerpt(posts[0].Title))}); err != nil {
+			return err
+		}
+		return audit(repos.Audit, &admin.User, models.AuditDeletePost, models.TargetPost, PId, snapshotPost(posts[0]), nil)
+	})
 }
 
-func (s *AdminService) ReActivate(UId int) error {
-	err := s.UserRepo.UpdateActiveStatus(UId, true)
+func (s *AdminService) DeleteQuestion(admin *models.Admin, QId int) error {
+	if err := s.authorize(admin, models.PermModerateContent); err != nil {
+		return err
+	}
+	return s.uow.Do(func(repos interfaces.Repositories) error {
+		question, err := repos.Questions.FindByQId(QId)
+		if err != nil {
+			return notFound(err, "No Question exist with this id")
+		}
+		if err := repos.Questions.DeleteByQId(QId); err != nil {
+			return err
+		}
+		if err := repos.Answers.DeleteByQId(QId); err != nil {
+			return err
+		}
+		if err := notify(repos, admin.User.UId, question.UserId, &models.Notification{Type: models.NotifyAdminAction,
+			SourceType: models.TargetPost, SourceId: question.PostId, Message: fmt.Sprintf("A moderator removed your question %q", excerpt(question.Text))}); err != nil {
+			return err
+		}
+		return audit(repos.Audit, &admin.User, models.AuditDeleteQuestion, models.TargetQuestion, QId, snapshotQuestion(question), nil)
+	})
+}
+
+func (s *AdminService) DeleteAnswer(admin *models.Admin, AnswerId int) error {
+	if err := s.authorize(admin, models.PermModerateContent); err != nil {
+		return err
+	}
+	return s.uow.Do(func(repos interfaces.Repositories) error {
+		answer, err := repos.Answers.FindByAnswerId(AnswerId)
+		if err != nil {
+			return notFound(err, "No Answer exist with this id")
+		}
+		if err := repos.Answers.DeleteByAnswerId(AnswerId); err != nil {
+			return err
+		}
+		if err := notify(repos, admin.User.UId, answer.UserId, &models.Notification{Type: models.NotifyAdminAction,
+			SourceType: models.TargetQuestion, SourceId: answer.QId, Message: fmt.Sprintf("A moderator removed your answer %q", excerpt(answer.Text))}); err != nil {
+			return err
+		}
+		return audit(repos.Audit, &admin.User, models.AuditDeleteAnswer, models.TargetAnswer, AnswerId, snapshotAnswer(answer), nil)
+	})
+}
+
+// DeleteComment removes anyone's comment the way its author could, leaving
+// a placeholder when others have replied to it.
+func (s *AdminService) DeleteComment(admin *models.Admin, CommentId int) error {
+	if err := s.authorize(admin, models.PermModerateContent); err != nil {
+		return err
+	}
+	return s.uow.Do(func(repos interfaces.Repositories) error {
+		comment, err := liveComment(repos.Comments, CommentId)
+		if err != nil {
+			return err
+		}
+		if err := removeComment(repos.Comments, comment); err != nil {
+			return err
+		}
+		if err := notify(repos, admin.User.UId, comment.UserId, &models.Notification{Type: models.NotifyAdminAction,
+			SourceType: models.TargetPost, SourceId: comment.PostId, Message: fmt.Sprintf("A moderator removed your comment %q", excerpt(comment.Text))}); err != nil {
+			return err
+		}
+		return audit(repos.Audit, &admin.User, models.AuditDeleteComment, models.TargetComment, CommentId, snapshotComment(comment), nil)
+	})
+}
+
+func (s *AdminService) ReActivate(admin *models.Admin, UId int) error {
+	if err := s.authorize(admin, models.PermManageUsers); err != nil {
+		return err
+	}
+	return s.uow.Do(func(repos interfaces.Repositories) error {
+		user, err := repos.Users.FindByUId(UId)
+		if err != nil {
+			return notFound(err, "No inActive user exist with this id")
+		}
+		if err := repos.Users.UpdateActiveStatus(UId, true); err != nil {
+			return err
+		}
+		if err := notify(repos, admin.User.UId, UId, &models.Notification{Type: models.NotifyAdminAction,
+			Message: "An admin reactivated your account"}); err != nil {
+			return err
+		}
+		after := *user
+		after.IsActive = true
+		return audit(repos.Audit, &admin.User, models.AuditReactivateUser, models.TargetUser, UId, snapshotUser(user), snapshotUser(&after))
+	})
+}
+
+// GrantRole gives the user a role. Admins cannot change their own role, so
+// the last admin cannot lock everyone out by accident.
+func (s *AdminService) GrantRole(admin *models.Admin, UId int, role string) error {
+	if err := s.authorize(admin, models.PermManageRoles); err != nil {
+		return err
+	}
+	if !models.ValidRole(role) {
+		return models.NewError(models.ErrInvalid, "Unknown role "+role+", use user, moderator or admin")
+	}
+	if UId == admin.User.UId {
+		return models.NewError(models.ErrForbidden, "You cannot change your own role")
+	}
+	return s.uow.Do(func(repos interfaces.Repositories) error {
+		user, err := repos.Users.FindByUId(UId)
+		if err != nil || user == nil {
+			return models.NewError(models.ErrNotFound, "No user exist with this id")
+		}
+		if user.Role == role {
+			return models.NewError(models.ErrConflict, "User already has the "+role+" role")
+		}
+		if err := repos.Users.UpdateRole(UId, role); err != nil {
+			return err
+		}
+		if err := notify(repos, admin.User.UId, UId, &models.Notification{Type: models.NotifyAdminAction,
+			Message: "An admin made you a " + role}); err != nil {
+			return err
+		}
+		after := *user
+		after.Role = role
+		return audit(repos.Audit, &admin.User, models.AuditChangeRole, models.TargetUser, UId, snapshotUser(user), snapshotUser(&after))
+	})
+}
+
+// BootstrapAdmin makes the account called username an admin without needing
+// an admin to grant it, so a fresh install can get its first one. It is for
+// the operator to run once: as soon as any admin exists it refuses, and roles
+// are granted by admins from then on.
+func (s *AdminService) BootstrapAdmin(username string) (*models.User, error) {
+	var after models.User
+	err := s.uow.Do(func(repos interfaces.Repositories) error {
+		admins, err := repos.Users.CountByRole(models.RoleAdmin)
+		if err != nil {
+			return err
+		}
+		if admins > 0 {
+			return models.NewError(models.ErrConflict, "An admin already exists, ask them to grant the role")
+		}
+		user, err := repos.Users.FindByUsername(username)
+		if err != nil {
+			return notFound(err, "No user exist with this username")
+		}
+		if err := repos.Users.UpdateRole(user.UId, models.RoleAdmin); err != nil {
+			return err
+		}
+		after = *user
+		after.Role = models.RoleAdmin
+		return audit(repos.Audit, user, models.AuditChangeRole, models.TargetUser, user.UId, snapshotUser(user), snapshotUser(&after))
+	})
 	if err != nil {
+		return nil, err
+	}
+	return &after, nil
+}
+
+// RevokeRole turns the user back into a plain user.
+func (s *AdminService) RevokeRole(admin *models.Admin, UId int) error {
+	return s.GrantRole(admin, UId, models.RoleUser)
+}
+
+// GetAuditLog returns the audit entries matching filter, oldest first.
+func (s *AdminService) GetAuditLog(admin *models.Admin, filter models.AuditFilter) ([]*models.AuditEntry, error) {
+	if err := s.authorize(admin, models.PermViewAudit); err != nil {
+		return nil, err
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && filter.To.Before(filter.From) {
+		return nil, models.NewError(models.ErrInvalid, "The end of the time range is before its start")
+	}
+	return s.AuditRepo.Find(filter)
+}
+
+// ListCategories returns every category, inactive ones included.
+func (s *AdminService) ListCategories(admin *models.Admin) ([]*models.Category, error) {
+	if err := s.authorize(admin, models.PermManageCategories); err != nil {
+		return nil, err
+	}
+	var categories []*models.Category
+	err := s.uow.Do(func(repos interfaces.Repositories) error {
+		var err error
+		categories, err = repos.Categories.GetAll()
 		return err
+	})
+	return categories, err
+}
+
+// CreateCategory adds an active category, open for posts straight away.
+func (s *AdminService) CreateCategory(admin *models.Admin, name, description string) (*models.Category, error) {
+	if err := s.authorize(admin, models.PermManageCategories); err != nil {
+		return nil, err
 	}
-	return nil
+	if !utils.ValidateCategoryName(name) {
+		return nil, models.NewError(models.ErrInvalid, categoryNameRule)
+	}
+	category := &models.Category{Name: name, Description: description, IsActive: true}
+	err := s.uow.Do(func(repos interfaces.Repositories) error {
+		if err := categoryNameFree(repos.Categories, name); err != nil {
+			return err
+		}
+		if err := repos.Categories.Create(category); err != nil {
+			return err
+		}
+		return audit(repos.Audit, &admin.User, models.AuditCreateCategory, models.TargetCategory, category.Id, nil, snapshotCategory(category))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+// UpdateCategory applies the changes to the category. A rename carries the
+// category's posts over to the new name.
+func (s *AdminService) UpdateCategory(admin *models.Admin, id int, update models.CategoryUpdate) (*models.Category, error) {
+	if err := s.authorize(admin, models.PermManageCategories); err != nil {
+		return nil, err
+	}
+	if update.Name != nil && !utils.ValidateCategoryName(*update.Name) {
+		return nil, models.NewError(models.ErrInvalid, categoryNameRule)
+	}
+	var after models.Category
+	err := s.uow.Do(func(repos interfaces.Repositories) error {
+		before, err := repos.Categories.FindById(id)
+		if err != nil {
+			return notFound(err, "No category exist with this id")
+		}
+		after = *before
+		if update.Name != nil && *update.Name != before.Name {
+			if err := categoryNameFree(repos.Categories, *update.Name); err != nil {
+				return err
+			}
+			if err := repos.Posts.UpdateType(before.Name, *update.Name); err != nil {
+				return err
+			}
+			after.Name = *update.Name
+		}
+		if update.Description != nil {
+			after.Description = *update.Description
+		}
+		if update.IsActive != nil {
+			after.IsActive = *update.IsActive
+		}
+		if after == *before {
+			return models.NewError(models.ErrConflict, "The category already looks like this")
+		}
+		if err := repos.Categories.Update(&after); err != nil {
+			return err
+		}
+		return audit(repos.Audit, &admin.User, models.AuditUpdateCategory, models.TargetCategory, id, snapshotCategory(before), snapshotCategory(&after))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &after, nil
+}
+
+// DeleteCategory removes a category nobody has posted under; one with posts
+// can only be deactivated, so the posts keep a category to be filtered by.
+// Subscriptions to it go with it.
+func (s *AdminService) DeleteCategory(admin *models.Admin, id int) error {
+	if err := s.authorize(admin, models.PermManageCategories); err != nil {
+		return err
+	}
+	return s.uow.Do(func(repos interfaces.Repositories) error {
+		category, err := repos.Categories.FindById(id)
+		if err != nil {
+			return notFound(err, "No category exist with this id")
+		}
+		posts, err := repos.Posts.ListPosts(models.PostFilter{Type: category.Name}, models.SortNewest, 1, 0)
+		if err != nil {
+			return err
+		}
+		if len(posts) > 0 {
+			return models.NewError(models.ErrConflict, "Category "+category.Name+" still has posts, deactivate it instead")
+		}
+		if err := repos.Categories.DeleteById(id); err != nil {
+			return err
+		}
+		if err := repos.NotificationPrefs.DeleteSubscriptionsTo(models.TargetCategory, id); err != nil {
+			return err
+		}
+		return audit(repos.Audit, &admin.User, models.AuditDeleteCategory, models.TargetCategory, id, snapshotCategory(category), nil)
+	})
+}
+
+const categoryNameRule = "Category names are 2 to 30 lowercase letters, digits or hyphens"
+
+func categoryNameFree(repo interfaces.CategoryRepository, name string) error {
+	_, err := repo.FindByName(name)
+	if err == nil {
+		return models.NewError(models.ErrConflict, "Category "+name+" already exists")
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	return err
+}
+
+// CreateCity opens LocalEyes in another city, so users can sign up or move
+// there.
+func (s *AdminService) CreateCity(admin *models.Admin, name string) (*models.City, error) {
+	if err := s.authorize(admin, models.PermManageCities); err != nil {
+		return nil, err
+	}
+	name = CityName(name)
+	if !utils.ValidateCityName(name) {
+		return nil, models.NewError(models.ErrInvalid, "City names are 2 to 40 letters, spaces or hyphens")
+	}
+	city := &models.City{Name: name}
+	err := s.uow.Do(func(repos interfaces.Repositories) error {
+		_, err := repos.Cities.FindByName(name)
+		if err == nil {
+			return models.NewError(models.ErrConflict, "City "+name+" already exists")
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		if err := repos.Cities.Create(city); err != nil {
+			return err
+		}
+		return audit(repos.Audit, &admin.User, models.AuditCreateCity, models.TargetCity, city.Id, nil, snapshotCity(city))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return city, nil
+}
+
+// notFound turns a missing row into message and passes other errors through.
+func notFound(err error, message string) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.NewError(models.ErrNotFound, message)
+	}
+	return err
 }
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"encoding/json"
+	"localEyes/internal/interfaces"
+	"localEyes/internal/models"
+	"time"
+)
+
+// The snapshot types fix what the audit log keeps of each kind of record;
+// password hashes are left out.
+
+type userSnapshot struct {
+	Id          int    `json:"id"`
+	Username    string `json:"username"`
+	City        string `json:"city"`
+	DwellingAge int    `json:"dwelling_age"`
+	Active      bool   `json:"active"`
+	Tag         string `json:"tag"`
+	Role        string `json:"role"`
+}
+
+type postSnapshot struct {
+	Id        int       `json:"id"`
+	UserId    int       `json:"user_id"`
+	Title     string    `json:"title"`
+	Type      string    `json:"type"`
+	Content   string    `json:"content"`
+	Likes     int       `json:"likes"`
+	City      string    `json:"city"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type questionSnapshot struct {
+	Id        int       `json:"id"`
+	PostId    int       `json:"post_id"`
+	UserId    int       `json:"user_id"`
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type answerSnapshot struct {
+	Id        int       `json:"id"`
+	QId       int       `json:"q_id"`
+	UserId    int       `json:"user_id"`
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type commentSnapshot struct {
+	Id        int       `json:"id"`
+	PostId    int       `json:"post_id"`
+	ParentId  int       `json:"parent_id"`
+	UserId    int       `json:"user_id"`
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type categorySnapshot struct {
+	Id          int    `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Active      bool   `json:"active"`
+}
+
+type citySnapshot struct {
+	Id   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func snapshotUser(user *models.User) userSnapshot {
+	return userSnapshot{Id: user.UId, Username: user.Username, City: user.City, DwellingAge: user.DwellingAge,
+		Active: user.IsActive, Tag: user.Tag, Role: user.Role}
+}
+
+func snapshotPost(post *models.Post) postSnapshot {
+	return postSnapshot{Id: post.PostId, UserId: post.UId, Title: post.Title, Type: post.Type, Content: post.Content,
+		Likes: post.Likes, City: post.City, CreatedAt: post.CreatedAt}
+}
+
+func snapshotQuestion(question *models.Question) questionSnapshot {
+	return questionSnapshot{Id: question.QId, PostId: question.PostId, UserId: question.UserId, Text: question.Text,
+		CreatedAt: question.CreatedAt}
+}
+
+func snapshotAnswer(answer *models.Answer) answerSnapshot {
+	return answerSnapshot{Id: answer.AnswerId, QId: answer.QId, UserId: answer.UserId, Text: answer.Text,
+		CreatedAt: answer.CreatedAt}
+}
+
+func snapshotComment(comment *models.Comment) commentSnapshot {
+	return commentSnapshot{Id: comment.CommentId, PostId: comment.PostId, ParentId: comment.ParentId, UserId: comment.UserId,
+		Text: comment.Text, CreatedAt: comment.CreatedAt}
+}
+
+func snapshotCategory(category *models.Category) categorySnapshot {
+	return categorySnapshot{Id: category.Id, Name: category.Name, Description: category.Description,
+		Active: category.IsActive}
+}
+
+func snapshotCity(city *models.City) citySnapshot {
+	return citySnapshot{Id: city.Id, Name: city.Name}
+}
+
+// audit appends an entry for an action actor took on a target. It is called
+// inside the action's unit of work, so the entry and the change are committed
+// or rolled back together. A nil before or after is stored as "".
+func audit(repo interfaces.AuditRepository, actor *models.User, action, targetType string, targetId int, before, after any) error {
+	entry := &models.AuditEntry{
+		ActorId:    actor.UId,
+		ActorName:  actor.Username,
+		Action:     action,
+		TargetType: targetType,
+		TargetId:   targetId,
+		CreatedAt:  time.Now(),
+	}
+	var err error
+	if entry.Before, err = snapshotJSON(before); err != nil {
+		return err
+	}
+	if entry.After, err = snapshotJSON(after); err != nil {
+		return err
+	}
+	return repo.Create(entry)
+}
+
+func snapshotJSON(snapshot any) (string, error) {
+	if snapshot == nil {
+		return "", nil
+	}
+	encoded, err := json.Marshal(snapshot)
+	return string(encoded), err
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"localEyes/internal/interfaces"
+	"localEyes/internal/models"
+	"strings"
+)
+
+type CategoryService struct {
+	repo interfaces.CategoryRepository
+}
+
+func NewCategoryService(repo interfaces.CategoryRepository) *CategoryService {
+	return &CategoryService{repo: repo}
+}
+
+// GiveCategories returns the categories by name, leaving out the inactive
+// ones when activeOnly is set.
+func (s *CategoryService) GiveCategories(activeOnly bool) ([]*models.Category, error) {
+	categories, err := s.repo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	if !activeOnly {
+		return categories, nil
+	}
+	active := make([]*models.Category, 0, len(categories))
+	for _, category := range categories {
+		if category.IsActive {
+			active = append(active, category)
+		}
+	}
+	return active, nil
+}
+
+// ValidateCategory checks a post type filter. Inactive categories still
+// filter, since their old posts are still around; an empty name means any.
+func (s *CategoryService) ValidateCategory(name string) error {
+	if name == "" {
+		return nil
+	}
+	_, err := s.repo.FindByName(name)
+	if err == nil {
+		return nil
+	}
+	return unknownCategory(err, name, s.repo)
+}
+
+// unknownCategory turns a missing category into an error listing the active
+// ones and passes other errors through.
+func unknownCategory(err error, name string, repo interfaces.CategoryRepository) error {
+	if !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	categories, err := repo.GetAll()
+	if err != nil {
+		return err
+	}
+	var names []string
+	for _, category := range categories {
+		if category.IsActive {
+			names = append(names, category.Name)
+		}
+	}
+	return models.NewError(models.ErrInvalid, "Unknown category "+name+", use "+strings.Join(names, ", "))
+}
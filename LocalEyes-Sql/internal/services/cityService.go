@@ -0,0 +1,64 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"localEyes/internal/interfaces"
+	"localEyes/internal/models"
+	"strings"
+)
+
+type CityService struct {
+	repo interfaces.CityRepository
+}
+
+func NewCityService(repo interfaces.CityRepository) *CityService {
+	return &CityService{repo: repo}
+}
+
+// GiveCities returns the cities LocalEyes is open in, by name.
+func (s *CityService) GiveCities() ([]*models.City, error) {
+	return s.repo.GetAll()
+}
+
+// ValidateCity checks a city filter; an empty name means every city.
+func (s *CityService) ValidateCity(name string) error {
+	name = CityName(name)
+	if name == "" {
+		return nil
+	}
+	_, err := findCity(s.repo, name)
+	return err
+}
+
+// CityName folds what a user typed into the form cities are stored in.
+func CityName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// NeighbourhoodName folds a neighbourhood like CityName does and also
+// squeezes inner spaces, so "Hauz  Khas " files posts under "hauz khas".
+func NeighbourhoodName(name string) string {
+	return strings.Join(strings.Fields(CityName(name)), " ")
+}
+
+// findCity looks up a city by name and turns a missing one into an error
+// listing the cities that are open.
+func findCity(repo interfaces.CityRepository, name string) (*models.City, error) {
+	city, err := repo.FindByName(name)
+	if err == nil {
+		return city, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	cities, err := repo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(cities))
+	for _, city := range cities {
+		names = append(names, city.Name)
+	}
+	return nil, models.NewError(models.ErrInvalid, "Unknown city "+name+", use "+strings.Join(names, ", "))
+}
@@ -0,0 +1,166 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"localEyes/internal/interfaces"
+	"localEyes/internal/models"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+type CommentService struct {
+	repo interfaces.CommentRepository
+	uow  interfaces.UnitOfWork
+}
+
+func NewCommentService(repo interfaces.CommentRepository, uow interfaces.UnitOfWork) *CommentService {
+	return &CommentService{repo: repo, uow: uow}
+}
+
+// AddComment starts a new thread on the post.
+func (s *CommentService) AddComment(UId, PId int, text string) error {
+	text, err := commentText(text)
+	if err != nil {
+		return err
+	}
+	return s.repo.Create(&models.Comment{PostId: PId, UserId: UId, Text: text, CreatedAt: time.Now()})
+}
+
+// Reply answers a comment on the same post. Deleted comments take no new
+// replies.
+func (s *CommentService) Reply(UId, ParentId int, text string) error {
+	text, err := commentText(text)
+	if err != nil {
+		return err
+	}
+	return s.uow.Do(func(repos interfaces.Repositories) error {
+		parent, err := liveComment(repos.Comments, ParentId)
+		if err != nil {
+			return err
+		}
+		return repos.Comments.Create(&models.Comment{PostId: parent.PostId, ParentId: parent.CommentId, UserId: UId, Text: text,
+			CreatedAt: time.Now()})
+	})
+}
+
+func (s *CommentService) EditComment(UId, CommentId int, text string) error {
+	text, err := commentText(text)
+	if err != nil {
+		return err
+	}
+	return s.repo.UpdateUserComment(CommentId, UId, text)
+}
+
+// DeleteMyComment removes one of the user's comments, see removeComment.
+func (s *CommentService) DeleteMyComment(UId, CommentId int) error {
+	return s.uow.Do(func(repos interfaces.Repositories) error {
+		comment, err := liveComment(repos.Comments, CommentId)
+		if err != nil {
+			return err
+		}
+		if comment.UserId != UId {
+			return models.NewError(models.ErrForbidden, "You can only delete your comment")
+		}
+		return removeComment(repos.Comments, comment)
+	})
+}
+
+// GetPostComments returns the post's discussion as threads: the top-level
+// comments, oldest first, each with its replies nested below it.
+func (s *CommentService) GetPostComments(PId int) ([]*models.Comment, error) {
+	comments, err := s.repo.GetCommentsByPId(PId)
+	if err != nil {
+		return nil, err
+	}
+	return threadComments(comments, 0), nil
+}
+
+// GetThread returns one comment with every reply below it.
+func (s *CommentService) GetThread(CommentId int) (*models.Comment, error) {
+	comment, err := s.repo.FindByCommentId(CommentId)
+	if err != nil {
+		return nil, notFound(err, "No Comment exist with this id")
+	}
+	comments, err := s.repo.GetCommentsByPId(comment.PostId)
+	if err != nil {
+		return nil, err
+	}
+	comment.Replies = threadComments(comments, comment.CommentId)
+	return comment, nil
+}
+
+// threadComments nests the comments under their parents and returns the
+// children of parentId. Comments come in id order, so every list of replies
+// is oldest first.
+func threadComments(comments []*models.Comment, parentId int) []*models.Comment {
+	children := make(map[int][]*models.Comment)
+	for _, comment := range comments {
+		children[comment.ParentId] = append(children[comment.ParentId], comment)
+	}
+	for _, comment := range comments {
+		comment.Replies = children[comment.CommentId]
+	}
+	return children[parentId]
+}
+
+// removeComment deletes a comment nobody has replied to, and afterwards any
+// deleted ancestors left with no replies. A comment with replies becomes a
+// placeholder instead, so its thread stays readable.
+func removeComment(repo interfaces.CommentRepository, comment *models.Comment) error {
+	replies, err := repo.CountReplies(comment.CommentId)
+	if err != nil {
+		return err
+	}
+	if replies > 0 {
+		return repo.MarkDeleted(comment.CommentId)
+	}
+	if err := repo.DeleteByCommentId(comment.CommentId); err != nil {
+		return err
+	}
+	for parentId := comment.ParentId; parentId != 0; {
+		parent, err := repo.FindByCommentId(parentId)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !parent.Deleted {
+			return nil
+		}
+		if replies, err := repo.CountReplies(parentId); err != nil || replies > 0 {
+			return err
+		}
+		if err := repo.DeleteByCommentId(parentId); err != nil {
+			return err
+		}
+		parentId = parent.ParentId
+	}
+	return nil
+}
+
+// liveComment finds a comment that has not been deleted.
+func liveComment(repo interfaces.CommentRepository, CommentId int) (*models.Comment, error) {
+	comment, err := repo.FindByCommentId(CommentId)
+	if err != nil {
+		return nil, notFound(err, "No Comment exist with this id")
+	}
+	if comment.Deleted {
+		return nil, models.NewError(models.ErrNotFound, "No Comment exist with this id")
+	}
+	return comment, nil
+}
+
+func commentText(text string) (string, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "", models.NewError(models.ErrInvalid, "Comments cannot be empty")
+	}
+	if utf8.RuneCountInString(text) > models.MaxCommentLength {
+		return "", models.NewError(models.ErrInvalid, fmt.Sprintf("Comments are at most %d characters", models.MaxCommentLength))
+	}
+	return text, nil
+}
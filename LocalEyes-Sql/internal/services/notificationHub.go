@@ -0,0 +1,205 @@
+package services
+
+import (
+	"context"
+	"localEyes/internal/interfaces"
+	"localEyes/internal/models"
+	"localEyes/utils"
+	"sync"
+	"time"
+)
+
+// listenerBuffer is how many notifications a listener may fall behind by
+// before it starts missing them.
+const listenerBuffer = 16
+
+// publishedMemory is how many of the latest ids a hub remembers, so that a
+// notification published in-process and found again by the source is only
+// pushed once.
+const publishedMemory = 1024
+
+// NotificationHub hands new notifications to whoever is listening for their
+// user right now, such as an open menu session or an API event stream. It
+// only fans out; what is new is published to it as this process commits it,
+// and a NotificationSource tells it what other processes added.
+type NotificationHub struct {
+	mu        sync.Mutex
+	listeners map[int]map[chan *models.Notification]bool
+	closed    bool
+	published map[int]bool
+	recent    []int
+}
+
+func NewNotificationHub() *NotificationHub {
+	return &NotificationHub{listeners: make(map[int]map[chan *models.Notification]bool), published: make(map[int]bool)}
+}
+
+// Listen returns a channel of the user's new notifications and a function
+// to stop listening, which closes it. Closing the hub closes it as well.
+func (h *NotificationHub) Listen(UId int) (<-chan *models.Notification, func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	live := make(chan *models.Notification, listenerBuffer)
+	if h.closed {
+		close(live)
+		return live, func() {}
+	}
+	if h.listeners[UId] == nil {
+		h.listeners[UId] = make(map[chan *models.Notification]bool)
+	}
+	h.listeners[UId][live] = true
+	var once sync.Once
+	return live, func() {
+		once.Do(func() {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			if h.listeners[UId][live] {
+				delete(h.listeners[UId], live)
+				close(live)
+			}
+		})
+	}
+}
+
+// Publish hands the notification to its user's listeners, unless it was
+// published lately already. One that has fallen behind misses it rather than
+// hold up the rest; it is in the inbox all the same.
+func (h *NotificationHub) Publish(notification *models.Notification) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.published[notification.Id] {
+		return
+	}
+	if len(h.recent) == publishedMemory {
+		delete(h.published, h.recent[0])
+		h.recent = h.recent[1:]
+	}
+	h.published[notification.Id] = true
+	h.recent = append(h.recent, notification.Id)
+	for live := range h.listeners[notification.UserId] {
+		select {
+		case live <- notification:
+		default:
+		}
+	}
+}
+
+// Close ends every listener and turns new ones away.
+func (h *NotificationHub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for UId, listeners := range h.listeners {
+		for live := range listeners {
+			close(live)
+		}
+		delete(h.listeners, UId)
+	}
+	h.closed = true
+}
+
+// NotificationSource tells a hub about new notifications. Run publishes each
+// one as it learns of it, until ctx ends.
+type NotificationSource interface {
+	Run(ctx context.Context, publish func(notification *models.Notification)) error
+}
+
+// DefaultPollInterval is how often a PollingSource looks for new rows.
+const DefaultPollInterval = time.Second
+
+// DefaultSettle is how long a PollingSource waits for a skipped id to turn
+// up before taking it for rolled back.
+const DefaultSettle = 30 * time.Second
+
+// DefaultMaxGaps is how many skipped ids a PollingSource keeps looking for.
+const DefaultMaxGaps = 1000
+
+// PollingSource follows the notifications table. It sees what every process
+// sharing the database adds, once committed, so several front ends on one
+// database push to each other's sessions. Ids are handed out before commit,
+// so a row can show up after newer ones; the ids skipped over are looked for
+// again until they turn up or Settle has passed. Only the MaxGaps ids just
+// below the newest are looked for, so a jump in the ids costs no more than
+// that; any further back are taken for rolled back straight away.
+type PollingSource struct {
+	Interval  time.Duration
+	BatchSize int
+	Settle    time.Duration
+	MaxGaps   int
+	Now       func() time.Time
+
+	repo interfaces.NotificationRepository
+}
+
+func NewPollingSource(repo interfaces.NotificationRepository) *PollingSource {
+	return &PollingSource{Interval: DefaultPollInterval, BatchSize: 100, Settle: DefaultSettle, MaxGaps: DefaultMaxGaps, Now: time.Now, repo: repo}
+}
+
+// Run publishes only what is added after it starts, each notification once.
+func (p *PollingSource) Run(ctx context.Context, publish func(notification *models.Notification)) error {
+	lastId, err := p.repo.LastId()
+	if err != nil {
+		return err
+	}
+	// missing holds the ids below lastId not seen yet, and since when.
+	missing := make(map[int]time.Time)
+	ticker := time.NewTicker(p.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		now := p.Now()
+		afterId := lastId
+		for id, since := range missing {
+			if now.Sub(since) >= p.Settle {
+				delete(missing, id)
+			} else if id <= afterId {
+				afterId = id - 1
+			}
+		}
+		for {
+			notifications, err := p.repo.GetSince(afterId, p.BatchSize)
+			if err != nil {
+				utils.Logger.Println("ERROR: Error polling for new notifications:", err)
+				break
+			}
+			for _, notification := range notifications {
+				afterId = notification.Id
+				if notification.Id <= lastId {
+					if _, ok := missing[notification.Id]; !ok {
+						continue
+					}
+					delete(missing, notification.Id)
+				} else {
+					p.skipped(missing, lastId, notification.Id, now)
+					lastId = notification.Id
+				}
+				// Read ones were let out of a digest, not just added.
+				if !notification.IsRead {
+					publish(notification)
+				}
+			}
+			if len(notifications) < p.BatchSize {
+				break
+			}
+		}
+	}
+}
+
+// skipped notes the ids between lastId and id as missing since now, keeping
+// only the MaxGaps ids just below id.
+func (p *PollingSource) skipped(missing map[int]time.Time, lastId, id int, now time.Time) {
+	oldest := id - p.MaxGaps
+	for gap := max(lastId+1, oldest); gap < id; gap++ {
+		missing[gap] = now
+	}
+	if len(missing) > p.MaxGaps {
+		for gap := range missing {
+			if gap < oldest {
+				delete(missing, gap)
+			}
+		}
+	}
+}
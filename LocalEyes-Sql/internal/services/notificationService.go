@@ -0,0 +1,355 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"localEyes/internal/interfaces"
+	"localEyes/internal/models"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+type NotificationService struct {
+	repo      interfaces.NotificationRepository
+	prefsRepo interfaces.NotificationPrefsRepository
+	uow       interfaces.UnitOfWork
+	hub       *NotificationHub
+}
+
+func NewNotificationService(repo interfaces.NotificationRepository, prefsRepo interfaces.NotificationPrefsRepository, uow interfaces.UnitOfWork) *NotificationService {
+	s := &NotificationService{repo: repo, prefsRepo: prefsRepo, hub: NewNotificationHub()}
+	s.uow = s.Publishing(uow)
+	return s
+}
+
+// Publishing wraps uow so that the notifications its units of work add are
+// pushed to this process's listeners as soon as they commit. The other
+// services should share it; what other processes add still comes from the
+// source being followed.
+func (s *NotificationService) Publishing(uow interfaces.UnitOfWork) interfaces.UnitOfWork {
+	return &publishingUnitOfWork{UnitOfWork: uow, hub: s.hub}
+}
+
+type publishingUnitOfWork struct {
+	interfaces.UnitOfWork
+	hub *NotificationHub
+}
+
+func (u *publishingUnitOfWork) Do(fn func(repos interfaces.Repositories) error) error {
+	var added *addedNotifications
+	err := u.UnitOfWork.Do(func(repos interfaces.Repositories) error {
+		added = &addedNotifications{NotificationRepository: repos.Notifications}
+		repos.Notifications = added
+		return fn(repos)
+	})
+	if err != nil {
+		return err
+	}
+	added.publish(u.hub)
+	return nil
+}
+
+// addedNotifications keeps the notifications a unit of work adds, so they
+// can be published once it commits.
+type addedNotifications struct {
+	interfaces.NotificationRepository
+	notifications []*models.Notification
+}
+
+func (r *addedNotifications) Create(notification *models.Notification) error {
+	if err := r.NotificationRepository.Create(notification); err != nil {
+		return err
+	}
+	r.notifications = append(r.notifications, notification)
+	return nil
+}
+
+// CreateForPost does not say whom it told, so the rows it added are read
+// back by id.
+func (r *addedNotifications) CreateForPost(notification *models.Notification, post *models.Post) error {
+	const batch = 100
+	lastId, err := r.LastId()
+	if err != nil {
+		return err
+	}
+	if err := r.NotificationRepository.CreateForPost(notification, post); err != nil {
+		return err
+	}
+	for {
+		added, err := r.GetSince(lastId, batch)
+		if err != nil {
+			return err
+		}
+		for _, notification := range added {
+			lastId = notification.Id
+			r.notifications = append(r.notifications, notification)
+		}
+		if len(added) < batch {
+			return nil
+		}
+	}
+}
+
+// publish leaves out what is held for a digest, like a NotificationSource.
+func (r *addedNotifications) publish(hub *NotificationHub) {
+	for _, notification := range r.notifications {
+		if !notification.Held && !notification.IsRead {
+			hub.Publish(notification)
+		}
+	}
+}
+
+// Listen pushes the user's new notifications to the returned channel, as
+// they are committed here or Follow learns of them, until stop is called.
+func (s *NotificationService) Listen(UId int) (live <-chan *models.Notification, stop func()) {
+	return s.hub.Listen(UId)
+}
+
+// Follow feeds what source reports to the listeners until ctx ends, and then
+// ends every listener.
+func (s *NotificationService) Follow(ctx context.Context, source NotificationSource) error {
+	defer s.hub.Close()
+	return source.Run(ctx, s.hub.Publish)
+}
+
+// NotifyNewPost tells the other users in the post's city about it, as far as
+// their preferences and subscriptions allow. New posts are normally announced
+// by the OutboxWorker; this does it straight away.
+func (s *NotificationService) NotifyNewPost(post *models.Post) error {
+	added := &addedNotifications{NotificationRepository: s.repo}
+	if err := added.CreateForPost(newPostNotification(post), post); err != nil {
+		return err
+	}
+	added.publish(s.hub)
+	return nil
+}
+
+func newPostNotification(post *models.Post) *models.Notification {
+	return &models.Notification{Type: models.NotifyNewPost, SourceType: models.TargetPost,
+		SourceId: post.PostId, Message: "New post: " + post.Title, CreatedAt: time.Now()}
+}
+
+// Inbox returns one page of the user's notifications, read and unread alike
+// unless unreadOnly is set.
+func (s *NotificationService) Inbox(UId int, unreadOnly bool, page models.PageRequest) (*models.Page[*models.Notification], error) {
+	page, err := normalizePage(page, models.RecordSorts)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.deliverDigest(UId); err != nil {
+		return nil, err
+	}
+	notifications, err := s.repo.GetUserNotifications(UId, unreadOnly, page.Sort, page.Size+1, page.Offset())
+	if err != nil {
+		return nil, err
+	}
+	return pageOf(notifications, page), nil
+}
+
+func (s *NotificationService) UnreadCount(UId int) (int, error) {
+	if err := s.deliverDigest(UId); err != nil {
+		return 0, err
+	}
+	return s.repo.CountUnread(UId)
+}
+
+// TakeUnread returns the user's unread notifications, oldest first and at
+// most a page's worth, and marks the ones returned read. It is how the menus
+// and "user notifications" show what is new without emptying the inbox.
+func (s *NotificationService) TakeUnread(UId int) ([]*models.Notification, error) {
+	if err := s.deliverDigest(UId); err != nil {
+		return nil, err
+	}
+	notifications, err := s.repo.GetUserNotifications(UId, true, models.SortOldest, models.MaxPageSize, 0)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int, 0, len(notifications))
+	for _, notification := range notifications {
+		ids = append(ids, notification.Id)
+	}
+	if err := s.repo.MarkRead(UId, ids); err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+// MarkRead marks one of the user's notifications read. Other users'
+// notifications, and those held for a digest, are reported as missing.
+func (s *NotificationService) MarkRead(UId, Id int) error {
+	notification, err := s.repo.FindByNotificationId(Id)
+	if err != nil {
+		return notFound(err, "No Notification exist with this id")
+	}
+	if notification.UserId != UId || notification.Held {
+		return models.NewError(models.ErrNotFound, "No Notification exist with this id")
+	}
+	return s.repo.MarkRead(UId, []int{Id})
+}
+
+func (s *NotificationService) MarkAllRead(UId int) error {
+	return s.repo.MarkAllRead(UId)
+}
+
+func (s *NotificationService) Preferences(UId int) (*models.NotificationPrefs, error) {
+	return s.prefsRepo.FindByUId(UId)
+}
+
+// UpdatePreferences changes how the user is notified; an empty delivery or
+// posts leaves that setting as it is. Choosing digests starts the clock on
+// the first one, and leaving them sends what was held straight away.
+func (s *NotificationService) UpdatePreferences(UId int, delivery, posts string) (*models.NotificationPrefs, error) {
+	if delivery != "" && !slices.Contains(models.Deliveries, delivery) {
+		return nil, models.NewError(models.ErrInvalid, "Unknown delivery "+delivery+", use "+strings.Join(models.Deliveries, ", "))
+	}
+	if posts != "" && !slices.Contains(models.PostScopes, posts) {
+		return nil, models.NewError(models.ErrInvalid, "Unknown posts setting "+posts+", use "+strings.Join(models.PostScopes, ", "))
+	}
+	var prefs *models.NotificationPrefs
+	err := s.uow.Do(func(repos interfaces.Repositories) error {
+		var err error
+		prefs, err = repos.NotificationPrefs.FindByUId(UId)
+		if err != nil {
+			return err
+		}
+		if delivery != "" && delivery != prefs.Delivery {
+			if prefs.Delivery == models.DeliverDigest {
+				if err := sendDigest(repos, prefs); err != nil {
+					return err
+				}
+			}
+			if delivery == models.DeliverDigest {
+				prefs.DigestAt = time.Now()
+			}
+			prefs.Delivery = delivery
+		}
+		if posts != "" {
+			prefs.Posts = posts
+		}
+		return repos.NotificationPrefs.Save(prefs)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return prefs, nil
+}
+
+// Subscribe has the user hear about new posts in the category, or by the
+// author, named. It only matters while their posts setting is subscribed.
+func (s *NotificationService) Subscribe(UId int, kind, name string) error {
+	return s.uow.Do(func(repos interfaces.Repositories) error {
+		targetId, err := subscriptionTarget(repos, kind, name)
+		if err != nil {
+			return err
+		}
+		if kind == models.TargetUser && targetId == UId {
+			return models.NewError(models.ErrForbidden, "You cannot subscribe to yourself")
+		}
+		return repos.NotificationPrefs.Subscribe(&models.Subscription{UserId: UId, Kind: kind, TargetId: targetId})
+	})
+}
+
+func (s *NotificationService) Unsubscribe(UId int, kind, name string) error {
+	return s.uow.Do(func(repos interfaces.Repositories) error {
+		targetId, err := subscriptionTarget(repos, kind, name)
+		if err != nil {
+			return err
+		}
+		return repos.NotificationPrefs.Unsubscribe(UId, kind, targetId)
+	})
+}
+
+// subscriptionTarget finds the id of the category or author named.
+func subscriptionTarget(repos interfaces.Repositories, kind, name string) (int, error) {
+	if kind == models.TargetCategory {
+		category, err := repos.Categories.FindByName(name)
+		if err != nil {
+			return 0, unknownCategory(err, name, repos.Categories)
+		}
+		return category.Id, nil
+	}
+	user, err := repos.Users.FindByUsername(name)
+	if err != nil {
+		return 0, notFound(err, "No user exist with this username")
+	}
+	return user.UId, nil
+}
+
+// deliverDigest sends the user's digest when one is due. It runs whenever
+// the inbox is looked at, so digests go out the first time the user checks
+// after DigestInterval rather than on a timer.
+func (s *NotificationService) deliverDigest(UId int) error {
+	prefs, err := s.prefsRepo.FindByUId(UId)
+	if err != nil {
+		return err
+	}
+	if prefs.Delivery != models.DeliverDigest || time.Since(prefs.DigestAt) < models.DigestInterval {
+		return nil
+	}
+	return s.uow.Do(func(repos interfaces.Repositories) error {
+		return sendDigest(repos, prefs)
+	})
+}
+
+// sendDigest sums up the held notifications in one, releases them into the
+// inbox as read and saves when it went out. With nothing held it does
+// nothing, so the next digest comes as soon as there is something to say.
+func sendDigest(repos interfaces.Repositories, prefs *models.NotificationPrefs) error {
+	const listed = 3
+	held, err := repos.Notifications.GetHeld(prefs.UserId)
+	if err != nil || len(held) == 0 {
+		return err
+	}
+	var messages []string
+	for _, notification := range held[:min(len(held), listed)] {
+		messages = append(messages, notification.Message)
+	}
+	if len(held) > listed {
+		messages = append(messages, fmt.Sprintf("and %d more", len(held)-listed))
+	}
+	now := time.Now()
+	err = repos.Notifications.Create(&models.Notification{UserId: prefs.UserId, Type: models.NotifyDigest,
+		Message: fmt.Sprintf("Digest of %d notifications: %s", len(held), strings.Join(messages, "; ")), CreatedAt: now})
+	if err != nil {
+		return err
+	}
+	if err := repos.Notifications.Release(prefs.UserId); err != nil {
+		return err
+	}
+	prefs.DigestAt = now
+	return repos.NotificationPrefs.Save(prefs)
+}
+
+// notify puts the notification in UId's inbox, or holds it for their digest,
+// or drops it if they turned notifications off; admin actions always go
+// straight in. Nobody hears about their own doing, and content from before
+// authors were recorded has nobody to tell.
+func notify(repos interfaces.Repositories, actorId, UId int, notification *models.Notification) error {
+	if UId == 0 || UId == actorId {
+		return nil
+	}
+	if notification.Type != models.NotifyAdminAction {
+		prefs, err := repos.NotificationPrefs.FindByUId(UId)
+		if err != nil {
+			return err
+		}
+		if prefs.Delivery == models.DeliverOff {
+			return nil
+		}
+		notification.Held = prefs.Delivery == models.DeliverDigest
+	}
+	notification.UserId = UId
+	notification.CreatedAt = time.Now()
+	return repos.Notifications.Create(notification)
+}
+
+// excerpt shortens text for quoting in a notification.
+func excerpt(text string) string {
+	const length = 40
+	if utf8.RuneCountInString(text) <= length {
+		return text
+	}
+	return string([]rune(text)[:length-1]) + "…"
+}
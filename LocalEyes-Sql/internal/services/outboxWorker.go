@@ -0,0 +1,201 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"localEyes/internal/interfaces"
+	"localEyes/internal/models"
+	"localEyes/utils"
+	"sync"
+	"time"
+)
+
+// Defaults for a new OutboxWorker.
+const (
+	DefaultOutboxWorkers      = 4
+	DefaultOutboxBatchSize    = 50
+	DefaultOutboxMaxAttempts  = 5
+	DefaultOutboxPollInterval = 2 * time.Second
+	DefaultOutboxLease        = time.Minute
+)
+
+// OutboxWorker announces new posts in the background. CreatePost leaves an
+// outbox entry in the post's own transaction; the worker claims due entries
+// and fans each one out in a transaction of its own, so posting never waits
+// on the fan-out and a crash in between loses nothing. A failed entry is
+// retried after Backoff and kept as dead after MaxAttempts.
+type OutboxWorker struct {
+	Workers      int           // entries delivered at the same time
+	BatchSize    int           // entries claimed per round
+	MaxAttempts  int           // attempts before an entry is given up on
+	PollInterval time.Duration // how often Start looks for due entries
+	Lease        time.Duration // how long a claimed entry stays hidden from other workers
+	Backoff      func(attempts int) time.Duration
+	Now          func() time.Time
+
+	repo     interfaces.OutboxRepository
+	uow      interfaces.UnitOfWork
+	flushing sync.Mutex
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+func NewOutboxWorker(repo interfaces.OutboxRepository, uow interfaces.UnitOfWork) *OutboxWorker {
+	return &OutboxWorker{
+		Workers:      DefaultOutboxWorkers,
+		BatchSize:    DefaultOutboxBatchSize,
+		MaxAttempts:  DefaultOutboxMaxAttempts,
+		PollInterval: DefaultOutboxPollInterval,
+		Lease:        DefaultOutboxLease,
+		Backoff:      OutboxBackoff,
+		Now:          time.Now,
+		repo:         repo,
+		uow:          uow,
+	}
+}
+
+// OutboxBackoff waits two seconds after the first failed attempt and doubles
+// the wait after each one that follows, up to ten minutes.
+func OutboxBackoff(attempts int) time.Duration {
+	wait := 2 * time.Second
+	for i := 1; i < attempts && wait < 10*time.Minute; i++ {
+		wait *= 2
+	}
+	return min(wait, 10*time.Minute)
+}
+
+// Start looks for due entries every PollInterval until Stop is called.
+func (w *OutboxWorker) Start() {
+	w.stop = make(chan struct{})
+	w.done = make(chan struct{})
+	go func() {
+		defer close(w.done)
+		ticker := time.NewTicker(w.PollInterval)
+		defer ticker.Stop()
+		for {
+			if err := w.Flush(); err != nil {
+				utils.Logger.Println("ERROR: Error delivering the notification outbox:", err)
+			}
+			select {
+			case <-w.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop stops polling and delivers what is still due. If ctx ends first, Stop
+// returns its error; entries left half done are picked up again once their
+// lease runs out.
+func (w *OutboxWorker) Stop(ctx context.Context) error {
+	flushed := make(chan error, 1)
+	go func() {
+		if w.stop != nil {
+			close(w.stop)
+			<-w.done
+			w.stop = nil
+		}
+		flushed <- w.Flush()
+	}()
+	select {
+	case err := <-flushed:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Flush delivers everything that is due, Workers entries at a time, and
+// returns once nothing is. Entries that fail are rescheduled for later, so a
+// flush always comes to an end.
+func (w *OutboxWorker) Flush() error {
+	w.flushing.Lock()
+	defer w.flushing.Unlock()
+	for {
+		now := w.Now()
+		entries, err := w.repo.GetDue(now, w.BatchSize)
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			return nil
+		}
+		// Another worker may have claimed some of them since.
+		var claimed []*models.OutboxEntry
+		for _, entry := range entries {
+			ok, err := w.repo.Claim(entry, now.Add(w.Lease))
+			if err != nil {
+				return err
+			}
+			if ok {
+				claimed = append(claimed, entry)
+			}
+		}
+		w.deliverAll(claimed)
+	}
+}
+
+func (w *OutboxWorker) deliverAll(entries []*models.OutboxEntry) {
+	queue := make(chan *models.OutboxEntry)
+	var wg sync.WaitGroup
+	for i := 0; i < max(w.Workers, 1); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for entry := range queue {
+				w.deliver(entry)
+			}
+		}()
+	}
+	for _, entry := range entries {
+		queue <- entry
+	}
+	close(queue)
+	wg.Wait()
+}
+
+// errLeaseLost rolls back a delivery that outlasted its lease, as another
+// worker has claimed the entry since and is the one to announce it.
+var errLeaseLost = errors.New("the lease on the outbox entry ran out")
+
+// deliver removes the entry and fans its post out in one transaction. The
+// entry goes first, and only while this worker's claim on it stands, so a
+// delivery that outlasts its lease is undone instead of announcing the post
+// a second time; on SQL the delete also holds off later claims until the
+// transaction ends. A post deleted before it was announced is simply dropped.
+func (w *OutboxWorker) deliver(entry *models.OutboxEntry) {
+	err := w.uow.Do(func(repos interfaces.Repositories) error {
+		held, err := repos.Outbox.Complete(entry)
+		if err != nil {
+			return err
+		}
+		if !held {
+			return errLeaseLost
+		}
+		posts, err := repos.Posts.GetPostsByPId(entry.PostId)
+		if err != nil {
+			return err
+		}
+		if len(posts) == 0 {
+			return nil
+		}
+		return repos.Notifications.CreateForPost(newPostNotification(posts[0]), posts[0])
+	})
+	if err == nil {
+		return
+	}
+	if errors.Is(err, errLeaseLost) {
+		utils.Logger.Println("INFO: Outbox entry id-", entry.Id, "was claimed again before it was delivered, leaving it to that claim")
+		return
+	}
+	utils.Logger.Println("ERROR: Error announcing post id-", entry.PostId, "attempt", entry.Attempts, err)
+	if entry.Attempts >= w.MaxAttempts {
+		err = w.repo.MarkDead(entry.Id, err.Error())
+	} else {
+		err = w.repo.Retry(entry.Id, w.Now().Add(w.Backoff(entry.Attempts)), err.Error())
+	}
+	if err != nil {
+		utils.Logger.Println("ERROR: Error rescheduling outbox entry id-", entry.Id, err)
+	}
+}
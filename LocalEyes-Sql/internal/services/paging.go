@@ -0,0 +1,50 @@
+package services
+
+import (
+	"fmt"
+	"localEyes/internal/models"
+	"strings"
+)
+
+// normalizePage fills in the defaults of a page request and checks its sort
+// order against the ones the listing supports, the first being the default.
+func normalizePage(page models.PageRequest, orders []string) (models.PageRequest, error) {
+	if page.Number < 1 {
+		page.Number = 1
+	}
+	if page.Size < 1 {
+		page.Size = models.DefaultPageSize
+	}
+	if page.Size > models.MaxPageSize {
+		page.Size = models.MaxPageSize
+	}
+	if page.Sort == "" {
+		page.Sort = orders[0]
+	}
+	for _, order := range orders {
+		if page.Sort == order {
+			return page, nil
+		}
+	}
+	return page, models.NewError(models.ErrInvalid, fmt.Sprintf("Unknown sort order %s, use %s", page.Sort, strings.Join(orders, ", ")))
+}
+
+// pageOf builds a page from a query for one item more than the page holds;
+// getting that extra item back means there is a next page.
+func pageOf[T any](items []T, page models.PageRequest) *models.Page[T] {
+	result := &models.Page[T]{Items: items, Request: page}
+	if len(items) > page.Size {
+		result.Items = items[:page.Size]
+		result.HasNext = true
+	}
+	return result
+}
+
+// searchText trims a search and rejects one with nothing to look for.
+func searchText(search string) (string, error) {
+	search = strings.TrimSpace(search)
+	if search == "" {
+		return "", models.NewError(models.ErrInvalid, "Enter something to search for")
+	}
+	return search, nil
+}
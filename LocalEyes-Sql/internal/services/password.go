@@ -0,0 +1,55 @@
+package services
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"golang.org/x/crypto/bcrypt"
+	"sync"
+)
+
+// legacyHashLength is the length of the unsalted SHA-256 hex digests stored
+// before passwords moved to bcrypt.
+const legacyHashLength = sha256.Size * 2
+
+// HashPassword returns a salted bcrypt hash of password.
+func HashPassword(password string) (string, error) {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hashed), nil
+}
+
+// VerifyPassword reports whether password matches the stored hash, and
+// whether the hash should be replaced: either it is a legacy SHA-256 digest
+// or it was produced with a lower bcrypt cost than the current default.
+func VerifyPassword(hashed, password string) (match bool, needsRehash bool) {
+	if len(hashed) == legacyHashLength {
+		legacy := legacyHashPassword(password)
+		return subtle.ConstantTimeCompare([]byte(hashed), []byte(legacy)) == 1, true
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(hashed), []byte(password)); err != nil {
+		return false, false
+	}
+	cost, err := bcrypt.Cost([]byte(hashed))
+	return true, err != nil || cost < bcrypt.DefaultCost
+}
+
+// missingUserHash stands in for the hash of an account that does not exist.
+var missingUserHash = sync.OnceValue(func() []byte {
+	hashed, _ := bcrypt.GenerateFromPassword([]byte("no such account"), bcrypt.DefaultCost)
+	return hashed
+})
+
+// verifyMissingUser spends the time VerifyPassword would on a real account,
+// so an unknown username cannot be told from a wrong password by timing.
+func verifyMissingUser(password string) {
+	_ = bcrypt.CompareHashAndPassword(missingUserHash(), []byte(password))
+}
+
+func legacyHashPassword(password string) string {
+	hash := sha256.New()
+	hash.Write([]byte(password))
+	return hex.EncodeToString(hash.Sum(nil))
+}
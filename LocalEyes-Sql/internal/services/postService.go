@@ -1,20 +1,32 @@
 package services
 
 import (
+	"fmt"
 	"localEyes/internal/interfaces"
 	"localEyes/internal/models"
+	"localEyes/utils"
+	"sort"
 	"time"
 )
 
 type PostService struct {
-	repo interfaces.PostRepository
+	repo     interfaces.PostRepository
+	likeRepo interfaces.PostLikeRepository
+	uow      interfaces.UnitOfWork
 }
 
-func NewPostService(repo interfaces.PostRepository) *PostService {
-	return &PostService{repo: repo}
+func NewPostService(repo interfaces.PostRepository, likeRepo interfaces.PostLikeRepository, uow interfaces.UnitOfWork) *PostService {
+	return &PostService{repo: repo, likeRepo: likeRepo, uow: uow}
 }
 
-func (s *PostService) CreatePost(userId int, title, content, postType string) error {
+// CreatePost files the post under postType, which must name an active
+// category, in the author's city and at the optional location, and returns
+// the stored post.
+func (s *PostService) CreatePost(userId int, title, content, postType string, location models.Location) (*models.Post, error) {
+	location, err := validLocation(location)
+	if err != nil {
+		return nil, err
+	}
 	post := &models.Post{
 		UId:       userId,
 		Title:     title,
@@ -22,9 +34,31 @@ func (s *PostService) CreatePost(userId int, title, content, postType string) er
 		Type:      postType,
 		CreatedAt: time.Now(),
 		Likes:     0,
+		Location:  location,
+	}
+	err = s.uow.Do(func(repos interfaces.Repositories) error {
+		category, err := repos.Categories.FindByName(postType)
+		if err != nil {
+			return unknownCategory(err, postType, repos.Categories)
+		}
+		if !category.IsActive {
+			return models.NewError(models.ErrInvalid, "Category "+postType+" is not taking new posts")
+		}
+		author, err := repos.Users.FindByUId(userId)
+		if err != nil {
+			return notFound(err, "No user exist with this id")
+		}
+		post.City = author.City
+		if err := repos.Posts.Create(post); err != nil {
+			return err
+		}
+		// The post is announced by the OutboxWorker once this commits.
+		return repos.Outbox.Create(&models.OutboxEntry{PostId: post.PostId, AvailableAt: post.CreatedAt, CreatedAt: post.CreatedAt})
+	})
+	if err != nil {
+		return nil, err
 	}
-	err := s.repo.Create(post)
-	return err
+	return post, nil
 }
 
 func (s *PostService) UpdateMyPost(postId, userId int, title, content string) error {
@@ -35,12 +69,133 @@ func (s *PostService) UpdateMyPost(postId, userId int, title, content string) er
 	return nil
 }
 
-func (s *PostService) GiveAllPosts() ([]*models.Post, error) {
+// GiveAllPosts returns every post, marking the ones UId has liked.
+func (s *PostService) GiveAllPosts(UId int) ([]*models.Post, error) {
 	posts, err := s.repo.GetAllPosts()
 	if err != nil {
 		return nil, err
 	}
-	return posts, nil
+	return s.markLiked(UId, posts)
+}
+
+// ListPosts returns one page of the posts matching filter, marking the ones
+// UId has liked. Feeds set filter.City to the viewer's city.
+func (s *PostService) ListPosts(UId int, filter models.PostFilter, page models.PageRequest) (*models.Page[*models.Post], error) {
+	page, err := normalizePage(page, models.PostSorts)
+	if err != nil {
+		return nil, err
+	}
+	filter.Neighbourhood = NeighbourhoodName(filter.Neighbourhood)
+	posts, err := s.repo.ListPosts(filter, page.Sort, page.Size+1, page.Offset())
+	if err != nil {
+		return nil, err
+	}
+	result := pageOf(posts, page)
+	if result.Items, err = s.markLiked(UId, result.Items); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SearchPosts returns one page of the posts in city matching the search,
+// most relevant first, marking the ones UId has liked.
+func (s *PostService) SearchPosts(UId int, city, search string, page models.PageRequest) (*models.Page[*models.Post], error) {
+	search, err := searchText(search)
+	if err != nil {
+		return nil, err
+	}
+	page, err = normalizePage(page, models.SearchSorts)
+	if err != nil {
+		return nil, err
+	}
+	posts, err := s.repo.SearchPosts(city, search, page.Size+1, page.Offset())
+	if err != nil {
+		return nil, err
+	}
+	result := pageOf(posts, page)
+	if result.Items, err = s.markLiked(UId, result.Items); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// NearPosts returns one page of the posts tagged within radiusKm of the
+// viewer's coordinates, nearest first, with their distance set. Posts without
+// coordinates never show up here, whatever their neighbourhood.
+func (s *PostService) NearPosts(UId int, latitude, longitude, radiusKm float64, page models.PageRequest) (*models.Page[*models.Post], error) {
+	if !utils.ValidCoordinates(latitude, longitude) {
+		return nil, models.NewError(models.ErrInvalid, coordinatesRule)
+	}
+	if radiusKm <= 0 || radiusKm > models.MaxNearRadiusKm {
+		return nil, models.NewError(models.ErrInvalid, fmt.Sprintf("The radius must be more than 0 and at most %g km", models.MaxNearRadiusKm))
+	}
+	page, err := normalizePage(page, models.NearSorts)
+	if err != nil {
+		return nil, err
+	}
+	candidates, err := s.repo.PostsInArea(utils.AreaAround(latitude, longitude, radiusKm))
+	if err != nil {
+		return nil, err
+	}
+	// The area is a box around the circle, so its corners still need cutting.
+	var posts []*models.Post
+	for _, post := range candidates {
+		distance := utils.DistanceKm(latitude, longitude, *post.Latitude, *post.Longitude)
+		if distance <= radiusKm {
+			post.Distance = &distance
+			posts = append(posts, post)
+		}
+	}
+	sort.SliceStable(posts, func(i, j int) bool {
+		if *posts[i].Distance != *posts[j].Distance {
+			return *posts[i].Distance < *posts[j].Distance
+		}
+		return posts[i].PostId > posts[j].PostId
+	})
+	if start := page.Offset(); start < len(posts) {
+		posts = posts[start:min(len(posts), start+page.Size+1)]
+	} else {
+		posts = nil
+	}
+	result := pageOf(posts, page)
+	if result.Items, err = s.markLiked(UId, result.Items); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+const coordinatesRule = "Latitude must be between -90 and 90 and longitude between -180 and 180"
+
+// validLocation folds the neighbourhood and checks the coordinates, which
+// come as a pair or not at all.
+func validLocation(location models.Location) (models.Location, error) {
+	location.Neighbourhood = NeighbourhoodName(location.Neighbourhood)
+	if len(location.Neighbourhood) > 60 {
+		return location, models.NewError(models.ErrInvalid, "Neighbourhoods are at most 60 characters")
+	}
+	if (location.Latitude == nil) != (location.Longitude == nil) {
+		return location, models.NewError(models.ErrInvalid, "Give both latitude and longitude, or neither")
+	}
+	if location.HasCoordinates() && !utils.ValidCoordinates(*location.Latitude, *location.Longitude) {
+		return location, models.NewError(models.ErrInvalid, coordinatesRule)
+	}
+	return location, nil
+}
+
+// GivePost returns one post, marked if UId has liked it.
+func (s *PostService) GivePost(UId, PId int) (*models.Post, error) {
+	posts, err := s.repo.GetPostsByPId(PId)
+	if err != nil {
+		return nil, err
+	}
+	if len(posts) == 0 {
+		return nil, models.NewError(models.ErrNotFound, "No post exist with this id")
+	}
+	posts, err = s.markLiked(UId, posts[:1])
+	if err != nil {
+		return nil, err
+	}
+	return posts[0], nil
 }
 
 func (s *PostService) GiveMyPosts(UId int) ([]*models.Post, error) {
@@ -51,27 +206,82 @@ func (s *PostService) GiveMyPosts(UId int) ([]*models.Post, error) {
 	return posts, nil
 }
 
+// DeleteMyPost deletes one of the user's posts, its likes, its questions,
+// their answers, its comments and the notifications still to go out about it
+// atomically.
 func (s *PostService) DeleteMyPost(UId, PId int) error {
-	err := s.repo.DeleteByUIdPId(UId, PId)
-	if err != nil {
-		return err
-	}
-	return nil
+	return s.uow.Do(func(repos interfaces.Repositories) error {
+		if err := repos.Posts.DeleteByUIdPId(UId, PId); err != nil {
+			return err
+		}
+		if err := repos.Likes.DeleteByPId(PId); err != nil {
+			return err
+		}
+		if err := repos.Answers.DeleteByPId(PId); err != nil {
+			return err
+		}
+		if err := repos.Questions.DeleteByPId(PId); err != nil {
+			return err
+		}
+		if err := repos.Comments.DeleteByPId(PId); err != nil {
+			return err
+		}
+		return repos.Outbox.DeleteByPId(PId)
+	})
+}
+
+// Like records UId's like on PId and bumps the post's counter in the same
+// unit of work. A post can be liked once per user, and never by its author.
+func (s *PostService) Like(UId, PId int) error {
+	return s.uow.Do(func(repos interfaces.Repositories) error {
+		posts, err := repos.Posts.GetPostsByPId(PId)
+		if err != nil {
+			return err
+		}
+		if len(posts) == 0 {
+			return models.NewError(models.ErrNotFound, "No post exist with this id")
+		}
+		if posts[0].UId == UId {
+			return models.NewError(models.ErrForbidden, "You cannot like your own post")
+		}
+		if err := repos.Likes.Create(UId, PId); err != nil {
+			return err
+		}
+		return repos.Posts.UpdateLike(PId)
+	})
 }
 
-func (s *PostService) Like(PId int) error {
-	err := s.repo.UpdateLike(PId)
+// Unlike takes back UId's like on PId together with the counter.
+func (s *PostService) Unlike(UId, PId int) error {
+	return s.uow.Do(func(repos interfaces.Repositories) error {
+		if err := repos.Likes.Delete(UId, PId); err != nil {
+			return err
+		}
+		return repos.Posts.RemoveLike(PId)
+	})
+}
+
+// GiveFilteredPosts returns the posts of one type, marking the ones UId has liked.
+func (s *PostService) GiveFilteredPosts(UId int, filterType string) ([]*models.Post, error) {
+	posts, err := s.repo.GetPostsByFilter(filterType)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return nil
+	return s.markLiked(UId, posts)
 }
 
-func (s *PostService) GiveFilteredPosts(filterType string) ([]*models.Post, error) {
-	posts, err := s.repo.GetPostsByFilter(filterType)
+func (s *PostService) markLiked(UId int, posts []*models.Post) ([]*models.Post, error) {
+	liked, err := s.likeRepo.GetLikedPIds(UId)
 	if err != nil {
 		return nil, err
 	}
+	likedPIds := make(map[int]bool, len(liked))
+	for _, pId := range liked {
+		likedPIds[pId] = true
+	}
+	for _, post := range posts {
+		post.LikedByMe = likedPIds[post.PostId]
+	}
 	return posts, nil
 }
 
@@ -1,19 +1,24 @@
 package services
 
 import (
+	"fmt"
 	"localEyes/internal/interfaces"
 	"localEyes/internal/models"
 	"time"
 )
 
 type QuestionService struct {
-	repo interfaces.QuestionRepository
+	repo       interfaces.QuestionRepository
+	answerRepo interfaces.AnswerRepository
+	uow        interfaces.UnitOfWork
 }
 
-func NewQuestionService(repo interfaces.QuestionRepository) *QuestionService {
-	return &QuestionService{repo: repo}
+func NewQuestionService(repo interfaces.QuestionRepository, answerRepo interfaces.AnswerRepository, uow interfaces.UnitOfWork) *QuestionService {
+	return &QuestionService{repo: repo, answerRepo: answerRepo, uow: uow}
 }
 
+// AskQuestion adds the question to an existing post and tells the post's
+// author about it.
 func (s *QuestionService) AskQuestion(userId, postId int, content string) error {
 	question := &models.Question{
 		PostId:    postId,
@@ -22,37 +27,129 @@ func (s *QuestionService) AskQuestion(userId, postId int, content string) error
 		Replies:   make([]string, 0),
 		CreatedAt: time.Now(),
 	}
-	return s.repo.Create(question)
+	return s.uow.Do(func(repos interfaces.Repositories) error {
+		posts, err := repos.Posts.GetPostsByPId(postId)
+		if err != nil {
+			return err
+		}
+		if len(posts) == 0 {
+			return models.NewError(models.ErrNotFound, "No Post exist with this id")
+		}
+		if err := repos.Questions.Create(question); err != nil {
+			return err
+		}
+		return notify(repos, userId, posts[0].UId, &models.Notification{Type: models.NotifyNewQuestion,
+			SourceType: models.TargetPost, SourceId: postId,
+			Message: fmt.Sprintf("New question on your post %q: %q", excerpt(posts[0].Title), excerpt(content))})
+	})
 }
 
+// DeleteQuesByPId removes a post's questions together with their answers.
 func (s *QuestionService) DeleteQuesByPId(postId int) error {
-	err := s.repo.DeleteByPId(postId)
-	if err != nil {
-		return err
-	}
-	return nil
+	return s.uow.Do(func(repos interfaces.Repositories) error {
+		if err := repos.Answers.DeleteByPId(postId); err != nil {
+			return err
+		}
+		return repos.Questions.DeleteByPId(postId)
+	})
 }
 
+// DeleteUserQues removes one of the user's questions together with its answers.
 func (s *QuestionService) DeleteUserQues(UId, QId int) error {
-	err := s.repo.DeleteByQIdUId(QId, UId)
-	if err != nil {
-		return err
-	}
-	return nil
+	return s.uow.Do(func(repos interfaces.Repositories) error {
+		if err := repos.Questions.DeleteByQIdUId(QId, UId); err != nil {
+			return err
+		}
+		return repos.Answers.DeleteByQId(QId)
+	})
 }
 
+// GetPostQuestions returns the post's questions with their answers attached.
 func (s *QuestionService) GetPostQuestions(PId int) ([]*models.Question, error) {
 	questions, err := s.repo.GetQuestionsByPId(PId)
 	if err != nil {
 		return nil, err
 	}
+	answers, err := s.answerRepo.GetAnswersByPId(PId)
+	if err != nil {
+		return nil, err
+	}
+	attachAnswers(questions, answers)
 	return questions, nil
 }
 
-func (s *QuestionService) AddAnswer(QId int, answer string) error {
-	err := s.repo.UpdateQuestion(QId, answer)
+// SearchQuestions returns one page of the questions whose text or answers
+// match the search, most relevant first, with their answers attached.
+func (s *QuestionService) SearchQuestions(search string, page models.PageRequest) (*models.Page[*models.Question], error) {
+	search, err := searchText(search)
+	if err != nil {
+		return nil, err
+	}
+	page, err = normalizePage(page, models.SearchSorts)
+	if err != nil {
+		return nil, err
+	}
+	questions, err := s.repo.SearchQuestions(search, page.Size+1, page.Offset())
+	if err != nil {
+		return nil, err
+	}
+	result := pageOf(questions, page)
+	for _, question := range result.Items {
+		if question.Answers, err = s.answerRepo.GetAnswersByQId(question.QId); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// AddAnswer adds the answer and tells whoever asked the question about it.
+func (s *QuestionService) AddAnswer(QId, UId int, text string) error {
+	answer := &models.Answer{
+		QId:       QId,
+		UserId:    UId,
+		Text:      text,
+		CreatedAt: time.Now(),
+	}
+	return s.uow.Do(func(repos interfaces.Repositories) error {
+		if err := repos.Answers.Create(answer); err != nil {
+			return err
+		}
+		question, err := repos.Questions.FindByQId(QId)
+		if err != nil {
+			return err
+		}
+		return notify(repos, UId, question.UserId, &models.Notification{Type: models.NotifyNewAnswer,
+			SourceType: models.TargetQuestion, SourceId: QId,
+			Message: fmt.Sprintf("New answer to your question %q: %q", excerpt(question.Text), excerpt(text))})
+	})
+}
+
+func (s *QuestionService) EditAnswer(UId, AnswerId int, text string) error {
+	err := s.answerRepo.UpdateUserAnswer(AnswerId, UId, text)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *QuestionService) DeleteMyAnswer(UId, AnswerId int) error {
+	err := s.answerRepo.DeleteByAnswerIdUId(AnswerId, UId)
 	if err != nil {
 		return err
 	}
 	return nil
 }
+
+// attachAnswers hands each answer to its question, keeping the answers in the
+// order they were given.
+func attachAnswers(questions []*models.Question, answers []*models.Answer) {
+	byQId := make(map[int]*models.Question, len(questions))
+	for _, question := range questions {
+		byQId[question.QId] = question
+	}
+	for _, answer := range answers {
+		if question, ok := byQId[answer.QId]; ok {
+			question.Answers = append(question.Answers, answer)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"localEyes/internal/interfaces"
+	"localEyes/internal/models"
+	"localEyes/utils"
+	"time"
+)
+
+// DefaultSessionTTL is how long a session stays valid after login.
+const DefaultSessionTTL = 7 * 24 * time.Hour
+
+type SessionService struct {
+	repo     interfaces.SessionRepository
+	userRepo interfaces.UserRepository
+	TTL      time.Duration
+}
+
+func NewSessionService(repo interfaces.SessionRepository, userRepo interfaces.UserRepository) *SessionService {
+	return &SessionService{repo: repo, userRepo: userRepo, TTL: DefaultSessionTTL}
+}
+
+// Start opens a session for a user who has already logged in and returns its
+// token. The token is random and opaque; only its hash is stored, so a leaked
+// sessions table cannot be replayed.
+func (s *SessionService) Start(UId int) (string, *models.Session, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", nil, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+	now := time.Now()
+	session := &models.Session{
+		TokenHash: hashToken(token),
+		UserId:    UId,
+		CreatedAt: now,
+		ExpiresAt: now.Add(s.TTL),
+	}
+	if err := s.repo.Create(session); err != nil {
+		return "", nil, err
+	}
+	// Expired sessions are cleared out as new ones start; failing to do so
+	// only leaves dead rows behind.
+	if _, err := s.repo.DeleteExpired(now); err != nil {
+		utils.Logger.Println("ERROR: Error deleting expired sessions:", err)
+	}
+	return token, session, nil
+}
+
+// Authenticate returns the user a token belongs to, as long as the session
+// has not expired and the account is still active.
+func (s *SessionService) Authenticate(token string) (*models.User, error) {
+	invalid := models.NewError(models.ErrUnauthorized, "Invalid or expired session")
+	session, err := s.repo.FindByTokenHash(hashToken(token))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, invalid
+	} else if err != nil {
+		return nil, err
+	}
+	if !time.Now().Before(session.ExpiresAt) {
+		if err := s.repo.DeleteByTokenHash(session.TokenHash); err != nil {
+			utils.Logger.Println("ERROR: Error deleting expired session:", err)
+		}
+		return nil, invalid
+	}
+	user, err := s.userRepo.FindByUId(session.UserId)
+	if err != nil || user == nil {
+		return nil, invalid
+	}
+	if !user.IsActive {
+		return nil, models.NewError(models.ErrForbidden, "InActive Account")
+	}
+	return user, nil
+}
+
+// Logout ends the session the token belongs to.
+func (s *SessionService) Logout(token string) error {
+	return s.repo.DeleteByTokenHash(hashToken(token))
+}
+
+// LogoutAll ends every session of the user, on every device.
+func (s *SessionService) LogoutAll(UId int) error {
+	return s.repo.DeleteForUser(UId)
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -1,76 +1,111 @@
 package services
 
 import (
-	"crypto/sha256"
-	"database/sql"
-	"encoding/hex"
-	"errors"
-	"localEyes/config"
 	"localEyes/internal/interfaces"
 	"localEyes/internal/models"
+	"localEyes/utils"
 )
 
 type UserService struct {
 	Repo interfaces.UserRepository
+	uow  interfaces.UnitOfWork
 }
 
-func NewUserService(repo interfaces.UserRepository) *UserService {
-	return &UserService{Repo: repo}
+func NewUserService(repo interfaces.UserRepository, uow interfaces.UnitOfWork) *UserService {
+	return &UserService{Repo: repo, uow: uow}
 }
 
-func (s *UserService) Signup(username, password string, dwellingAge int, tag string) error {
-	hashedPassword := HashPassword(password)
+// Signup creates an account in city, which must be one LocalEyes is open in.
+// Every account starts as a plain user; roles are only ever granted.
+func (s *UserService) Signup(username, password, city string, dwellingAge int, tag string) error {
+	hashedPassword, err := HashPassword(password)
+	if err != nil {
+		return err
+	}
+	city = CityName(city)
 
 	user := &models.User{
 		//UId:          primitive.NewObjectID(),
 		Username:     username,
 		Password:     hashedPassword,
-		City:         "delhi",
+		City:         city,
 		Notification: []string{},
 		IsActive:     true,
 		DwellingAge:  dwellingAge,
 		Tag:          tag,
 	}
-	err := s.Repo.Create(user)
-	return err
+	return s.uow.Do(func(repos interfaces.Repositories) error {
+		if _, err := findCity(repos.Cities, city); err != nil {
+			return err
+		}
+		return repos.Users.Create(user)
+	})
+}
+
+// ChangeCity moves the user to another city. Their feed and notifications
+// follow them; posts they already made stay in the city they were made in.
+func (s *UserService) ChangeCity(UId int, city string) error {
+	city = CityName(city)
+	return s.uow.Do(func(repos interfaces.Repositories) error {
+		user, err := repos.Users.FindByUId(UId)
+		if err != nil {
+			return notFound(err, "No user exist with this id")
+		}
+		if user.City == city {
+			return models.NewError(models.ErrConflict, "You already live in "+city)
+		}
+		if _, err := findCity(repos.Cities, city); err != nil {
+			return err
+		}
+		return repos.Users.UpdateCity(UId, city)
+	})
 }
 
 func (s *UserService) Login(Username, password string) (*models.User, error) {
-	hashedPassword := HashPassword(password)
-	user, err := s.Repo.FindByUsernamePassword(Username, hashedPassword)
-	//user.NotifyChannel = make(chan string, 5)
-	if err != nil {
-		return nil, errors.New(config.Red + "Invalid Account credentials" + config.Reset)
-	} else if user == nil {
-		return nil, errors.New(config.Red + "Invalid Account credentials" + config.Reset)
+	user, err := s.Repo.FindByUsername(Username)
+	if err != nil || user == nil {
+		verifyMissingUser(password)
+		return nil, models.NewError(models.ErrUnauthorized, "Invalid Account credentials")
+	}
+	match, needsRehash := VerifyPassword(user.Password, password)
+	if !match {
+		return nil, models.NewError(models.ErrUnauthorized, "Invalid Account credentials")
 	} else if user.IsActive == false {
-		return nil, errors.New(config.Red + "InActive Account" + config.Reset)
+		return nil, models.NewError(models.ErrForbidden, "InActive Account")
+	}
+	if needsRehash {
+		rehashPassword(s.Repo, user, password)
 	}
 	return user, nil
 }
 
+// DeActivate closes the user's own account and records it in the audit log,
+// with the user as the actor.
 func (s *UserService) DeActivate(UId int) error {
-	err := s.Repo.UpdateActiveStatus(UId, false)
-	if err != nil {
-		return err
-	}
-	return nil
-}
-
-func HashPassword(password string) string {
-	hash := sha256.New()
-	hash.Write([]byte(password))
-	return hex.EncodeToString(hash.Sum(nil))
+	return s.uow.Do(func(repos interfaces.Repositories) error {
+		user, err := repos.Users.FindByUId(UId)
+		if err != nil {
+			return notFound(err, "No user exist with this id")
+		}
+		if err := repos.Users.UpdateActiveStatus(UId, false); err != nil {
+			return err
+		}
+		after := *user
+		after.IsActive = false
+		return audit(repos.Audit, user, models.AuditDeactivateUser, models.TargetUser, UId, snapshotUser(user), snapshotUser(&after))
+	})
 }
 
-func (s *UserService) NotifyUsers(UId int, title string) error {
-	return s.Repo.PushNotification(UId, title)
-}
-
-func (s *UserService) UnNotifyUsers(UId int) error {
-	err := s.Repo.ClearNotification(UId)
-	if errors.Is(err, sql.ErrNoRows) {
-		return nil
+// rehashPassword upgrades a stored hash after a successful login. Failing to
+// upgrade is not a login failure, the old hash keeps working until next time.
+func rehashPassword(repo interfaces.UserRepository, user *models.User, password string) {
+	hashedPassword, err := HashPassword(password)
+	if err == nil {
+		err = repo.UpdatePassword(user.UId, hashedPassword)
+	}
+	if err != nil {
+		utils.Logger.Println("ERROR: Error upgrading password hash for user id-", user.UId, err)
+		return
 	}
-	return err
+	user.Password = hashedPassword
 }
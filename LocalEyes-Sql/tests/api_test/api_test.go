@@ -0,0 +1,772 @@
+package api_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"localEyes/cmd/api"
+	"localEyes/internal/models"
+	"localEyes/internal/repositories"
+	"localEyes/internal/services"
+	"localEyes/utils"
+)
+
+func TestMain(m *testing.M) {
+	utils.Logger = log.New(io.Discard, "", 0)
+	os.Exit(m.Run())
+}
+
+type client struct {
+	t      *testing.T
+	server *httptest.Server
+	outbox *services.OutboxWorker
+}
+
+func newClient(t *testing.T) *client {
+	users := repositories.NewInMemoryUserRepository()
+	posts := repositories.NewInMemoryPostRepository()
+	questions := repositories.NewInMemoryQuestionRepository()
+	answers := repositories.NewInMemoryAnswerRepository()
+	likes := repositories.NewInMemoryPostLikeRepository()
+	sessions := repositories.NewInMemorySessionRepository()
+	audit := repositories.NewInMemoryAuditRepository()
+	categories := repositories.NewInMemoryCategoryRepository()
+	cities := repositories.NewInMemoryCityRepository()
+	comments := repositories.NewInMemoryCommentRepository()
+	notifications := repositories.NewInMemoryNotificationRepository()
+	notificationPrefs := repositories.NewInMemoryNotificationPrefsRepository()
+	outbox := repositories.NewInMemoryOutboxRepository()
+	uow := repositories.NewInMemoryUnitOfWork(users, posts, questions, answers, likes, sessions, audit, categories, cities, comments, notifications, notificationPrefs, outbox)
+
+	adminHash, err := services.HashPassword("admin@123")
+	require.NoError(t, err)
+	require.NoError(t, users.Create(&models.User{Username: "admin", Password: adminHash, City: repositories.DefaultCity, IsActive: true, Role: models.RoleAdmin}))
+
+	notificationService := services.NewNotificationService(notifications, notificationPrefs, uow)
+	server := httptest.NewServer(api.NewServer(
+		services.NewUserService(users, uow),
+		services.NewPostService(posts, likes, uow),
+		services.NewQuestionService(questions, answers, uow),
+		services.NewAdminService(users, posts, questions, answers, audit, uow),
+		services.NewSessionService(sessions, users),
+		services.NewCategoryService(categories),
+		services.NewCityService(cities),
+		services.NewCommentService(comments, uow),
+		notificationService,
+	))
+	t.Cleanup(server.Close)
+	live := services.NewPollingSource(notifications)
+	live.Interval = 10 * time.Millisecond
+	ctx, endLive := context.WithCancel(context.Background())
+	go notificationService.Follow(ctx, live)
+	// Ending the feed ends the event streams, which the server waits for.
+	t.Cleanup(endLive)
+	return &client{t: t, server: server, outbox: services.NewOutboxWorker(outbox, uow)}
+}
+
+// do sends body as JSON, authenticating as username when it is not empty,
+// with the password username+"@123". It returns the status and decoded body.
+func (c *client) do(method, path, username string, body any) (int, any) {
+	return c.send(method, path, body, func(req *http.Request) {
+		if username != "" {
+			req.SetBasicAuth(username, username+"@123")
+		}
+	})
+}
+
+// withToken is do with a Bearer session token instead of Basic credentials.
+func (c *client) withToken(method, path, token string, body any) (int, any) {
+	return c.send(method, path, body, func(req *http.Request) {
+		req.Header.Set("Authorization", "Bearer "+token)
+	})
+}
+
+func (c *client) send(method, path string, body any, authenticate func(req *http.Request)) (int, any) {
+	var reader io.Reader
+	if body != nil {
+		if raw, ok := body.(string); ok {
+			reader = strings.NewReader(raw)
+		} else {
+			encoded, err := json.Marshal(body)
+			require.NoError(c.t, err)
+			reader = bytes.NewReader(encoded)
+		}
+	}
+	req, err := http.NewRequest(method, c.server.URL+path, reader)
+	require.NoError(c.t, err)
+	authenticate(req)
+	resp, err := c.server.Client().Do(req)
+	require.NoError(c.t, err)
+	defer resp.Body.Close()
+	// Rather than poll, announce new posts as soon as each request is done.
+	require.NoError(c.t, c.outbox.Flush())
+	var decoded any
+	if resp.StatusCode != http.StatusNoContent {
+		_ = json.NewDecoder(resp.Body).Decode(&decoded)
+	}
+	return resp.StatusCode, decoded
+}
+
+func (c *client) signup(username string, dwellingAge int) {
+	status, body := c.do("POST", "/users", "", map[string]any{"username": username, "password": username + "@123", "city": "delhi", "dwelling_age": dwellingAge})
+	require.Equal(c.t, http.StatusCreated, status, body)
+}
+
+func TestAPI_Signup(t *testing.T) {
+	c := newClient(t)
+
+	status, body := c.do("POST", "/users", "", map[string]any{"username": "riya", "password": "riya@123", "city": "Delhi", "dwelling_age": 4})
+	require.Equal(t, http.StatusCreated, status)
+	user := body.(map[string]any)
+	assert.Equal(t, "riya", user["username"])
+	assert.Equal(t, "resident", user["tag"])
+	assert.Equal(t, "delhi", user["city"])
+	assert.NotContains(t, user, "password")
+
+	status, _ = c.do("POST", "/users", "", map[string]any{"username": "riya", "password": "riya@123", "city": "Delhi", "dwelling_age": 4})
+	assert.Equal(t, http.StatusConflict, status)
+
+	status, body = c.do("POST", "/users", "", map[string]any{"username": "aman", "password": "weak", "city": "delhi", "dwelling_age": 1})
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Contains(t, body.(map[string]any)["error"], "password is weak")
+
+	status, body = c.do("POST", "/users", "", map[string]any{"username": "aman", "password": "aman@123", "city": "delhi"})
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Contains(t, body.(map[string]any)["error"], "dwelling_age")
+
+	status, body = c.do("POST", "/users", "", map[string]any{"username": "aman", "password": "aman@123", "dwelling_age": 1})
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Contains(t, body.(map[string]any)["error"], "city")
+
+	status, body = c.do("POST", "/users", "", map[string]any{"username": "aman", "password": "aman@123", "city": "goa", "dwelling_age": 1})
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, "Unknown city goa, use delhi", body.(map[string]any)["error"])
+
+	status, _ = c.do("POST", "/users", "", `{"username": "aman", "password": "aman@123", "dwelling_age": 1, "admin": true}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+	status, _ = c.do("POST", "/users", "", map[string]any{"username": "Admin", "password": "aman@123", "city": "delhi", "dwelling_age": 1})
+	assert.Equal(t, http.StatusConflict, status)
+	status, _ = c.do("POST", "/users", "", `{"username": `)
+	assert.Equal(t, http.StatusBadRequest, status)
+}
+
+func TestAPI_Authentication(t *testing.T) {
+	c := newClient(t)
+	c.signup("riya", 4)
+
+	status, body := c.do("POST", "/login", "", map[string]any{"username": "riya", "password": "riya@123"})
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "riya", body.(map[string]any)["user"].(map[string]any)["username"])
+
+	status, body = c.do("POST", "/login", "", map[string]any{"username": "riya", "password": "wrong@123"})
+	assert.Equal(t, http.StatusUnauthorized, status)
+	assert.Equal(t, "Invalid Account credentials", body.(map[string]any)["error"])
+
+	status, _ = c.do("GET", "/posts", "", nil)
+	assert.Equal(t, http.StatusUnauthorized, status)
+	status, _ = c.do("GET", "/admin/users", "riya", nil)
+	assert.Equal(t, http.StatusForbidden, status)
+
+	status, _ = c.do("DELETE", "/me", "riya", nil)
+	assert.Equal(t, http.StatusNoContent, status)
+	status, _ = c.do("GET", "/me", "riya", nil)
+	assert.Equal(t, http.StatusForbidden, status)
+}
+
+func TestAPI_Sessions(t *testing.T) {
+	c := newClient(t)
+	c.signup("riya", 4)
+
+	login := func() string {
+		status, body := c.do("POST", "/login", "", map[string]any{"username": "riya", "password": "riya@123"})
+		require.Equal(t, http.StatusOK, status)
+		assert.NotEmpty(t, body.(map[string]any)["expires_at"])
+		return body.(map[string]any)["token"].(string)
+	}
+	phone, laptop := login(), login()
+
+	status, body := c.withToken("GET", "/me", phone, nil)
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "riya", body.(map[string]any)["username"])
+	status, _ = c.withToken("POST", "/posts", phone, map[string]string{"type": "food", "title": "Momos", "content": "Lajpat Nagar"})
+	assert.Equal(t, http.StatusCreated, status)
+
+	status, _ = c.withToken("POST", "/logout", phone, nil)
+	assert.Equal(t, http.StatusNoContent, status)
+	status, body = c.withToken("GET", "/me", phone, nil)
+	assert.Equal(t, http.StatusUnauthorized, status)
+	assert.Equal(t, "Invalid or expired session", body.(map[string]any)["error"])
+	status, _ = c.withToken("GET", "/me", laptop, nil)
+	assert.Equal(t, http.StatusOK, status)
+
+	status, _ = c.withToken("POST", "/logout-all", laptop, nil)
+	assert.Equal(t, http.StatusNoContent, status)
+	status, _ = c.withToken("GET", "/me", laptop, nil)
+	assert.Equal(t, http.StatusUnauthorized, status)
+
+	status, _ = c.do("POST", "/logout", "", nil)
+	assert.Equal(t, http.StatusUnauthorized, status)
+}
+
+func TestAPI_Paging(t *testing.T) {
+	c := newClient(t)
+	c.signup("riya", 4)
+	for _, title := range []string{"Momos", "Metro", "Market"} {
+		status, _ := c.do("POST", "/posts", "riya", map[string]string{"type": "food", "title": title, "content": "Delhi"})
+		require.Equal(t, http.StatusCreated, status)
+	}
+
+	get := func(path string) (*http.Response, []any) {
+		req, err := http.NewRequest("GET", c.server.URL+path, nil)
+		require.NoError(t, err)
+		req.SetBasicAuth("riya", "riya@123")
+		resp, err := c.server.Client().Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var posts []any
+		_ = json.NewDecoder(resp.Body).Decode(&posts)
+		return resp, posts
+	}
+
+	resp, posts := get("/posts?type=food&per_page=2")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Len(t, posts, 2)
+	assert.Equal(t, "Market", posts[0].(map[string]any)["title"])
+	assert.Equal(t, `</posts?page=2&per_page=2&sort=newest&type=food>; rel="next"`, resp.Header.Get("Link"))
+
+	resp, posts = get("/posts?page=2&per_page=2&sort=oldest")
+	require.Len(t, posts, 1)
+	assert.Equal(t, "Market", posts[0].(map[string]any)["title"])
+	assert.Equal(t, `</posts?page=1&per_page=2&sort=oldest>; rel="prev"`, resp.Header.Get("Link"))
+
+	resp, _ = get("/posts?page=0")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	resp, _ = get("/posts?sort=title")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestAPI_Search(t *testing.T) {
+	c := newClient(t)
+	c.signup("riya", 4)
+	status, _ := c.do("POST", "/posts", "riya", map[string]string{"type": "food", "title": "Momos", "content": "Lajpat Nagar"})
+	require.Equal(t, http.StatusCreated, status)
+	status, _ = c.do("POST", "/posts/1/questions", "riya", map[string]string{"text": "Open on Sunday?"})
+	require.Equal(t, http.StatusCreated, status)
+
+	status, body := c.do("GET", "/posts/search?q=lajpat+nagar", "riya", nil)
+	require.Equal(t, http.StatusOK, status)
+	require.Len(t, body, 1)
+	assert.Equal(t, "Momos", body.([]any)[0].(map[string]any)["title"])
+
+	status, body = c.do("GET", "/questions/search?q=sunday", "riya", nil)
+	require.Equal(t, http.StatusOK, status)
+	require.Len(t, body, 1)
+	assert.Equal(t, "Open on Sunday?", body.([]any)[0].(map[string]any)["text"])
+
+	status, _ = c.do("GET", "/posts/search", "riya", nil)
+	assert.Equal(t, http.StatusBadRequest, status)
+	status, _ = c.do("GET", "/questions/search?q=x", "", nil)
+	assert.Equal(t, http.StatusUnauthorized, status)
+}
+
+func TestAPI_PostsQuestionsAndAnswers(t *testing.T) {
+	c := newClient(t)
+	c.signup("riya", 4)
+	c.signup("aman", 1)
+
+	req, err := http.NewRequest("POST", c.server.URL+"/posts", strings.NewReader(`{"type": "food", "title": "Momos", "content": "Lajpat Nagar"}`))
+	require.NoError(t, err)
+	req.SetBasicAuth("riya", "riya@123")
+	resp, err := c.server.Client().Do(req)
+	require.NoError(t, err)
+	var created map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&created))
+	resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, "/posts/1", resp.Header.Get("Location"))
+	assert.Equal(t, float64(1), created["id"])
+	assert.Equal(t, "Momos", created["title"])
+	assert.Equal(t, "food", created["type"])
+	status, _ := c.do("POST", "/posts", "riya", map[string]string{"type": "sports", "title": "Cricket", "content": "Sunday"})
+	assert.Equal(t, http.StatusBadRequest, status)
+	status, body := c.do("POST", "/posts", "riya", map[string]string{"type": "food", "title": "  "})
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, "title is required", body.(map[string]any)["error"])
+
+	status, body = c.do("GET", "/posts?type=food", "aman", nil)
+	require.Equal(t, http.StatusOK, status)
+	require.Len(t, body, 1)
+	assert.Equal(t, "Momos", body.([]any)[0].(map[string]any)["title"])
+	status, _ = c.do("GET", "/posts?type=sports", "aman", nil)
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	status, _ = c.do("PUT", "/posts/1/like", "aman", nil)
+	assert.Equal(t, http.StatusNoContent, status)
+	status, _ = c.do("PUT", "/posts/1/like", "aman", nil)
+	assert.Equal(t, http.StatusConflict, status)
+	status, _ = c.do("PUT", "/posts/1/like", "riya", nil)
+	assert.Equal(t, http.StatusForbidden, status)
+	status, body = c.do("GET", "/posts/1", "aman", nil)
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, true, body.(map[string]any)["liked_by_me"])
+	assert.Equal(t, float64(1), body.(map[string]any)["likes"])
+	status, _ = c.do("DELETE", "/posts/1/like", "aman", nil)
+	assert.Equal(t, http.StatusNoContent, status)
+
+	status, _ = c.do("GET", "/posts/9", "aman", nil)
+	assert.Equal(t, http.StatusNotFound, status)
+	status, _ = c.do("GET", "/posts/abc", "aman", nil)
+	assert.Equal(t, http.StatusBadRequest, status)
+	status, _ = c.do("PUT", "/posts/1", "aman", map[string]string{"title": "Mine now", "content": "x"})
+	assert.Equal(t, http.StatusForbidden, status)
+	status, _ = c.do("PUT", "/posts/1", "riya", map[string]string{"title": "Momos!", "content": "Lajpat Nagar, gate 2"})
+	assert.Equal(t, http.StatusNoContent, status)
+
+	status, _ = c.do("POST", "/posts/1/questions", "aman", map[string]string{"text": "Open on Sunday?"})
+	require.Equal(t, http.StatusCreated, status)
+	status, _ = c.do("POST", "/posts/9/questions", "aman", map[string]string{"text": "Anyone?"})
+	assert.Equal(t, http.StatusNotFound, status)
+	status, _ = c.do("POST", "/questions/1/answers", "riya", map[string]string{"text": "Yes"})
+	require.Equal(t, http.StatusCreated, status)
+	status, _ = c.do("PUT", "/answers/1", "aman", map[string]string{"text": "No"})
+	assert.Equal(t, http.StatusForbidden, status)
+	status, _ = c.do("PUT", "/answers/1", "riya", map[string]string{"text": "Yes, till 10"})
+	assert.Equal(t, http.StatusNoContent, status)
+
+	status, body = c.do("GET", "/posts/1/questions", "aman", nil)
+	require.Equal(t, http.StatusOK, status)
+	require.Len(t, body, 1)
+	question := body.([]any)[0].(map[string]any)
+	assert.Equal(t, "Open on Sunday?", question["text"])
+	answers := question["answers"].([]any)
+	require.Len(t, answers, 1)
+	assert.Equal(t, "Yes, till 10", answers[0].(map[string]any)["text"])
+	assert.Equal(t, "riya", answers[0].(map[string]any)["username"])
+
+	status, body = c.do("GET", "/me/notifications?unread=true", "aman", nil)
+	require.Equal(t, http.StatusOK, status)
+	require.Len(t, body, 2)
+	assert.Equal(t, models.NotifyNewAnswer, body.([]any)[0].(map[string]any)["type"])
+	assert.Equal(t, "New post: Momos", body.([]any)[1].(map[string]any)["message"])
+	status, _ = c.do("POST", "/me/notifications/read", "aman", nil)
+	assert.Equal(t, http.StatusNoContent, status)
+	_, body = c.do("GET", "/me/notifications?unread=true", "aman", nil)
+	assert.Empty(t, body)
+	_, body = c.do("GET", "/me/notifications", "aman", nil)
+	assert.Len(t, body, 2)
+	_, body = c.do("GET", "/me/notifications", "riya", nil)
+	require.Len(t, body, 1)
+	assert.Equal(t, `New question on your post "Momos!": "Open on Sunday?"`, body.([]any)[0].(map[string]any)["message"])
+
+	status, _ = c.do("DELETE", "/posts/1", "riya", nil)
+	assert.Equal(t, http.StatusNoContent, status)
+	status, _ = c.do("DELETE", "/posts/1", "riya", nil)
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestAPI_Admin(t *testing.T) {
+	c := newClient(t)
+	c.signup("riya", 4)
+	status, _ := c.do("POST", "/posts", "riya", map[string]string{"type": "travel", "title": "Metro", "content": "Yellow line"})
+	require.Equal(t, http.StatusCreated, status)
+
+	status, body := c.do("GET", "/admin/users", "admin", nil)
+	require.Equal(t, http.StatusOK, status)
+	assert.Len(t, body, 2)
+	status, body = c.do("GET", "/admin/posts", "admin", nil)
+	require.Equal(t, http.StatusOK, status)
+	assert.Len(t, body, 1)
+
+	status, _ = c.do("DELETE", "/admin/posts/1", "admin", nil)
+	assert.Equal(t, http.StatusNoContent, status)
+	status, _ = c.do("DELETE", "/admin/posts/1", "admin", nil)
+	assert.Equal(t, http.StatusNotFound, status)
+
+	status, _ = c.do("DELETE", "/admin/users/2", "admin", nil)
+	assert.Equal(t, http.StatusNoContent, status)
+	status, _ = c.do("GET", "/me", "riya", nil)
+	assert.Equal(t, http.StatusUnauthorized, status)
+	status, _ = c.do("POST", "/admin/users/2/reactivate", "admin", nil)
+	assert.Equal(t, http.StatusNotFound, status)
+
+	status, body = c.do("GET", "/admin/audit?actor=1&target_type=post", "admin", nil)
+	require.Equal(t, http.StatusOK, status)
+	entries := body.([]any)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "delete_post", entries[0].(map[string]any)["action"])
+	assert.Equal(t, "Metro", entries[0].(map[string]any)["before"].(map[string]any)["title"])
+
+	status, body = c.do("GET", "/admin/audit?from=2000-01-01&to=2000-01-02", "admin", nil)
+	require.Equal(t, http.StatusOK, status)
+	assert.Empty(t, body)
+	status, _ = c.do("GET", "/admin/audit?actor=me", "admin", nil)
+	assert.Equal(t, http.StatusBadRequest, status)
+	status, _ = c.do("GET", "/admin/audit?from=2024-02-01&to=2024-01-01", "admin", nil)
+	assert.Equal(t, http.StatusBadRequest, status)
+}
+
+func TestAPI_Categories(t *testing.T) {
+	c := newClient(t)
+	c.signup("riya", 4)
+
+	status, body := c.do("GET", "/categories", "", nil)
+	require.Equal(t, http.StatusOK, status)
+	assert.Len(t, body, 4)
+	status, _ = c.do("POST", "/posts", "riya", map[string]string{"type": "events", "title": "Fair", "content": "Pragati Maidan"})
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	status, _ = c.do("POST", "/admin/categories", "riya", map[string]string{"name": "events"})
+	assert.Equal(t, http.StatusForbidden, status)
+	status, _ = c.do("POST", "/admin/categories", "admin", map[string]string{"name": "Events!"})
+	assert.Equal(t, http.StatusBadRequest, status)
+	status, body = c.do("POST", "/admin/categories", "admin", map[string]string{"name": "events", "description": "Fairs and concerts"})
+	require.Equal(t, http.StatusCreated, status, body)
+	id := int(body.(map[string]any)["id"].(float64))
+	status, _ = c.do("POST", "/admin/categories", "admin", map[string]string{"name": "events"})
+	assert.Equal(t, http.StatusConflict, status)
+
+	status, _ = c.do("POST", "/posts", "riya", map[string]string{"type": "events", "title": "Fair", "content": "Pragati Maidan"})
+	require.Equal(t, http.StatusCreated, status)
+
+	// A rename carries the posts over to the new name.
+	status, body = c.do("PUT", fmt.Sprintf("/admin/categories/%d", id), "admin", map[string]any{"name": "fairs", "active": false})
+	require.Equal(t, http.StatusOK, status, body)
+	assert.Equal(t, false, body.(map[string]any)["active"])
+	status, body = c.do("GET", "/posts?type=fairs", "riya", nil)
+	require.Equal(t, http.StatusOK, status)
+	assert.Len(t, body, 1)
+	status, _ = c.do("GET", "/posts?type=events", "riya", nil)
+	assert.Equal(t, http.StatusBadRequest, status)
+	status, body = c.do("GET", "/categories", "", nil)
+	require.Equal(t, http.StatusOK, status)
+	assert.Len(t, body, 4)
+
+	status, _ = c.do("DELETE", fmt.Sprintf("/admin/categories/%d", id), "admin", nil)
+	assert.Equal(t, http.StatusConflict, status)
+	status, _ = c.do("DELETE", "/admin/posts/1", "admin", nil)
+	require.Equal(t, http.StatusNoContent, status)
+	status, _ = c.do("DELETE", fmt.Sprintf("/admin/categories/%d", id), "admin", nil)
+	assert.Equal(t, http.StatusNoContent, status)
+	status, _ = c.do("PUT", fmt.Sprintf("/admin/categories/%d", id), "admin", map[string]any{"active": true})
+	assert.Equal(t, http.StatusNotFound, status)
+
+	status, body = c.do("GET", "/admin/categories", "admin", nil)
+	require.Equal(t, http.StatusOK, status)
+	assert.Len(t, body, 4)
+}
+
+func TestAPI_Cities(t *testing.T) {
+	c := newClient(t)
+	c.signup("riya", 4)
+
+	status, _ := c.do("POST", "/admin/cities", "riya", map[string]string{"name": "pune"})
+	assert.Equal(t, http.StatusForbidden, status)
+	status, _ = c.do("POST", "/admin/cities", "admin", map[string]string{"name": "pune 2"})
+	assert.Equal(t, http.StatusBadRequest, status)
+	status, body := c.do("POST", "/admin/cities", "admin", map[string]string{"name": "Pune"})
+	require.Equal(t, http.StatusCreated, status, body)
+	assert.Equal(t, "pune", body.(map[string]any)["name"])
+	status, _ = c.do("POST", "/admin/cities", "admin", map[string]string{"name": "pune"})
+	assert.Equal(t, http.StatusConflict, status)
+	status, body = c.do("GET", "/cities", "", nil)
+	require.Equal(t, http.StatusOK, status)
+	assert.Len(t, body, 2)
+
+	status, _ = c.do("POST", "/users", "", map[string]any{"username": "aman", "password": "aman@123", "city": "pune", "dwelling_age": 1})
+	require.Equal(t, http.StatusCreated, status)
+	status, _ = c.do("POST", "/posts", "aman", map[string]string{"type": "food", "title": "Misal pav", "content": "Bedekar"})
+	require.Equal(t, http.StatusCreated, status)
+
+	status, body = c.do("GET", "/posts", "riya", nil)
+	require.Equal(t, http.StatusOK, status)
+	assert.Empty(t, body)
+	status, body = c.do("GET", "/me/notifications", "riya", nil)
+	require.Equal(t, http.StatusOK, status)
+	assert.Empty(t, body)
+
+	status, _ = c.do("PUT", "/me/city", "riya", map[string]string{"name": "goa"})
+	assert.Equal(t, http.StatusBadRequest, status)
+	status, body = c.do("PUT", "/me/city", "riya", map[string]string{"name": "pune"})
+	require.Equal(t, http.StatusOK, status, body)
+	assert.Equal(t, "pune", body.(map[string]any)["city"])
+	status, _ = c.do("PUT", "/me/city", "riya", map[string]string{"name": "pune"})
+	assert.Equal(t, http.StatusConflict, status)
+	status, body = c.do("GET", "/posts", "riya", nil)
+	require.Equal(t, http.StatusOK, status)
+	assert.Len(t, body, 1)
+
+	status, body = c.do("GET", "/admin/posts?city=delhi", "admin", nil)
+	require.Equal(t, http.StatusOK, status)
+	assert.Empty(t, body)
+	status, body = c.do("GET", "/admin/posts?city=pune", "admin", nil)
+	require.Equal(t, http.StatusOK, status)
+	assert.Len(t, body, 1)
+	status, _ = c.do("GET", "/admin/questions?city=goa", "admin", nil)
+	assert.Equal(t, http.StatusBadRequest, status)
+}
+
+func TestAPI_PostsNearMe(t *testing.T) {
+	c := newClient(t)
+	c.signup("riya", 4)
+
+	status, body := c.do("POST", "/posts", "riya", map[string]any{"type": "food", "title": "Janpath chai", "content": "Near the market",
+		"neighbourhood": "Connaught Place", "latitude": 28.6250, "longitude": 77.2187})
+	require.Equal(t, http.StatusCreated, status, body)
+	status, _ = c.do("POST", "/posts", "riya", map[string]any{"type": "travel", "title": "Deer park", "content": "Come early",
+		"neighbourhood": "hauz khas"})
+	require.Equal(t, http.StatusCreated, status)
+	status, _ = c.do("POST", "/posts", "riya", map[string]any{"type": "food", "title": "Half", "content": "x", "latitude": 28.6})
+	assert.Equal(t, http.StatusBadRequest, status)
+	status, _ = c.do("POST", "/posts", "riya", map[string]any{"type": "food", "title": "Pole", "content": "x", "latitude": 91, "longitude": 0})
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	status, body = c.do("GET", "/posts?neighbourhood=hauz+khas", "riya", nil)
+	require.Equal(t, http.StatusOK, status)
+	require.Len(t, body, 1)
+	assert.Equal(t, "Deer park", body.([]any)[0].(map[string]any)["title"])
+
+	status, body = c.do("GET", "/posts/near?lat=28.6315&lng=77.2167", "riya", nil)
+	require.Equal(t, http.StatusOK, status, body)
+	require.Len(t, body, 1)
+	post := body.([]any)[0].(map[string]any)
+	assert.Equal(t, "connaught place", post["neighbourhood"])
+	assert.Equal(t, 28.625, post["latitude"])
+	assert.InDelta(t, 0.7, post["distance_km"], 0.1)
+
+	status, _ = c.do("GET", "/posts/near?lat=28.6315", "riya", nil)
+	assert.Equal(t, http.StatusBadRequest, status)
+	status, _ = c.do("GET", "/posts/near?lat=28.6315&lng=east", "riya", nil)
+	assert.Equal(t, http.StatusBadRequest, status)
+	status, _ = c.do("GET", "/posts/near?lat=28.6315&lng=77.2167&radius_km=100", "riya", nil)
+	assert.Equal(t, http.StatusBadRequest, status)
+}
+
+func TestAPI_Comments(t *testing.T) {
+	c := newClient(t)
+	c.signup("riya", 4)
+	c.signup("aman", 1)
+	status, _ := c.do("POST", "/posts", "riya", map[string]any{"type": "food", "title": "Momos", "content": "Lajpat Nagar"})
+	require.Equal(t, http.StatusCreated, status)
+
+	status, _ = c.do("POST", "/posts/1/comments", "aman", map[string]any{"text": "Which stall?"})
+	require.Equal(t, http.StatusCreated, status)
+	status, _ = c.do("POST", "/comments/1/replies", "riya", map[string]any{"text": "By the gate"})
+	require.Equal(t, http.StatusCreated, status)
+	status, _ = c.do("POST", "/comments/2/replies", "aman", map[string]any{"text": "Thanks"})
+	require.Equal(t, http.StatusCreated, status)
+	status, _ = c.do("POST", "/posts/9/comments", "aman", map[string]any{"text": "Hi"})
+	assert.Equal(t, http.StatusNotFound, status)
+	status, _ = c.do("POST", "/posts/1/comments", "aman", map[string]any{"text": strings.Repeat("a", 1001)})
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	status, body := c.do("GET", "/posts/1/comments?depth=1", "aman", nil)
+	require.Equal(t, http.StatusOK, status, body)
+	require.Len(t, body, 1)
+	thread := body.([]any)[0].(map[string]any)
+	assert.Equal(t, "aman", thread["username"])
+	reply := thread["replies"].([]any)[0].(map[string]any)
+	assert.Equal(t, "By the gate", reply["text"])
+	assert.Empty(t, reply["replies"])
+	assert.Equal(t, float64(1), reply["more_replies"])
+	status, _ = c.do("GET", "/posts/1/comments?depth=-1", "aman", nil)
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	status, body = c.do("GET", "/comments/2", "aman", nil)
+	require.Equal(t, http.StatusOK, status)
+	assert.Len(t, body.(map[string]any)["replies"], 1)
+
+	status, _ = c.do("PUT", "/comments/1", "riya", map[string]any{"text": "Mine now"})
+	assert.Equal(t, http.StatusForbidden, status)
+	status, _ = c.do("PUT", "/comments/1", "aman", map[string]any{"text": "Which momo stall?"})
+	assert.Equal(t, http.StatusNoContent, status)
+	status, _ = c.do("DELETE", "/comments/1", "riya", nil)
+	assert.Equal(t, http.StatusForbidden, status)
+	status, _ = c.do("DELETE", "/comments/1", "aman", nil)
+	assert.Equal(t, http.StatusNoContent, status)
+	status, body = c.do("GET", "/posts/1/comments", "aman", nil)
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, true, body.([]any)[0].(map[string]any)["deleted"])
+	status, _ = c.do("POST", "/comments/1/replies", "riya", map[string]any{"text": "Hello?"})
+	assert.Equal(t, http.StatusNotFound, status)
+
+	status, _ = c.do("DELETE", "/admin/comments/3", "aman", nil)
+	assert.Equal(t, http.StatusForbidden, status)
+	status, _ = c.do("DELETE", "/admin/comments/3", "admin", nil)
+	assert.Equal(t, http.StatusNoContent, status)
+	status, _ = c.do("GET", "/comments/3", "aman", nil)
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestAPI_AdminRoles(t *testing.T) {
+	c := newClient(t)
+	c.signup("riya", 4)
+	c.signup("aman", 1)
+
+	status, body := c.do("PUT", "/admin/users/2/role", "admin", map[string]string{"role": "moderator"})
+	require.Equal(t, http.StatusNoContent, status, body)
+	status, _ = c.do("PUT", "/admin/users/2/role", "admin", map[string]string{"role": "moderator"})
+	assert.Equal(t, http.StatusConflict, status)
+	status, _ = c.do("PUT", "/admin/users/2/role", "admin", map[string]string{"role": "owner"})
+	assert.Equal(t, http.StatusBadRequest, status)
+	status, _ = c.do("PUT", "/admin/users/1/role", "admin", map[string]string{"role": "user"})
+	assert.Equal(t, http.StatusForbidden, status)
+
+	// A moderator sees content but cannot manage accounts or roles.
+	status, _ = c.do("GET", "/admin/posts", "riya", nil)
+	assert.Equal(t, http.StatusOK, status)
+	status, _ = c.do("GET", "/admin/users", "riya", nil)
+	assert.Equal(t, http.StatusForbidden, status)
+	status, _ = c.do("DELETE", "/admin/users/3", "riya", nil)
+	assert.Equal(t, http.StatusForbidden, status)
+	status, _ = c.do("PUT", "/admin/users/3/role", "riya", map[string]string{"role": "admin"})
+	assert.Equal(t, http.StatusForbidden, status)
+
+	status, _ = c.do("DELETE", "/admin/users/2/role", "admin", nil)
+	assert.Equal(t, http.StatusNoContent, status)
+	status, _ = c.do("GET", "/admin/posts", "riya", nil)
+	assert.Equal(t, http.StatusForbidden, status)
+}
+
+func TestAPI_Notifications(t *testing.T) {
+	c := newClient(t)
+	c.signup("riya", 4)
+	c.signup("aman", 1)
+	status, _ := c.do("POST", "/posts", "riya", map[string]any{"type": "food", "title": "Momos", "content": "Lajpat Nagar"})
+	require.Equal(t, http.StatusCreated, status)
+	status, _ = c.do("POST", "/posts", "riya", map[string]any{"type": "food", "title": "Chaat", "content": "CP"})
+	require.Equal(t, http.StatusCreated, status)
+	status, _ = c.do("DELETE", "/admin/posts/2", "admin", nil)
+	require.Equal(t, http.StatusNoContent, status)
+
+	status, body := c.do("GET", "/me/notifications", "riya", nil)
+	require.Equal(t, http.StatusOK, status)
+	require.Len(t, body, 1)
+	removed := body.([]any)[0].(map[string]any)
+	assert.Equal(t, models.NotifyAdminAction, removed["type"])
+	assert.Equal(t, models.TargetPost, removed["source_type"])
+	assert.Equal(t, `A moderator removed your post "Chaat"`, removed["message"])
+
+	status, body = c.do("GET", "/me/notifications?sort=oldest&per_page=1", "aman", nil)
+	require.Equal(t, http.StatusOK, status)
+	require.Len(t, body, 1)
+	first := body.([]any)[0].(map[string]any)
+	assert.Equal(t, "New post: Momos", first["message"])
+	id := int(first["id"].(float64))
+
+	status, _ = c.do("POST", fmt.Sprintf("/me/notifications/%d/read", id), "riya", nil)
+	assert.Equal(t, http.StatusNotFound, status)
+	status, _ = c.do("POST", fmt.Sprintf("/me/notifications/%d/read", id), "aman", nil)
+	assert.Equal(t, http.StatusNoContent, status)
+	_, body = c.do("GET", "/me/notifications?unread=true", "aman", nil)
+	require.Len(t, body, 1)
+	assert.Equal(t, "New post: Chaat", body.([]any)[0].(map[string]any)["message"])
+	_, body = c.do("GET", "/me/notifications", "aman", nil)
+	assert.Len(t, body, 2)
+	status, _ = c.do("GET", "/me/notifications?sort=most-liked", "aman", nil)
+	assert.Equal(t, http.StatusBadRequest, status)
+}
+
+func TestAPI_NotificationStream(t *testing.T) {
+	c := newClient(t)
+	c.signup("riya", 4)
+	c.signup("aman", 1)
+
+	req, err := http.NewRequest("GET", c.server.URL+"/me/notifications/stream", nil)
+	require.NoError(t, err)
+	req.SetBasicAuth("aman", "aman@123")
+	resp, err := c.server.Client().Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	status, _ := c.do("POST", "/posts", "riya", map[string]any{"type": "food", "title": "Momos", "content": "Lajpat Nagar"})
+	require.Equal(t, http.StatusCreated, status)
+
+	events := make(chan string, 1)
+	go func() {
+		var event strings.Builder
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			if scanner.Text() == "" {
+				events <- event.String()
+				return
+			}
+			event.WriteString(scanner.Text() + "\n")
+		}
+	}()
+	select {
+	case event := <-events:
+		assert.Contains(t, event, "event: notification\n")
+		assert.Contains(t, event, `"message":"New post: Momos"`)
+		assert.Contains(t, event, `"source_type":"post"`)
+	case <-time.After(2 * time.Second):
+		t.Fatal("no event streamed")
+	}
+
+	status, _ = c.do("GET", "/me/notifications/stream", "", nil)
+	assert.Equal(t, http.StatusUnauthorized, status)
+}
+
+func TestAPI_NotificationSettings(t *testing.T) {
+	c := newClient(t)
+	c.signup("riya", 4)
+	c.signup("aman", 1)
+
+	status, body := c.do("GET", "/me/notification-settings", "aman", nil)
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, map[string]any{"delivery": "immediate", "posts": "all", "subscriptions": []any{}}, body)
+
+	status, _ = c.do("PUT", "/me/notification-settings", "aman", map[string]any{})
+	assert.Equal(t, http.StatusBadRequest, status)
+	status, _ = c.do("PUT", "/me/notification-settings", "aman", map[string]any{"posts": "some"})
+	assert.Equal(t, http.StatusBadRequest, status)
+	status, body = c.do("PUT", "/me/notification-settings", "aman", map[string]any{"posts": "subscribed"})
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "subscribed", body.(map[string]any)["posts"])
+
+	status, _ = c.do("PUT", "/me/subscriptions/authors/riya", "aman", nil)
+	assert.Equal(t, http.StatusNoContent, status)
+	status, _ = c.do("PUT", "/me/subscriptions/authors/riya", "aman", nil)
+	assert.Equal(t, http.StatusConflict, status)
+	status, _ = c.do("PUT", "/me/subscriptions/authors/aman", "aman", nil)
+	assert.Equal(t, http.StatusForbidden, status)
+	status, _ = c.do("PUT", "/me/subscriptions/authors/nobody", "aman", nil)
+	assert.Equal(t, http.StatusNotFound, status)
+	status, _ = c.do("PUT", "/me/subscriptions/categories/gossip", "aman", nil)
+	assert.Equal(t, http.StatusBadRequest, status)
+	status, _ = c.do("PUT", "/me/subscriptions/cities/delhi", "aman", nil)
+	assert.Equal(t, http.StatusNotFound, status)
+	status, _ = c.do("PUT", "/me/subscriptions/categories/travel", "aman", nil)
+	assert.Equal(t, http.StatusNoContent, status)
+	_, body = c.do("GET", "/me/notification-settings", "aman", nil)
+	assert.Equal(t, []any{
+		map[string]any{"kind": "category", "id": float64(2), "name": "travel"},
+		map[string]any{"kind": "author", "id": float64(2), "name": "riya"},
+	}, body.(map[string]any)["subscriptions"])
+
+	status, _ = c.do("DELETE", "/me/subscriptions/authors/riya", "aman", nil)
+	assert.Equal(t, http.StatusNoContent, status)
+	status, _ = c.do("DELETE", "/me/subscriptions/authors/riya", "aman", nil)
+	assert.Equal(t, http.StatusConflict, status)
+	status, _ = c.do("POST", "/posts", "riya", map[string]any{"type": "food", "title": "Momos", "content": "Lajpat Nagar"})
+	require.Equal(t, http.StatusCreated, status)
+	status, _ = c.do("POST", "/posts", "riya", map[string]any{"type": "travel", "title": "Metro", "content": "Yellow line"})
+	require.Equal(t, http.StatusCreated, status)
+	_, body = c.do("GET", "/me/notifications", "aman", nil)
+	require.Len(t, body, 1)
+	assert.Equal(t, "New post: Metro", body.([]any)[0].(map[string]any)["message"])
+}
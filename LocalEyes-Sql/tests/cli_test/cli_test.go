@@ -0,0 +1,534 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"localEyes/cmd/cli"
+	"localEyes/internal/models"
+	"localEyes/internal/render"
+	"localEyes/internal/repositories"
+	"localEyes/internal/services"
+	"localEyes/utils"
+)
+
+func TestMain(m *testing.M) {
+	utils.Logger = log.New(io.Discard, "", 0)
+	os.Exit(m.Run())
+}
+
+type harness struct {
+	cli    *cli.CLI
+	users  *repositories.InMemoryUserRepository
+	env    map[string]string
+	stdout *bytes.Buffer
+	stderr *bytes.Buffer
+	outbox *services.OutboxWorker
+}
+
+func newHarness(t *testing.T) *harness {
+	users := repositories.NewInMemoryUserRepository()
+	posts := repositories.NewInMemoryPostRepository()
+	questions := repositories.NewInMemoryQuestionRepository()
+	answers := repositories.NewInMemoryAnswerRepository()
+	likes := repositories.NewInMemoryPostLikeRepository()
+	sessions := repositories.NewInMemorySessionRepository()
+	audit := repositories.NewInMemoryAuditRepository()
+	categories := repositories.NewInMemoryCategoryRepository()
+	cities := repositories.NewInMemoryCityRepository()
+	comments := repositories.NewInMemoryCommentRepository()
+	notifications := repositories.NewInMemoryNotificationRepository()
+	notificationPrefs := repositories.NewInMemoryNotificationPrefsRepository()
+	outbox := repositories.NewInMemoryOutboxRepository()
+	uow := repositories.NewInMemoryUnitOfWork(users, posts, questions, answers, likes, sessions, audit, categories, cities, comments, notifications, notificationPrefs, outbox)
+
+	adminHash, err := services.HashPassword("admin@123")
+	require.NoError(t, err)
+	require.NoError(t, users.Create(&models.User{Username: "admin", Password: adminHash, City: repositories.DefaultCity, IsActive: true, Role: models.RoleAdmin}))
+
+	h := &harness{users: users, env: map[string]string{}, stdout: &bytes.Buffer{}, stderr: &bytes.Buffer{},
+		outbox: services.NewOutboxWorker(outbox, uow)}
+	h.cli = &cli.CLI{
+		Services: cli.Services{
+			Users:         services.NewUserService(users, uow),
+			Posts:         services.NewPostService(posts, likes, uow),
+			Questions:     services.NewQuestionService(questions, answers, uow),
+			Admin:         services.NewAdminService(users, posts, questions, answers, audit, uow),
+			Sessions:      services.NewSessionService(sessions, users),
+			Categories:    services.NewCategoryService(categories),
+			Cities:        services.NewCityService(cities),
+			Comments:      services.NewCommentService(comments, uow),
+			Notifications: services.NewNotificationService(notifications, notificationPrefs, uow),
+		},
+		Stdout: h.stdout,
+		Stderr: h.stderr,
+		Getenv: func(key string) string { return h.env[key] },
+
+		SessionFile: filepath.Join(t.TempDir(), "localeyes", "session"),
+	}
+	return h
+}
+
+// as runs the command with the given user's credentials in the environment.
+func (h *harness) as(username string, args ...string) int {
+	h.stdout.Reset()
+	h.stderr.Reset()
+	h.env[cli.UsernameEnv] = username
+	h.env[cli.PasswordEnv] = username + "@123"
+	code := h.cli.Run(args)
+	// As main does before exiting, announce the posts the command created.
+	if err := h.outbox.Flush(); err != nil {
+		panic(err)
+	}
+	return code
+}
+
+func TestCLI_UsageErrors(t *testing.T) {
+	h := newHarness(t)
+
+	assert.Equal(t, cli.ExitUsage, h.cli.Run(nil))
+	assert.Equal(t, cli.ExitUsage, h.cli.Run([]string{"bogus"}))
+	assert.Equal(t, cli.ExitUsage, h.cli.Run([]string{"post", "create", "--title", "x"}))
+	assert.Contains(t, h.stderr.String(), "flag -type is required")
+	assert.Equal(t, cli.ExitUsage, h.cli.Run([]string{"post", "list", "--type", "sports"}))
+	assert.Equal(t, cli.ExitOK, h.cli.Run([]string{"help"}))
+}
+
+func TestCLI_RequiresCredentials(t *testing.T) {
+	h := newHarness(t)
+
+	assert.Equal(t, cli.ExitAuth, h.cli.Run([]string{"post", "list"}))
+	assert.Contains(t, h.stderr.String(), cli.UsernameEnv)
+
+	assert.Equal(t, cli.ExitAuth, h.as("nobody", "post", "list"))
+	assert.Contains(t, h.stderr.String(), "Invalid Account credentials")
+	assert.NotContains(t, h.stderr.String(), "\033[")
+}
+
+func TestCLI_PostAndQuestionFlow(t *testing.T) {
+	h := newHarness(t)
+	require.Equal(t, cli.ExitOK, h.as("riya", "user", "signup", "--city", "delhi", "--dwelling-age", "4"), h.stderr.String())
+	require.Equal(t, cli.ExitOK, h.as("aman", "user", "signup", "--city", "delhi", "--dwelling-age", "1"), h.stderr.String())
+	assert.Equal(t, cli.ExitError, h.as("aman", "user", "signup", "--city", "delhi", "--dwelling-age", "1"))
+
+	require.Equal(t, cli.ExitOK, h.as("riya", "post", "create", "--type", "food", "--title", "Momos", "--content", "Lajpat Nagar"), h.stderr.String())
+	require.Equal(t, cli.ExitOK, h.as("riya", "post", "create", "--type", "travel", "--title", "Metro", "--content", "Yellow line"))
+
+	assert.Equal(t, cli.ExitOK, h.as("aman", "post", "list", "--type", "food"))
+	assert.Contains(t, h.stdout.String(), "Momos")
+	assert.NotContains(t, h.stdout.String(), "Metro")
+
+	assert.Equal(t, cli.ExitOK, h.as("aman", "post", "like", "--id", "1"))
+	assert.Equal(t, cli.ExitError, h.as("riya", "post", "like", "--id", "1"))
+	assert.Contains(t, h.stderr.String(), "You cannot like your own post")
+
+	assert.Equal(t, cli.ExitOK, h.as("aman", "question", "ask", "--post", "1", "--text", "Open on Sunday?"))
+	assert.Equal(t, cli.ExitError, h.as("aman", "question", "ask", "--post", "9", "--text", "Anyone?"))
+	assert.Equal(t, cli.ExitOK, h.as("riya", "question", "answer", "--id", "1", "--text", "Yes, till 10"))
+	assert.Equal(t, cli.ExitOK, h.as("aman", "question", "list", "--post", "1"))
+	assert.Contains(t, h.stdout.String(), "Open on Sunday?")
+	assert.Contains(t, h.stdout.String(), "riya")
+	assert.Contains(t, h.stdout.String(), "Yes, till 10")
+
+	assert.Equal(t, cli.ExitOK, h.as("aman", "user", "notifications"))
+	assert.Contains(t, h.stdout.String(), "New post: Momos")
+	assert.Contains(t, h.stdout.String(), `New answer to your question "Open on Sunday?": "Yes, till 10"`)
+	assert.Equal(t, cli.ExitOK, h.as("riya", "user", "notifications"))
+	assert.Equal(t, "New question on your post \"Momos\": \"Open on Sunday?\"\n", h.stdout.String())
+}
+
+func TestCLI_OutputFormats(t *testing.T) {
+	h := newHarness(t)
+	require.Equal(t, cli.ExitOK, h.as("riya", "user", "signup", "--city", "delhi", "--dwelling-age", "4"))
+	require.Equal(t, cli.ExitOK, h.as("riya", "post", "create", "--type", "food", "--title", "Momos", "--content", "Lajpat Nagar, gate 2"))
+
+	require.Equal(t, cli.ExitOK, h.as("riya", "post", "list", "--output", "json"), h.stderr.String())
+	var posts []map[string]any
+	require.NoError(t, json.Unmarshal(h.stdout.Bytes(), &posts))
+	require.Len(t, posts, 1)
+	assert.Equal(t, "Momos", posts[0]["title"])
+
+	require.Equal(t, cli.ExitOK, h.as("riya", "--output", "csv", "post", "list"))
+	records, err := csv.NewReader(h.stdout).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, "Lajpat Nagar, gate 2", records[1][3])
+
+	h.env[cli.OutputEnv] = "ndjson"
+	h.cli.Output = ""
+	require.Equal(t, cli.ExitOK, h.as("riya", "post", "list"))
+	assert.Equal(t, 1, strings.Count(h.stdout.String(), "\n"))
+	assert.Contains(t, h.stdout.String(), `"title":"Momos"`)
+
+	assert.Equal(t, cli.ExitUsage, h.as("riya", "post", "list", "--output", "xml"))
+	h.env[cli.OutputEnv] = "xml"
+	h.cli.Output = ""
+	assert.Equal(t, cli.ExitUsage, h.as("riya", "post", "list"))
+	assert.Contains(t, h.stderr.String(), cli.OutputEnv)
+}
+
+func TestCLI_Paging(t *testing.T) {
+	h := newHarness(t)
+	require.Equal(t, cli.ExitOK, h.as("riya", "user", "signup", "--city", "delhi", "--dwelling-age", "4"))
+	require.Equal(t, cli.ExitOK, h.as("aman", "user", "signup", "--city", "delhi", "--dwelling-age", "1"))
+	for _, title := range []string{"Momos", "Metro", "Market"} {
+		require.Equal(t, cli.ExitOK, h.as("riya", "post", "create", "--type", "food", "--title", title, "--content", "Delhi"))
+	}
+	require.Equal(t, cli.ExitOK, h.as("aman", "post", "like", "--id", "2"))
+
+	require.Equal(t, cli.ExitOK, h.as("aman", "post", "list", "--per-page", "2"), h.stderr.String())
+	assert.Contains(t, h.stdout.String(), "Market")
+	assert.NotContains(t, h.stdout.String(), "Momos")
+	assert.Contains(t, h.stderr.String(), "page 1 (next: --page 2)")
+
+	require.Equal(t, cli.ExitOK, h.as("aman", "post", "list", "--per-page", "2", "--page", "2"))
+	assert.Contains(t, h.stdout.String(), "Momos")
+	assert.Contains(t, h.stderr.String(), "page 2 (previous: --page 1)")
+
+	require.Equal(t, cli.ExitOK, h.as("aman", "post", "list", "--per-page", "1", "--sort", "most-liked", "--output", "json"))
+	assert.Contains(t, h.stdout.String(), `"title": "Metro"`)
+	assert.Empty(t, h.stderr.String())
+
+	assert.Equal(t, cli.ExitError, h.as("aman", "post", "list", "--sort", "title"))
+	assert.Contains(t, h.stderr.String(), "Unknown sort order title")
+
+	h.cli.Output = render.Table
+	require.Equal(t, cli.ExitOK, h.as("admin", "admin", "user", "list", "--per-page", "1", "--sort", "oldest"), h.stderr.String())
+	assert.Contains(t, h.stdout.String(), "admin")
+	assert.NotContains(t, h.stdout.String(), "riya")
+	assert.Contains(t, h.stderr.String(), "next: --page 2")
+}
+
+func TestCLI_Search(t *testing.T) {
+	h := newHarness(t)
+	require.Equal(t, cli.ExitOK, h.as("riya", "user", "signup", "--city", "delhi", "--dwelling-age", "4"))
+	require.Equal(t, cli.ExitOK, h.as("riya", "post", "create", "--type", "food", "--title", "Momos", "--content", "Lajpat Nagar"))
+	require.Equal(t, cli.ExitOK, h.as("riya", "post", "create", "--type", "travel", "--title", "Metro", "--content", "Yellow line"))
+	require.Equal(t, cli.ExitOK, h.as("riya", "question", "ask", "--post", "2", "--text", "Is it crowded?"))
+	require.Equal(t, cli.ExitOK, h.as("riya", "question", "answer", "--id", "1", "--text", "Only at rush hour"))
+
+	require.Equal(t, cli.ExitOK, h.as("riya", "post", "search", "--query", "lajpat"), h.stderr.String())
+	assert.Contains(t, h.stdout.String(), "Momos")
+	assert.NotContains(t, h.stdout.String(), "Metro")
+
+	require.Equal(t, cli.ExitOK, h.as("riya", "question", "search", "--query", "rush"), h.stderr.String())
+	assert.Contains(t, h.stdout.String(), "Is it crowded?")
+
+	assert.Equal(t, cli.ExitUsage, h.as("riya", "post", "search"))
+	assert.Equal(t, cli.ExitError, h.as("riya", "post", "search", "--query", " "))
+	assert.Contains(t, h.stderr.String(), "Enter something to search for")
+}
+
+// session runs the command with no credentials in the environment, so it has
+// to rely on the cached session.
+func (h *harness) session(args ...string) int {
+	h.stdout.Reset()
+	h.stderr.Reset()
+	delete(h.env, cli.UsernameEnv)
+	delete(h.env, cli.PasswordEnv)
+	return h.cli.Run(args)
+}
+
+func TestCLI_Sessions(t *testing.T) {
+	h := newHarness(t)
+	require.Equal(t, cli.ExitOK, h.as("riya", "user", "signup", "--city", "delhi", "--dwelling-age", "4"))
+
+	assert.Equal(t, cli.ExitAuth, h.session("user", "profile"))
+	assert.Contains(t, h.stderr.String(), "user login")
+
+	require.Equal(t, cli.ExitOK, h.as("riya", "user", "login"), h.stderr.String())
+	assert.Contains(t, h.stdout.String(), "Logged in as riya")
+	info, err := os.Stat(h.cli.SessionFile)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	assert.Equal(t, cli.ExitOK, h.session("user", "profile"))
+	assert.Contains(t, h.stdout.String(), "Username: riya")
+	assert.Equal(t, cli.ExitOK, h.session("post", "create", "--type", "food", "--title", "Momos", "--content", "Lajpat Nagar"))
+
+	require.Equal(t, cli.ExitOK, h.session("user", "logout"))
+	_, err = os.Stat(h.cli.SessionFile)
+	assert.True(t, os.IsNotExist(err))
+	assert.Equal(t, cli.ExitAuth, h.session("user", "profile"))
+
+	// A printed token works from the environment, until every session ends.
+	require.Equal(t, cli.ExitOK, h.as("riya", "user", "login", "--print-token"))
+	h.env[cli.TokenEnv] = strings.TrimSpace(h.stdout.String())
+	require.Equal(t, cli.ExitOK, h.as("riya", "user", "login"))
+	assert.Equal(t, cli.ExitOK, h.session("user", "profile"))
+	require.Equal(t, cli.ExitOK, h.session("user", "logout-all"))
+	assert.Equal(t, cli.ExitAuth, h.session("user", "profile"))
+	assert.Contains(t, h.stderr.String(), "Invalid or expired session")
+	delete(h.env, cli.TokenEnv)
+	assert.Equal(t, cli.ExitAuth, h.session("user", "profile"))
+}
+
+func TestCLI_Categories(t *testing.T) {
+	h := newHarness(t)
+	h.cli.Output = render.Table
+	require.Equal(t, cli.ExitOK, h.as("riya", "user", "signup", "--city", "delhi", "--dwelling-age", "4"))
+
+	assert.Equal(t, cli.ExitUsage, h.as("riya", "post", "create", "--type", "healthcare", "--title", "Clinic", "--content", "Open late"))
+	assert.Contains(t, h.stderr.String(), "Unknown category healthcare, use food, other, shopping, travel")
+
+	assert.Equal(t, cli.ExitAuth, h.as("riya", "admin", "category", "create", "--name", "healthcare"))
+	assert.Equal(t, cli.ExitError, h.as("admin", "admin", "category", "create", "--name", "Health Care"))
+	require.Equal(t, cli.ExitOK, h.as("admin", "admin", "category", "create", "--name", "healthcare", "--description", "Clinics"), h.stderr.String())
+	assert.Equal(t, cli.ExitOK, h.cli.Run([]string{"category", "list"}))
+	assert.Contains(t, h.stdout.String(), "healthcare")
+
+	require.Equal(t, cli.ExitOK, h.as("riya", "post", "create", "--type", "healthcare", "--title", "Clinic", "--content", "Open late"), h.stderr.String())
+	assert.Equal(t, cli.ExitOK, h.as("riya", "post", "list", "--type", "healthcare"))
+	assert.Contains(t, h.stdout.String(), "Clinic")
+
+	assert.Equal(t, cli.ExitUsage, h.as("admin", "admin", "category", "update", "--id", "5"))
+	require.Equal(t, cli.ExitOK, h.as("admin", "admin", "category", "update", "--id", "5", "--active=false"), h.stderr.String())
+	assert.Equal(t, cli.ExitError, h.as("riya", "post", "create", "--type", "healthcare", "--title", "Pharmacy", "--content", "24x7"))
+	assert.Contains(t, h.stderr.String(), "Category healthcare is not taking new posts")
+	assert.Equal(t, cli.ExitOK, h.as("riya", "post", "list", "--type", "healthcare"))
+	assert.Contains(t, h.stdout.String(), "Clinic")
+
+	assert.Equal(t, cli.ExitError, h.as("admin", "admin", "category", "delete", "--id", "5"))
+	assert.Contains(t, h.stderr.String(), "still has posts")
+	require.Equal(t, cli.ExitOK, h.as("admin", "admin", "category", "create", "--name", "events"))
+	assert.Equal(t, cli.ExitOK, h.as("admin", "admin", "category", "delete", "--id", "6"), h.stderr.String())
+	assert.Equal(t, cli.ExitOK, h.as("admin", "admin", "category", "list"))
+	assert.Contains(t, h.stdout.String(), "healthcare")
+	assert.NotContains(t, h.stdout.String(), "events")
+}
+
+func TestCLI_Cities(t *testing.T) {
+	h := newHarness(t)
+	h.cli.Output = render.Table
+	assert.Equal(t, cli.ExitUsage, h.as("riya", "user", "signup", "--dwelling-age", "4"))
+	assert.Equal(t, cli.ExitUsage, h.as("riya", "user", "signup", "--city", "pune", "--dwelling-age", "4"))
+	assert.Contains(t, h.stderr.String(), "Unknown city pune, use delhi")
+
+	assert.Equal(t, cli.ExitAuth, h.as("riya", "admin", "city", "create", "--name", "pune"))
+	require.Equal(t, cli.ExitOK, h.as("admin", "admin", "city", "create", "--name", "Pune"), h.stderr.String())
+	assert.Equal(t, cli.ExitError, h.as("admin", "admin", "city", "create", "--name", "pune"))
+	assert.Equal(t, cli.ExitOK, h.cli.Run([]string{"city", "list"}))
+	assert.Contains(t, h.stdout.String(), "pune")
+
+	require.Equal(t, cli.ExitOK, h.as("riya", "user", "signup", "--city", "delhi", "--dwelling-age", "4"), h.stderr.String())
+	require.Equal(t, cli.ExitOK, h.as("aman", "user", "signup", "--city", "Pune", "--dwelling-age", "1"), h.stderr.String())
+	require.Equal(t, cli.ExitOK, h.as("riya", "post", "create", "--type", "food", "--title", "Chole bhature", "--content", "Sita Ram"))
+	require.Equal(t, cli.ExitOK, h.as("aman", "post", "create", "--type", "food", "--title", "Misal pav", "--content", "Bedekar"))
+
+	// Feeds, search and notifications stay within the viewer's city.
+	assert.Equal(t, cli.ExitOK, h.as("riya", "post", "list"))
+	assert.Contains(t, h.stdout.String(), "Chole bhature")
+	assert.NotContains(t, h.stdout.String(), "Misal pav")
+	assert.Equal(t, cli.ExitOK, h.as("riya", "post", "search", "--query", "misal"))
+	assert.NotContains(t, h.stdout.String(), "Misal pav")
+	assert.Equal(t, cli.ExitOK, h.as("admin", "user", "notifications"))
+	assert.Contains(t, h.stdout.String(), "Chole bhature")
+	assert.NotContains(t, h.stdout.String(), "Misal pav")
+
+	assert.Equal(t, cli.ExitUsage, h.as("riya", "user", "move", "--city", "goa"))
+	assert.Equal(t, cli.ExitError, h.as("riya", "user", "move", "--city", "delhi"))
+	require.Equal(t, cli.ExitOK, h.as("riya", "user", "move", "--city", "pune"), h.stderr.String())
+	assert.Equal(t, cli.ExitOK, h.as("riya", "post", "list"))
+	assert.Contains(t, h.stdout.String(), "Misal pav")
+	assert.NotContains(t, h.stdout.String(), "Chole bhature")
+
+	assert.Equal(t, cli.ExitOK, h.as("admin", "admin", "post", "list", "--city", "delhi"))
+	assert.Contains(t, h.stdout.String(), "Chole bhature")
+	assert.NotContains(t, h.stdout.String(), "Misal pav")
+	assert.Equal(t, cli.ExitOK, h.as("admin", "admin", "post", "list"))
+	assert.Contains(t, h.stdout.String(), "Misal pav")
+	assert.Equal(t, cli.ExitUsage, h.as("admin", "admin", "question", "list", "--city", "goa"))
+}
+
+func TestCLI_PostsNearMe(t *testing.T) {
+	h := newHarness(t)
+	h.cli.Output = render.Table
+	require.Equal(t, cli.ExitOK, h.as("riya", "user", "signup", "--city", "delhi", "--dwelling-age", "4"))
+	require.Equal(t, cli.ExitOK, h.as("riya", "post", "create", "--type", "food", "--title", "Chandni Chowk parathas",
+		"--content", "Paranthe wali gali", "--lat", "28.6506", "--lng", "77.2303"), h.stderr.String())
+	require.Equal(t, cli.ExitOK, h.as("riya", "post", "create", "--type", "food", "--title", "Janpath chai",
+		"--content", "Near the market", "--neighbourhood", "Connaught Place", "--lat", "28.6250", "--lng", "77.2187"), h.stderr.String())
+	require.Equal(t, cli.ExitOK, h.as("riya", "post", "create", "--type", "travel", "--title", "Deer park",
+		"--content", "Come early", "--neighbourhood", "hauz khas"), h.stderr.String())
+	assert.Equal(t, cli.ExitError, h.as("riya", "post", "create", "--type", "food", "--title", "Half", "--content", "x", "--lat", "28.6"))
+	assert.Contains(t, h.stderr.String(), "Give both latitude and longitude")
+	assert.Equal(t, cli.ExitUsage, h.as("riya", "post", "create", "--type", "food", "--title", "Bad", "--content", "x", "--lat", "north"))
+
+	assert.Equal(t, cli.ExitOK, h.as("riya", "post", "list", "--neighbourhood", "Hauz Khas"))
+	assert.Contains(t, h.stdout.String(), "Deer park")
+	assert.NotContains(t, h.stdout.String(), "Janpath chai")
+
+	assert.Equal(t, cli.ExitOK, h.as("riya", "post", "near", "--lat", "28.6315", "--lng", "77.2167"), h.stderr.String())
+	out := h.stdout.String()
+	assert.Contains(t, out, "DISTANCE")
+	assert.Contains(t, out, "connaught place (28.62500,")
+	assert.Less(t, strings.Index(out, "Janpath chai"), strings.Index(out, "Chandni Chowk parathas"))
+	assert.NotContains(t, out, "Deer park")
+
+	assert.Equal(t, cli.ExitOK, h.as("riya", "post", "near", "--lat", "28.6315", "--lng", "77.2167", "--radius", "1"))
+	assert.NotContains(t, h.stdout.String(), "Chandni Chowk parathas")
+	assert.Equal(t, cli.ExitUsage, h.as("riya", "post", "near", "--lat", "28.6315"))
+	assert.Equal(t, cli.ExitError, h.as("riya", "post", "near", "--lat", "28.6315", "--lng", "77.2167", "--radius", "500"))
+}
+
+func TestCLI_Notifications(t *testing.T) {
+	h := newHarness(t)
+	h.cli.Output = render.Table
+	require.Equal(t, cli.ExitOK, h.as("riya", "user", "signup", "--city", "delhi", "--dwelling-age", "4"))
+	require.Equal(t, cli.ExitOK, h.as("aman", "user", "signup", "--city", "delhi", "--dwelling-age", "1"))
+	require.Equal(t, cli.ExitOK, h.as("riya", "post", "create", "--type", "food", "--title", "Momos", "--content", "Lajpat Nagar"))
+	require.Equal(t, cli.ExitOK, h.as("riya", "post", "create", "--type", "food", "--title", "Chaat", "--content", "CP"))
+
+	assert.Equal(t, cli.ExitOK, h.as("aman", "notification", "list", "--unread", "--sort", "oldest"))
+	assert.Contains(t, h.stdout.String(), "post #1")
+	assert.Contains(t, h.stdout.String(), "New post: Chaat")
+	assert.Equal(t, cli.ExitOK, h.as("aman", "notification", "read", "--id", "2"))
+	assert.Equal(t, cli.ExitError, h.as("riya", "notification", "read", "--id", "4"))
+	assert.Contains(t, h.stderr.String(), "No Notification exist with this id")
+	assert.Equal(t, cli.ExitUsage, h.as("aman", "notification", "read"))
+	assert.Equal(t, cli.ExitUsage, h.as("aman", "notification", "read", "--id", "4", "--all"))
+
+	// What "user notifications" shows is marked read but stays in the inbox.
+	assert.Equal(t, cli.ExitOK, h.as("aman", "user", "notifications"))
+	assert.Equal(t, "New post: Chaat\n", h.stdout.String())
+	assert.Equal(t, cli.ExitOK, h.as("aman", "user", "notifications"))
+	assert.Empty(t, h.stdout.String())
+	assert.Equal(t, cli.ExitOK, h.as("aman", "notification", "list", "--output", "json"))
+	assert.Contains(t, h.stdout.String(), `"message": "New post: Momos"`)
+	assert.Contains(t, h.stdout.String(), `"message": "New post: Chaat"`)
+
+	require.Equal(t, cli.ExitOK, h.as("riya", "post", "create", "--type", "food", "--title", "Misal", "--content", "Bedekar"))
+	assert.Equal(t, cli.ExitOK, h.as("aman", "notification", "read", "--all"))
+	assert.Equal(t, cli.ExitOK, h.as("aman", "notification", "list", "--unread", "--output", "json"))
+	assert.Equal(t, "[]\n", h.stdout.String())
+}
+
+func TestCLI_NotificationSettings(t *testing.T) {
+	h := newHarness(t)
+	h.cli.Output = render.Table
+	require.Equal(t, cli.ExitOK, h.as("riya", "user", "signup", "--city", "delhi", "--dwelling-age", "4"))
+	require.Equal(t, cli.ExitOK, h.as("kabir", "user", "signup", "--city", "delhi", "--dwelling-age", "2"))
+	require.Equal(t, cli.ExitOK, h.as("aman", "user", "signup", "--city", "delhi", "--dwelling-age", "1"))
+
+	assert.Equal(t, cli.ExitUsage, h.as("aman", "notification", "set"))
+	assert.Equal(t, cli.ExitError, h.as("aman", "notification", "set", "--delivery", "weekly"))
+	assert.Contains(t, h.stderr.String(), "Unknown delivery weekly, use immediate, digest, off")
+	assert.Equal(t, cli.ExitOK, h.as("aman", "notification", "set", "--posts", "subscribed"))
+	assert.Equal(t, "Delivery immediate, posts subscribed\n", h.stdout.String())
+	assert.Equal(t, cli.ExitUsage, h.as("aman", "notification", "subscribe"))
+	assert.Equal(t, cli.ExitUsage, h.as("aman", "notification", "subscribe", "--category", "food", "--author", "riya"))
+	assert.Equal(t, cli.ExitOK, h.as("aman", "notification", "subscribe", "--category", "travel"))
+	assert.Equal(t, cli.ExitOK, h.as("aman", "notification", "subscribe", "--author", "riya"))
+	assert.Equal(t, "Subscribed to riya\n", h.stdout.String())
+	assert.Equal(t, cli.ExitError, h.as("aman", "notification", "subscribe", "--author", "riya"))
+	assert.Contains(t, h.stderr.String(), "You are already subscribed to that author")
+
+	assert.Equal(t, cli.ExitOK, h.as("aman", "notification", "settings"))
+	assert.Contains(t, h.stderr.String(), "Delivery: immediate\nPosts: subscribed\n")
+	assert.Contains(t, h.stdout.String(), "author")
+	assert.Contains(t, h.stdout.String(), "travel")
+
+	// Only riya's posts and travel posts reach aman now.
+	require.Equal(t, cli.ExitOK, h.as("riya", "post", "create", "--type", "food", "--title", "Momos", "--content", "Lajpat Nagar"))
+	require.Equal(t, cli.ExitOK, h.as("kabir", "post", "create", "--type", "food", "--title", "Chaat", "--content", "CP"))
+	require.Equal(t, cli.ExitOK, h.as("kabir", "post", "create", "--type", "travel", "--title", "Metro", "--content", "Yellow line"))
+	assert.Equal(t, cli.ExitOK, h.as("aman", "user", "notifications"))
+	assert.Equal(t, "New post: Momos\nNew post: Metro\n", h.stdout.String())
+
+	assert.Equal(t, cli.ExitOK, h.as("aman", "notification", "unsubscribe", "--author", "riya"))
+	assert.Equal(t, cli.ExitError, h.as("aman", "notification", "unsubscribe", "--author", "riya"))
+	assert.Contains(t, h.stderr.String(), "You are not subscribed to that author")
+	assert.Equal(t, cli.ExitOK, h.as("aman", "notification", "set", "--delivery", "digest", "--posts", "all"))
+	require.Equal(t, cli.ExitOK, h.as("riya", "post", "create", "--type", "food", "--title", "Kulfi", "--content", "Chandni Chowk"))
+	assert.Equal(t, cli.ExitOK, h.as("aman", "user", "notifications"))
+	assert.Empty(t, h.stdout.String())
+	assert.Equal(t, cli.ExitOK, h.as("aman", "notification", "set", "--delivery", "immediate"))
+	assert.Equal(t, cli.ExitOK, h.as("aman", "user", "notifications"))
+	assert.Equal(t, "Digest of 1 notifications: New post: Kulfi\n", h.stdout.String())
+}
+
+func TestCLI_Comments(t *testing.T) {
+	h := newHarness(t)
+	h.cli.Output = render.Table
+	require.Equal(t, cli.ExitOK, h.as("riya", "user", "signup", "--city", "delhi", "--dwelling-age", "4"))
+	require.Equal(t, cli.ExitOK, h.as("aman", "user", "signup", "--city", "delhi", "--dwelling-age", "1"))
+	require.Equal(t, cli.ExitOK, h.as("riya", "post", "create", "--type", "food", "--title", "Momos", "--content", "Lajpat Nagar"))
+
+	require.Equal(t, cli.ExitOK, h.as("aman", "comment", "add", "--post", "1", "--text", "Which stall?"), h.stderr.String())
+	require.Equal(t, cli.ExitOK, h.as("riya", "comment", "reply", "--id", "1", "--text", "By the gate"), h.stderr.String())
+	require.Equal(t, cli.ExitOK, h.as("aman", "comment", "reply", "--id", "2", "--text", "Thanks"), h.stderr.String())
+	assert.Equal(t, cli.ExitUsage, h.as("aman", "comment", "add", "--post", "1"))
+	assert.Equal(t, cli.ExitError, h.as("aman", "comment", "add", "--post", "9", "--text", "Hi"))
+	assert.Contains(t, h.stderr.String(), "No Post exist with this id")
+
+	assert.Equal(t, cli.ExitOK, h.as("aman", "comment", "list", "--post", "1", "--depth", "1"))
+	out := h.stdout.String()
+	assert.Contains(t, out, "Which stall?")
+	assert.Contains(t, out, "↳ By the gate")
+	assert.NotContains(t, out, "Thanks")
+	assert.Contains(t, out, "(1 more reply)")
+	assert.Equal(t, cli.ExitOK, h.as("aman", "comment", "show", "--id", "2"))
+	assert.Contains(t, h.stdout.String(), "↳ Thanks")
+	assert.NotContains(t, h.stdout.String(), "Which stall?")
+
+	assert.Equal(t, cli.ExitError, h.as("riya", "comment", "edit", "--id", "1", "--text", "Mine now"))
+	assert.Equal(t, cli.ExitOK, h.as("aman", "comment", "edit", "--id", "1", "--text", "Which momo stall?"))
+	assert.Equal(t, cli.ExitError, h.as("riya", "comment", "delete", "--id", "1"))
+	assert.Contains(t, h.stderr.String(), "You can only delete your comment")
+	assert.Equal(t, cli.ExitOK, h.as("aman", "comment", "delete", "--id", "1"))
+	assert.Equal(t, cli.ExitOK, h.as("aman", "comment", "list", "--post", "1", "--depth", "0"))
+	assert.Contains(t, h.stdout.String(), "[deleted]")
+	assert.Contains(t, h.stdout.String(), "Thanks")
+
+	assert.Equal(t, cli.ExitAuth, h.as("aman", "admin", "comment", "delete", "--id", "3"))
+	assert.Equal(t, cli.ExitOK, h.as("admin", "admin", "comment", "delete", "--id", "3"), h.stderr.String())
+	assert.Equal(t, cli.ExitOK, h.as("admin", "admin", "audit", "list", "--target-type", "comment"))
+	assert.Contains(t, h.stdout.String(), "delete_comment")
+}
+
+func TestCLI_AdminCommands(t *testing.T) {
+	h := newHarness(t)
+	require.Equal(t, cli.ExitOK, h.as("riya", "user", "signup", "--city", "delhi", "--dwelling-age", "4"))
+	require.Equal(t, cli.ExitOK, h.as("aman", "user", "signup", "--city", "delhi", "--dwelling-age", "1"))
+
+	assert.Equal(t, cli.ExitAuth, h.session("admin", "user", "list"))
+	assert.Equal(t, cli.ExitAuth, h.as("riya", "admin", "user", "list"))
+	assert.Contains(t, h.stderr.String(), "You do not have permission")
+
+	assert.Equal(t, cli.ExitOK, h.as("admin", "admin", "user", "list"))
+	assert.Contains(t, h.stdout.String(), "riya")
+
+	assert.Equal(t, cli.ExitUsage, h.as("admin", "admin", "role", "grant", "--id", "2"))
+	assert.Equal(t, cli.ExitOK, h.as("admin", "admin", "role", "grant", "--id", "2", "--role", "moderator"), h.stderr.String())
+	assert.Equal(t, cli.ExitOK, h.as("riya", "admin", "post", "list"))
+	assert.Equal(t, cli.ExitError, h.as("riya", "admin", "user", "delete", "--id", "3"))
+	assert.Equal(t, cli.ExitOK, h.as("admin", "admin", "role", "revoke", "--id", "2"))
+	assert.Equal(t, cli.ExitAuth, h.as("riya", "admin", "post", "list"))
+
+	assert.Equal(t, cli.ExitUsage, h.as("admin", "admin", "user", "delete"))
+	assert.Equal(t, cli.ExitOK, h.as("admin", "admin", "user", "delete", "--id", "2"))
+	assert.Equal(t, cli.ExitError, h.as("admin", "admin", "user", "delete", "--id", "2"))
+	_, err := h.users.FindByUsername("riya")
+	assert.Error(t, err)
+
+	require.Equal(t, cli.ExitOK, h.as("admin", "admin", "audit", "list", "--target-type", "user", "--target", "2", "--output", "json"), h.stderr.String())
+	var entries []map[string]any
+	require.NoError(t, json.Unmarshal(h.stdout.Bytes(), &entries))
+	require.Len(t, entries, 3)
+	assert.Equal(t, "change_role", entries[0]["action"])
+	assert.Equal(t, "delete_user", entries[2]["action"])
+	assert.Equal(t, "riya", entries[2]["before"].(map[string]any)["username"])
+
+	assert.Equal(t, cli.ExitOK, h.as("admin", "admin", "audit", "list", "--actor", "2", "--from", "2000-01-01", "--to", "2000-12-31"))
+	assert.NotContains(t, h.stdout.String(), "delete_user")
+	assert.Equal(t, cli.ExitUsage, h.as("admin", "admin", "audit", "list", "--from", "yesterday"))
+}
@@ -0,0 +1,153 @@
+package migrations_test
+
+import (
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"localEyes/config"
+	"localEyes/internal/migrations"
+)
+
+func TestLoad_OrdersByVersion(t *testing.T) {
+	fsys := fstest.MapFS{
+		"mysql/0002_create_posts.up.sql":   {Data: []byte("CREATE TABLE posts (id INT);")},
+		"mysql/0002_create_posts.down.sql": {Data: []byte("DROP TABLE posts;")},
+		"mysql/0001_create_users.up.sql":   {Data: []byte("CREATE TABLE users (id INT);")},
+		"mysql/0001_create_users.down.sql": {Data: []byte("DROP TABLE users;")},
+	}
+
+	loaded, err := migrations.Load(fsys, "mysql")
+
+	assert.NoError(t, err)
+	assert.Len(t, loaded, 2)
+	assert.Equal(t, 1, loaded[0].Version)
+	assert.Equal(t, "create_users", loaded[0].Name)
+	assert.Equal(t, 2, loaded[1].Version)
+	assert.Equal(t, "DROP TABLE posts;", loaded[1].Down)
+}
+
+func TestLoad_MissingDownFile(t *testing.T) {
+	fsys := fstest.MapFS{
+		"mysql/0001_create_users.up.sql": {Data: []byte("CREATE TABLE users (id INT);")},
+	}
+
+	_, err := migrations.Load(fsys, "mysql")
+
+	assert.Error(t, err)
+}
+
+func TestSplitStatements(t *testing.T) {
+	script := `-- two statements
+CREATE TABLE a (
+    id INT
+);
+
+INSERT INTO a VALUES (1);
+`
+	statements := migrations.SplitStatements(script)
+
+	assert.Equal(t, []string{"CREATE TABLE a (\n    id INT\n)", "INSERT INTO a VALUES (1)"}, statements)
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	db, _, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	migrator, err := migrations.NewMigrator(db, config.MySQLDriver)
+
+	assert.NoError(t, err)
+	loaded := migrator.Migrations()
+	assert.NotEmpty(t, loaded)
+	for i, migration := range loaded {
+		assert.Equal(t, i+1, migration.Version)
+	}
+}
+
+func TestMigrator_Up_AppliesPending(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	migrator, err := migrations.NewMigrator(db, config.MySQLDriver)
+	assert.NoError(t, err)
+	loaded := migrator.Migrations()
+
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_version").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("^SELECT version, applied_at FROM schema_version$").
+		WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}).AddRow(1, time.Now()))
+	for _, migration := range loaded[1:] {
+		mock.ExpectBegin()
+		for range migrations.SplitStatements(migration.Up) {
+			mock.ExpectExec(".*").WillReturnResult(sqlmock.NewResult(0, 0))
+		}
+		mock.ExpectExec("INSERT INTO schema_version").
+			WithArgs(migration.Version, migration.Name, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+	}
+
+	ran, err := migrator.Up()
+
+	assert.NoError(t, err)
+	assert.Len(t, ran, len(loaded)-1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Down_RevertsLatest(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	migrator, err := migrations.NewMigrator(db, config.MySQLDriver)
+	assert.NoError(t, err)
+	loaded := migrator.Migrations()
+	latest := loaded[len(loaded)-1]
+
+	rows := sqlmock.NewRows([]string{"version", "applied_at"})
+	for _, migration := range loaded {
+		rows.AddRow(migration.Version, time.Now())
+	}
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_version").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("^SELECT version, applied_at FROM schema_version$").WillReturnRows(rows)
+	mock.ExpectBegin()
+	for range migrations.SplitStatements(latest.Down) {
+		mock.ExpectExec(".*").WillReturnResult(sqlmock.NewResult(0, 0))
+	}
+	mock.ExpectExec("^DELETE FROM schema_version WHERE version = \\?$").
+		WithArgs(latest.Version).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	reverted, err := migrator.Down(1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []migrations.Migration{latest}, reverted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Status(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	migrator, err := migrations.NewMigrator(db, config.MySQLDriver)
+	assert.NoError(t, err)
+
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_version").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("^SELECT version, applied_at FROM schema_version$").
+		WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}).AddRow(1, time.Now()))
+
+	statuses, err := migrator.Status()
+
+	assert.NoError(t, err)
+	assert.Len(t, statuses, len(migrator.Migrations()))
+	assert.True(t, statuses[0].Applied)
+	assert.False(t, statuses[len(statuses)-1].Applied)
+}
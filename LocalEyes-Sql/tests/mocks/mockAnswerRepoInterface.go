@@ -0,0 +1,193 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/interfaces/answerRepoInterface.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	models "localEyes/internal/models"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockAnswerRepository is a mock of AnswerRepository interface.
+type MockAnswerRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAnswerRepositoryMockRecorder
+}
+
+// MockAnswerRepositoryMockRecorder is the mock recorder for MockAnswerRepository.
+type MockAnswerRepositoryMockRecorder struct {
+	mock *MockAnswerRepository
+}
+
+// NewMockAnswerRepository creates a new mock instance.
+func NewMockAnswerRepository(ctrl *gomock.Controller) *MockAnswerRepository {
+	mock := &MockAnswerRepository{ctrl: ctrl}
+	mock.recorder = &MockAnswerRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAnswerRepository) EXPECT() *MockAnswerRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAnswerRepository) Create(answer *models.Answer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", answer)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAnswerRepositoryMockRecorder) Create(answer interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAnswerRepository)(nil).Create), answer)
+}
+
+// DeleteByAnswerId mocks base method.
+func (m *MockAnswerRepository) DeleteByAnswerId(AnswerId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByAnswerId", AnswerId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByAnswerId indicates an expected call of DeleteByAnswerId.
+func (mr *MockAnswerRepositoryMockRecorder) DeleteByAnswerId(AnswerId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByAnswerId", reflect.TypeOf((*MockAnswerRepository)(nil).DeleteByAnswerId), AnswerId)
+}
+
+// DeleteByAnswerIdUId mocks base method.
+func (m *MockAnswerRepository) DeleteByAnswerIdUId(AnswerId, UId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByAnswerIdUId", AnswerId, UId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByAnswerIdUId indicates an expected call of DeleteByAnswerIdUId.
+func (mr *MockAnswerRepositoryMockRecorder) DeleteByAnswerIdUId(AnswerId, UId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByAnswerIdUId", reflect.TypeOf((*MockAnswerRepository)(nil).DeleteByAnswerIdUId), AnswerId, UId)
+}
+
+// DeleteByPId mocks base method.
+func (m *MockAnswerRepository) DeleteByPId(PId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByPId", PId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByPId indicates an expected call of DeleteByPId.
+func (mr *MockAnswerRepositoryMockRecorder) DeleteByPId(PId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByPId", reflect.TypeOf((*MockAnswerRepository)(nil).DeleteByPId), PId)
+}
+
+// DeleteByQId mocks base method.
+func (m *MockAnswerRepository) DeleteByQId(QId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByQId", QId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByQId indicates an expected call of DeleteByQId.
+func (mr *MockAnswerRepositoryMockRecorder) DeleteByQId(QId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByQId", reflect.TypeOf((*MockAnswerRepository)(nil).DeleteByQId), QId)
+}
+
+// DeleteForUser mocks base method.
+func (m *MockAnswerRepository) DeleteForUser(UId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteForUser", UId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteForUser indicates an expected call of DeleteForUser.
+func (mr *MockAnswerRepositoryMockRecorder) DeleteForUser(UId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteForUser", reflect.TypeOf((*MockAnswerRepository)(nil).DeleteForUser), UId)
+}
+
+// FindByAnswerId mocks base method.
+func (m *MockAnswerRepository) FindByAnswerId(AnswerId int) (*models.Answer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByAnswerId", AnswerId)
+	ret0, _ := ret[0].(*models.Answer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByAnswerId indicates an expected call of FindByAnswerId.
+func (mr *MockAnswerRepositoryMockRecorder) FindByAnswerId(AnswerId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByAnswerId", reflect.TypeOf((*MockAnswerRepository)(nil).FindByAnswerId), AnswerId)
+}
+
+// GetAllAnswers mocks base method.
+func (m *MockAnswerRepository) GetAllAnswers() ([]*models.Answer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllAnswers")
+	ret0, _ := ret[0].([]*models.Answer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllAnswers indicates an expected call of GetAllAnswers.
+func (mr *MockAnswerRepositoryMockRecorder) GetAllAnswers() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllAnswers", reflect.TypeOf((*MockAnswerRepository)(nil).GetAllAnswers))
+}
+
+// GetAnswersByPId mocks base method.
+func (m *MockAnswerRepository) GetAnswersByPId(PId int) ([]*models.Answer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAnswersByPId", PId)
+	ret0, _ := ret[0].([]*models.Answer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAnswersByPId indicates an expected call of GetAnswersByPId.
+func (mr *MockAnswerRepositoryMockRecorder) GetAnswersByPId(PId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAnswersByPId", reflect.TypeOf((*MockAnswerRepository)(nil).GetAnswersByPId), PId)
+}
+
+// GetAnswersByQId mocks base method.
+func (m *MockAnswerRepository) GetAnswersByQId(QId int) ([]*models.Answer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAnswersByQId", QId)
+	ret0, _ := ret[0].([]*models.Answer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAnswersByQId indicates an expected call of GetAnswersByQId.
+func (mr *MockAnswerRepositoryMockRecorder) GetAnswersByQId(QId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAnswersByQId", reflect.TypeOf((*MockAnswerRepository)(nil).GetAnswersByQId), QId)
+}
+
+// UpdateUserAnswer mocks base method.
+func (m *MockAnswerRepository) UpdateUserAnswer(AnswerId, UId int, text string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserAnswer", AnswerId, UId, text)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUserAnswer indicates an expected call of UpdateUserAnswer.
+func (mr *MockAnswerRepositoryMockRecorder) UpdateUserAnswer(AnswerId, UId, text interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserAnswer", reflect.TypeOf((*MockAnswerRepository)(nil).UpdateUserAnswer), AnswerId, UId, text)
+}
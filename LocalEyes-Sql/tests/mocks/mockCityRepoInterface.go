@@ -0,0 +1,79 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/interfaces/cityRepoInterface.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	models "localEyes/internal/models"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockCityRepository is a mock of CityRepository interface.
+type MockCityRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCityRepositoryMockRecorder
+}
+
+// MockCityRepositoryMockRecorder is the mock recorder for MockCityRepository.
+type MockCityRepositoryMockRecorder struct {
+	mock *MockCityRepository
+}
+
+// NewMockCityRepository creates a new mock instance.
+func NewMockCityRepository(ctrl *gomock.Controller) *MockCityRepository {
+	mock := &MockCityRepository{ctrl: ctrl}
+	mock.recorder = &MockCityRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCityRepository) EXPECT() *MockCityRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockCityRepository) Create(city *models.City) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", city)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockCityRepositoryMockRecorder) Create(city interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCityRepository)(nil).Create), city)
+}
+
+// FindByName mocks base method.
+func (m *MockCityRepository) FindByName(name string) (*models.City, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByName", name)
+	ret0, _ := ret[0].(*models.City)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByName indicates an expected call of FindByName.
+func (mr *MockCityRepositoryMockRecorder) FindByName(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByName", reflect.TypeOf((*MockCityRepository)(nil).FindByName), name)
+}
+
+// GetAll mocks base method.
+func (m *MockCityRepository) GetAll() ([]*models.City, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll")
+	ret0, _ := ret[0].([]*models.City)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockCityRepositoryMockRecorder) GetAll() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockCityRepository)(nil).GetAll))
+}
@@ -0,0 +1,164 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/interfaces/commentRepoInterface.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	models "localEyes/internal/models"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockCommentRepository is a mock of CommentRepository interface.
+type MockCommentRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCommentRepositoryMockRecorder
+}
+
+// MockCommentRepositoryMockRecorder is the mock recorder for MockCommentRepository.
+type MockCommentRepositoryMockRecorder struct {
+	mock *MockCommentRepository
+}
+
+// NewMockCommentRepository creates a new mock instance.
+func NewMockCommentRepository(ctrl *gomock.Controller) *MockCommentRepository {
+	mock := &MockCommentRepository{ctrl: ctrl}
+	mock.recorder = &MockCommentRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCommentRepository) EXPECT() *MockCommentRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountReplies mocks base method.
+func (m *MockCommentRepository) CountReplies(CommentId int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountReplies", CommentId)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountReplies indicates an expected call of CountReplies.
+func (mr *MockCommentRepositoryMockRecorder) CountReplies(CommentId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountReplies", reflect.TypeOf((*MockCommentRepository)(nil).CountReplies), CommentId)
+}
+
+// Create mocks base method.
+func (m *MockCommentRepository) Create(comment *models.Comment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", comment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockCommentRepositoryMockRecorder) Create(comment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCommentRepository)(nil).Create), comment)
+}
+
+// DeleteByCommentId mocks base method.
+func (m *MockCommentRepository) DeleteByCommentId(CommentId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByCommentId", CommentId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByCommentId indicates an expected call of DeleteByCommentId.
+func (mr *MockCommentRepositoryMockRecorder) DeleteByCommentId(CommentId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByCommentId", reflect.TypeOf((*MockCommentRepository)(nil).DeleteByCommentId), CommentId)
+}
+
+// DeleteByPId mocks base method.
+func (m *MockCommentRepository) DeleteByPId(PId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByPId", PId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByPId indicates an expected call of DeleteByPId.
+func (mr *MockCommentRepositoryMockRecorder) DeleteByPId(PId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByPId", reflect.TypeOf((*MockCommentRepository)(nil).DeleteByPId), PId)
+}
+
+// DeleteForUser mocks base method.
+func (m *MockCommentRepository) DeleteForUser(UId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteForUser", UId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteForUser indicates an expected call of DeleteForUser.
+func (mr *MockCommentRepositoryMockRecorder) DeleteForUser(UId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteForUser", reflect.TypeOf((*MockCommentRepository)(nil).DeleteForUser), UId)
+}
+
+// FindByCommentId mocks base method.
+func (m *MockCommentRepository) FindByCommentId(CommentId int) (*models.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByCommentId", CommentId)
+	ret0, _ := ret[0].(*models.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByCommentId indicates an expected call of FindByCommentId.
+func (mr *MockCommentRepositoryMockRecorder) FindByCommentId(CommentId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByCommentId", reflect.TypeOf((*MockCommentRepository)(nil).FindByCommentId), CommentId)
+}
+
+// GetCommentsByPId mocks base method.
+func (m *MockCommentRepository) GetCommentsByPId(PId int) ([]*models.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCommentsByPId", PId)
+	ret0, _ := ret[0].([]*models.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCommentsByPId indicates an expected call of GetCommentsByPId.
+func (mr *MockCommentRepositoryMockRecorder) GetCommentsByPId(PId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCommentsByPId", reflect.TypeOf((*MockCommentRepository)(nil).GetCommentsByPId), PId)
+}
+
+// MarkDeleted mocks base method.
+func (m *MockCommentRepository) MarkDeleted(CommentId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkDeleted", CommentId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkDeleted indicates an expected call of MarkDeleted.
+func (mr *MockCommentRepositoryMockRecorder) MarkDeleted(CommentId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkDeleted", reflect.TypeOf((*MockCommentRepository)(nil).MarkDeleted), CommentId)
+}
+
+// UpdateUserComment mocks base method.
+func (m *MockCommentRepository) UpdateUserComment(CommentId, UId int, text string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserComment", CommentId, UId, text)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUserComment indicates an expected call of UpdateUserComment.
+func (mr *MockCommentRepositoryMockRecorder) UpdateUserComment(CommentId, UId, text interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserComment", reflect.TypeOf((*MockCommentRepository)(nil).UpdateUserComment), CommentId, UId, text)
+}
@@ -0,0 +1,120 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/interfaces/notificationPrefsRepoInterface.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	models "localEyes/internal/models"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockNotificationPrefsRepository is a mock of NotificationPrefsRepository interface.
+type MockNotificationPrefsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotificationPrefsRepositoryMockRecorder
+}
+
+// MockNotificationPrefsRepositoryMockRecorder is the mock recorder for MockNotificationPrefsRepository.
+type MockNotificationPrefsRepositoryMockRecorder struct {
+	mock *MockNotificationPrefsRepository
+}
+
+// NewMockNotificationPrefsRepository creates a new mock instance.
+func NewMockNotificationPrefsRepository(ctrl *gomock.Controller) *MockNotificationPrefsRepository {
+	mock := &MockNotificationPrefsRepository{ctrl: ctrl}
+	mock.recorder = &MockNotificationPrefsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotificationPrefsRepository) EXPECT() *MockNotificationPrefsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteForUser mocks base method.
+func (m *MockNotificationPrefsRepository) DeleteForUser(UId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteForUser", UId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteForUser indicates an expected call of DeleteForUser.
+func (mr *MockNotificationPrefsRepositoryMockRecorder) DeleteForUser(UId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteForUser", reflect.TypeOf((*MockNotificationPrefsRepository)(nil).DeleteForUser), UId)
+}
+
+// DeleteSubscriptionsTo mocks base method.
+func (m *MockNotificationPrefsRepository) DeleteSubscriptionsTo(kind string, targetId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSubscriptionsTo", kind, targetId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSubscriptionsTo indicates an expected call of DeleteSubscriptionsTo.
+func (mr *MockNotificationPrefsRepositoryMockRecorder) DeleteSubscriptionsTo(kind, targetId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSubscriptionsTo", reflect.TypeOf((*MockNotificationPrefsRepository)(nil).DeleteSubscriptionsTo), kind, targetId)
+}
+
+// FindByUId mocks base method.
+func (m *MockNotificationPrefsRepository) FindByUId(UId int) (*models.NotificationPrefs, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUId", UId)
+	ret0, _ := ret[0].(*models.NotificationPrefs)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByUId indicates an expected call of FindByUId.
+func (mr *MockNotificationPrefsRepositoryMockRecorder) FindByUId(UId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUId", reflect.TypeOf((*MockNotificationPrefsRepository)(nil).FindByUId), UId)
+}
+
+// Save mocks base method.
+func (m *MockNotificationPrefsRepository) Save(prefs *models.NotificationPrefs) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", prefs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockNotificationPrefsRepositoryMockRecorder) Save(prefs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockNotificationPrefsRepository)(nil).Save), prefs)
+}
+
+// Subscribe mocks base method.
+func (m *MockNotificationPrefsRepository) Subscribe(subscription *models.Subscription) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", subscription)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockNotificationPrefsRepositoryMockRecorder) Subscribe(subscription interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockNotificationPrefsRepository)(nil).Subscribe), subscription)
+}
+
+// Unsubscribe mocks base method.
+func (m *MockNotificationPrefsRepository) Unsubscribe(UId int, kind string, targetId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unsubscribe", UId, kind, targetId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unsubscribe indicates an expected call of Unsubscribe.
+func (mr *MockNotificationPrefsRepositoryMockRecorder) Unsubscribe(UId, kind, targetId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unsubscribe", reflect.TypeOf((*MockNotificationPrefsRepository)(nil).Unsubscribe), UId, kind, targetId)
+}
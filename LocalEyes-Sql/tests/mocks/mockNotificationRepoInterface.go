@@ -0,0 +1,209 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/interfaces/notificationRepoInterface.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	models "localEyes/internal/models"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockNotificationRepository is a mock of NotificationRepository interface.
+type MockNotificationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotificationRepositoryMockRecorder
+}
+
+// MockNotificationRepositoryMockRecorder is the mock recorder for MockNotificationRepository.
+type MockNotificationRepositoryMockRecorder struct {
+	mock *MockNotificationRepository
+}
+
+// NewMockNotificationRepository creates a new mock instance.
+func NewMockNotificationRepository(ctrl *gomock.Controller) *MockNotificationRepository {
+	mock := &MockNotificationRepository{ctrl: ctrl}
+	mock.recorder = &MockNotificationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotificationRepository) EXPECT() *MockNotificationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountUnread mocks base method.
+func (m *MockNotificationRepository) CountUnread(UId int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUnread", UId)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUnread indicates an expected call of CountUnread.
+func (mr *MockNotificationRepositoryMockRecorder) CountUnread(UId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUnread", reflect.TypeOf((*MockNotificationRepository)(nil).CountUnread), UId)
+}
+
+// Create mocks base method.
+func (m *MockNotificationRepository) Create(notification *models.Notification) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", notification)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockNotificationRepositoryMockRecorder) Create(notification interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockNotificationRepository)(nil).Create), notification)
+}
+
+// CreateForPost mocks base method.
+func (m *MockNotificationRepository) CreateForPost(notification *models.Notification, post *models.Post) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateForPost", notification, post)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateForPost indicates an expected call of CreateForPost.
+func (mr *MockNotificationRepositoryMockRecorder) CreateForPost(notification, post interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateForPost", reflect.TypeOf((*MockNotificationRepository)(nil).CreateForPost), notification, post)
+}
+
+// DeleteForUser mocks base method.
+func (m *MockNotificationRepository) DeleteForUser(UId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteForUser", UId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteForUser indicates an expected call of DeleteForUser.
+func (mr *MockNotificationRepositoryMockRecorder) DeleteForUser(UId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteForUser", reflect.TypeOf((*MockNotificationRepository)(nil).DeleteForUser), UId)
+}
+
+// FindByNotificationId mocks base method.
+func (m *MockNotificationRepository) FindByNotificationId(Id int) (*models.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByNotificationId", Id)
+	ret0, _ := ret[0].(*models.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByNotificationId indicates an expected call of FindByNotificationId.
+func (mr *MockNotificationRepositoryMockRecorder) FindByNotificationId(Id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByNotificationId", reflect.TypeOf((*MockNotificationRepository)(nil).FindByNotificationId), Id)
+}
+
+// GetHeld mocks base method.
+func (m *MockNotificationRepository) GetHeld(UId int) ([]*models.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHeld", UId)
+	ret0, _ := ret[0].([]*models.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHeld indicates an expected call of GetHeld.
+func (mr *MockNotificationRepositoryMockRecorder) GetHeld(UId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHeld", reflect.TypeOf((*MockNotificationRepository)(nil).GetHeld), UId)
+}
+
+// GetSince mocks base method.
+func (m *MockNotificationRepository) GetSince(afterId, limit int) ([]*models.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSince", afterId, limit)
+	ret0, _ := ret[0].([]*models.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSince indicates an expected call of GetSince.
+func (mr *MockNotificationRepositoryMockRecorder) GetSince(afterId, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSince", reflect.TypeOf((*MockNotificationRepository)(nil).GetSince), afterId, limit)
+}
+
+// GetUserNotifications mocks base method.
+func (m *MockNotificationRepository) GetUserNotifications(UId int, unreadOnly bool, order string, limit, offset int) ([]*models.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserNotifications", UId, unreadOnly, order, limit, offset)
+	ret0, _ := ret[0].([]*models.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserNotifications indicates an expected call of GetUserNotifications.
+func (mr *MockNotificationRepositoryMockRecorder) GetUserNotifications(UId, unreadOnly, order, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserNotifications", reflect.TypeOf((*MockNotificationRepository)(nil).GetUserNotifications), UId, unreadOnly, order, limit, offset)
+}
+
+// LastId mocks base method.
+func (m *MockNotificationRepository) LastId() (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LastId")
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LastId indicates an expected call of LastId.
+func (mr *MockNotificationRepositoryMockRecorder) LastId() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LastId", reflect.TypeOf((*MockNotificationRepository)(nil).LastId))
+}
+
+// MarkAllRead mocks base method.
+func (m *MockNotificationRepository) MarkAllRead(UId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkAllRead", UId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkAllRead indicates an expected call of MarkAllRead.
+func (mr *MockNotificationRepositoryMockRecorder) MarkAllRead(UId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAllRead", reflect.TypeOf((*MockNotificationRepository)(nil).MarkAllRead), UId)
+}
+
+// MarkRead mocks base method.
+func (m *MockNotificationRepository) MarkRead(UId int, Ids []int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRead", UId, Ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkRead indicates an expected call of MarkRead.
+func (mr *MockNotificationRepositoryMockRecorder) MarkRead(UId, Ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRead", reflect.TypeOf((*MockNotificationRepository)(nil).MarkRead), UId, Ids)
+}
+
+// Release mocks base method.
+func (m *MockNotificationRepository) Release(UId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", UId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockNotificationRepositoryMockRecorder) Release(UId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockNotificationRepository)(nil).Release), UId)
+}
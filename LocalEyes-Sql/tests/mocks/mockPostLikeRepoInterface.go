@@ -0,0 +1,105 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/interfaces/postLikeRepoInterface.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockPostLikeRepository is a mock of PostLikeRepository interface.
+type MockPostLikeRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPostLikeRepositoryMockRecorder
+}
+
+// MockPostLikeRepositoryMockRecorder is the mock recorder for MockPostLikeRepository.
+type MockPostLikeRepositoryMockRecorder struct {
+	mock *MockPostLikeRepository
+}
+
+// NewMockPostLikeRepository creates a new mock instance.
+func NewMockPostLikeRepository(ctrl *gomock.Controller) *MockPostLikeRepository {
+	mock := &MockPostLikeRepository{ctrl: ctrl}
+	mock.recorder = &MockPostLikeRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPostLikeRepository) EXPECT() *MockPostLikeRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockPostLikeRepository) Create(UId, PId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", UId, PId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPostLikeRepositoryMockRecorder) Create(UId, PId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPostLikeRepository)(nil).Create), UId, PId)
+}
+
+// Delete mocks base method.
+func (m *MockPostLikeRepository) Delete(UId, PId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", UId, PId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockPostLikeRepositoryMockRecorder) Delete(UId, PId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockPostLikeRepository)(nil).Delete), UId, PId)
+}
+
+// DeleteByPId mocks base method.
+func (m *MockPostLikeRepository) DeleteByPId(PId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByPId", PId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByPId indicates an expected call of DeleteByPId.
+func (mr *MockPostLikeRepositoryMockRecorder) DeleteByPId(PId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByPId", reflect.TypeOf((*MockPostLikeRepository)(nil).DeleteByPId), PId)
+}
+
+// DeleteForUser mocks base method.
+func (m *MockPostLikeRepository) DeleteForUser(UId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteForUser", UId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteForUser indicates an expected call of DeleteForUser.
+func (mr *MockPostLikeRepositoryMockRecorder) DeleteForUser(UId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteForUser", reflect.TypeOf((*MockPostLikeRepository)(nil).DeleteForUser), UId)
+}
+
+// GetLikedPIds mocks base method.
+func (m *MockPostLikeRepository) GetLikedPIds(UId int) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLikedPIds", UId)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLikedPIds indicates an expected call of GetLikedPIds.
+func (mr *MockPostLikeRepositoryMockRecorder) GetLikedPIds(UId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLikedPIds", reflect.TypeOf((*MockPostLikeRepository)(nil).GetLikedPIds), UId)
+}
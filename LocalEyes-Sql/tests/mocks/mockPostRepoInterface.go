@@ -150,6 +150,79 @@ func (mr *MockPostRepositoryMockRecorder) GetPostsByUId(UId interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPostsByUId", reflect.TypeOf((*MockPostRepository)(nil).GetPostsByUId), UId)
 }
 
+// ListPosts mocks base method.
+func (m *MockPostRepository) ListPosts(filter models.PostFilter, order string, limit, offset int) ([]*models.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPosts", filter, order, limit, offset)
+	ret0, _ := ret[0].([]*models.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPosts indicates an expected call of ListPosts.
+func (mr *MockPostRepositoryMockRecorder) ListPosts(filter, order, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPosts", reflect.TypeOf((*MockPostRepository)(nil).ListPosts), filter, order, limit, offset)
+}
+
+// PostsInArea mocks base method.
+func (m *MockPostRepository) PostsInArea(area models.Area) ([]*models.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostsInArea", area)
+	ret0, _ := ret[0].([]*models.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PostsInArea indicates an expected call of PostsInArea.
+func (mr *MockPostRepositoryMockRecorder) PostsInArea(area interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostsInArea", reflect.TypeOf((*MockPostRepository)(nil).PostsInArea), area)
+}
+
+// RemoveLike mocks base method.
+func (m *MockPostRepository) RemoveLike(PId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveLike", PId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveLike indicates an expected call of RemoveLike.
+func (mr *MockPostRepositoryMockRecorder) RemoveLike(PId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveLike", reflect.TypeOf((*MockPostRepository)(nil).RemoveLike), PId)
+}
+
+// RemoveUserLikes mocks base method.
+func (m *MockPostRepository) RemoveUserLikes(UId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveUserLikes", UId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveUserLikes indicates an expected call of RemoveUserLikes.
+func (mr *MockPostRepositoryMockRecorder) RemoveUserLikes(UId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveUserLikes", reflect.TypeOf((*MockPostRepository)(nil).RemoveUserLikes), UId)
+}
+
+// SearchPosts mocks base method.
+func (m *MockPostRepository) SearchPosts(city, search string, limit, offset int) ([]*models.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchPosts", city, search, limit, offset)
+	ret0, _ := ret[0].([]*models.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchPosts indicates an expected call of SearchPosts.
+func (mr *MockPostRepositoryMockRecorder) SearchPosts(city, search, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchPosts", reflect.TypeOf((*MockPostRepository)(nil).SearchPosts), city, search, limit, offset)
+}
+
 // UpdateLike mocks base method.
 func (m *MockPostRepository) UpdateLike(PId int) error {
 	m.ctrl.T.Helper()
@@ -164,6 +237,20 @@ func (mr *MockPostRepositoryMockRecorder) UpdateLike(PId interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLike", reflect.TypeOf((*MockPostRepository)(nil).UpdateLike), PId)
 }
 
+// UpdateType mocks base method.
+func (m *MockPostRepository) UpdateType(from, to string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateType", from, to)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateType indicates an expected call of UpdateType.
+func (mr *MockPostRepositoryMockRecorder) UpdateType(from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateType", reflect.TypeOf((*MockPostRepository)(nil).UpdateType), from, to)
+}
+
 // UpdateUserPost mocks base method.
 func (m *MockPostRepository) UpdateUserPost(PId, UId int, title, content string) error {
 	m.ctrl.T.Helper()
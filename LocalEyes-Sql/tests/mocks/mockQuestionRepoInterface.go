@@ -62,6 +62,20 @@ func (mr *MockQuestionRepositoryMockRecorder) DeleteByPId(PId interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByPId", reflect.TypeOf((*MockQuestionRepository)(nil).DeleteByPId), PId)
 }
 
+// DeleteByPostOwner mocks base method.
+func (m *MockQuestionRepository) DeleteByPostOwner(UId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByPostOwner", UId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByPostOwner indicates an expected call of DeleteByPostOwner.
+func (mr *MockQuestionRepositoryMockRecorder) DeleteByPostOwner(UId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByPostOwner", reflect.TypeOf((*MockQuestionRepository)(nil).DeleteByPostOwner), UId)
+}
+
 // DeleteByQId mocks base method.
 func (m *MockQuestionRepository) DeleteByQId(QId int) error {
 	m.ctrl.T.Helper()
@@ -90,6 +104,35 @@ func (mr *MockQuestionRepositoryMockRecorder) DeleteByQIdUId(QId, UId interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByQIdUId", reflect.TypeOf((*MockQuestionRepository)(nil).DeleteByQIdUId), QId, UId)
 }
 
+// DeleteByUId mocks base method.
+func (m *MockQuestionRepository) DeleteByUId(UId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByUId", UId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByUId indicates an expected call of DeleteByUId.
+func (mr *MockQuestionRepositoryMockRecorder) DeleteByUId(UId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByUId", reflect.TypeOf((*MockQuestionRepository)(nil).DeleteByUId), UId)
+}
+
+// FindByQId mocks base method.
+func (m *MockQuestionRepository) FindByQId(QId int) (*models.Question, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByQId", QId)
+	ret0, _ := ret[0].(*models.Question)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByQId indicates an expected call of FindByQId.
+func (mr *MockQuestionRepositoryMockRecorder) FindByQId(QId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByQId", reflect.TypeOf((*MockQuestionRepository)(nil).FindByQId), QId)
+}
+
 // GetAllQuestions mocks base method.
 func (m *MockQuestionRepository) GetAllQuestions() ([]*models.Question, error) {
 	m.ctrl.T.Helper()
@@ -120,16 +163,32 @@ func (mr *MockQuestionRepositoryMockRecorder) GetQuestionsByPId(PId interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQuestionsByPId", reflect.TypeOf((*MockQuestionRepository)(nil).GetQuestionsByPId), PId)
 }
 
-// UpdateQuestion mocks base method.
-func (m *MockQuestionRepository) UpdateQuestion(QId int, answer string) error {
+// ListQuestions mocks base method.
+func (m *MockQuestionRepository) ListQuestions(city, order string, limit, offset int) ([]*models.Question, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateQuestion", QId, answer)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "ListQuestions", city, order, limit, offset)
+	ret0, _ := ret[0].([]*models.Question)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListQuestions indicates an expected call of ListQuestions.
+func (mr *MockQuestionRepositoryMockRecorder) ListQuestions(city, order, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListQuestions", reflect.TypeOf((*MockQuestionRepository)(nil).ListQuestions), city, order, limit, offset)
+}
+
+// SearchQuestions mocks base method.
+func (m *MockQuestionRepository) SearchQuestions(search string, limit, offset int) ([]*models.Question, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchQuestions", search, limit, offset)
+	ret0, _ := ret[0].([]*models.Question)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// UpdateQuestion indicates an expected call of UpdateQuestion.
-func (mr *MockQuestionRepositoryMockRecorder) UpdateQuestion(QId, answer interface{}) *gomock.Call {
+// SearchQuestions indicates an expected call of SearchQuestions.
+func (mr *MockQuestionRepositoryMockRecorder) SearchQuestions(search, limit, offset interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateQuestion", reflect.TypeOf((*MockQuestionRepository)(nil).UpdateQuestion), QId, answer)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchQuestions", reflect.TypeOf((*MockQuestionRepository)(nil).SearchQuestions), search, limit, offset)
 }
@@ -34,18 +34,19 @@ func (m *MockUserRepository) EXPECT() *MockUserRepositoryMockRecorder {
 	return m.recorder
 }
 
-// ClearNotification mocks base method.
-func (m *MockUserRepository) ClearNotification(UId int) error {
+// CountByRole mocks base method.
+func (m *MockUserRepository) CountByRole(role string) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ClearNotification", UId)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "CountByRole", role)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// ClearNotification indicates an expected call of ClearNotification.
-func (mr *MockUserRepositoryMockRecorder) ClearNotification(UId interface{}) *gomock.Call {
+// CountByRole indicates an expected call of CountByRole.
+func (mr *MockUserRepositoryMockRecorder) CountByRole(role interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearNotification", reflect.TypeOf((*MockUserRepository)(nil).ClearNotification), UId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountByRole", reflect.TypeOf((*MockUserRepository)(nil).CountByRole), role)
 }
 
 // Create mocks base method.
@@ -76,21 +77,6 @@ func (mr *MockUserRepositoryMockRecorder) DeleteByUId(UId interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByUId", reflect.TypeOf((*MockUserRepository)(nil).DeleteByUId), UId)
 }
 
-// FindAdminByUsernamePassword mocks base method.
-func (m *MockUserRepository) FindAdminByUsernamePassword(username, password string) (*models.Admin, error) {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindAdminByUsernamePassword", username, password)
-	ret0, _ := ret[0].(*models.Admin)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
-}
-
-// FindAdminByUsernamePassword indicates an expected call of FindAdminByUsernamePassword.
-func (mr *MockUserRepositoryMockRecorder) FindAdminByUsernamePassword(username, password interface{}) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAdminByUsernamePassword", reflect.TypeOf((*MockUserRepository)(nil).FindAdminByUsernamePassword), username, password)
-}
-
 // FindByUId mocks base method.
 func (m *MockUserRepository) FindByUId(UId int) (*models.User, error) {
 	m.ctrl.T.Helper()
@@ -121,21 +107,6 @@ func (mr *MockUserRepositoryMockRecorder) FindByUsername(username interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUsername", reflect.TypeOf((*MockUserRepository)(nil).FindByUsername), username)
 }
 
-// FindByUsernamePassword mocks base method.
-func (m *MockUserRepository) FindByUsernamePassword(username, password string) (*models.User, error) {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindByUsernamePassword", username, password)
-	ret0, _ := ret[0].(*models.User)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
-}
-
-// FindByUsernamePassword indicates an expected call of FindByUsernamePassword.
-func (mr *MockUserRepositoryMockRecorder) FindByUsernamePassword(username, password interface{}) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUsernamePassword", reflect.TypeOf((*MockUserRepository)(nil).FindByUsernamePassword), username, password)
-}
-
 // GetAllUsers mocks base method.
 func (m *MockUserRepository) GetAllUsers() ([]*models.User, error) {
 	m.ctrl.T.Helper()
@@ -151,18 +122,19 @@ func (mr *MockUserRepositoryMockRecorder) GetAllUsers() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllUsers", reflect.TypeOf((*MockUserRepository)(nil).GetAllUsers))
 }
 
-// PushNotification mocks base method.
-func (m *MockUserRepository) PushNotification(UId int, title string) error {
+// ListUsers mocks base method.
+func (m *MockUserRepository) ListUsers(order string, limit, offset int) ([]*models.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "PushNotification", UId, title)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "ListUsers", order, limit, offset)
+	ret0, _ := ret[0].([]*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// PushNotification indicates an expected call of PushNotification.
-func (mr *MockUserRepositoryMockRecorder) PushNotification(UId, title interface{}) *gomock.Call {
+// ListUsers indicates an expected call of ListUsers.
+func (mr *MockUserRepositoryMockRecorder) ListUsers(order, limit, offset interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PushNotification", reflect.TypeOf((*MockUserRepository)(nil).PushNotification), UId, title)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsers", reflect.TypeOf((*MockUserRepository)(nil).ListUsers), order, limit, offset)
 }
 
 // UpdateActiveStatus mocks base method.
@@ -178,3 +150,45 @@ func (mr *MockUserRepositoryMockRecorder) UpdateActiveStatus(UId, status interfa
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateActiveStatus", reflect.TypeOf((*MockUserRepository)(nil).UpdateActiveStatus), UId, status)
 }
+
+// UpdateCity mocks base method.
+func (m *MockUserRepository) UpdateCity(UId int, city string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCity", UId, city)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateCity indicates an expected call of UpdateCity.
+func (mr *MockUserRepositoryMockRecorder) UpdateCity(UId, city interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCity", reflect.TypeOf((*MockUserRepository)(nil).UpdateCity), UId, city)
+}
+
+// UpdatePassword mocks base method.
+func (m *MockUserRepository) UpdatePassword(UId int, password string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePassword", UId, password)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePassword indicates an expected call of UpdatePassword.
+func (mr *MockUserRepositoryMockRecorder) UpdatePassword(UId, password interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePassword", reflect.TypeOf((*MockUserRepository)(nil).UpdatePassword), UId, password)
+}
+
+// UpdateRole mocks base method.
+func (m *MockUserRepository) UpdateRole(UId int, role string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRole", UId, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRole indicates an expected call of UpdateRole.
+func (mr *MockUserRepositoryMockRecorder) UpdateRole(UId, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRole", reflect.TypeOf((*MockUserRepository)(nil).UpdateRole), UId, role)
+}
@@ -0,0 +1,196 @@
+package render_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"localEyes/internal/models"
+	"localEyes/internal/render"
+)
+
+var createdAt = time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)
+
+func samplePosts() []*models.Post {
+	return []*models.Post{
+		{PostId: 1, UId: 2, Title: "Momos", Type: "food", Content: "Lajpat Nagar, near gate 2", Likes: 3, LikedByMe: true, CreatedAt: createdAt},
+		{PostId: 2, UId: 3, Title: `The "best" chai`, Type: "food", Content: "Line one\nline two", CreatedAt: createdAt},
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, name := range []string{"table", "json", "ndjson", "csv", "JSON"} {
+		format, err := render.ParseFormat(name)
+		assert.NoError(t, err)
+		assert.Equal(t, render.Format(strings.ToLower(name)), format)
+	}
+	_, err := render.ParseFormat("xml")
+	assert.Error(t, err)
+	_, err = render.ParseFormat("")
+	assert.Error(t, err)
+}
+
+func TestRender_Table(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, render.New(render.Table).Render(&out, render.Posts(samplePosts())))
+
+	assert.Contains(t, out.String(), "POSTID")
+	assert.Contains(t, out.String(), "Momos")
+	assert.Contains(t, out.String(), "2024-05-01 10:30:00")
+}
+
+func TestRender_JSON(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, render.New(render.JSON).Render(&out, render.Posts(samplePosts())))
+
+	var posts []map[string]any
+	require.NoError(t, json.Unmarshal(out.Bytes(), &posts))
+	require.Len(t, posts, 2)
+	assert.Equal(t, float64(1), posts[0]["id"])
+	assert.Equal(t, "Momos", posts[0]["title"])
+	assert.Equal(t, true, posts[0]["liked_by_me"])
+	assert.Equal(t, "2024-05-01T10:30:00Z", posts[0]["created_at"])
+}
+
+func TestRender_JSONEmptyListing(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, render.New(render.JSON).Render(&out, render.Posts(nil)))
+	assert.Equal(t, "[]\n", out.String())
+
+	out.Reset()
+	require.NoError(t, render.New(render.NDJSON).Render(&out, render.Posts(nil)))
+	assert.Empty(t, out.String())
+}
+
+func TestRender_NDJSON(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, render.New(render.NDJSON).Render(&out, render.Posts(samplePosts())))
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	require.Len(t, lines, 2)
+	var post map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &post))
+	assert.Equal(t, `The "best" chai`, post["title"])
+	assert.Equal(t, "Line one\nline two", post["content"])
+}
+
+func TestRender_CSV(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, render.New(render.CSV).Render(&out, render.Posts(samplePosts())))
+
+	records, err := csv.NewReader(&out).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, []string{"PostId", "Title", "Type", "Content", "Location", "Likes", "Liked By Me", "Created At"}, records[0])
+	assert.Equal(t, []string{"1", "Momos", "food", "Lajpat Nagar, near gate 2", "", "3", "Yes", "2024-05-01 10:30:00"}, records[1])
+	assert.Equal(t, `The "best" chai`, records[2][1])
+	assert.Equal(t, "Line one\nline two", records[2][3])
+}
+
+func TestRender_UsersLeaveOutPasswords(t *testing.T) {
+	users := []*models.User{{UId: 1, Username: "riya", Password: "secret-hash", City: "delhi", DwellingAge: 4, IsActive: true, Tag: "Resident"}}
+
+	for _, format := range render.Formats {
+		var out bytes.Buffer
+		require.NoError(t, render.New(format).Render(&out, render.Users(users)))
+		assert.Contains(t, out.String(), "riya", format)
+		assert.NotContains(t, out.String(), "secret-hash", format)
+	}
+}
+
+func TestRender_QuestionsNestAnswers(t *testing.T) {
+	questions := []*models.Question{{
+		QId: 7, PostId: 1, UserId: 3, Text: "Open on Sunday?", CreatedAt: createdAt,
+		Answers: []*models.Answer{
+			{AnswerId: 1, QId: 7, UserId: 2, Username: "riya", Text: "Yes", CreatedAt: createdAt, UpdatedAt: createdAt.Add(time.Hour)},
+			{AnswerId: 2, QId: 7, Text: "Till 10", CreatedAt: createdAt},
+		},
+	}}
+
+	var out bytes.Buffer
+	require.NoError(t, render.New(render.JSON).Render(&out, render.Questions(questions)))
+	var decoded []struct {
+		Id      int `json:"id"`
+		Answers []struct {
+			Id        int     `json:"id"`
+			Username  string  `json:"username"`
+			Text      string  `json:"text"`
+			UpdatedAt *string `json:"updated_at"`
+		} `json:"answers"`
+	}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+	require.Len(t, decoded, 1)
+	require.Len(t, decoded[0].Answers, 2)
+	assert.Equal(t, "riya", decoded[0].Answers[0].Username)
+	assert.NotNil(t, decoded[0].Answers[0].UpdatedAt)
+	assert.Nil(t, decoded[0].Answers[1].UpdatedAt)
+
+	out.Reset()
+	require.NoError(t, render.New(render.CSV).Render(&out, render.Questions(questions)))
+	records, err := csv.NewReader(&out).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, "#1 riya (2024-05-01 10:30): Yes (edited)\n#2 anonymous (2024-05-01 10:30): Till 10", records[1][2])
+}
+
+func TestRender_CommentsFoldDeepReplies(t *testing.T) {
+	deepest := &models.Comment{CommentId: 4, PostId: 1, ParentId: 3, Username: "riya", Text: "Agreed", CreatedAt: createdAt}
+	deep := &models.Comment{CommentId: 3, PostId: 1, ParentId: 2, Username: "dev", Text: "Go early", CreatedAt: createdAt,
+		Replies: []*models.Comment{deepest}}
+	reply := &models.Comment{CommentId: 2, PostId: 1, ParentId: 1, Username: "dev", Text: "By the gate", CreatedAt: createdAt,
+		UpdatedAt: createdAt.Add(time.Hour), Replies: []*models.Comment{deep}}
+	threads := []*models.Comment{{CommentId: 1, PostId: 1, Deleted: true, CreatedAt: createdAt, Replies: []*models.Comment{reply}}}
+
+	var out bytes.Buffer
+	require.NoError(t, render.New(render.CSV).Render(&out, render.Comments(threads, 1)))
+	records, err := csv.NewReader(&out).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 4)
+	assert.Equal(t, []string{"1", "", "", "[deleted]"}, records[1][:4])
+	assert.Equal(t, "↳ By the gate", records[2][3])
+	assert.Equal(t, "2024-05-01 11:30:00", records[2][5])
+	assert.Equal(t, []string{"", "2", "", "  ↳ (2 more replies)"}, records[3][:4])
+
+	out.Reset()
+	require.NoError(t, render.New(render.JSON).Render(&out, render.Comments(threads, 0)))
+	var decoded []struct {
+		Deleted bool `json:"deleted"`
+		Replies []struct {
+			Id      int `json:"id"`
+			Replies []struct {
+				Replies []struct {
+					Text string `json:"text"`
+				} `json:"replies"`
+			} `json:"replies"`
+		} `json:"replies"`
+	}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+	require.Len(t, decoded, 1)
+	assert.True(t, decoded[0].Deleted)
+	assert.Equal(t, "Agreed", decoded[0].Replies[0].Replies[0].Replies[0].Text)
+}
+
+func TestRender_NotificationsLinkTheirSource(t *testing.T) {
+	notifications := []*models.Notification{
+		{Id: 1, Type: models.NotifyNewPost, SourceType: models.TargetPost, SourceId: 3, Message: "New post: Momos", CreatedAt: createdAt},
+		{Id: 2, Type: models.NotifyAdminAction, Message: "An admin reactivated your account", IsRead: true, CreatedAt: createdAt},
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, render.New(render.CSV).Render(&out, render.Notifications(notifications)))
+	records, err := csv.NewReader(&out).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, []string{"1", "new_post", "post #3", "New post: Momos", "No"}, records[1][:5])
+	assert.Equal(t, []string{"2", "admin_action", "", "An admin reactivated your account", "Yes"}, records[2][:5])
+
+	out.Reset()
+	require.NoError(t, render.New(render.NDJSON).Render(&out, render.Notifications(notifications[1:])))
+	assert.NotContains(t, out.String(), "source_")
+	assert.Contains(t, out.String(), `"read":true`)
+}
@@ -0,0 +1,129 @@
+package repositories_test
+
+import (
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"localEyes/config"
+	"localEyes/internal/models"
+	"localEyes/internal/repositories"
+	"testing"
+	"time"
+)
+
+func TestMySQLAnswerRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repositories.NewMySQLAnswerRepository(db)
+	answer := &models.Answer{QId: 1, UserId: 2, Text: "Try the corner stall", CreatedAt: time.Now()}
+
+	mock.ExpectExec(`INSERT INTO answers \(q_id, user_id, text, created_at\) SELECT q_id, \?, \?, \? FROM questions WHERE q_id = \?`).
+		WithArgs(answer.UserId, answer.Text, answer.CreatedAt, answer.QId).
+		WillReturnResult(sqlmock.NewResult(5, 1))
+
+	err = repo.Create(answer)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 5, answer.AnswerId)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMySQLAnswerRepository_Create_NoQuestion(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repositories.NewMySQLAnswerRepository(db)
+
+	mock.ExpectExec("INSERT INTO answers").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.Create(&models.Answer{QId: 9, UserId: 2, Text: "Lost", CreatedAt: time.Now()})
+
+	assert.EqualError(t, err, config.Red+"No Question exist with this id"+config.Reset)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMySQLAnswerRepository_GetAnswersByQId(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repositories.NewMySQLAnswerRepository(db)
+	createdAt := time.Date(2024, 9, 12, 10, 0, 0, 0, time.UTC)
+
+	rows := sqlmock.NewRows([]string{"answer_id", "q_id", "user_id", "username", "text", "created_at", "updated_at"}).
+		AddRow(1, 1, 2, "riya", "Winter", createdAt, nil).
+		AddRow(2, 1, 0, "", "Evenings", createdAt, createdAt)
+	mock.ExpectQuery(`SELECT a.answer_id, .* FROM answers a LEFT JOIN users u ON u.id = a.user_id WHERE a.q_id = \? ORDER BY a.answer_id`).
+		WithArgs(1).
+		WillReturnRows(rows)
+
+	answers, err := repo.GetAnswersByQId(1)
+
+	assert.NoError(t, err)
+	assert.Len(t, answers, 2)
+	assert.Equal(t, "riya", answers[0].Username)
+	assert.True(t, answers[0].CreatedAt.Equal(createdAt))
+	assert.True(t, answers[0].UpdatedAt.IsZero())
+	assert.Equal(t, "", answers[1].Username)
+	assert.False(t, answers[1].UpdatedAt.IsZero())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMySQLAnswerRepository_UpdateUserAnswer_NotOwner(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repositories.NewMySQLAnswerRepository(db)
+
+	mock.ExpectExec(`UPDATE answers SET text = \?, updated_at = \? WHERE answer_id = \? AND user_id = \?`).
+		WithArgs("edited", sqlmock.AnyArg(), 1, 3).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.UpdateUserAnswer(1, 3, "edited")
+
+	assert.EqualError(t, err, config.Red+"You can only update your answer"+config.Reset)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMySQLAnswerRepository_DeleteByAnswerId(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repositories.NewMySQLAnswerRepository(db)
+
+	mock.ExpectExec(`DELETE FROM answers WHERE answer_id = \?`).
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM answers WHERE answer_id = \?`).
+		WithArgs(2).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`DELETE FROM answers WHERE answer_id = \? AND user_id = \?`).
+		WithArgs(3, 4).
+		WillReturnError(errors.New("delete error"))
+
+	assert.NoError(t, repo.DeleteByAnswerId(1))
+	assert.EqualError(t, repo.DeleteByAnswerId(2), config.Red+"No Answer exist with this id"+config.Reset)
+	assert.EqualError(t, repo.DeleteByAnswerIdUId(3, 4), "delete error")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMySQLAnswerRepository_DeleteForUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repositories.NewMySQLAnswerRepository(db)
+
+	mock.ExpectExec(`DELETE FROM answers WHERE user_id = \? OR q_id IN \(SELECT q_id FROM questions WHERE user_id = \? OR post_id IN \(SELECT post_id FROM posts WHERE user_id = \?\)\)`).
+		WithArgs(1, 1, 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.NoError(t, repo.DeleteForUser(1))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,59 @@
+package repositories_test
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"localEyes/internal/models"
+	"localEyes/internal/repositories"
+	"testing"
+	"time"
+)
+
+func TestMySQLAuditRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repositories.NewMySQLAuditRepository(db)
+	entry := &models.AuditEntry{ActorId: 1, ActorName: "admin", Action: models.AuditDeletePost, TargetType: models.TargetPost,
+		TargetId: 4, Before: `{"id":4}`, CreatedAt: time.Unix(1700000000, 0)}
+
+	mock.ExpectExec(`INSERT INTO audit_log \(actor_id, actor_name, action, target_type, target_id, before_state, after_state, created_at\)`).
+		WithArgs(1, "admin", models.AuditDeletePost, models.TargetPost, 4, `{"id":4}`, "", int64(1700000000)).
+		WillReturnResult(sqlmock.NewResult(7, 1))
+
+	err = repo.Create(entry)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 7, entry.Id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMySQLAuditRepository_Find(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repositories.NewMySQLAuditRepository(db)
+	from, to := time.Unix(1700000000, 0), time.Unix(1700086400, 0)
+
+	rows := sqlmock.NewRows([]string{"id", "actor_id", "actor_name", "action", "target_type", "target_id", "before_state", "after_state", "created_at"}).
+		AddRow(1, 1, "admin", models.AuditDeleteUser, models.TargetUser, 2, `{"id":2}`, "", int64(1700000100))
+	mock.ExpectQuery(`SELECT id, actor_id, .* FROM audit_log WHERE actor_id = \? AND target_type = \? AND target_id = \? AND created_at >= \? AND created_at <= \? ORDER BY id`).
+		WithArgs(1, models.TargetUser, 2, from.Unix(), to.Unix()).
+		WillReturnRows(rows)
+
+	entries, err := repo.Find(models.AuditFilter{ActorId: 1, TargetType: models.TargetUser, TargetId: 2, From: from, To: to})
+
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "admin", entries[0].ActorName)
+	assert.Equal(t, int64(1700000100), entries[0].CreatedAt.Unix())
+
+	mock.ExpectQuery(`SELECT id, actor_id, .* FROM audit_log ORDER BY id`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	entries, err = repo.Find(models.AuditFilter{})
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
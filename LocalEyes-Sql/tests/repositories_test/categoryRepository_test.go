@@ -0,0 +1,87 @@
+package repositories_test
+
+import (
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"localEyes/internal/models"
+	"localEyes/internal/repositories"
+	"testing"
+)
+
+func TestMySQLCategoryRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repositories.NewMySQLCategoryRepository(db)
+	category := &models.Category{Name: "healthcare", Description: "Clinics and pharmacies", IsActive: true}
+
+	mock.ExpectExec(`INSERT INTO categories \(name, description, is_active\) VALUES \(\?, \?, \?\)`).
+		WithArgs("healthcare", "Clinics and pharmacies", true).
+		WillReturnResult(sqlmock.NewResult(5, 1))
+
+	err = repo.Create(category)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 5, category.Id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMySQLCategoryRepository_FindByName(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repositories.NewMySQLCategoryRepository(db)
+
+	rows := sqlmock.NewRows([]string{"id", "name", "description", "is_active"}).
+		AddRow(1, "food", "Places to eat", true)
+	mock.ExpectQuery(`SELECT id, name, description, is_active FROM categories WHERE name = \?`).
+		WithArgs("food").
+		WillReturnRows(rows)
+
+	category, err := repo.FindByName("food")
+
+	assert.NoError(t, err)
+	assert.Equal(t, &models.Category{Id: 1, Name: "food", Description: "Places to eat", IsActive: true}, category)
+
+	mock.ExpectQuery("SELECT id, name").WithArgs("sports").WillReturnError(sql.ErrNoRows)
+	_, err = repo.FindByName("sports")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMySQLCategoryRepository_UpdateAndDelete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repositories.NewMySQLCategoryRepository(db)
+
+	mock.ExpectExec(`UPDATE categories SET name = \?, description = \?, is_active = \? WHERE id = \?`).
+		WithArgs("events", "Fairs and concerts", false, 5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.Update(&models.Category{Id: 5, Name: "events", Description: "Fairs and concerts"}))
+
+	mock.ExpectExec(`DELETE FROM categories WHERE id = \?`).
+		WithArgs(9).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorContains(t, repo.DeleteById(9), "No category exist with this id")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMySQLPostRepository_UpdateType(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repositories.NewMySQLPostRepository(db)
+
+	mock.ExpectExec(`UPDATE posts SET type = \? WHERE type = \?`).
+		WithArgs("events", "fairs").
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	assert.NoError(t, repo.UpdateType("fairs", "events"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,69 @@
+package repositories_test
+
+import (
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"localEyes/internal/models"
+	"localEyes/internal/repositories"
+	"testing"
+)
+
+func TestMySQLCityRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repositories.NewMySQLCityRepository(db)
+	city := &models.City{Name: "pune"}
+
+	mock.ExpectExec(`INSERT INTO cities \(name\) VALUES \(\?\)`).
+		WithArgs("pune").
+		WillReturnResult(sqlmock.NewResult(2, 1))
+
+	err = repo.Create(city)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, city.Id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMySQLCityRepository_FindByName(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repositories.NewMySQLCityRepository(db)
+
+	rows := sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "delhi")
+	mock.ExpectQuery(`SELECT id, name FROM cities WHERE name = \?`).
+		WithArgs("delhi").
+		WillReturnRows(rows)
+
+	city, err := repo.FindByName("delhi")
+
+	assert.NoError(t, err)
+	assert.Equal(t, &models.City{Id: 1, Name: "delhi"}, city)
+
+	mock.ExpectQuery("SELECT id, name").WithArgs("goa").WillReturnError(sql.ErrNoRows)
+	_, err = repo.FindByName("goa")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMySQLCityRepository_GetAll(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repositories.NewMySQLCityRepository(db)
+
+	rows := sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "delhi").AddRow(2, "pune")
+	mock.ExpectQuery(`^SELECT id, name FROM cities ORDER BY name$`).WillReturnRows(rows)
+
+	cities, err := repo.GetAll()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []*models.City{{Id: 1, Name: "delhi"}, {Id: 2, Name: "pune"}}, cities)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
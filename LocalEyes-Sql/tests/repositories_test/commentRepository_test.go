@@ -0,0 +1,138 @@
+package repositories_test
+
+import (
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"localEyes/config"
+	"localEyes/internal/models"
+	"localEyes/internal/repositories"
+	"testing"
+	"time"
+)
+
+var commentRowColumns = []string{"comment_id", "post_id", "parent_id", "user_id", "username", "text", "created_at", "updated_at", "deleted"}
+
+func TestMySQLCommentRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repositories.NewMySQLCommentRepository(db)
+	createdAt := time.Now()
+	comment := &models.Comment{PostId: 1, ParentId: 2, UserId: 3, Text: "Try the chutney", CreatedAt: createdAt}
+
+	mock.ExpectExec(`INSERT INTO comments \(post_id, parent_id, user_id, text, created_at\) SELECT post_id, \?, \?, \?, \? FROM posts WHERE post_id = \?`).
+		WithArgs(2, 3, "Try the chutney", createdAt, 1).
+		WillReturnResult(sqlmock.NewResult(7, 1))
+	mock.ExpectExec("INSERT INTO comments").
+		WithArgs(0, 3, "Hello", createdAt, 42).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.Create(comment)
+	assert.NoError(t, err)
+	assert.Equal(t, 7, comment.CommentId)
+
+	err = repo.Create(&models.Comment{PostId: 42, UserId: 3, Text: "Hello", CreatedAt: createdAt})
+	assert.EqualError(t, err, config.Red+"No Post exist with this id"+config.Reset)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMySQLCommentRepository_GetCommentsByPId(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repositories.NewMySQLCommentRepository(db)
+
+	rows := sqlmock.NewRows(commentRowColumns).
+		AddRow(1, 5, 0, 3, "riya", "Great spot", "2024-01-02 10:00:00", nil, false).
+		AddRow(2, 5, 1, 4, "", "", "2024-01-02 11:00:00", "2024-01-03 09:00:00", true)
+	mock.ExpectQuery(`SELECT c.comment_id, .* FROM comments c LEFT JOIN users u ON u.id = c.user_id WHERE c.post_id = \? ORDER BY c.comment_id`).
+		WithArgs(5).
+		WillReturnRows(rows)
+
+	comments, err := repo.GetCommentsByPId(5)
+
+	assert.NoError(t, err)
+	assert.Len(t, comments, 2)
+	assert.Equal(t, "riya", comments[0].Username)
+	assert.True(t, comments[0].UpdatedAt.IsZero())
+	assert.Equal(t, 1, comments[1].ParentId)
+	assert.True(t, comments[1].Deleted)
+	assert.False(t, comments[1].UpdatedAt.IsZero())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMySQLCommentRepository_FindByCommentId_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repositories.NewMySQLCommentRepository(db)
+
+	mock.ExpectQuery("WHERE c.comment_id = ").WithArgs(9).WillReturnRows(sqlmock.NewRows(commentRowColumns))
+
+	_, err = repo.FindByCommentId(9)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMySQLCommentRepository_UpdateUserComment(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repositories.NewMySQLCommentRepository(db)
+
+	mock.ExpectExec(`UPDATE comments SET text = \?, updated_at = \? WHERE comment_id = \? AND user_id = \? AND deleted = \?`).
+		WithArgs("Edited", sqlmock.AnyArg(), 1, 3, false).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE comments").
+		WithArgs("Edited", sqlmock.AnyArg(), 1, 4, false).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.NoError(t, repo.UpdateUserComment(1, 3, "Edited"))
+	assert.EqualError(t, repo.UpdateUserComment(1, 4, "Edited"), config.Red+"You can only update your comment"+config.Reset)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMySQLCommentRepository_MarkDeletedAndDelete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repositories.NewMySQLCommentRepository(db)
+
+	mock.ExpectExec(`UPDATE comments SET text = \?, deleted = \?, updated_at = \? WHERE comment_id = \?`).
+		WithArgs("", true, sqlmock.AnyArg(), 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM comments WHERE comment_id = \?`).
+		WithArgs(2).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.NoError(t, repo.MarkDeleted(1))
+	assert.EqualError(t, repo.DeleteByCommentId(2), config.Red+"No Comment exist with this id"+config.Reset)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMySQLCommentRepository_DeleteForUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repositories.NewMySQLCommentRepository(db)
+
+	mock.ExpectExec(`DELETE FROM comments WHERE post_id IN \(SELECT post_id FROM posts WHERE user_id = \?\)`).
+		WithArgs(3).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(`DELETE FROM comments WHERE user_id = \? AND comment_id NOT IN`).
+		WithArgs(3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE comments SET text = \?, deleted = \? WHERE user_id = \?`).
+		WithArgs("", true, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.DeleteForUser(3))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
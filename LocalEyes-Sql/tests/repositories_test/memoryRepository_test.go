@@ -0,0 +1,524 @@
+package repositories_test
+
+import (
+	"database/sql"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"localEyes/config"
+	"localEyes/internal/interfaces"
+	"localEyes/internal/models"
+	"localEyes/internal/repositories"
+)
+
+func TestInMemoryUserRepository_CreateAssignsIdsAndRejectsDuplicates(t *testing.T) {
+	repo := repositories.NewInMemoryUserRepository()
+
+	first := &models.User{Username: "riya", Password: "hash", IsActive: true}
+	second := &models.User{Username: "aman", Password: "hash", IsActive: true}
+	require.NoError(t, repo.Create(first))
+	require.NoError(t, repo.Create(second))
+	assert.Equal(t, 1, first.UId)
+	assert.Equal(t, 2, second.UId)
+
+	err := repo.Create(&models.User{Username: "riya", Password: "other"})
+	assert.EqualError(t, err, config.Red+"Username already taken"+config.Reset)
+
+	_, err = repo.FindByUsername("nobody")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestInMemoryUserRepository_ReturnsCopies(t *testing.T) {
+	repo := repositories.NewInMemoryUserRepository()
+	require.NoError(t, repo.Create(&models.User{Username: "riya", Password: "hash", IsActive: true}))
+
+	user, err := repo.FindByUId(1)
+	require.NoError(t, err)
+	user.Username = "changed"
+
+	stored, err := repo.FindByUId(1)
+	require.NoError(t, err)
+	assert.Equal(t, "riya", stored.Username)
+}
+
+func TestInMemoryUserRepository_NoRowsAffectedErrors(t *testing.T) {
+	repo := repositories.NewInMemoryUserRepository()
+	require.NoError(t, repo.Create(&models.User{Username: "riya", Password: "hash", IsActive: true}))
+
+	assert.EqualError(t, repo.DeleteByUId(9), config.Red+"No user exist with this id"+config.Reset)
+	assert.EqualError(t, repo.UpdateActiveStatus(1, true), config.Red+"No inActive user exist with this id"+config.Reset)
+	assert.NoError(t, repo.UpdateActiveStatus(1, false))
+}
+
+func TestInMemoryUserRepository_UpdateRole(t *testing.T) {
+	repo := repositories.NewInMemoryUserRepository()
+	require.NoError(t, repo.Create(&models.User{Username: "admin", Password: "hash", City: "delhi", Role: models.RoleAdmin}))
+	require.NoError(t, repo.Create(&models.User{Username: "author", Password: "hash", City: "delhi"}))
+	require.NoError(t, repo.Create(&models.User{Username: "reader", Password: "hash", City: "delhi"}))
+
+	reader, err := repo.FindByUId(3)
+	require.NoError(t, err)
+	assert.Equal(t, models.RoleUser, reader.Role)
+
+	require.NoError(t, repo.UpdateRole(3, models.RoleModerator))
+	reader, err = repo.FindByUId(3)
+	require.NoError(t, err)
+	assert.Equal(t, models.RoleModerator, reader.Role)
+	assert.Error(t, repo.UpdateRole(9, models.RoleModerator))
+}
+
+func TestInMemoryPostRepository(t *testing.T) {
+	repo := repositories.NewInMemoryPostRepository()
+	require.NoError(t, repo.Create(&models.Post{UId: 1, Title: "Momos", Type: "food", CreatedAt: time.Now()}))
+	require.NoError(t, repo.Create(&models.Post{UId: 2, Title: "Metro", Type: "travel", CreatedAt: time.Now()}))
+
+	food, err := repo.GetPostsByFilter("food")
+	require.NoError(t, err)
+	assert.Len(t, food, 1)
+
+	require.NoError(t, repo.UpdateLike(1))
+	posts, err := repo.GetPostsByPId(1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, posts[0].Likes)
+
+	assert.EqualError(t, repo.UpdateUserPost(1, 2, "x", "y"), config.Red+"You can only update your post"+config.Reset)
+	assert.EqualError(t, repo.DeleteByUIdPId(2, 1), config.Red+"No Post exist with this id"+config.Reset)
+	assert.EqualError(t, repo.UpdateLike(7), config.Red+"No post exist with this id"+config.Reset)
+
+	require.NoError(t, repo.DeleteByUId(1))
+	all, err := repo.GetAllPosts()
+	require.NoError(t, err)
+	assert.Len(t, all, 1)
+	assert.Equal(t, "Metro", all[0].Title)
+}
+
+func TestInMemoryPostRepository_ListPosts(t *testing.T) {
+	users, posts, questions := repositories.NewInMemoryUserRepository(), repositories.NewInMemoryPostRepository(), repositories.NewInMemoryQuestionRepository()
+	repositories.NewInMemoryUnitOfWork(users, posts, questions, repositories.NewInMemoryAnswerRepository(),
+		repositories.NewInMemoryPostLikeRepository(), repositories.NewInMemorySessionRepository(), repositories.NewInMemoryAuditRepository(), repositories.NewInMemoryCategoryRepository(), repositories.NewInMemoryCityRepository(), repositories.NewInMemoryCommentRepository(), repositories.NewInMemoryNotificationRepository(), repositories.NewInMemoryNotificationPrefsRepository(), repositories.NewInMemoryOutboxRepository())
+	for _, post := range []*models.Post{{UId: 1, Title: "Momos", Type: "food"}, {UId: 2, Title: "Metro", Type: "travel"}, {UId: 1, Title: "Market", Type: "food"}} {
+		require.NoError(t, posts.Create(post))
+	}
+	require.NoError(t, posts.UpdateLike(1))
+	require.NoError(t, questions.Create(&models.Question{PostId: 2, UserId: 1, Text: "Which line?"}))
+
+	newest, err := posts.ListPosts(models.PostFilter{}, models.SortNewest, 2, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []int{2, 1}, []int{newest[0].PostId, newest[1].PostId})
+
+	mine, err := posts.ListPosts(models.PostFilter{Type: "food", UId: 1}, models.SortMostLiked, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 3}, []int{mine[0].PostId, mine[1].PostId})
+
+	asked, err := posts.ListPosts(models.PostFilter{}, models.SortMostQuestions, 1, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 2, asked[0].PostId)
+
+	beyond, err := posts.ListPosts(models.PostFilter{}, models.SortOldest, 10, 3)
+	require.NoError(t, err)
+	assert.Empty(t, beyond)
+
+	_, err = posts.ListPosts(models.PostFilter{}, "title", 10, 0)
+	assert.Error(t, err)
+}
+
+func TestInMemoryPostRepository_Location(t *testing.T) {
+	repo := repositories.NewInMemoryPostRepository()
+	latitude, longitude := 28.6250, 77.2187
+	require.NoError(t, repo.Create(&models.Post{UId: 1, Title: "Janpath", Location: models.Location{Latitude: &latitude, Longitude: &longitude}}))
+	require.NoError(t, repo.Create(&models.Post{UId: 1, Title: "Lake", Location: models.Location{Neighbourhood: "hauz khas"}}))
+
+	tagged, err := repo.ListPosts(models.PostFilter{Neighbourhood: "hauz khas"}, models.SortNewest, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, tagged, 1)
+	assert.Equal(t, "Lake", tagged[0].Title)
+
+	near, err := repo.PostsInArea(models.Area{MinLatitude: 28.6, MaxLatitude: 28.7, MinLongitude: 77.2, MaxLongitude: 77.3})
+	require.NoError(t, err)
+	require.Len(t, near, 1)
+	*near[0].Latitude = 0
+	again, err := repo.PostsInArea(models.Area{MinLatitude: 28.6, MaxLatitude: 28.7, MinLongitude: 77.2, MaxLongitude: 77.3})
+	require.NoError(t, err)
+	assert.Len(t, again, 1, "callers get copies of the coordinates")
+}
+
+func TestInMemoryRepositories_Search(t *testing.T) {
+	users, posts, questions, answers := repositories.NewInMemoryUserRepository(), repositories.NewInMemoryPostRepository(),
+		repositories.NewInMemoryQuestionRepository(), repositories.NewInMemoryAnswerRepository()
+	repositories.NewInMemoryUnitOfWork(users, posts, questions, answers,
+		repositories.NewInMemoryPostLikeRepository(), repositories.NewInMemorySessionRepository(), repositories.NewInMemoryAuditRepository(), repositories.NewInMemoryCategoryRepository(), repositories.NewInMemoryCityRepository(), repositories.NewInMemoryCommentRepository(), repositories.NewInMemoryNotificationRepository(), repositories.NewInMemoryNotificationPrefsRepository(), repositories.NewInMemoryOutboxRepository())
+	require.NoError(t, posts.Create(&models.Post{UId: 1, Title: "Street food", Content: "Momos near the metro"}))
+	require.NoError(t, posts.Create(&models.Post{UId: 1, Title: "Momos", Content: "Lajpat Nagar"}))
+	require.NoError(t, questions.Create(&models.Question{PostId: 1, UserId: 2, Text: "Open on Sunday?"}))
+	require.NoError(t, answers.Create(&models.Answer{QId: 1, UserId: 1, Text: "Yes, till 10"}))
+
+	found, err := posts.SearchPosts("", "momos Metro", 10, 0)
+	require.NoError(t, err)
+	require.Len(t, found, 2)
+	assert.Equal(t, 2, found[0].PostId)
+
+	found, err = posts.SearchPosts("", "momos", 1, 1)
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, 1, found[0].PostId)
+
+	asked, err := questions.SearchQuestions("till", 10, 0)
+	require.NoError(t, err)
+	require.Len(t, asked, 1)
+	assert.Equal(t, 1, asked[0].QId)
+
+	asked, err = questions.SearchQuestions("   ", 10, 0)
+	require.NoError(t, err)
+	assert.Empty(t, asked)
+}
+
+func TestInMemoryCategoryRepository(t *testing.T) {
+	repo := repositories.NewInMemoryCategoryRepository()
+	categories, err := repo.GetAll()
+	require.NoError(t, err)
+	assert.Len(t, categories, len(repositories.DefaultCategories))
+	assert.Equal(t, "food", categories[0].Name)
+
+	events := &models.Category{Name: "events", IsActive: true}
+	require.NoError(t, repo.Create(events))
+	assert.Error(t, repo.Create(&models.Category{Name: "events"}))
+
+	found, err := repo.FindByName("events")
+	require.NoError(t, err)
+	found.Name = "changed"
+	again, err := repo.FindById(events.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "events", again.Name)
+
+	assert.Error(t, repo.Update(&models.Category{Id: events.Id, Name: "food"}))
+	require.NoError(t, repo.DeleteById(events.Id))
+	_, err = repo.FindById(events.Id)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestInMemoryCityRepository(t *testing.T) {
+	repo := repositories.NewInMemoryCityRepository()
+	require.NoError(t, repo.Create(&models.City{Name: "pune"}))
+	require.NoError(t, repo.Create(&models.City{Name: "agra"}))
+	assert.Error(t, repo.Create(&models.City{Name: "pune"}))
+
+	cities, err := repo.GetAll()
+	require.NoError(t, err)
+	var names []string
+	for _, city := range cities {
+		names = append(names, city.Name)
+	}
+	assert.Equal(t, []string{"agra", repositories.DefaultCity, "pune"}, names)
+
+	_, err = repo.FindByName("goa")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestInMemoryRepositories_ScopeToCity(t *testing.T) {
+	users := repositories.NewInMemoryUserRepository()
+	posts := repositories.NewInMemoryPostRepository()
+	questions := repositories.NewInMemoryQuestionRepository()
+	repositories.NewInMemoryUnitOfWork(users, posts, questions, repositories.NewInMemoryAnswerRepository(),
+		repositories.NewInMemoryPostLikeRepository(), repositories.NewInMemorySessionRepository(), repositories.NewInMemoryAuditRepository(),
+		repositories.NewInMemoryCategoryRepository(), repositories.NewInMemoryCityRepository(), repositories.NewInMemoryCommentRepository(), repositories.NewInMemoryNotificationRepository(), repositories.NewInMemoryNotificationPrefsRepository(), repositories.NewInMemoryOutboxRepository())
+	require.NoError(t, posts.Create(&models.Post{UId: 1, Title: "Momos", Type: "food", City: "delhi", CreatedAt: time.Now()}))
+	require.NoError(t, posts.Create(&models.Post{UId: 2, Title: "Misal momos", Type: "food", City: "pune", CreatedAt: time.Now()}))
+	require.NoError(t, questions.Create(&models.Question{PostId: 2, UserId: 1, Text: "Spicy?", CreatedAt: time.Now()}))
+
+	inPune, err := posts.ListPosts(models.PostFilter{City: "pune"}, models.SortNewest, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, inPune, 1)
+	assert.Equal(t, "Misal momos", inPune[0].Title)
+	found, err := posts.SearchPosts("delhi", "momos", 10, 0)
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, "Momos", found[0].Title)
+
+	asked, err := questions.ListQuestions("delhi", models.SortNewest, 10, 0)
+	require.NoError(t, err)
+	assert.Empty(t, asked)
+	asked, err = questions.ListQuestions("", models.SortNewest, 10, 0)
+	require.NoError(t, err)
+	assert.Len(t, asked, 1)
+
+	require.NoError(t, users.Create(&models.User{Username: "riya", Password: "hash", City: "delhi"}))
+	require.NoError(t, users.UpdateCity(1, "pune"))
+	assert.Error(t, users.UpdateCity(1, "pune"))
+	assert.Error(t, users.UpdateCity(9, "pune"))
+}
+
+func TestInMemoryQuestionRepository(t *testing.T) {
+	repo := repositories.NewInMemoryQuestionRepository()
+	require.NoError(t, repo.Create(&models.Question{PostId: 1, UserId: 1, Text: "Best time?", Replies: []string{}}))
+
+	questions, err := repo.GetQuestionsByPId(1)
+	require.NoError(t, err)
+	assert.Equal(t, "Best time?", questions[0].Text)
+
+	assert.EqualError(t, repo.DeleteByQIdUId(1, 2), config.Red+"No Question exist with this id"+config.Reset)
+	assert.NoError(t, repo.DeleteByPId(1))
+	remaining, err := repo.GetAllQuestions()
+	require.NoError(t, err)
+	assert.Empty(t, remaining)
+}
+
+func TestInMemoryAnswerRepository(t *testing.T) {
+	users := repositories.NewInMemoryUserRepository()
+	posts := repositories.NewInMemoryPostRepository()
+	questions := repositories.NewInMemoryQuestionRepository()
+	repo := repositories.NewInMemoryAnswerRepository()
+	repositories.NewInMemoryUnitOfWork(users, posts, questions, repo, repositories.NewInMemoryPostLikeRepository(), repositories.NewInMemorySessionRepository(), repositories.NewInMemoryAuditRepository(), repositories.NewInMemoryCategoryRepository(), repositories.NewInMemoryCityRepository(), repositories.NewInMemoryCommentRepository(), repositories.NewInMemoryNotificationRepository(), repositories.NewInMemoryNotificationPrefsRepository(), repositories.NewInMemoryOutboxRepository())
+	require.NoError(t, users.Create(&models.User{Username: "local"}))
+	require.NoError(t, questions.Create(&models.Question{PostId: 1, UserId: 2, Text: "Best time?"}))
+
+	require.NoError(t, repo.Create(&models.Answer{QId: 1, UserId: 1, Text: "Winter"}))
+	assert.EqualError(t, repo.Create(&models.Answer{QId: 5, UserId: 1, Text: "x"}), config.Red+"No Question exist with this id"+config.Reset)
+
+	answers, err := repo.GetAnswersByQId(1)
+	require.NoError(t, err)
+	assert.Equal(t, "local", answers[0].Username)
+
+	assert.EqualError(t, repo.UpdateUserAnswer(1, 2, "x"), config.Red+"You can only update your answer"+config.Reset)
+	assert.EqualError(t, repo.DeleteByAnswerIdUId(1, 2), config.Red+"No Answer exist with this id"+config.Reset)
+	require.NoError(t, repo.DeleteByPId(1))
+	remaining, err := repo.GetAllAnswers()
+	require.NoError(t, err)
+	assert.Empty(t, remaining)
+}
+
+func TestInMemoryCommentRepository(t *testing.T) {
+	users := repositories.NewInMemoryUserRepository()
+	posts := repositories.NewInMemoryPostRepository()
+	repo := repositories.NewInMemoryCommentRepository()
+	repositories.NewInMemoryUnitOfWork(users, posts, repositories.NewInMemoryQuestionRepository(), repositories.NewInMemoryAnswerRepository(), repositories.NewInMemoryPostLikeRepository(), repositories.NewInMemorySessionRepository(), repositories.NewInMemoryAuditRepository(), repositories.NewInMemoryCategoryRepository(), repositories.NewInMemoryCityRepository(), repo, repositories.NewInMemoryNotificationRepository(), repositories.NewInMemoryNotificationPrefsRepository(), repositories.NewInMemoryOutboxRepository())
+	require.NoError(t, users.Create(&models.User{Username: "author"}))
+	require.NoError(t, users.Create(&models.User{Username: "reader"}))
+	require.NoError(t, posts.Create(&models.Post{UId: 1, Title: "Momos"}))
+	require.NoError(t, posts.Create(&models.Post{UId: 2, Title: "Metro"}))
+
+	require.NoError(t, repo.Create(&models.Comment{PostId: 2, UserId: 1, Text: "Crowded at 9"}))
+	require.NoError(t, repo.Create(&models.Comment{PostId: 2, ParentId: 1, UserId: 2, Text: "Try 10"}))
+	require.NoError(t, repo.Create(&models.Comment{PostId: 2, UserId: 1, Text: "Cards work"}))
+	require.NoError(t, repo.Create(&models.Comment{PostId: 1, UserId: 2, Text: "Which stall?"}))
+	assert.EqualError(t, repo.Create(&models.Comment{PostId: 5, UserId: 1, Text: "x"}), config.Red+"No Post exist with this id"+config.Reset)
+
+	comments, err := repo.GetCommentsByPId(2)
+	require.NoError(t, err)
+	require.Len(t, comments, 3)
+	assert.Equal(t, "reader", comments[1].Username)
+	comments[0].Text = "changed"
+	found, err := repo.FindByCommentId(1)
+	require.NoError(t, err)
+	assert.Equal(t, "Crowded at 9", found.Text)
+
+	assert.EqualError(t, repo.UpdateUserComment(2, 1, "x"), config.Red+"You can only update your comment"+config.Reset)
+	require.NoError(t, repo.DeleteForUser(1))
+	remaining, err := repo.GetCommentsByPId(1)
+	require.NoError(t, err)
+	assert.Empty(t, remaining)
+	remaining, err = repo.GetCommentsByPId(2)
+	require.NoError(t, err)
+	require.Len(t, remaining, 2)
+	assert.True(t, remaining[0].Deleted)
+	assert.EqualError(t, repo.DeleteByCommentId(3), config.Red+"No Comment exist with this id"+config.Reset)
+}
+
+func TestInMemoryNotificationRepository(t *testing.T) {
+	users := repositories.NewInMemoryUserRepository()
+	repo := repositories.NewInMemoryNotificationRepository()
+	repositories.NewInMemoryUnitOfWork(users, repositories.NewInMemoryPostRepository(), repositories.NewInMemoryQuestionRepository(), repositories.NewInMemoryAnswerRepository(), repositories.NewInMemoryPostLikeRepository(), repositories.NewInMemorySessionRepository(), repositories.NewInMemoryAuditRepository(), repositories.NewInMemoryCategoryRepository(), repositories.NewInMemoryCityRepository(), repositories.NewInMemoryCommentRepository(), repo, repositories.NewInMemoryNotificationPrefsRepository(), repositories.NewInMemoryOutboxRepository())
+	require.NoError(t, users.Create(&models.User{Username: "author", City: "delhi"}))
+	require.NoError(t, users.Create(&models.User{Username: "reader", City: "delhi"}))
+	require.NoError(t, users.Create(&models.User{Username: "faraway", City: "pune"}))
+
+	require.NoError(t, repo.CreateForPost(&models.Notification{Type: models.NotifyNewPost, Message: "New post: Chaat at CP"}, &models.Post{PostId: 4, UId: 1, City: "delhi", Type: "food"}))
+	require.NoError(t, repo.Create(&models.Notification{UserId: 2, Type: models.NotifyAdminAction, Message: "An admin made you a moderator"}))
+	for uId, want := range map[int]int{1: 0, 2: 2, 3: 0} {
+		count, err := repo.CountUnread(uId)
+		require.NoError(t, err)
+		assert.Equal(t, want, count, "user %d", uId)
+	}
+
+	newest, err := repo.GetUserNotifications(2, false, models.SortNewest, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, newest, 2)
+	assert.Equal(t, models.NotifyAdminAction, newest[0].Type)
+	newest[0].IsRead = true
+	require.NoError(t, repo.MarkRead(3, []int{2}))
+	require.NoError(t, repo.MarkRead(2, []int{1}))
+	unread, err := repo.GetUserNotifications(2, true, models.SortOldest, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, unread, 1)
+	assert.Equal(t, 2, unread[0].Id)
+
+	require.NoError(t, repo.MarkAllRead(2))
+	count, err := repo.CountUnread(2)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+	require.NoError(t, repo.DeleteForUser(2))
+	_, err = repo.FindByNotificationId(1)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestInMemoryNotificationPrefsRepository(t *testing.T) {
+	users := repositories.NewInMemoryUserRepository()
+	categories := repositories.NewInMemoryCategoryRepository()
+	notifications := repositories.NewInMemoryNotificationRepository()
+	repo := repositories.NewInMemoryNotificationPrefsRepository()
+	repositories.NewInMemoryUnitOfWork(users, repositories.NewInMemoryPostRepository(), repositories.NewInMemoryQuestionRepository(), repositories.NewInMemoryAnswerRepository(), repositories.NewInMemoryPostLikeRepository(), repositories.NewInMemorySessionRepository(), repositories.NewInMemoryAuditRepository(), categories, repositories.NewInMemoryCityRepository(), repositories.NewInMemoryCommentRepository(), notifications, repo, repositories.NewInMemoryOutboxRepository())
+	for _, name := range []string{"author", "all", "foodie", "digest", "quiet"} {
+		require.NoError(t, users.Create(&models.User{Username: name, City: "delhi"}))
+	}
+	food, err := categories.FindByName("food")
+	require.NoError(t, err)
+
+	require.NoError(t, repo.Save(&models.NotificationPrefs{UserId: 3, Delivery: models.DeliverImmediately, Posts: models.PostsSubscribed}))
+	require.NoError(t, repo.Save(&models.NotificationPrefs{UserId: 4, Delivery: models.DeliverDigest, Posts: models.PostsAll}))
+	require.NoError(t, repo.Save(&models.NotificationPrefs{UserId: 5, Delivery: models.DeliverOff, Posts: models.PostsAll}))
+	require.NoError(t, repo.Subscribe(&models.Subscription{UserId: 3, Kind: models.TargetCategory, TargetId: food.Id}))
+	require.NoError(t, repo.Subscribe(&models.Subscription{UserId: 3, Kind: models.TargetUser, TargetId: 1}))
+	assert.EqualError(t, repo.Subscribe(&models.Subscription{UserId: 3, Kind: models.TargetCategory, TargetId: food.Id}),
+		config.Red+"You are already subscribed to that category"+config.Reset)
+	prefs, err := repo.FindByUId(3)
+	require.NoError(t, err)
+	require.Len(t, prefs.Subscriptions, 2)
+	assert.Equal(t, "food", prefs.Subscriptions[0].Name)
+	assert.Equal(t, "author", prefs.Subscriptions[1].Name)
+
+	require.NoError(t, repo.Unsubscribe(3, models.TargetUser, 1))
+	for _, post := range []*models.Post{{PostId: 1, UId: 1, City: "delhi", Type: "food"}, {PostId: 2, UId: 1, City: "delhi", Type: "travel"}} {
+		require.NoError(t, notifications.CreateForPost(&models.Notification{Type: models.NotifyNewPost, Message: "New post"}, post))
+	}
+	for uId, want := range map[int]int{1: 0, 2: 2, 3: 1, 4: 0, 5: 0} {
+		count, err := notifications.CountUnread(uId)
+		require.NoError(t, err)
+		assert.Equal(t, want, count, "user %d", uId)
+	}
+	held, err := notifications.GetHeld(4)
+	require.NoError(t, err)
+	assert.Len(t, held, 2)
+	require.NoError(t, notifications.Release(4))
+	inbox, err := notifications.GetUserNotifications(4, true, models.SortOldest, 10, 0)
+	require.NoError(t, err)
+	assert.Empty(t, inbox)
+
+	require.NoError(t, repo.DeleteSubscriptionsTo(models.TargetCategory, food.Id))
+	require.NoError(t, repo.DeleteForUser(4))
+	prefs, err = repo.FindByUId(3)
+	require.NoError(t, err)
+	assert.Empty(t, prefs.Subscriptions)
+	prefs, err = repo.FindByUId(4)
+	require.NoError(t, err)
+	assert.Equal(t, models.DeliverImmediately, prefs.Delivery)
+}
+
+func TestInMemoryPostRepository_ConcurrentLikes(t *testing.T) {
+	repo := repositories.NewInMemoryPostRepository()
+	require.NoError(t, repo.Create(&models.Post{UId: 1, Title: "Momos", Type: "food"}))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, repo.UpdateLike(1))
+		}()
+	}
+	wg.Wait()
+
+	posts, err := repo.GetPostsByPId(1)
+	require.NoError(t, err)
+	assert.Equal(t, 50, posts[0].Likes)
+}
+
+func TestInMemoryOutboxRepository(t *testing.T) {
+	repo := repositories.NewInMemoryOutboxRepository()
+	uow := repositories.NewInMemoryUnitOfWork(repositories.NewInMemoryUserRepository(), repositories.NewInMemoryPostRepository(), repositories.NewInMemoryQuestionRepository(), repositories.NewInMemoryAnswerRepository(), repositories.NewInMemoryPostLikeRepository(), repositories.NewInMemorySessionRepository(), repositories.NewInMemoryAuditRepository(), repositories.NewInMemoryCategoryRepository(), repositories.NewInMemoryCityRepository(), repositories.NewInMemoryCommentRepository(), repositories.NewInMemoryNotificationRepository(), repositories.NewInMemoryNotificationPrefsRepository(), repo)
+	now := time.Now()
+
+	// An entry written in a failed unit of work goes with it.
+	err := uow.Do(func(repos interfaces.Repositories) error {
+		require.NoError(t, repos.Outbox.Create(&models.OutboxEntry{PostId: 1, AvailableAt: now, CreatedAt: now}))
+		return errors.New("post failed")
+	})
+	assert.Error(t, err)
+	due, err := repo.GetDue(now, 10)
+	require.NoError(t, err)
+	assert.Empty(t, due)
+
+	require.NoError(t, repo.Create(&models.OutboxEntry{PostId: 2, AvailableAt: now, CreatedAt: now}))
+	due, err = repo.GetDue(now, 10)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	stale := *due[0]
+	ok, err := repo.Claim(due[0], now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = repo.Claim(&stale, now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.False(t, ok)
+	due, err = repo.GetDue(now, 10)
+	require.NoError(t, err)
+	assert.Empty(t, due)
+}
+
+func TestInMemoryOutboxRepository_DeleteByPIdForUser(t *testing.T) {
+	posts := repositories.NewInMemoryPostRepository()
+	repo := repositories.NewInMemoryOutboxRepository()
+	assert.Error(t, repo.DeleteForUser(1), "not linked to the posts yet")
+	repositories.NewInMemoryUnitOfWork(repositories.NewInMemoryUserRepository(), posts, repositories.NewInMemoryQuestionRepository(), repositories.NewInMemoryAnswerRepository(), repositories.NewInMemoryPostLikeRepository(), repositories.NewInMemorySessionRepository(), repositories.NewInMemoryAuditRepository(), repositories.NewInMemoryCategoryRepository(), repositories.NewInMemoryCityRepository(), repositories.NewInMemoryCommentRepository(), repositories.NewInMemoryNotificationRepository(), repositories.NewInMemoryNotificationPrefsRepository(), repo)
+	now := time.Now()
+	for _, UId := range []int{1, 1, 2} {
+		post := &models.Post{UId: UId, Title: "Momos"}
+		require.NoError(t, posts.Create(post))
+		require.NoError(t, repo.Create(&models.OutboxEntry{PostId: post.PostId, AvailableAt: now, CreatedAt: now}))
+	}
+
+	require.NoError(t, repo.DeleteByPId(1))
+	require.NoError(t, repo.DeleteForUser(2))
+	due, err := repo.GetDue(now, 10)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	assert.Equal(t, 2, due[0].PostId)
+}
+
+func TestInMemoryUnitOfWork_RestoresWhatTheFailedWorkWrote(t *testing.T) {
+	users, posts, audit := repositories.NewInMemoryUserRepository(), repositories.NewInMemoryPostRepository(), repositories.NewInMemoryAuditRepository()
+	uow := repositories.NewInMemoryUnitOfWork(users, posts, repositories.NewInMemoryQuestionRepository(), repositories.NewInMemoryAnswerRepository(), repositories.NewInMemoryPostLikeRepository(), repositories.NewInMemorySessionRepository(), audit, repositories.NewInMemoryCategoryRepository(), repositories.NewInMemoryCityRepository(), repositories.NewInMemoryCommentRepository(), repositories.NewInMemoryNotificationRepository(), repositories.NewInMemoryNotificationPrefsRepository(), repositories.NewInMemoryOutboxRepository())
+	require.NoError(t, users.Create(&models.User{Username: "riya"}))
+
+	require.NoError(t, uow.Do(func(repos interfaces.Repositories) error {
+		return repos.Posts.Create(&models.Post{UId: 1, Title: "Momos"})
+	}))
+	failed := errors.New("failed")
+	err := uow.Do(func(repos interfaces.Repositories) error {
+		require.NoError(t, repos.Users.UpdateRole(1, models.RoleAdmin))
+		require.NoError(t, repos.Audit.Create(&models.AuditEntry{ActorId: 1, Action: models.AuditChangeRole}))
+		require.NoError(t, repos.Users.UpdateCity(1, "goa"))
+		return failed
+	})
+	assert.ErrorIs(t, err, failed)
+
+	user, err := users.FindByUId(1)
+	require.NoError(t, err)
+	assert.Equal(t, models.RoleUser, user.Role)
+	assert.Equal(t, "", user.City)
+	entries, err := audit.Find(models.AuditFilter{})
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+	// The post from the earlier, successful unit of work stays.
+	kept, err := posts.GetPostsByPId(1)
+	require.NoError(t, err)
+	assert.Len(t, kept, 1)
+
+	// Writes outside a unit of work are not undone by a later failure.
+	require.NoError(t, users.UpdateCity(1, "delhi"))
+	assert.Error(t, uow.Do(func(repos interfaces.Repositories) error { return failed }))
+	user, err = users.FindByUId(1)
+	require.NoError(t, err)
+	assert.Equal(t, "delhi", user.City)
+}
@@ -0,0 +1,105 @@
+package repositories_test
+
+import (
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"localEyes/config"
+	"localEyes/internal/models"
+	"localEyes/internal/repositories"
+	"testing"
+	"time"
+)
+
+func TestMySQLNotificationPrefsRepository_FindByUId(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repositories.NewMySQLNotificationPrefsRepository(db)
+	mock.ExpectQuery(`SELECT delivery, posts, digest_at FROM notification_prefs WHERE user_id = \?`).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"delivery", "posts", "digest_at"}).AddRow("digest", "subscribed", "2024-01-02 10:00:00"))
+	mock.ExpectQuery(`SELECT s.user_id, s.kind, s.target_id, COALESCE\(c.name, u.username, ''\) AS name FROM notification_subscriptions s`).
+		WithArgs(models.TargetCategory, models.TargetUser, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "kind", "target_id", "name"}).AddRow(2, models.TargetCategory, 1, "food"))
+	mock.ExpectQuery(`SELECT delivery, posts, digest_at FROM notification_prefs WHERE user_id = \?`).
+		WithArgs(3).
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery(`SELECT s.user_id, .* FROM notification_subscriptions s`).
+		WithArgs(models.TargetCategory, models.TargetUser, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "kind", "target_id", "name"}))
+
+	prefs, err := repo.FindByUId(2)
+	assert.NoError(t, err)
+	assert.Equal(t, models.DeliverDigest, prefs.Delivery)
+	assert.Equal(t, models.PostsSubscribed, prefs.Posts)
+	assert.Equal(t, 2024, prefs.DigestAt.Year())
+	assert.Len(t, prefs.Subscriptions, 1)
+	assert.Equal(t, "food", prefs.Subscriptions[0].Name)
+
+	// No row means the defaults.
+	prefs, err = repo.FindByUId(3)
+	assert.NoError(t, err)
+	assert.Equal(t, models.DeliverImmediately, prefs.Delivery)
+	assert.Equal(t, models.PostsAll, prefs.Posts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMySQLNotificationPrefsRepository_Save(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repositories.NewMySQLNotificationPrefsRepository(db)
+	digestAt := time.Now()
+	prefs := &models.NotificationPrefs{UserId: 2, Delivery: models.DeliverDigest, Posts: models.PostsAll, DigestAt: digestAt}
+	mock.ExpectExec(`INSERT INTO notification_prefs \(user_id, delivery, posts, digest_at\) SELECT id, \?, \?, \? FROM users WHERE id = \? AND NOT EXISTS`).
+		WithArgs("digest", "all", digestAt, 2, 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO notification_prefs`).
+		WithArgs("digest", "all", digestAt, 2, 2).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`UPDATE notification_prefs SET delivery = \?, posts = \?, digest_at = \? WHERE user_id = \?`).
+		WithArgs("digest", "all", digestAt, 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.Save(prefs))
+	// The second time the row is there and gets updated.
+	assert.NoError(t, repo.Save(prefs))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMySQLNotificationPrefsRepository_Subscriptions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repositories.NewMySQLNotificationPrefsRepository(db)
+	subscription := &models.Subscription{UserId: 2, Kind: models.TargetCategory, TargetId: 1}
+	mock.ExpectExec(`INSERT INTO notification_subscriptions \(user_id, kind, target_id\) SELECT id, \?, \? FROM users WHERE id = \? AND NOT EXISTS`).
+		WithArgs(models.TargetCategory, 1, 2, 2, models.TargetCategory, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO notification_subscriptions`).
+		WithArgs(models.TargetCategory, 1, 2, 2, models.TargetCategory, 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`DELETE FROM notification_subscriptions WHERE user_id = \? AND kind = \? AND target_id = \?`).
+		WithArgs(2, models.TargetUser, 5).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`DELETE FROM notification_subscriptions WHERE kind = \? AND target_id = \?`).
+		WithArgs(models.TargetCategory, 1).
+		WillReturnResult(sqlmock.NewResult(0, 4))
+	mock.ExpectExec(`DELETE FROM notification_prefs WHERE user_id = \?`).
+		WithArgs(2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM notification_subscriptions WHERE user_id = \? OR kind = \? AND target_id = \?`).
+		WithArgs(2, models.TargetUser, 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.Subscribe(subscription))
+	assert.EqualError(t, repo.Subscribe(subscription), config.Red+"You are already subscribed to that category"+config.Reset)
+	assert.EqualError(t, repo.Unsubscribe(2, models.TargetUser, 5), config.Red+"You are not subscribed to that author"+config.Reset)
+	assert.NoError(t, repo.DeleteSubscriptionsTo(models.TargetCategory, 1))
+	assert.NoError(t, repo.DeleteForUser(2))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
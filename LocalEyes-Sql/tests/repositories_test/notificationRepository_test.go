@@ -0,0 +1,187 @@
+package repositories_test
+
+import (
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"localEyes/internal/models"
+	"localEyes/internal/repositories"
+	"testing"
+	"time"
+)
+
+var notificationRowColumns = []string{"id", "user_id", "type", "source_type", "source_id", "message", "is_read", "held", "created_at"}
+
+func TestMySQLNotificationRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repositories.NewMySQLNotificationRepository(db)
+	createdAt := time.Now()
+	notification := &models.Notification{UserId: 2, Type: models.NotifyAdminAction, Message: "An admin reactivated your account", CreatedAt: createdAt}
+
+	mock.ExpectExec(`INSERT INTO notifications \(user_id, type, source_type, source_id, message, is_read, held, created_at\) VALUES \(\?, \?, \?, \?, \?, \?, \?, \?\)`).
+		WithArgs(2, models.NotifyAdminAction, "", 0, "An admin reactivated your account", false, false, createdAt).
+		WillReturnResult(sqlmock.NewResult(9, 1))
+
+	err = repo.Create(notification)
+	assert.NoError(t, err)
+	assert.Equal(t, 9, notification.Id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMySQLNotificationRepository_CreateForPost(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repositories.NewMySQLNotificationRepository(db)
+	createdAt := time.Now()
+
+	mock.ExpectExec(`INSERT INTO notifications \(user_id, .*\)\s+SELECT u.id, .* FROM users u LEFT JOIN notification_prefs p ON p.user_id = u.id\s+WHERE u.city = \? AND u.id != \? .*notification_subscriptions`).
+		WithArgs(models.NotifyNewPost, models.TargetPost, 4, "New post: Chaat at CP", false, "immediate", "digest", createdAt,
+			"delhi", 1, "immediate", "off", "all", "all", "subscribed", models.TargetUser, 1, models.TargetCategory, "food").
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	err = repo.CreateForPost(&models.Notification{Type: models.NotifyNewPost, SourceType: models.TargetPost, SourceId: 4,
+		Message: "New post: Chaat at CP", CreatedAt: createdAt}, &models.Post{PostId: 4, UId: 1, City: "delhi", Type: "food"})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMySQLNotificationRepository_GetUserNotifications(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repositories.NewMySQLNotificationRepository(db)
+
+	rows := sqlmock.NewRows(notificationRowColumns).
+		AddRow(3, 2, models.NotifyNewPost, models.TargetPost, 4, "New post: Chaat at CP", false, false, "2024-01-02 10:00:00")
+	mock.ExpectQuery(`SELECT id, .* FROM notifications WHERE user_id = \? AND held = \? AND is_read = \? ORDER BY id DESC LIMIT \? OFFSET \?`).
+		WithArgs(2, false, false, 11, 0).
+		WillReturnRows(rows)
+	mock.ExpectQuery(`SELECT id, .* FROM notifications WHERE user_id = \? AND held = \? ORDER BY id LIMIT \? OFFSET \?`).
+		WithArgs(2, false, 11, 10).
+		WillReturnRows(sqlmock.NewRows(notificationRowColumns))
+
+	notifications, err := repo.GetUserNotifications(2, true, models.SortNewest, 11, 0)
+	assert.NoError(t, err)
+	assert.Len(t, notifications, 1)
+	assert.Equal(t, 4, notifications[0].SourceId)
+	assert.Equal(t, 2024, notifications[0].CreatedAt.Year())
+
+	notifications, err = repo.GetUserNotifications(2, false, models.SortOldest, 11, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, notifications)
+
+	_, err = repo.GetUserNotifications(2, false, "most-liked", 11, 0)
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMySQLNotificationRepository_FindByNotificationId_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repositories.NewMySQLNotificationRepository(db)
+	mock.ExpectQuery(`SELECT id, .* FROM notifications WHERE id = \?`).
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows(notificationRowColumns))
+
+	_, err = repo.FindByNotificationId(7)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMySQLNotificationRepository_MarkRead(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repositories.NewMySQLNotificationRepository(db)
+	mock.ExpectExec(`UPDATE notifications SET is_read = \? WHERE user_id = \? AND id IN \(\?, \?\)`).
+		WithArgs(true, 2, 3, 5).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(`UPDATE notifications SET is_read = \? WHERE user_id = \? AND held = \?`).
+		WithArgs(true, 2, false).
+		WillReturnResult(sqlmock.NewResult(0, 4))
+
+	assert.NoError(t, repo.MarkRead(2, []int{3, 5}))
+	// Nothing to mark runs no query.
+	assert.NoError(t, repo.MarkRead(2, nil))
+	assert.NoError(t, repo.MarkAllRead(2))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMySQLNotificationRepository_CountUnreadAndDelete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repositories.NewMySQLNotificationRepository(db)
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM notifications WHERE user_id = \? AND is_read = \? AND held = \?`).
+		WithArgs(2, false, false).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	mock.ExpectExec(`DELETE FROM notifications WHERE user_id = \?`).
+		WithArgs(2).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	count, err := repo.CountUnread(2)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+	assert.NoError(t, repo.DeleteForUser(2))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMySQLNotificationRepository_GetHeldAndRelease(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repositories.NewMySQLNotificationRepository(db)
+	rows := sqlmock.NewRows(notificationRowColumns).
+		AddRow(3, 2, models.NotifyNewPost, models.TargetPost, 4, "New post: Chaat at CP", false, true, "2024-01-02 10:00:00")
+	mock.ExpectQuery(`SELECT id, .* FROM notifications WHERE user_id = \? AND held = \? ORDER BY id`).
+		WithArgs(2, true).
+		WillReturnRows(rows)
+	mock.ExpectExec(`UPDATE notifications SET held = \?, is_read = \? WHERE user_id = \? AND held = \?`).
+		WithArgs(false, true, 2, true).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	held, err := repo.GetHeld(2)
+	assert.NoError(t, err)
+	assert.Len(t, held, 1)
+	assert.True(t, held[0].Held)
+	assert.NoError(t, repo.Release(2))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMySQLNotificationRepository_GetSinceAndLastId(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repositories.NewMySQLNotificationRepository(db)
+	mock.ExpectQuery(`SELECT COALESCE\(MAX\(id\), 0\) FROM notifications`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	rows := sqlmock.NewRows(notificationRowColumns).
+		AddRow(8, 2, models.NotifyNewPost, models.TargetPost, 4, "New post: Chaat at CP", false, false, "2024-01-02 10:00:00").
+		AddRow(9, 3, models.NotifyNewAnswer, models.TargetQuestion, 1, "New answer", false, false, "2024-01-02 10:00:01")
+	mock.ExpectQuery(`SELECT id, .* FROM notifications WHERE id > \? AND held = \? ORDER BY id LIMIT \?`).
+		WithArgs(7, false, 100).
+		WillReturnRows(rows)
+
+	lastId, err := repo.LastId()
+	assert.NoError(t, err)
+	assert.Equal(t, 7, lastId)
+	since, err := repo.GetSince(lastId, 100)
+	assert.NoError(t, err)
+	if assert.Len(t, since, 2) {
+		assert.Equal(t, 8, since[0].Id)
+		assert.Equal(t, 3, since[1].UserId)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
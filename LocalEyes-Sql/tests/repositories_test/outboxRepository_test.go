@@ -0,0 +1,146 @@
+package repositories_test
+
+import (
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"localEyes/internal/models"
+	"localEyes/internal/repositories"
+	"testing"
+	"time"
+)
+
+func TestMySQLOutboxRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repositories.NewMySQLOutboxRepository(db)
+	now := time.Now()
+	entry := &models.OutboxEntry{PostId: 4, AvailableAt: now, CreatedAt: now}
+	mock.ExpectExec(`INSERT INTO notification_outbox \(post_id, attempts, available_at, last_error, dead, created_at\) VALUES \(\?, \?, \?, \?, \?, \?\)`).
+		WithArgs(4, 0, now.Unix(), "", false, now).
+		WillReturnResult(sqlmock.NewResult(9, 1))
+
+	assert.NoError(t, repo.Create(entry))
+	assert.Equal(t, 9, entry.Id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMySQLOutboxRepository_GetDue(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repositories.NewMySQLOutboxRepository(db)
+	now := time.Now()
+	mock.ExpectQuery(`SELECT id, post_id, attempts, available_at, last_error, dead, created_at FROM notification_outbox WHERE dead = \? AND available_at <= \? ORDER BY id LIMIT \?`).
+		WithArgs(false, now.Unix(), 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "post_id", "attempts", "available_at", "last_error", "dead", "created_at"}).
+			AddRow(1, 4, 0, now.Unix(), "", false, "2024-01-02 10:00:00").
+			AddRow(2, 5, 2, now.Unix()-60, "database is locked", false, "2024-01-02 10:00:01"))
+
+	entries, err := repo.GetDue(now, 10)
+
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 2) {
+		assert.Equal(t, 4, entries[0].PostId)
+		assert.Equal(t, now.Unix(), entries[0].AvailableAt.Unix())
+		assert.Equal(t, 2, entries[1].Attempts)
+		assert.Equal(t, "database is locked", entries[1].LastError)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMySQLOutboxRepository_FindById_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repositories.NewMySQLOutboxRepository(db)
+	mock.ExpectQuery(`SELECT id, post_id, attempts, available_at, last_error, dead, created_at FROM notification_outbox WHERE id = \?`).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "post_id", "attempts", "available_at", "last_error", "dead", "created_at"}))
+
+	entry, err := repo.FindById(3)
+
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.Nil(t, entry)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMySQLOutboxRepository_Claim(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repositories.NewMySQLOutboxRepository(db)
+	until := time.Now().Add(time.Minute)
+	claim := `UPDATE notification_outbox SET attempts = attempts \+ 1, available_at = \? WHERE id = \? AND attempts = \? AND dead = \?`
+	mock.ExpectExec(claim).WithArgs(until.Unix(), 1, 0, false).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(claim).WithArgs(until.Unix(), 2, 1, false).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	entry := &models.OutboxEntry{Id: 1}
+	ok, err := repo.Claim(entry, until)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 1, entry.Attempts)
+	assert.Equal(t, until.Unix(), entry.AvailableAt.Unix())
+
+	// Someone else claimed it after it was read.
+	entry = &models.OutboxEntry{Id: 2, Attempts: 1}
+	ok, err = repo.Claim(entry, until)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, 1, entry.Attempts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMySQLOutboxRepository_RetryMarkDeadComplete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repositories.NewMySQLOutboxRepository(db)
+	at := time.Now().Add(4 * time.Second)
+	mock.ExpectExec(`UPDATE notification_outbox SET available_at = \?, last_error = \? WHERE id = \?`).
+		WithArgs(at.Unix(), "database is locked", 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE notification_outbox SET dead = \?, last_error = \? WHERE id = \?`).
+		WithArgs(true, "database is locked", 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM notification_outbox WHERE id = \? AND attempts = \?`).
+		WithArgs(2, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM notification_outbox WHERE id = \? AND attempts = \?`).
+		WithArgs(2, 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.NoError(t, repo.Retry(1, at, "database is locked"))
+	assert.NoError(t, repo.MarkDead(1, "database is locked"))
+	completed, err := repo.Complete(&models.OutboxEntry{Id: 2, Attempts: 1})
+	assert.NoError(t, err)
+	assert.True(t, completed)
+	completed, err = repo.Complete(&models.OutboxEntry{Id: 2, Attempts: 1})
+	assert.NoError(t, err)
+	assert.False(t, completed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMySQLOutboxRepository_DeleteByPIdForUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repositories.NewMySQLOutboxRepository(db)
+	mock.ExpectExec(`DELETE FROM notification_outbox WHERE post_id = \?`).
+		WithArgs(3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM notification_outbox WHERE post_id IN \(SELECT post_id FROM posts WHERE user_id = \?\)`).
+		WithArgs(2).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	assert.NoError(t, repo.DeleteByPId(3))
+	assert.NoError(t, repo.DeleteForUser(2))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
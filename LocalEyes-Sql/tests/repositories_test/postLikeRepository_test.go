@@ -0,0 +1,72 @@
+package repositories_test
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"localEyes/internal/models"
+	"localEyes/internal/repositories"
+	"regexp"
+	"testing"
+)
+
+const createLikeQuery = "INSERT INTO post_likes (user_id, post_id, created_at) SELECT ?, post_id, ? FROM posts WHERE post_id = ? AND NOT EXISTS (SELECT 1 FROM post_likes WHERE user_id = ? AND post_id = ?)"
+
+// A like racing an identical one passes the NOT EXISTS check and is then
+// refused by the primary key; that is the same conflict as a plain repeat.
+func TestMySQLPostLikeRepository_Create_RacingDuplicate(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"mysql", &mysql.MySQLError{Number: 1062, Message: "Duplicate entry '2-1' for key 'PRIMARY'"}},
+		{"sqlite", sqliteDuplicateKeyError(t)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+			mock.ExpectExec(regexp.QuoteMeta(createLikeQuery)).
+				WithArgs(2, sqlmock.AnyArg(), 1, 2, 1).
+				WillReturnError(tt.err)
+
+			err = repositories.NewMySQLPostLikeRepository(db).Create(2, 1)
+
+			assert.ErrorIs(t, err, models.ErrConflict)
+			assert.EqualError(t, err, models.NewError(models.ErrConflict, "You have already liked this post").Error())
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMySQLPostLikeRepository_Create_OtherErrorsPassThrough(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	mock.ExpectExec(regexp.QuoteMeta(createLikeQuery)).
+		WillReturnError(&mysql.MySQLError{Number: 1213, Message: "Deadlock found"})
+
+	err = repositories.NewMySQLPostLikeRepository(db).Create(2, 1)
+
+	assert.False(t, errors.Is(err, models.ErrConflict))
+	assert.ErrorContains(t, err, "Deadlock found")
+}
+
+// sqliteDuplicateKeyError gets the error SQLite reports for a taken primary
+// key from a real database, as the driver does not let us build one.
+func sqliteDuplicateKeyError(t *testing.T) error {
+	db, err := sql.Open("sqlite", "file::memory:")
+	require.NoError(t, err)
+	defer db.Close()
+	_, err = db.Exec("CREATE TABLE likes (user_id INTEGER, post_id INTEGER, PRIMARY KEY (user_id, post_id))")
+	require.NoError(t, err)
+	_, err = db.Exec("INSERT INTO likes VALUES (2, 1)")
+	require.NoError(t, err)
+	_, err = db.Exec("INSERT INTO likes VALUES (2, 1)")
+	require.Error(t, err)
+	return err
+}
@@ -8,6 +8,7 @@ import (
 	"localEyes/config"
 	"localEyes/internal/models"
 	"localEyes/internal/repositories"
+	"regexp"
 	"testing"
 	"time"
 )
@@ -28,12 +29,14 @@ func TestMySQLPostRepository_Create(t *testing.T) {
 		Content:   "This is a test post",
 		Likes:     0,
 		CreatedAt: time.Now(),
+		City:      "delhi",
 	}
 
-	mock.ExpectExec("INSERT INTO posts").WithArgs(post.UId, post.Title, post.Type, post.Content, post.Likes, post.CreatedAt).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO posts").WithArgs(post.UId, post.Title, post.Type, post.Content, post.Likes, post.CreatedAt, post.City, post.Neighbourhood, post.Latitude, post.Longitude).WillReturnResult(sqlmock.NewResult(1, 1))
 
 	err = repo.Create(post)
 	assert.NoError(t, err)
+	assert.Equal(t, 1, post.PostId)
 }
 
 func TestMySQLPostRepository_GetAllPosts(t *testing.T) {
@@ -45,10 +48,10 @@ func TestMySQLPostRepository_GetAllPosts(t *testing.T) {
 
 	repo := repositories.NewMySQLPostRepository(db)
 
-	rows := sqlmock.NewRows([]string{"post_id", "user_id", "title", "type", "content", "likes", "created_at"}).
-		AddRow(1, 1, "Test Post", "food", "This is a test post", 0, "2006-01-02T15:04:05Z")
+	rows := sqlmock.NewRows([]string{"post_id", "user_id", "title", "type", "content", "likes", "created_at", "city", "neighbourhood", "latitude", "longitude"}).
+		AddRow(1, 1, "Test Post", "food", "This is a test post", 0, "2006-01-02T15:04:05Z", "delhi", "", nil, nil)
 
-	mock.ExpectQuery(`^SELECT post_id, user_id, title, type, content, likes, created_at FROM posts$`).WillReturnRows(rows)
+	mock.ExpectQuery(`^SELECT post_id, user_id, title, type, content, likes, created_at, city, neighbourhood, latitude, longitude FROM posts$`).WillReturnRows(rows)
 
 	posts, err := repo.GetAllPosts()
 	if err != nil {
@@ -100,11 +103,11 @@ func TestMySQLPostRepository_GetPostsByFilter(t *testing.T) {
 
 	repo := repositories.NewMySQLPostRepository(db)
 
-	rows := sqlmock.NewRows([]string{"post_id", "user_id", "title", "type", "content", "likes", "created_at"}).
-		AddRow(1, 1, "Test Post", "food", "This is a test post", 0, "2024-09-08 00:00:00")
+	rows := sqlmock.NewRows([]string{"post_id", "user_id", "title", "type", "content", "likes", "created_at", "city", "neighbourhood", "latitude", "longitude"}).
+		AddRow(1, 1, "Test Post", "food", "This is a test post", 0, "2024-09-08 00:00:00", "delhi", "", nil, nil)
 
 	// Ensure the expected query matches exactly with the actual query
-	mock.ExpectQuery(`^SELECT post_id, user_id, title,type, content, likes,created_at FROM posts WHERE type = \?$`).
+	mock.ExpectQuery(`^SELECT post_id, user_id, title, type, content, likes, created_at, city, neighbourhood, latitude, longitude FROM posts WHERE type = \?$`).
 		WithArgs("food").
 		WillReturnRows(rows)
 
@@ -119,6 +122,85 @@ func TestMySQLPostRepository_GetPostsByFilter(t *testing.T) {
 	assert.Equal(t, "Test Post", posts[0].Title)
 }
 
+func TestMySQLPostRepository_ListPosts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock instance: %v", err)
+	}
+	defer db.Close()
+
+	repo := repositories.NewMySQLPostRepository(db)
+
+	rows := sqlmock.NewRows([]string{"post_id", "user_id", "title", "type", "content", "likes", "created_at", "city", "neighbourhood", "latitude", "longitude"}).
+		AddRow(4, 2, "Momos", "food", "Lajpat Nagar", 3, "2024-09-08 00:00:00", "delhi", "", nil, nil)
+
+	mock.ExpectQuery(`^SELECT post_id, user_id, title, type, content, likes, created_at, city, neighbourhood, latitude, longitude FROM posts WHERE type = \? AND user_id = \? AND city = \? ORDER BY likes DESC, post_id DESC LIMIT \? OFFSET \?$`).
+		WithArgs("food", 2, "delhi", 11, 10).
+		WillReturnRows(rows)
+
+	posts, err := repo.ListPosts(models.PostFilter{Type: "food", UId: 2, City: "delhi"}, models.SortMostLiked, 11, 10)
+	assert.NoError(t, err)
+	assert.Len(t, posts, 1)
+	assert.Equal(t, 3, posts[0].Likes)
+	assert.Equal(t, "delhi", posts[0].City)
+
+	_, err = repo.ListPosts(models.PostFilter{}, "title", 10, 0)
+	assert.EqualError(t, err, `unknown sort order "title"`)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMySQLPostRepository_Location(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock instance: %v", err)
+	}
+	defer db.Close()
+
+	repo := repositories.NewMySQLPostRepository(db)
+	columns := []string{"post_id", "user_id", "title", "type", "content", "likes", "created_at", "city", "neighbourhood", "latitude", "longitude"}
+
+	mock.ExpectQuery(`^SELECT post_id, user_id, title, type, content, likes, created_at, city, neighbourhood, latitude, longitude FROM posts WHERE city = \? AND neighbourhood = \? ORDER BY post_id DESC LIMIT \? OFFSET \?$`).
+		WithArgs("delhi", "hauz khas", 11, 0).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(5, 2, "Lake", "travel", "Ducks", 0, "2024-09-08 00:00:00", "delhi", "hauz khas", nil, nil))
+	posts, err := repo.ListPosts(models.PostFilter{City: "delhi", Neighbourhood: "hauz khas"}, models.SortNewest, 11, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "hauz khas", posts[0].Neighbourhood)
+	assert.False(t, posts[0].HasCoordinates())
+
+	mock.ExpectQuery(`^SELECT post_id, user_id, title, type, content, likes, created_at, city, neighbourhood, latitude, longitude FROM posts WHERE latitude BETWEEN \? AND \? AND longitude BETWEEN \? AND \?$`).
+		WithArgs(28.5, 28.7, 77.1, 77.3).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(6, 2, "Janpath", "food", "Chai", 0, "2024-09-08 00:00:00", "delhi", "", 28.625, 77.2187))
+	posts, err = repo.PostsInArea(models.Area{MinLatitude: 28.5, MaxLatitude: 28.7, MinLongitude: 77.1, MaxLongitude: 77.3})
+	assert.NoError(t, err)
+	assert.Equal(t, 28.625, *posts[0].Latitude)
+	assert.Equal(t, 77.2187, *posts[0].Longitude)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMySQLPostRepository_SearchPosts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock instance: %v", err)
+	}
+	defer db.Close()
+
+	repo := repositories.NewMySQLPostRepository(db)
+
+	rows := sqlmock.NewRows([]string{"post_id", "user_id", "title", "type", "content", "likes", "created_at", "city", "neighbourhood", "latitude", "longitude"}).
+		AddRow(4, 2, "Momos", "food", "Lajpat Nagar", 3, "2024-09-08 00:00:00", "delhi", "", nil, nil)
+
+	match := regexp.QuoteMeta("MATCH (title, content) AGAINST (? IN NATURAL LANGUAGE MODE)")
+	mock.ExpectQuery(`^SELECT post_id, user_id, title, type, content, likes, created_at, city, neighbourhood, latitude, longitude FROM posts WHERE city = \? AND `+match+` ORDER BY `+match+` DESC, post_id DESC LIMIT \? OFFSET \?$`).
+		WithArgs("delhi", "steamed momos", "steamed momos", 11, 0).
+		WillReturnRows(rows)
+
+	posts, err := repo.SearchPosts("delhi", "steamed momos", 11, 0)
+	assert.NoError(t, err)
+	assert.Len(t, posts, 1)
+	assert.Equal(t, "Momos", posts[0].Title)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestMySQLPostRepository_UpdateUserPost(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -162,7 +244,7 @@ func TestMySQLPostRepository_UpdateLike(t *testing.T) {
 
 	repo := repositories.NewMySQLPostRepository(db)
 
-	mock.ExpectExec("^UPDATE posts SET likes = likes \\+ 1 WHERE post_id = \\?$").WithArgs(1).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("^UPDATE posts SET likes=likes\\+1 WHERE post_id=\\?$").WithArgs(1).WillReturnResult(sqlmock.NewResult(1, 1))
 
 	err = repo.UpdateLike(1)
 	assert.NoError(t, err)
@@ -178,7 +260,7 @@ func TestMySQLPostRepository_UpdateLike_NoRowsAffected(t *testing.T) {
 	repo := repositories.NewMySQLPostRepository(db)
 
 	// Mock the expectation for the query
-	mock.ExpectExec(`^UPDATE posts SET likes = likes \+ 1 WHERE post_id = \?$`).
+	mock.ExpectExec(`^UPDATE posts SET likes=likes\+1 WHERE post_id=\?$`).
 		WithArgs(1).
 		WillReturnResult(sqlmock.NewResult(0, 0)) // No rows affected
 
@@ -201,7 +283,7 @@ func TestDeleteByUIdPId_Success(t *testing.T) {
 	UId := 1
 	PId := 10
 
-	mock.ExpectExec("^DELETE FROM posts WHERE post_id = \\? AND user_id=\\?$").
+	mock.ExpectExec("^DELETE FROM posts WHERE post_id = \\? AND user_id = \\?$").
 		WithArgs(PId, UId).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
@@ -219,7 +301,7 @@ func TestDeleteByUIdPId_NoRows(t *testing.T) {
 	UId := 1
 	PId := 999
 
-	mock.ExpectExec("^DELETE FROM posts WHERE post_id = \\? AND user_id=\\?$").
+	mock.ExpectExec("^DELETE FROM posts WHERE post_id = \\? AND user_id = \\?$").
 		WithArgs(PId, UId).
 		WillReturnResult(sqlmock.NewResult(0, 0))
 
@@ -273,11 +355,11 @@ func TestGetPostsByUId_Success(t *testing.T) {
 	UId := 1
 	createdAt := time.Now()
 
-	rows := sqlmock.NewRows([]string{"post_id", "user_id", "title", "type", "content", "likes", "created_at"}).
-		AddRow(1, UId, "Title 1", "food", "Content 1", 10, createdAt).
-		AddRow(2, UId, "Title 2", "travel", "Content 2", 15, createdAt)
+	rows := sqlmock.NewRows([]string{"post_id", "user_id", "title", "type", "content", "likes", "created_at", "city", "neighbourhood", "latitude", "longitude"}).
+		AddRow(1, UId, "Title 1", "food", "Content 1", 10, createdAt, "delhi", "", nil, nil).
+		AddRow(2, UId, "Title 2", "travel", "Content 2", 15, createdAt, "delhi", "", nil, nil)
 
-	mock.ExpectQuery("^SELECT post_id, user_id, title,type, content, likes,created_at FROM posts WHERE user_id = \\?$").
+	mock.ExpectQuery("^SELECT post_id, user_id, title, type, content, likes, created_at, city, neighbourhood, latitude, longitude FROM posts WHERE user_id = \\?$").
 		WithArgs(UId).
 		WillReturnRows(rows)
 
@@ -298,9 +380,9 @@ func TestGetPostsByUId_NoPosts(t *testing.T) {
 
 	UId := 1
 
-	rows := sqlmock.NewRows([]string{"post_id", "user_id", "title", "type", "content", "likes", "created_at"})
+	rows := sqlmock.NewRows([]string{"post_id", "user_id", "title", "type", "content", "likes", "created_at", "city", "neighbourhood", "latitude", "longitude"})
 
-	mock.ExpectQuery("^SELECT post_id, user_id, title,type, content, likes,created_at FROM posts WHERE user_id = \\?$").
+	mock.ExpectQuery("^SELECT post_id, user_id, title, type, content, likes, created_at, city, neighbourhood, latitude, longitude FROM posts WHERE user_id = \\?$").
 		WithArgs(UId).
 		WillReturnRows(rows)
 
@@ -318,7 +400,7 @@ func TestGetPostsByUId_Error(t *testing.T) {
 
 	UId := 1
 
-	mock.ExpectQuery("^SELECT post_id, user_id, title,type, content, likes,created_at FROM posts WHERE user_id = \\?$").
+	mock.ExpectQuery("^SELECT post_id, user_id, title, type, content, likes, created_at, city, neighbourhood, latitude, longitude FROM posts WHERE user_id = \\?$").
 		WithArgs(UId).
 		WillReturnError(errors.New("query error"))
 
@@ -338,10 +420,10 @@ func TestGetPostsByPId_Success(t *testing.T) {
 	PId := 1
 	createdAt := time.Now()
 
-	rows := sqlmock.NewRows([]string{"post_id", "user_id", "title", "type", "content", "likes", "created_at"}).
-		AddRow(PId, 1, "Title 1", "food", "Content 1", 10, createdAt)
+	rows := sqlmock.NewRows([]string{"post_id", "user_id", "title", "type", "content", "likes", "created_at", "city", "neighbourhood", "latitude", "longitude"}).
+		AddRow(PId, 1, "Title 1", "food", "Content 1", 10, createdAt, "delhi", "", nil, nil)
 
-	mock.ExpectQuery("^SELECT post_id, user_id, title,type, content, likes,created_at FROM posts WHERE post_id = \\?$").
+	mock.ExpectQuery("^SELECT post_id, user_id, title, type, content, likes, created_at, city, neighbourhood, latitude, longitude FROM posts WHERE post_id = \\?$").
 		WithArgs(PId).
 		WillReturnRows(rows)
 
@@ -362,9 +444,9 @@ func TestGetPostsByPId_NoPosts(t *testing.T) {
 
 	PId := 1
 
-	rows := sqlmock.NewRows([]string{"post_id", "user_id", "title", "type", "content", "likes", "created_at"})
+	rows := sqlmock.NewRows([]string{"post_id", "user_id", "title", "type", "content", "likes", "created_at", "city", "neighbourhood", "latitude", "longitude"})
 
-	mock.ExpectQuery("^SELECT post_id, user_id, title,type, content, likes,created_at FROM posts WHERE post_id = \\?$").
+	mock.ExpectQuery("^SELECT post_id, user_id, title, type, content, likes, created_at, city, neighbourhood, latitude, longitude FROM posts WHERE post_id = \\?$").
 		WithArgs(PId).
 		WillReturnRows(rows)
 
@@ -382,7 +464,7 @@ func TestGetPostsByPId_Error(t *testing.T) {
 
 	PId := 1
 
-	mock.ExpectQuery("^SELECT post_id, user_id, title,type, content, likes,created_at FROM posts WHERE post_id = \\?").
+	mock.ExpectQuery("^SELECT post_id, user_id, title, type, content, likes, created_at, city, neighbourhood, latitude, longitude FROM posts WHERE post_id = \\?").
 		WithArgs(PId).
 		WillReturnError(errors.New("query error"))
 
@@ -393,3 +475,35 @@ func TestGetPostsByPId_Error(t *testing.T) {
 	assert.Nil(t, posts)
 	assert.EqualError(t, err, "query error")
 }
+
+func TestRemoveLike_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repositories.NewMySQLPostRepository(db)
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE posts SET likes=likes-1 WHERE post_id=? AND likes>0")).
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.RemoveLike(1)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRemoveUserLikes_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repositories.NewMySQLPostRepository(db)
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE posts SET likes=likes-1 WHERE post_id IN (SELECT post_id FROM post_likes WHERE user_id = ?) AND likes>0")).
+		WithArgs(3).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	err = repo.RemoveUserLikes(3)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -148,7 +148,7 @@ func TestDeleteByQIdUId(t *testing.T) {
 			name:          "no rows affected",
 			qId:           2,
 			uId:           2,
-			expectedError: errors.New(config.Red + "No Question exist with this id" + config.Reset),
+			expectedError: models.NewError(models.ErrNotFound, "No Question exist with this id"),
 			affectedRows:  0,
 			mockExpectation: func() {
 				mock.ExpectExec("DELETE FROM questions WHERE q_id = \\? AND user_id = \\?").
@@ -188,121 +188,78 @@ func TestDeleteByQIdUId(t *testing.T) {
 	}
 }
 
-func TestUpdateQuestion_Success(t *testing.T) {
-	// Create a new mock database connection
+func TestDeleteByPId_Success(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
 
-	QId := 1
-	answer := "New answer"
+	repo := repositories.NewMySQLQuestionRepository(db)
 
 	// Set up mock expectations
-	mock.ExpectExec("UPDATE questions SET replies= JSON_ARRAY_APPEND").
-		WithArgs(answer, QId).
-		WillReturnResult(sqlmock.NewResult(1, 1)) // Simulate a successful update with 1 affected row
-
-	// Initialize repository
-	repo := repositories.NewMySQLQuestionRepository(db)
+	PId := 1
+	mock.ExpectExec("^DELETE FROM questions WHERE post_id = \\?$").
+		WithArgs(PId).
+		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	// Call the method
-	err = repo.UpdateQuestion(QId, answer)
+	err = repo.DeleteByPId(PId)
 
-	// Assert
+	// Assert results
 	assert.NoError(t, err)
-	mock.ExpectationsWereMet()
 }
 
-func TestUpdateQuestion_NoRowsAffected(t *testing.T) {
-	// Create a new mock database connection
+func TestDeleteByPId_NoRowsAffected(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
 
-	QId := 1
-	answer := "New answer"
-
-	// Set up mock expectations
-	mock.ExpectExec("UPDATE questions SET replies= JSON_ARRAY_APPEND").
-		WithArgs(answer, QId).
-		WillReturnResult(sqlmock.NewResult(1, 0)) // Simulate no rows affected
-
-	// Initialize repositories
 	repo := repositories.NewMySQLQuestionRepository(db)
 
-	// Call the method
-	err = repo.UpdateQuestion(QId, answer)
-
-	// Assert
-	assert.Error(t, err)
-	assert.Equal(t, config.Red+"No Question exist with this id"+config.Reset, err.Error())
-	mock.ExpectationsWereMet()
-}
-
-func TestUpdateQuestion_QueryError(t *testing.T) {
-	// Create a new mock database connection
-	db, mock, err := sqlmock.New()
-	assert.NoError(t, err)
-	defer db.Close()
-
-	QId := 1
-	answer := "New answer"
-
 	// Set up mock expectations
-	mock.ExpectExec("UPDATE questions SET replies= JSON_ARRAY_APPEND").
-		WithArgs(answer, QId).
-		WillReturnError(errors.New("query error")) // Simulate a query error
-
-	// Initialize repositories
-	repo := repositories.NewMySQLQuestionRepository(db)
+	PId := 1
+	mock.ExpectExec("DELETE FROM questions WHERE post_id = ?").
+		WithArgs(PId).
+		WillReturnResult(sqlmock.NewResult(1, 0))
 
 	// Call the method
-	err = repo.UpdateQuestion(QId, answer)
+	err = repo.DeleteByPId(PId)
 
-	// Assert
-	assert.Error(t, err)
-	assert.Equal(t, "query error", err.Error())
-	mock.ExpectationsWereMet()
+	// A post without questions is not an error
+	assert.NoError(t, err)
 }
 
-func TestDeleteByPId_Success(t *testing.T) {
+func TestDeleteByPostOwner_Success(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
 
 	repo := repositories.NewMySQLQuestionRepository(db)
 
-	// Set up mock expectations
-	PId := 1
-	mock.ExpectExec("^DELETE FROM questions WHERE post_id = \\?$").
-		WithArgs(PId).
-		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("^DELETE FROM questions WHERE post_id IN \\(SELECT post_id FROM posts WHERE user_id = \\?\\)$").
+		WithArgs(3).
+		WillReturnResult(sqlmock.NewResult(0, 2))
 
-	// Call the method
-	err = repo.DeleteByPId(PId)
+	err = repo.DeleteByPostOwner(3)
 
-	// Assert results
 	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestDeleteByPId_NoRowsAffected(t *testing.T) {
+func TestQuestionDeleteByUId_Success(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
 
 	repo := repositories.NewMySQLQuestionRepository(db)
 
-	// Set up mock expectations
-	PId := 1
-	mock.ExpectExec("DELETE FROM questions WHERE post_id = ?").
-		WithArgs(PId).
-		WillReturnResult(sqlmock.NewResult(1, 0))
+	mock.ExpectExec("^DELETE FROM questions WHERE user_id = \\?$").
+		WithArgs(3).
+		WillReturnResult(sqlmock.NewResult(0, 0))
 
-	// Call the method
-	err = repo.DeleteByPId(PId)
+	err = repo.DeleteByUId(3)
 
-	// Assert results
-	assert.EqualError(t, err, config.Red+"No Question exist with this id"+config.Reset)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
 func TestDeleteByPId_Error(t *testing.T) {
@@ -394,12 +351,12 @@ func TestGetQuestionsByPId_Success(t *testing.T) {
 
 	PId := 1
 	createdAt := time.Now()
-	replies := `[{"text":"First reply"}]`
+	replies := `["First reply"]`
 
 	// Set up mock expectations
 	rows := sqlmock.NewRows([]string{"q_id", "post_id", "user_id", "text", "replies", "created_at"}).
 		AddRow(1, PId, 1, "Test question", []byte(replies), createdAt)
-	mock.ExpectQuery("^SELECT q_id, post_id,user_id, text, replies ,created_at FROM questions WHERE post_id = \\?$").
+	mock.ExpectQuery("^SELECT q_id, post_id, user_id, text, replies, created_at FROM questions WHERE post_id = \\?$").
 		WithArgs(PId).
 		WillReturnRows(rows)
 
@@ -425,7 +382,7 @@ func TestGetQuestionsByPId_Error(t *testing.T) {
 	PId := 1
 
 	// Set up mock expectations
-	mock.ExpectQuery("^SELECT q_id, post_id,user_id, text, replies ,created_at FROM questions WHERE post_id = \\?$").
+	mock.ExpectQuery("^SELECT q_id, post_id, user_id, text, replies, created_at FROM questions WHERE post_id = \\?$").
 		WithArgs(PId).
 		WillReturnError(errors.New("some error"))
 
@@ -0,0 +1,74 @@
+package repositories_test
+
+import (
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"localEyes/internal/models"
+	"localEyes/internal/repositories"
+	"testing"
+	"time"
+)
+
+func TestMySQLSessionRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repositories.NewMySQLSessionRepository(db)
+	session := &models.Session{TokenHash: "hash", UserId: 1, CreatedAt: time.Now(), ExpiresAt: time.Unix(1700000000, 0)}
+
+	mock.ExpectExec(`INSERT INTO sessions \(token_hash, user_id, created_at, expires_at\) VALUES \(\?, \?, \?, \?\)`).
+		WithArgs("hash", 1, session.CreatedAt, int64(1700000000)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.Create(session)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMySQLSessionRepository_FindByTokenHash(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repositories.NewMySQLSessionRepository(db)
+
+	rows := sqlmock.NewRows([]string{"token_hash", "user_id", "created_at", "expires_at"}).
+		AddRow("hash", 1, "2024-05-01 10:30:00", int64(1700000000))
+	mock.ExpectQuery(`SELECT token_hash, user_id, created_at, expires_at FROM sessions WHERE token_hash = \?`).
+		WithArgs("hash").
+		WillReturnRows(rows)
+
+	session, err := repo.FindByTokenHash("hash")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, session.UserId)
+	assert.Equal(t, int64(1700000000), session.ExpiresAt.Unix())
+	assert.Equal(t, 2024, session.CreatedAt.Year())
+
+	mock.ExpectQuery("SELECT token_hash").WithArgs("gone").WillReturnError(sql.ErrNoRows)
+	_, err = repo.FindByTokenHash("gone")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMySQLSessionRepository_DeleteExpired(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repositories.NewMySQLSessionRepository(db)
+	now := time.Unix(1700000000, 0)
+
+	mock.ExpectExec(`DELETE FROM sessions WHERE expires_at <= \?`).
+		WithArgs(int64(1700000000)).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	deleted, err := repo.DeleteExpired(now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,748 @@
+package repositories_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"localEyes/config"
+	"localEyes/internal/interfaces"
+	"localEyes/internal/migrations"
+	"localEyes/internal/models"
+	"localEyes/internal/repositories"
+	_ "modernc.org/sqlite"
+)
+
+// newSQLiteDB opens a private in-memory database with every migration applied.
+func newSQLiteDB(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite", "file::memory:")
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	migrator, err := migrations.NewMigrator(db, config.SQLiteDriver)
+	require.NoError(t, err)
+	_, err = migrator.Up()
+	require.NoError(t, err)
+	return db
+}
+
+func TestSQLiteMigrations_DownAndUp(t *testing.T) {
+	db := newSQLiteDB(t)
+	migrator, err := migrations.NewMigrator(db, config.SQLiteDriver)
+	require.NoError(t, err)
+
+	reverted, err := migrator.Down(len(migrator.Migrations()))
+	assert.NoError(t, err)
+	assert.Len(t, reverted, len(migrator.Migrations()))
+
+	ran, err := migrator.Up()
+	assert.NoError(t, err)
+	assert.Len(t, ran, len(migrator.Migrations()))
+
+	statuses, err := migrator.Status()
+	assert.NoError(t, err)
+	for _, status := range statuses {
+		assert.True(t, status.Applied)
+	}
+}
+
+func TestSQLiteUserRepository_CreateAndFind(t *testing.T) {
+	repo := repositories.NewSQLiteUserRepository(newSQLiteDB(t))
+
+	err := repo.Create(&models.User{Username: "riya", Password: "hash", City: "delhi", IsActive: true, DwellingAge: 3, Tag: "resident", Notification: []string{}})
+	require.NoError(t, err)
+
+	user, err := repo.FindByUsername("riya")
+	require.NoError(t, err)
+	assert.Equal(t, 1, user.UId)
+	assert.True(t, user.IsActive)
+	assert.Equal(t, "resident", user.Tag)
+	_, err = repo.FindByUsername("nobody")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	_, err = repo.FindByUId(9)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	users, err := repo.GetAllUsers()
+	assert.NoError(t, err)
+	assert.Len(t, users, 1)
+}
+
+func TestSQLiteNotificationRepository(t *testing.T) {
+	db := newSQLiteDB(t)
+	users := repositories.NewSQLiteUserRepository(db)
+	require.NoError(t, users.Create(&models.User{Username: "author", Password: "hash", City: "delhi", IsActive: true, Tag: "newbie", Notification: []string{}}))
+	require.NoError(t, users.Create(&models.User{Username: "reader", Password: "hash", City: "delhi", IsActive: true, Tag: "newbie", Notification: []string{}}))
+	require.NoError(t, users.Create(&models.User{Username: "faraway", Password: "hash", City: "pune", IsActive: true, Tag: "newbie", Notification: []string{}}))
+	repo := repositories.NewSQLiteNotificationRepository(db)
+
+	require.NoError(t, repo.CreateForPost(&models.Notification{Type: models.NotifyNewPost, SourceType: models.TargetPost, SourceId: 4,
+		Message: "New post: Chaat at CP", CreatedAt: time.Now()}, &models.Post{PostId: 4, UId: 1, City: "delhi", Type: "food"}))
+	admin := &models.Notification{UserId: 2, Type: models.NotifyAdminAction, Message: "An admin made you a moderator", CreatedAt: time.Now()}
+	require.NoError(t, repo.Create(admin))
+	assert.Equal(t, 2, admin.Id)
+
+	for uId, want := range map[int]int{1: 0, 2: 2, 3: 0} {
+		count, err := repo.CountUnread(uId)
+		require.NoError(t, err)
+		assert.Equal(t, want, count, "user %d", uId)
+	}
+	inbox, err := repo.GetUserNotifications(2, false, models.SortOldest, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, inbox, 2)
+	assert.Equal(t, models.TargetPost, inbox[0].SourceType)
+	assert.Equal(t, 4, inbox[0].SourceId)
+	assert.False(t, inbox[0].IsRead)
+	assert.False(t, inbox[0].CreatedAt.IsZero())
+
+	require.NoError(t, repo.MarkRead(3, []int{1}))
+	require.NoError(t, repo.MarkRead(2, []int{1}))
+	unread, err := repo.GetUserNotifications(2, true, models.SortNewest, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, unread, 1)
+	assert.Equal(t, admin.Id, unread[0].Id)
+	found, err := repo.FindByNotificationId(1)
+	require.NoError(t, err)
+	assert.True(t, found.IsRead)
+
+	require.NoError(t, repo.MarkAllRead(2))
+	count, err := repo.CountUnread(2)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	lastId, err := repo.LastId()
+	require.NoError(t, err)
+	assert.Equal(t, admin.Id, lastId)
+	since, err := repo.GetSince(0, 1)
+	require.NoError(t, err)
+	require.Len(t, since, 1)
+	assert.Equal(t, 1, since[0].Id)
+	since, err = repo.GetSince(1, 10)
+	require.NoError(t, err)
+	require.Len(t, since, 1)
+	assert.Equal(t, admin.Id, since[0].Id)
+
+	require.NoError(t, repo.DeleteForUser(2))
+	_, err = repo.FindByNotificationId(1)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	_, err = repo.GetUserNotifications(2, false, "most-liked", 10, 0)
+	assert.Error(t, err)
+}
+
+func TestSQLiteNotificationPrefsRepository(t *testing.T) {
+	db := newSQLiteDB(t)
+	users := repositories.NewSQLiteUserRepository(db)
+	for _, name := range []string{"author", "all", "foodie", "fan", "digest", "quiet"} {
+		require.NoError(t, users.Create(&models.User{Username: name, Password: "hash", City: "delhi", IsActive: true, Tag: "newbie", Notification: []string{}}))
+	}
+	food, err := repositories.NewSQLiteCategoryRepository(db).FindByName("food")
+	require.NoError(t, err)
+	repo := repositories.NewSQLiteNotificationPrefsRepository(db)
+
+	prefs, err := repo.FindByUId(2)
+	require.NoError(t, err)
+	assert.Equal(t, models.DeliverImmediately, prefs.Delivery)
+	assert.Equal(t, models.PostsAll, prefs.Posts)
+	assert.Empty(t, prefs.Subscriptions)
+
+	digestAt := time.Now().Add(-time.Hour)
+	require.NoError(t, repo.Save(&models.NotificationPrefs{UserId: 3, Delivery: models.DeliverImmediately, Posts: models.PostsSubscribed}))
+	require.NoError(t, repo.Save(&models.NotificationPrefs{UserId: 4, Delivery: models.DeliverImmediately, Posts: models.PostsSubscribed}))
+	require.NoError(t, repo.Save(&models.NotificationPrefs{UserId: 5, Delivery: models.DeliverImmediately, Posts: models.PostsAll}))
+	require.NoError(t, repo.Save(&models.NotificationPrefs{UserId: 5, Delivery: models.DeliverDigest, Posts: models.PostsAll, DigestAt: digestAt}))
+	require.NoError(t, repo.Save(&models.NotificationPrefs{UserId: 6, Delivery: models.DeliverOff, Posts: models.PostsAll}))
+	require.NoError(t, repo.Subscribe(&models.Subscription{UserId: 3, Kind: models.TargetCategory, TargetId: food.Id}))
+	require.NoError(t, repo.Subscribe(&models.Subscription{UserId: 4, Kind: models.TargetUser, TargetId: 1}))
+	require.NoError(t, repo.Subscribe(&models.Subscription{UserId: 4, Kind: models.TargetCategory, TargetId: food.Id}))
+	assert.EqualError(t, repo.Subscribe(&models.Subscription{UserId: 4, Kind: models.TargetUser, TargetId: 1}),
+		config.Red+"You are already subscribed to that author"+config.Reset)
+
+	prefs, err = repo.FindByUId(5)
+	require.NoError(t, err)
+	assert.Equal(t, models.DeliverDigest, prefs.Delivery)
+	assert.WithinDuration(t, digestAt, prefs.DigestAt, time.Second)
+	prefs, err = repo.FindByUId(4)
+	require.NoError(t, err)
+	require.Len(t, prefs.Subscriptions, 2)
+	assert.Equal(t, models.Subscription{UserId: 4, Kind: models.TargetCategory, TargetId: food.Id, Name: "food"}, *prefs.Subscriptions[0])
+	assert.Equal(t, models.Subscription{UserId: 4, Kind: models.TargetUser, TargetId: 1, Name: "author"}, *prefs.Subscriptions[1])
+
+	// The fan-out follows the preferences: foodie only hears about food,
+	// digest gets it held and quiet gets nothing.
+	notifications := repositories.NewSQLiteNotificationRepository(db)
+	for _, post := range []*models.Post{{PostId: 1, UId: 1, City: "delhi", Type: "food"}, {PostId: 2, UId: 1, City: "delhi", Type: "travel"}} {
+		require.NoError(t, notifications.CreateForPost(&models.Notification{Type: models.NotifyNewPost, SourceType: models.TargetPost,
+			SourceId: post.PostId, Message: "New post", CreatedAt: time.Now()}, post))
+	}
+	for uId, want := range map[int]int{1: 0, 2: 2, 3: 1, 4: 2, 5: 0, 6: 0} {
+		count, err := notifications.CountUnread(uId)
+		require.NoError(t, err)
+		assert.Equal(t, want, count, "user %d", uId)
+	}
+	held, err := notifications.GetHeld(5)
+	require.NoError(t, err)
+	require.Len(t, held, 2)
+	assert.True(t, held[0].Held)
+	require.NoError(t, notifications.MarkAllRead(5))
+	require.NoError(t, notifications.Release(5))
+	held, err = notifications.GetHeld(5)
+	require.NoError(t, err)
+	assert.Empty(t, held)
+	inbox, err := notifications.GetUserNotifications(5, false, models.SortOldest, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, inbox, 2)
+	assert.True(t, inbox[0].IsRead)
+
+	require.NoError(t, repo.Unsubscribe(4, models.TargetUser, 1))
+	assert.EqualError(t, repo.Unsubscribe(4, models.TargetUser, 1), config.Red+"You are not subscribed to that author"+config.Reset)
+	require.NoError(t, repo.Subscribe(&models.Subscription{UserId: 3, Kind: models.TargetUser, TargetId: 1}))
+	require.NoError(t, repo.DeleteSubscriptionsTo(models.TargetCategory, food.Id))
+	require.NoError(t, repo.DeleteForUser(1))
+	for _, uId := range []int{3, 4} {
+		prefs, err = repo.FindByUId(uId)
+		require.NoError(t, err)
+		assert.Empty(t, prefs.Subscriptions, "user %d", uId)
+		assert.Equal(t, models.PostsSubscribed, prefs.Posts)
+	}
+	require.NoError(t, repo.DeleteForUser(5))
+	prefs, err = repo.FindByUId(5)
+	require.NoError(t, err)
+	assert.Equal(t, models.DeliverImmediately, prefs.Delivery)
+}
+
+func TestSQLitePostRepository_CreateFilterLike(t *testing.T) {
+	repo := repositories.NewSQLitePostRepository(newSQLiteDB(t))
+	require.NoError(t, repo.Create(&models.Post{UId: 1, Title: "Momos", Type: "food", Content: "Lajpat Nagar", CreatedAt: time.Now()}))
+	require.NoError(t, repo.Create(&models.Post{UId: 2, Title: "Metro", Type: "travel", Content: "Yellow line", CreatedAt: time.Now()}))
+
+	posts, err := repo.GetAllPosts()
+	require.NoError(t, err)
+	assert.Len(t, posts, 2)
+	assert.False(t, posts[0].CreatedAt.IsZero())
+
+	food, err := repo.GetPostsByFilter("food")
+	require.NoError(t, err)
+	assert.Len(t, food, 1)
+	assert.Equal(t, "Momos", food[0].Title)
+
+	require.NoError(t, repo.UpdateLike(food[0].PostId))
+	liked, err := repo.GetPostsByPId(food[0].PostId)
+	require.NoError(t, err)
+	assert.Equal(t, 1, liked[0].Likes)
+
+	err = repo.DeleteByUIdPId(2, food[0].PostId)
+	assert.EqualError(t, err, config.Red+"No Post exist with this id"+config.Reset)
+}
+
+func TestSQLitePostRepository_ListPosts(t *testing.T) {
+	db := newSQLiteDB(t)
+	posts := repositories.NewSQLitePostRepository(db)
+	questions := repositories.NewSQLiteQuestionRepository(db)
+	for _, title := range []string{"Momos", "Metro", "Market"} {
+		require.NoError(t, posts.Create(&models.Post{UId: 1, Title: title, Type: "food", CreatedAt: time.Now()}))
+	}
+	require.NoError(t, posts.UpdateLike(1))
+	require.NoError(t, questions.Create(&models.Question{PostId: 2, UserId: 2, Text: "Which line?", CreatedAt: time.Now()}))
+
+	titles := func(order string, limit, offset int) []string {
+		page, err := posts.ListPosts(models.PostFilter{Type: "food"}, order, limit, offset)
+		require.NoError(t, err)
+		var titles []string
+		for _, post := range page {
+			titles = append(titles, post.Title)
+		}
+		return titles
+	}
+	assert.Equal(t, []string{"Market", "Metro"}, titles(models.SortNewest, 2, 0))
+	assert.Equal(t, []string{"Momos"}, titles(models.SortNewest, 2, 2))
+	assert.Equal(t, []string{"Momos", "Market", "Metro"}, titles(models.SortMostLiked, 10, 0))
+	assert.Equal(t, []string{"Metro", "Market", "Momos"}, titles(models.SortMostQuestions, 10, 0))
+}
+
+func TestSQLitePostRepository_Location(t *testing.T) {
+	repo := repositories.NewSQLitePostRepository(newSQLiteDB(t))
+	latitude, longitude := 28.6250, 77.2187
+	require.NoError(t, repo.Create(&models.Post{UId: 1, Title: "Janpath", Type: "food", City: "delhi", CreatedAt: time.Now(),
+		Location: models.Location{Neighbourhood: "connaught place", Latitude: &latitude, Longitude: &longitude}}))
+	require.NoError(t, repo.Create(&models.Post{UId: 1, Title: "Lake", Type: "travel", City: "delhi", CreatedAt: time.Now(),
+		Location: models.Location{Neighbourhood: "hauz khas"}}))
+
+	tagged, err := repo.ListPosts(models.PostFilter{City: "delhi", Neighbourhood: "hauz khas"}, models.SortNewest, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, tagged, 1)
+	assert.Equal(t, "Lake", tagged[0].Title)
+	assert.Nil(t, tagged[0].Latitude)
+
+	near, err := repo.PostsInArea(models.Area{MinLatitude: 28.6, MaxLatitude: 28.7, MinLongitude: 77.2, MaxLongitude: 77.3})
+	require.NoError(t, err)
+	require.Len(t, near, 1)
+	assert.Equal(t, "connaught place", near[0].Neighbourhood)
+	assert.Equal(t, latitude, *near[0].Latitude)
+
+	far, err := repo.PostsInArea(models.Area{MinLatitude: 19, MaxLatitude: 19.2, MinLongitude: 72.8, MaxLongitude: 73})
+	require.NoError(t, err)
+	assert.Empty(t, far)
+}
+
+func TestSQLiteRepositories_Search(t *testing.T) {
+	db := newSQLiteDB(t)
+	posts := repositories.NewSQLitePostRepository(db)
+	questions := repositories.NewSQLiteQuestionRepository(db)
+	answers := repositories.NewSQLiteAnswerRepository(db)
+	require.NoError(t, posts.Create(&models.Post{UId: 1, Title: "Street food", Type: "food", Content: "Momos near the metro", CreatedAt: time.Now()}))
+	require.NoError(t, posts.Create(&models.Post{UId: 1, Title: "Momos", Type: "food", Content: "Lajpat Nagar", CreatedAt: time.Now()}))
+	require.NoError(t, posts.Create(&models.Post{UId: 1, Title: "Sale", Type: "shopping", Content: "50% off", CreatedAt: time.Now()}))
+
+	// A word in the title outranks one in the content.
+	found, err := posts.SearchPosts("", "MOMOS", 10, 0)
+	require.NoError(t, err)
+	require.Len(t, found, 2)
+	assert.Equal(t, "Momos", found[0].Title)
+	assert.Equal(t, "Street food", found[1].Title)
+
+	// LIKE wildcards in the search are matched literally.
+	found, err = posts.SearchPosts("", "0%", 10, 0)
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, "Sale", found[0].Title)
+	found, err = posts.SearchPosts("", "_", 10, 0)
+	require.NoError(t, err)
+	assert.Empty(t, found)
+
+	require.NoError(t, questions.Create(&models.Question{PostId: 1, UserId: 2, Text: "Open on Sunday?", CreatedAt: time.Now()}))
+	require.NoError(t, questions.Create(&models.Question{PostId: 2, UserId: 2, Text: "Are they steamed?", CreatedAt: time.Now()}))
+	require.NoError(t, answers.Create(&models.Answer{QId: 1, UserId: 1, Text: "Yes, steamed and fried", CreatedAt: time.Now()}))
+
+	asked, err := questions.SearchQuestions("steamed", 10, 0)
+	require.NoError(t, err)
+	require.Len(t, asked, 2)
+	assert.Equal(t, 2, asked[0].QId)
+	assert.Equal(t, 1, asked[1].QId)
+
+	asked, err = questions.SearchQuestions("fried", 10, 0)
+	require.NoError(t, err)
+	require.Len(t, asked, 1)
+	assert.Equal(t, 1, asked[0].QId)
+}
+
+func TestSQLiteQuestionRepository_FindByQId(t *testing.T) {
+	repo := repositories.NewSQLiteQuestionRepository(newSQLiteDB(t))
+	require.NoError(t, repo.Create(&models.Question{PostId: 1, UserId: 1, Text: "Best time to visit?", CreatedAt: time.Now()}))
+
+	questions, err := repo.GetQuestionsByPId(1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{}, questions[0].Replies)
+
+	question, err := repo.FindByQId(1)
+	require.NoError(t, err)
+	assert.Equal(t, "Best time to visit?", question.Text)
+	assert.False(t, question.CreatedAt.IsZero())
+	_, err = repo.FindByQId(42)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestSQLiteAnswerRepository(t *testing.T) {
+	db := newSQLiteDB(t)
+	users := repositories.NewSQLiteUserRepository(db)
+	questions := repositories.NewSQLiteQuestionRepository(db)
+	repo := repositories.NewSQLiteAnswerRepository(db)
+	require.NoError(t, users.Create(&models.User{Username: "local", Password: "hash", City: "delhi", IsActive: true, Tag: "newbie", Notification: []string{}}))
+	require.NoError(t, questions.Create(&models.Question{PostId: 1, UserId: 2, Text: "Best time to visit?", Replies: []string{}, CreatedAt: time.Now()}))
+
+	answer := &models.Answer{QId: 1, UserId: 1, Text: "Winter", CreatedAt: time.Now()}
+	require.NoError(t, repo.Create(answer))
+	assert.Equal(t, 1, answer.AnswerId)
+	err := repo.Create(&models.Answer{QId: 42, UserId: 1, Text: "nobody", CreatedAt: time.Now()})
+	assert.EqualError(t, err, config.Red+"No Question exist with this id"+config.Reset)
+
+	require.NoError(t, repo.UpdateUserAnswer(1, 1, "Winter mornings"))
+	answers, err := repo.GetAnswersByPId(1)
+	require.NoError(t, err)
+	require.Len(t, answers, 1)
+	assert.Equal(t, "local", answers[0].Username)
+	assert.Equal(t, "Winter mornings", answers[0].Text)
+	assert.False(t, answers[0].UpdatedAt.IsZero())
+	found, err := repo.FindByAnswerId(1)
+	require.NoError(t, err)
+	assert.Equal(t, "Winter mornings", found.Text)
+	_, err = repo.FindByAnswerId(9)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	require.NoError(t, repo.DeleteForUser(2)) // the asker; their question's answers go too
+	answers, err = repo.GetAllAnswers()
+	require.NoError(t, err)
+	assert.Empty(t, answers)
+}
+
+func TestSQLiteMigrations_CopyRepliesIntoAnswers(t *testing.T) {
+	db := newSQLiteDB(t)
+	migrator, err := migrations.NewMigrator(db, config.SQLiteDriver)
+	require.NoError(t, err)
+	// Step back to just before 0004_create_answers.
+	_, err = migrator.Down(len(migrator.Migrations()) - 3)
+	require.NoError(t, err)
+
+	questions := repositories.NewSQLiteQuestionRepository(db)
+	require.NoError(t, questions.Create(&models.Question{PostId: 1, UserId: 1, Text: "Best time?", Replies: []string{"Winter", "Evenings"}, CreatedAt: time.Now()}))
+	_, err = migrator.Up()
+	require.NoError(t, err)
+
+	answers, err := repositories.NewSQLiteAnswerRepository(db).GetAnswersByQId(1)
+	require.NoError(t, err)
+	require.Len(t, answers, 2)
+	assert.Equal(t, "Winter", answers[0].Text)
+	assert.Equal(t, "Evenings", answers[1].Text)
+	assert.Equal(t, 0, answers[0].UserId)
+	assert.False(t, answers[0].CreatedAt.IsZero())
+}
+
+func TestSQLiteMigrations_MoveNotificationsIntoInbox(t *testing.T) {
+	db := newSQLiteDB(t)
+	migrator, err := migrations.NewMigrator(db, config.SQLiteDriver)
+	require.NoError(t, err)
+	// Step back to just before 0014_create_notifications.
+	_, err = migrator.Down(len(migrator.Migrations()) - 13)
+	require.NoError(t, err)
+
+	users := repositories.NewSQLiteUserRepository(db)
+	require.NoError(t, users.Create(&models.User{Username: "reader", Password: "hash", City: "delhi", IsActive: true, Tag: "newbie",
+		Notification: []string{"New post: Chaat at CP\n", "New post: Metro\n"}}))
+	_, err = migrator.Up()
+	require.NoError(t, err)
+
+	inbox, err := repositories.NewSQLiteNotificationRepository(db).GetUserNotifications(1, true, models.SortOldest, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, inbox, 2)
+	assert.Equal(t, "New post: Chaat at CP", inbox[0].Message)
+	assert.Equal(t, models.NotifyNewPost, inbox[0].Type)
+	assert.Equal(t, "New post: Metro", inbox[1].Message)
+	reader, err := users.FindByUId(1)
+	require.NoError(t, err)
+	assert.Empty(t, reader.Notification)
+
+	// Going back down hands the unread ones back to the column.
+	require.NoError(t, repositories.NewSQLiteNotificationRepository(db).MarkRead(1, []int{inbox[1].Id}))
+	_, err = migrator.Down(len(migrator.Migrations()) - 13)
+	require.NoError(t, err)
+	reader, err = users.FindByUId(1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"New post: Chaat at CP\n"}, reader.Notification)
+}
+
+func TestSQLitePostLikeRepository(t *testing.T) {
+	db := newSQLiteDB(t)
+	posts := repositories.NewSQLitePostRepository(db)
+	repo := repositories.NewSQLitePostLikeRepository(db)
+	require.NoError(t, posts.Create(&models.Post{UId: 1, Title: "Momos", Type: "food", CreatedAt: time.Now()}))
+	require.NoError(t, posts.Create(&models.Post{UId: 2, Title: "Metro", Type: "travel", CreatedAt: time.Now()}))
+
+	require.NoError(t, repo.Create(2, 1))
+	require.NoError(t, repo.Create(1, 2))
+	assert.EqualError(t, repo.Create(2, 1), config.Red+"You have already liked this post"+config.Reset)
+
+	liked, err := repo.GetLikedPIds(2)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1}, liked)
+
+	require.NoError(t, posts.UpdateLike(1))
+	require.NoError(t, posts.RemoveUserLikes(2))
+	momos, err := posts.GetPostsByPId(1)
+	require.NoError(t, err)
+	assert.Equal(t, 0, momos[0].Likes)
+	assert.EqualError(t, posts.RemoveLike(1), config.Red+"No post exist with this id"+config.Reset)
+
+	require.NoError(t, repo.Delete(2, 1))
+	assert.EqualError(t, repo.Delete(2, 1), config.Red+"You have not liked this post"+config.Reset)
+
+	require.NoError(t, repo.DeleteForUser(2)) // removes user 1's like on user 2's post
+	liked, err = repo.GetLikedPIds(1)
+	require.NoError(t, err)
+	assert.Empty(t, liked)
+}
+
+func TestSQLiteSessionRepository(t *testing.T) {
+	repo := repositories.NewSQLiteSessionRepository(newSQLiteDB(t))
+	now := time.Now()
+	require.NoError(t, repo.Create(&models.Session{TokenHash: "live", UserId: 1, CreatedAt: now, ExpiresAt: now.Add(time.Hour)}))
+	require.NoError(t, repo.Create(&models.Session{TokenHash: "dead", UserId: 1, CreatedAt: now, ExpiresAt: now.Add(-time.Hour)}))
+	require.NoError(t, repo.Create(&models.Session{TokenHash: "other", UserId: 2, CreatedAt: now, ExpiresAt: now.Add(time.Hour)}))
+
+	session, err := repo.FindByTokenHash("live")
+	require.NoError(t, err)
+	assert.Equal(t, 1, session.UserId)
+	assert.Equal(t, now.Add(time.Hour).Unix(), session.ExpiresAt.Unix())
+	assert.WithinDuration(t, now, session.CreatedAt, time.Second)
+
+	deleted, err := repo.DeleteExpired(now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+	_, err = repo.FindByTokenHash("dead")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	require.NoError(t, repo.DeleteForUser(1))
+	_, err = repo.FindByTokenHash("live")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	require.NoError(t, repo.DeleteByTokenHash("other"))
+	require.NoError(t, repo.DeleteByTokenHash("other"))
+}
+
+func TestSQLiteOutboxRepository(t *testing.T) {
+	repo := repositories.NewSQLiteOutboxRepository(newSQLiteDB(t))
+	now := time.Now()
+	require.NoError(t, repo.Create(&models.OutboxEntry{PostId: 1, AvailableAt: now, CreatedAt: now}))
+	require.NoError(t, repo.Create(&models.OutboxEntry{PostId: 2, AvailableAt: now, CreatedAt: now}))
+	require.NoError(t, repo.Create(&models.OutboxEntry{PostId: 3, AvailableAt: now.Add(time.Hour), CreatedAt: now}))
+
+	due, err := repo.GetDue(now, 10)
+	require.NoError(t, err)
+	require.Len(t, due, 2)
+	assert.Equal(t, []int{1, 2}, []int{due[0].PostId, due[1].PostId})
+	due, err = repo.GetDue(now, 1)
+	require.NoError(t, err)
+	assert.Len(t, due, 1)
+
+	// The first claim wins and hides the entry; a stale copy loses.
+	stale := *due[0]
+	ok, err := repo.Claim(due[0], now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = repo.Claim(&stale, now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.False(t, ok)
+	due, err = repo.GetDue(now, 10)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	assert.Equal(t, 2, due[0].PostId)
+
+	require.NoError(t, repo.Retry(1, now, "database is locked"))
+	require.NoError(t, repo.MarkDead(2, "post vanished"))
+	entry, err := repo.FindById(1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, entry.Attempts)
+	assert.Equal(t, "database is locked", entry.LastError)
+	assert.WithinDuration(t, now, entry.CreatedAt, time.Second)
+	entry, err = repo.FindById(2)
+	require.NoError(t, err)
+	assert.True(t, entry.Dead)
+	due, err = repo.GetDue(now.Add(2*time.Hour), 10)
+	require.NoError(t, err)
+	require.Len(t, due, 2)
+	assert.Equal(t, []int{1, 3}, []int{due[0].PostId, due[1].PostId})
+
+	// Only the latest claim completes the entry.
+	ok, err = repo.Complete(&models.OutboxEntry{Id: 1, Attempts: 0})
+	require.NoError(t, err)
+	assert.False(t, ok)
+	entry, err = repo.FindById(1)
+	require.NoError(t, err)
+	ok, err = repo.Complete(entry)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = repo.Complete(entry)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	_, err = repo.FindById(1)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestSQLiteAuditRepository(t *testing.T) {
+	db := newSQLiteDB(t)
+	repo := repositories.NewSQLiteAuditRepository(db)
+	day := time.Date(2024, 5, 1, 10, 0, 0, 0, time.Local)
+	require.NoError(t, repo.Create(&models.AuditEntry{ActorId: 1, ActorName: "admin", Action: models.AuditDeletePost, TargetType: models.TargetPost, TargetId: 4, Before: `{"id":4}`, CreatedAt: day}))
+	require.NoError(t, repo.Create(&models.AuditEntry{ActorId: 2, ActorName: "riya", Action: models.AuditDeactivateUser, TargetType: models.TargetUser, TargetId: 2, CreatedAt: day.Add(48 * time.Hour)}))
+
+	entries, err := repo.Find(models.AuditFilter{From: day, To: day.Add(24 * time.Hour)})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, `{"id":4}`, entries[0].Before)
+	assert.Equal(t, day.Unix(), entries[0].CreatedAt.Unix())
+
+	entries, err = repo.Find(models.AuditFilter{TargetType: models.TargetUser, TargetId: 2})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "riya", entries[0].ActorName)
+
+	// The triggers keep the log append-only.
+	_, err = db.Exec("UPDATE audit_log SET actor_name = 'someone else'")
+	assert.ErrorContains(t, err, "append-only")
+	_, err = db.Exec("DELETE FROM audit_log")
+	assert.ErrorContains(t, err, "append-only")
+}
+
+func TestSQLiteCategoryRepository(t *testing.T) {
+	db := newSQLiteDB(t)
+	repo := repositories.NewSQLiteCategoryRepository(db)
+
+	// The migration seeds the four categories that used to be hardcoded.
+	categories, err := repo.GetAll()
+	require.NoError(t, err)
+	var names []string
+	for _, category := range categories {
+		names = append(names, category.Name)
+		assert.True(t, category.IsActive)
+	}
+	assert.Equal(t, []string{"food", "other", "shopping", "travel"}, names)
+
+	healthcare := &models.Category{Name: "healthcare", Description: "Clinics", IsActive: true}
+	require.NoError(t, repo.Create(healthcare))
+	assert.NotZero(t, healthcare.Id)
+	assert.Error(t, repo.Create(&models.Category{Name: "healthcare"}))
+
+	healthcare.IsActive = false
+	require.NoError(t, repo.Update(healthcare))
+	found, err := repo.FindById(healthcare.Id)
+	require.NoError(t, err)
+	assert.Equal(t, healthcare, found)
+
+	require.NoError(t, repo.DeleteById(healthcare.Id))
+	_, err = repo.FindByName("healthcare")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.Error(t, repo.DeleteById(healthcare.Id))
+
+	posts := repositories.NewSQLitePostRepository(db)
+	require.NoError(t, posts.Create(&models.Post{UId: 1, Title: "Fair", Type: "fairs", Content: "c", CreatedAt: time.Now()}))
+	require.NoError(t, posts.UpdateType("fairs", "events"))
+	moved, err := posts.GetPostsByFilter("events")
+	require.NoError(t, err)
+	assert.Len(t, moved, 1)
+}
+
+func TestSQLiteCityRepository(t *testing.T) {
+	db := newSQLiteDB(t)
+	repo := repositories.NewSQLiteCityRepository(db)
+
+	// The migration seeds delhi, the only city LocalEyes used to allow.
+	cities, err := repo.GetAll()
+	require.NoError(t, err)
+	require.Len(t, cities, 1)
+	assert.Equal(t, repositories.DefaultCity, cities[0].Name)
+
+	pune := &models.City{Name: "pune"}
+	require.NoError(t, repo.Create(pune))
+	assert.NotZero(t, pune.Id)
+	assert.Error(t, repo.Create(&models.City{Name: "pune"}))
+	found, err := repo.FindByName("pune")
+	require.NoError(t, err)
+	assert.Equal(t, pune, found)
+	_, err = repo.FindByName("goa")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	users := repositories.NewSQLiteUserRepository(db)
+	require.NoError(t, users.Create(&models.User{Username: "riya", Password: "hash", City: "delhi", IsActive: true, Notification: []string{}}))
+	require.NoError(t, users.UpdateCity(1, "pune"))
+	riya, err := users.FindByUId(1)
+	require.NoError(t, err)
+	assert.Equal(t, "pune", riya.City)
+	assert.Error(t, users.UpdateCity(9, "pune"))
+
+	posts := repositories.NewSQLitePostRepository(db)
+	require.NoError(t, posts.Create(&models.Post{UId: 1, Title: "Momos", Type: "food", Content: "c", City: "delhi", CreatedAt: time.Now()}))
+	require.NoError(t, posts.Create(&models.Post{UId: 1, Title: "Misal", Type: "food", Content: "c", City: "pune", CreatedAt: time.Now()}))
+	inPune, err := posts.ListPosts(models.PostFilter{City: "pune"}, models.SortNewest, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, inPune, 1)
+	assert.Equal(t, "Misal", inPune[0].Title)
+	assert.Equal(t, "pune", inPune[0].City)
+	elsewhere, err := posts.SearchPosts("delhi", "misal", 10, 0)
+	require.NoError(t, err)
+	assert.Empty(t, elsewhere)
+
+	questions := repositories.NewSQLiteQuestionRepository(db)
+	require.NoError(t, questions.Create(&models.Question{PostId: 1, UserId: 1, Text: "Where?", CreatedAt: time.Now()}))
+	require.NoError(t, questions.Create(&models.Question{PostId: 2, UserId: 1, Text: "Spicy?", CreatedAt: time.Now()}))
+	asked, err := questions.ListQuestions("pune", models.SortNewest, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, asked, 1)
+	assert.Equal(t, "Spicy?", asked[0].Text)
+}
+
+func TestSQLiteCommentRepository(t *testing.T) {
+	db := newSQLiteDB(t)
+	users := repositories.NewSQLiteUserRepository(db)
+	posts := repositories.NewSQLitePostRepository(db)
+	repo := repositories.NewSQLiteCommentRepository(db)
+	require.NoError(t, users.Create(&models.User{Username: "author", Password: "hash", City: "delhi", IsActive: true, Notification: []string{}}))
+	require.NoError(t, users.Create(&models.User{Username: "reader", Password: "hash", City: "delhi", IsActive: true, Notification: []string{}}))
+	require.NoError(t, posts.Create(&models.Post{UId: 1, Title: "Momos", Type: "food", Content: "c", City: "delhi", CreatedAt: time.Now()}))
+	require.NoError(t, posts.Create(&models.Post{UId: 2, Title: "Metro", Type: "travel", Content: "c", City: "delhi", CreatedAt: time.Now()}))
+
+	top := &models.Comment{PostId: 2, UserId: 1, Text: "Crowded at 9", CreatedAt: time.Now()}
+	require.NoError(t, repo.Create(top))
+	reply := &models.Comment{PostId: 2, ParentId: top.CommentId, UserId: 2, Text: "Try 10", CreatedAt: time.Now()}
+	require.NoError(t, repo.Create(reply))
+	require.NoError(t, repo.Create(&models.Comment{PostId: 2, UserId: 1, Text: "Cards work", CreatedAt: time.Now()}))
+	require.NoError(t, repo.Create(&models.Comment{PostId: 1, UserId: 2, Text: "Which stall?", CreatedAt: time.Now()}))
+	err := repo.Create(&models.Comment{PostId: 42, UserId: 1, Text: "lost", CreatedAt: time.Now()})
+	assert.EqualError(t, err, config.Red+"No Post exist with this id"+config.Reset)
+
+	require.NoError(t, repo.UpdateUserComment(reply.CommentId, 2, "Try 10:30"))
+	assert.Error(t, repo.UpdateUserComment(reply.CommentId, 1, "mine now"))
+	comments, err := repo.GetCommentsByPId(2)
+	require.NoError(t, err)
+	require.Len(t, comments, 3)
+	assert.Equal(t, "author", comments[0].Username)
+	assert.Equal(t, top.CommentId, comments[1].ParentId)
+	assert.Equal(t, "Try 10:30", comments[1].Text)
+	assert.False(t, comments[1].UpdatedAt.IsZero())
+	replies, err := repo.CountReplies(top.CommentId)
+	require.NoError(t, err)
+	assert.Equal(t, 1, replies)
+
+	// The author's post loses its discussion, their replied-to comment stays
+	// as a placeholder and their other comment goes.
+	require.NoError(t, repo.DeleteForUser(1))
+	comments, err = repo.GetCommentsByPId(1)
+	require.NoError(t, err)
+	assert.Empty(t, comments)
+	comments, err = repo.GetCommentsByPId(2)
+	require.NoError(t, err)
+	require.Len(t, comments, 2)
+	assert.True(t, comments[0].Deleted)
+	assert.Empty(t, comments[0].Text)
+	assert.Error(t, repo.UpdateUserComment(top.CommentId, 1, "back"))
+
+	require.NoError(t, repo.DeleteByCommentId(reply.CommentId))
+	assert.Error(t, repo.MarkDeleted(reply.CommentId))
+	_, err = repo.FindByCommentId(reply.CommentId)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	require.NoError(t, repo.DeleteByPId(2))
+	comments, err = repo.GetCommentsByPId(2)
+	require.NoError(t, err)
+	assert.Empty(t, comments)
+}
+
+func TestSQLUnitOfWork_CommitsAndRollsBack(t *testing.T) {
+	db := newSQLiteDB(t)
+	repos := repositories.NewSQLRepositories(config.SQLiteDriver, db)
+	uow := repositories.NewSQLUnitOfWork(db, config.SQLiteDriver)
+	require.NoError(t, repos.Posts.Create(&models.Post{UId: 1, Title: "Momos", Type: "food", CreatedAt: time.Now()}))
+	require.NoError(t, repos.Questions.Create(&models.Question{PostId: 1, UserId: 2, Text: "Where?", Replies: []string{}, CreatedAt: time.Now()}))
+
+	err := uow.Do(func(tx interfaces.Repositories) error {
+		if err := tx.Questions.DeleteByPostOwner(1); err != nil {
+			return err
+		}
+		return tx.Users.DeleteByUId(1) // no such user, so everything rolls back
+	})
+	assert.EqualError(t, err, config.Red+"No user exist with this id"+config.Reset)
+	questions, err := repos.Questions.GetAllQuestions()
+	require.NoError(t, err)
+	assert.Len(t, questions, 1)
+
+	err = uow.Do(func(tx interfaces.Repositories) error {
+		if err := tx.Questions.DeleteByPostOwner(1); err != nil {
+			return err
+		}
+		return tx.Posts.DeleteByUId(1)
+	})
+	require.NoError(t, err)
+	questions, err = repos.Questions.GetAllQuestions()
+	require.NoError(t, err)
+	assert.Empty(t, questions)
+	posts, err := repos.Posts.GetAllPosts()
+	require.NoError(t, err)
+	assert.Empty(t, posts)
+}
@@ -1,13 +1,13 @@
 package repositories_test
 
 import (
-	"database/sql"
 	"encoding/json"
 	"errors"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
+	"localEyes/config"
 	"localEyes/internal/models"
 	"localEyes/internal/repositories"
 )
@@ -39,7 +39,7 @@ func TestMySQLUserRepository_Create(t *testing.T) {
 
 	// Expect the insert query
 	mock.ExpectExec("INSERT INTO users").
-		WithArgs(user.Username, user.Password, user.IsActive, user.City, user.DwellingAge, user.Tag, notification).
+		WithArgs(user.Username, user.Password, user.IsActive, user.City, user.DwellingAge, user.Tag, notification, models.RoleUser).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	// Call the Create method
@@ -70,6 +70,7 @@ func TestMySQLUserRepository_FindByUId(t *testing.T) {
 		City:        "New York",
 		DwellingAge: 5,
 		Tag:         "tag1",
+		Role:        models.RoleModerator,
 		Notification: []string{
 			"Welcome to LocalEyes",
 		},
@@ -77,10 +78,10 @@ func TestMySQLUserRepository_FindByUId(t *testing.T) {
 	notification, _ := json.Marshal(user.Notification)
 
 	// Expect the select query
-	mock.ExpectQuery("SELECT id, username, password, is_active, city, dwelling_age, tag, notification FROM users WHERE id = ?").
+	mock.ExpectQuery("SELECT id, username, password, is_active, city, dwelling_age, tag, notification, role FROM users WHERE id = ?").
 		WithArgs(1).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "username", "password", "is_active", "city", "dwelling_age", "tag", "notification"}).
-			AddRow(user.UId, user.Username, user.Password, user.IsActive, user.City, user.DwellingAge, user.Tag, notification))
+		WillReturnRows(sqlmock.NewRows([]string{"id", "username", "password", "is_active", "city", "dwelling_age", "tag", "notification", "role"}).
+			AddRow(user.UId, user.Username, user.Password, user.IsActive, user.City, user.DwellingAge, user.Tag, notification, user.Role))
 
 	// Call the FindByUId method
 	result, err := repo.FindByUId(1)
@@ -114,6 +115,7 @@ func TestMySQLUserRepository_FindByUsername(t *testing.T) {
 		City:        "New York",
 		DwellingAge: 5,
 		Tag:         "tag1",
+		Role:        models.RoleModerator,
 		Notification: []string{
 			"Welcome to LocalEyes",
 		},
@@ -121,10 +123,10 @@ func TestMySQLUserRepository_FindByUsername(t *testing.T) {
 	notification, _ := json.Marshal(user.Notification)
 
 	// Expect the select query
-	mock.ExpectQuery("SELECT id, username, password, is_active, city, dwelling_age, tag, notification FROM users WHERE username = ?").
+	mock.ExpectQuery("SELECT id, username, password, is_active, city, dwelling_age, tag, notification, role FROM users WHERE username = ?").
 		WithArgs("test_user").
-		WillReturnRows(sqlmock.NewRows([]string{"id", "username", "password", "is_active", "city", "dwelling_age", "tag", "notification"}).
-			AddRow(user.UId, user.Username, user.Password, user.IsActive, user.City, user.DwellingAge, user.Tag, notification))
+		WillReturnRows(sqlmock.NewRows([]string{"id", "username", "password", "is_active", "city", "dwelling_age", "tag", "notification", "role"}).
+			AddRow(user.UId, user.Username, user.Password, user.IsActive, user.City, user.DwellingAge, user.Tag, notification, user.Role))
 
 	// Call the FindByUsername method
 	result, err := repo.FindByUsername("test_user")
@@ -170,169 +172,35 @@ func TestMySQLUserRepository_UpdateActiveStatus(t *testing.T) {
 	}
 }
 
-func TestMySQLUserRepository_PushNotification(t *testing.T) {
-	// Create a mock database connection
+func TestUpdatePassword_Success(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
 
-	// Create a repository with the mock DB
-	repo := repositories.NewMySQLUserRepository(db)
-
-	// Expect the update query
-	mock.ExpectExec("UPDATE users SET notification= JSON_ARRAY_APPEND").
-		WithArgs("New post: Test Post\n", 1).
-		WillReturnResult(sqlmock.NewResult(1, 1))
-
-	// Call the PushNotification method
-	err = repo.PushNotification(1, "Test Post")
-
-	// Assert no error was returned
-	assert.NoError(t, err)
-
-	// Ensure all expectations were met
-	assert.NoError(t, mock.ExpectationsWereMet())
-}
+	mock.ExpectExec("^UPDATE users SET password = \\? WHERE id = \\?$").
+		WithArgs("$2a$10$newhash", 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
 
-func TestMySQLUserRepository_ClearNotification(t *testing.T) {
-	// Create a mock database connection
-	db, mock, err := sqlmock.New()
-	assert.NoError(t, err)
-	defer db.Close()
-
-	// Create a repository with the mock DB
 	repo := repositories.NewMySQLUserRepository(db)
+	err = repo.UpdatePassword(1, "$2a$10$newhash")
 
-	// Expect the update query to clear notifications (set notification to an empty array)
-	mock.ExpectExec("UPDATE users SET notification = '\\[\\]' WHERE id = \\?").
-		WithArgs(1).
-		WillReturnResult(sqlmock.NewResult(1, 1))
-
-	// Call the ClearNotification method
-	err = repo.ClearNotification(1)
-
-	// Assert no error was returned
 	assert.NoError(t, err)
-
-	// Ensure all expectations were met
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestFindByUsernamePassword_Success(t *testing.T) {
-	db, mock, err := sqlmock.New()
-	if err != nil {
-		t.Fatalf("failed to create sqlmock instance: %v", err)
-	}
-	defer db.Close()
-
-	repo := repositories.NewMySQLUserRepository(db)
-
-	// Define the expected results
-	notification := json.RawMessage(`{"email":"example@example.com"}`)
-	rows := sqlmock.NewRows([]string{"id", "username", "password", "is_active", "city", "dwelling_age", "tag", "notification"}).
-		AddRow(1, "testuser", "testpass", true, "New York", 5, "Admin", notification)
-
-	// Set the expectation for the query
-	mock.ExpectQuery("SELECT id, username, password, is_active, city, dwelling_age, tag, notification FROM users WHERE username = \\? AND password = \\?").
-		WithArgs("testuser", "testpass").
-		WillReturnRows(rows)
-
-	// Call the method to be tested
-	user, err := repo.FindByUsernamePassword("testuser", "testpass")
-	assert.NoError(t, err)
-	assert.NotNil(t, user)
-	assert.Equal(t, 1, user.UId)
-	assert.Equal(t, "testuser", user.Username)
-	assert.Equal(t, "testpass", user.Password)
-	assert.True(t, user.IsActive)
-	assert.Equal(t, "New York", user.City)
-	assert.Equal(t, 5, user.DwellingAge)
-	assert.Equal(t, "Admin", user.Tag)
-	//assert.Equal(t, `{"email":"example@example.com"}`,(user.Notification))
-}
-
-func TestFindByUsernamePassword_NotFound(t *testing.T) {
-	db, mock, err := sqlmock.New()
-	if err != nil {
-		t.Fatalf("failed to create sqlmock instance: %v", err)
-	}
-	defer db.Close()
-
-	repo := repositories.NewMySQLUserRepository(db)
-
-	// Set the expectation for the query
-	mock.ExpectQuery("SELECT id, username, password, is_active, city, dwelling_age, tag, notification FROM users WHERE username = \\? AND password = \\?").
-		WithArgs("nonexistentuser", "wrongpass").
-		WillReturnError(sql.ErrNoRows)
-
-	// Call the method to be tested
-	_, err = repo.FindByUsernamePassword("nonexistentuser", "wrongpass")
-	assert.Error(t, err)
-	//assert.Nil(t, user)
-}
-
-func TestFindByUsernamePassword_Error(t *testing.T) {
-	db, mock, err := sqlmock.New()
-	if err != nil {
-		t.Fatalf("failed to create sqlmock instance: %v", err)
-	}
-	defer db.Close()
-
-	repo := repositories.NewMySQLUserRepository(db)
-
-	// Set the expectation for the query
-	mock.ExpectQuery("SELECT id, username, password, is_active, city, dwelling_age, tag, notification FROM users WHERE username = \\? AND password = \\?").
-		WithArgs("testuser", "testpass").
-		WillReturnError(sql.ErrConnDone) // Simulate a connection error
-
-	// Call the method to be tested
-	_, err = repo.FindByUsernamePassword("testuser", "testpass")
-	assert.Error(t, err)
-	//assert.Nil(t, user)
-}
-
-func TestFindAdminByUsernamePassword_Success(t *testing.T) {
+func TestUpdatePassword_NoRowsAffected(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
 
-	username := "admin"
-	password := "password123"
-
-	rows := sqlmock.NewRows([]string{"id", "username", "password"}).
-		AddRow(1, username, password)
-
-	mock.ExpectQuery("^SELECT id, username, password FROM users WHERE username = \\? AND password = \\?$").
-		WithArgs(username, password).
-		WillReturnRows(rows)
+	mock.ExpectExec("^UPDATE users SET password = \\? WHERE id = \\?$").
+		WithArgs("$2a$10$newhash", 9).
+		WillReturnResult(sqlmock.NewResult(0, 0))
 
 	repo := repositories.NewMySQLUserRepository(db)
-	admin, err := repo.FindAdminByUsernamePassword(username, password)
+	err = repo.UpdatePassword(9, "$2a$10$newhash")
 
-	assert.NoError(t, err)
-	assert.NotNil(t, admin)
-	assert.Equal(t, 1, admin.User.UId)
-	assert.Equal(t, username, admin.User.Username)
-	assert.Equal(t, password, admin.User.Password)
-}
-
-func TestFindAdminByUsernamePassword_Error(t *testing.T) {
-	db, mock, err := sqlmock.New()
-	assert.NoError(t, err)
-	defer db.Close()
-
-	username := "admin"
-	password := "wrongpassword"
-
-	mock.ExpectQuery("^SELECT id, username, password FROM users WHERE username = \\? AND password = \\?$").
-		WithArgs(username, password).
-		WillReturnError(sql.ErrNoRows)
-
-	repo := repositories.NewMySQLUserRepository(db)
-	_, err = repo.FindAdminByUsernamePassword(username, password)
-
-	assert.Error(t, err)
-	assert.Equal(t, sql.ErrNoRows, err)
+	assert.EqualError(t, err, config.Red+"No user exist with this id"+config.Reset)
 }
 
 func TestGetAllUsers_Success(t *testing.T) {
@@ -340,11 +208,11 @@ func TestGetAllUsers_Success(t *testing.T) {
 	assert.NoError(t, err)
 	defer db.Close()
 
-	rows := sqlmock.NewRows([]string{"id", "username", "password", "is_active", "city", "dwelling_age", "tag", "notification"}).
-		AddRow(1, "user1", "pass1", true, "CityA", 5, "Tag1", `["notif1"]`).
-		AddRow(2, "user2", "pass2", false, "CityB", 10, "Tag2", `["notif2"]`)
+	rows := sqlmock.NewRows([]string{"id", "username", "password", "is_active", "city", "dwelling_age", "tag", "notification", "role"}).
+		AddRow(1, "user1", "pass1", true, "CityA", 5, "Tag1", `["notif1"]`, "admin").
+		AddRow(2, "user2", "pass2", false, "CityB", 10, "Tag2", `["notif2"]`, "user")
 
-	mock.ExpectQuery("^SELECT id, username, password, is_active, city, dwelling_age, tag, notification FROM users$").
+	mock.ExpectQuery("^SELECT id, username, password, is_active, city, dwelling_age, tag, notification, role FROM users$").
 		WillReturnRows(rows)
 
 	repo := repositories.NewMySQLUserRepository(db)
@@ -355,6 +223,7 @@ func TestGetAllUsers_Success(t *testing.T) {
 	assert.Equal(t, 1, users[0].UId)
 	assert.Equal(t, "user1", users[0].Username)
 	assert.Equal(t, "CityA", users[0].City)
+	assert.Equal(t, models.RoleAdmin, users[0].Role)
 }
 
 func TestGetAllUsers_Error(t *testing.T) {
@@ -362,7 +231,7 @@ func TestGetAllUsers_Error(t *testing.T) {
 	assert.NoError(t, err)
 	defer db.Close()
 
-	mock.ExpectQuery("^SELECT id, username, password, is_active, city, dwelling_age, tag, notification FROM users$").
+	mock.ExpectQuery("^SELECT id, username, password, is_active, city, dwelling_age, tag, notification, role FROM users$").
 		WillReturnError(errors.New("query error"))
 
 	repo := repositories.NewMySQLUserRepository(db)
@@ -388,3 +257,21 @@ func TestDeleteByUId_Success(t *testing.T) {
 
 	assert.NoError(t, err)
 }
+
+func TestMySQLUserRepository_UpdateRole(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec("^UPDATE users SET role = \\? WHERE id = \\?$").
+		WithArgs(models.RoleModerator, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("^UPDATE users SET role = \\? WHERE id = \\?$").
+		WithArgs(models.RoleModerator, 9).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	repo := repositories.NewMySQLUserRepository(db)
+	assert.NoError(t, repo.UpdateRole(1, models.RoleModerator))
+	assert.Error(t, repo.UpdateRole(9, models.RoleModerator))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -1,12 +1,18 @@
 package services_test
 
 import (
+	"database/sql"
 	"errors"
+	"fmt"
 	"localEyes/config"
+	"localEyes/internal/interfaces"
 	"localEyes/internal/models"
+	"localEyes/internal/repositories"
 	"localEyes/internal/services"
 	"localEyes/tests/mocks"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -17,21 +23,19 @@ func TestAdminService_Login_Success(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockUserRepo := mocks.NewMockUserRepository(ctrl)
-	adminService := services.NewAdminService(mockUserRepo, nil, nil)
+	adminService := services.NewAdminService(mockUserRepo, nil, nil, nil, nil, nil)
 
 	password := "admin123"
-	hashedPassword := services.HashPassword(password)
+	hashedPassword, err := services.HashPassword(password)
+	assert.NoError(t, err)
 
-	mockAdmin := &models.Admin{
-		models.User{Username: "admin",
-			Password: hashedPassword},
-	}
+	mockUser := &models.User{Username: "admin", Password: hashedPassword, Role: models.RoleAdmin, IsActive: true}
 
 	mockUserRepo.EXPECT().
-		FindAdminByUsernamePassword("admin", hashedPassword).
-		Return(mockAdmin, nil)
+		FindByUsername("admin").
+		Return(mockUser, nil)
 
-	admin, err := adminService.Login(password)
+	admin, err := adminService.Login("admin", password)
 	assert.NoError(t, err)
 	assert.NotNil(t, admin)
 	assert.Equal(t, "admin", admin.User.Username)
@@ -42,27 +46,147 @@ func TestAdminService_Login_InvalidPassword(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockUserRepo := mocks.NewMockUserRepository(ctrl)
-	adminService := services.NewAdminService(mockUserRepo, nil, nil)
+	adminService := services.NewAdminService(mockUserRepo, nil, nil, nil, nil, nil)
 
-	password := "wrongpassword"
-	hashedPassword := services.HashPassword(password)
+	hashedPassword, err := services.HashPassword("admin123")
+	assert.NoError(t, err)
 
 	mockUserRepo.EXPECT().
-		FindAdminByUsernamePassword("admin", hashedPassword).
-		Return(nil, errors.New("Invalid username or password"))
+		FindByUsername("admin").
+		Return(&models.User{Username: "admin", Password: hashedPassword}, nil)
 
-	admin, err := adminService.Login(password)
+	admin, err := adminService.Login("admin", "wrongpassword")
 	assert.Error(t, err)
 	assert.Equal(t, config.Red+"Invalid username or password"+config.Reset, err.Error())
 	assert.Nil(t, admin)
 }
 
+func TestAdminService_Login_NoAdminAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	adminService := services.NewAdminService(mockUserRepo, nil, nil, nil, nil, nil)
+
+	password := "wrongpassword"
+	mockUserRepo.EXPECT().
+		FindByUsername("admin").
+		Return(nil, errors.New("sql: no rows in result set"))
+
+	admin, err := adminService.Login("admin", password)
+	assert.Error(t, err)
+	assert.Equal(t, config.Red+"Invalid username or password"+config.Reset, err.Error())
+	assert.Nil(t, admin)
+}
+
+func TestAdminService_Login_PlainUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	adminService := services.NewAdminService(mockUserRepo, nil, nil, nil, nil, nil)
+
+	hashedPassword, err := services.HashPassword("riya@123")
+	assert.NoError(t, err)
+
+	// Being called "admin" no longer grants anything, only the role does.
+	mockUserRepo.EXPECT().
+		FindByUsername("admin").
+		Return(&models.User{Username: "admin", Password: hashedPassword, Role: models.RoleUser, IsActive: true}, nil)
+
+	admin, err := adminService.Login("admin", "riya@123")
+	assert.EqualError(t, err, config.Red+"You do not have permission to use admin commands"+config.Reset)
+	assert.Nil(t, admin)
+}
+
+// staff returns an admin holding role, whose role the mock user repository
+// reports on every permission check.
+func staff(userRepo *mocks.MockUserRepository, role string) *models.Admin {
+	user := models.User{UId: 100, Username: role, Role: role, IsActive: true}
+	userRepo.EXPECT().FindByUId(user.UId).Return(&user, nil).AnyTimes()
+	return &models.Admin{User: user}
+}
+
+func TestAdminService_ModeratorPermissions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockPostRepo := mocks.NewMockPostRepository(ctrl)
+	adminService := services.NewAdminService(mockUserRepo, mockPostRepo, nil, nil, nil, nil)
+	moderator := staff(mockUserRepo, models.RoleModerator)
+
+	mockPostRepo.EXPECT().GetAllPosts().Return([]*models.Post{{Title: "Post 1"}}, nil)
+
+	posts, err := adminService.GetAllPosts(moderator)
+	assert.NoError(t, err)
+	assert.Len(t, posts, 1)
+
+	denied := config.Red + "You do not have permission to do this" + config.Reset
+	_, err = adminService.GetAllUsers(moderator)
+	assert.EqualError(t, err, denied)
+	assert.EqualError(t, adminService.DeleteUser(moderator, 1), denied)
+	assert.EqualError(t, adminService.ReActivate(moderator, 1), denied)
+	assert.EqualError(t, adminService.GrantRole(moderator, 1, models.RoleAdmin), denied)
+	assert.EqualError(t, adminService.DeletePost(nil, 1), denied)
+	_, err = adminService.CreateCity(moderator, "pune")
+	assert.EqualError(t, err, denied)
+}
+
+func TestAdminService_RevokedRoleStopsWorking(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	adminService := services.NewAdminService(mockUserRepo, nil, nil, nil, nil, nil)
+	admin := &models.Admin{User: models.User{UId: 7, Username: "riya", Role: models.RoleAdmin, IsActive: true}}
+
+	mockUserRepo.EXPECT().
+		FindByUId(7).
+		Return(&models.User{UId: 7, Username: "riya", Role: models.RoleUser, IsActive: true}, nil)
+
+	_, err := adminService.GetAllUsers(admin)
+	assert.EqualError(t, err, config.Red+"You do not have permission to do this"+config.Reset)
+}
+
+func TestAdminService_GrantAndRevokeRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockAuditRepo := mocks.NewMockAuditRepository(ctrl)
+	mockNotificationRepo := mocks.NewMockNotificationRepository(ctrl)
+	uow := newUnitsOfWork(ctrl, interfaces.Repositories{Users: mockUserRepo, Audit: mockAuditRepo, Notifications: mockNotificationRepo}, 4)
+	adminService := services.NewAdminService(mockUserRepo, nil, nil, nil, mockAuditRepo, uow)
+	admin := staff(mockUserRepo, models.RoleAdmin)
+
+	mockUserRepo.EXPECT().FindByUId(2).Return(&models.User{UId: 2, Role: models.RoleUser}, nil).Times(2)
+	mockUserRepo.EXPECT().UpdateRole(2, models.RoleModerator).Return(nil)
+	expectAudit(mockAuditRepo, models.AuditChangeRole, models.TargetUser, 2)
+	expectNotification(mockNotificationRepo, 2, "made you a moderator")
+	assert.NoError(t, adminService.GrantRole(admin, 2, models.RoleModerator))
+	assert.EqualError(t, adminService.RevokeRole(admin, 2), config.Red+"User already has the user role"+config.Reset)
+
+	mockUserRepo.EXPECT().FindByUId(3).Return(&models.User{UId: 3, Role: models.RoleModerator}, nil)
+	mockUserRepo.EXPECT().UpdateRole(3, models.RoleUser).Return(nil)
+	expectAudit(mockAuditRepo, models.AuditChangeRole, models.TargetUser, 3)
+	expectNotification(mockNotificationRepo, 3, "made you a user")
+	assert.NoError(t, adminService.RevokeRole(admin, 3))
+
+	mockUserRepo.EXPECT().FindByUId(9).Return(nil, errors.New("sql: no rows in result set"))
+	assert.EqualError(t, adminService.GrantRole(admin, 9, models.RoleAdmin), config.Red+"No user exist with this id"+config.Reset)
+
+	assert.Error(t, adminService.GrantRole(admin, 2, "superuser"))
+	assert.EqualError(t, adminService.RevokeRole(admin, admin.User.UId), config.Red+"You cannot change your own role"+config.Reset)
+}
+
 func TestAdminService_GetAllUsers_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockUserRepo := mocks.NewMockUserRepository(ctrl)
-	adminService := services.NewAdminService(mockUserRepo, nil, nil)
+	adminService := services.NewAdminService(mockUserRepo, nil, nil, nil, nil, nil)
+	admin := staff(mockUserRepo, models.RoleAdmin)
 
 	mockUsers := []*models.User{
 		{Username: "user1"},
@@ -73,7 +197,7 @@ func TestAdminService_GetAllUsers_Success(t *testing.T) {
 		GetAllUsers().
 		Return(mockUsers, nil)
 
-	users, err := adminService.GetAllUsers()
+	users, err := adminService.GetAllUsers(admin)
 	assert.NoError(t, err)
 	assert.NotNil(t, users)
 	assert.Equal(t, len(mockUsers), len(users))
@@ -84,13 +208,14 @@ func TestAdminService_GetAllUsers_Error(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockUserRepo := mocks.NewMockUserRepository(ctrl)
-	adminService := services.NewAdminService(mockUserRepo, nil, nil)
+	adminService := services.NewAdminService(mockUserRepo, nil, nil, nil, nil, nil)
+	admin := staff(mockUserRepo, models.RoleAdmin)
 
 	mockUserRepo.EXPECT().
 		GetAllUsers().
 		Return(nil, errors.New("database error"))
 
-	users, err := adminService.GetAllUsers()
+	users, err := adminService.GetAllUsers(admin)
 	assert.Error(t, err)
 	assert.Nil(t, users)
 }
@@ -99,8 +224,10 @@ func TestAdminService_GetAllPosts_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
 	mockPostRepo := mocks.NewMockPostRepository(ctrl)
-	adminService := services.NewAdminService(nil, mockPostRepo, nil)
+	adminService := services.NewAdminService(mockUserRepo, mockPostRepo, nil, nil, nil, nil)
+	admin := staff(mockUserRepo, models.RoleAdmin)
 
 	mockPosts := []*models.Post{
 		{Title: "Post 1"},
@@ -111,7 +238,7 @@ func TestAdminService_GetAllPosts_Success(t *testing.T) {
 		GetAllPosts().
 		Return(mockPosts, nil)
 
-	posts, err := adminService.GetAllPosts()
+	posts, err := adminService.GetAllPosts(admin)
 	assert.NoError(t, err)
 	assert.NotNil(t, posts)
 	assert.Equal(t, len(mockPosts), len(posts))
@@ -121,14 +248,16 @@ func TestAdminService_GetAllPosts_Error(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
 	mockPostRepo := mocks.NewMockPostRepository(ctrl)
-	adminService := services.NewAdminService(nil, mockPostRepo, nil)
+	adminService := services.NewAdminService(mockUserRepo, mockPostRepo, nil, nil, nil, nil)
+	admin := staff(mockUserRepo, models.RoleAdmin)
 
 	mockPostRepo.EXPECT().
 		GetAllPosts().
 		Return(nil, errors.New("database error"))
 
-	posts, err := adminService.GetAllPosts()
+	posts, err := adminService.GetAllPosts(admin)
 	assert.Error(t, err)
 	assert.Nil(t, posts)
 }
@@ -137,57 +266,156 @@ func TestAdminService_GetAllQuestions_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
 	mockQuesRepo := mocks.NewMockQuestionRepository(ctrl)
-	adminService := services.NewAdminService(nil, nil, mockQuesRepo)
+	mockAnswerRepo := mocks.NewMockAnswerRepository(ctrl)
+	adminService := services.NewAdminService(mockUserRepo, nil, mockQuesRepo, mockAnswerRepo, nil, nil)
+	admin := staff(mockUserRepo, models.RoleAdmin)
 
 	mockQuestions := []*models.Question{
-		{Text: "Question 1"},
-		{Text: "Question 2"},
+		{QId: 1, Text: "Question 1"},
+		{QId: 2, Text: "Question 2"},
 	}
 
 	mockQuesRepo.EXPECT().
 		GetAllQuestions().
 		Return(mockQuestions, nil)
+	mockAnswerRepo.EXPECT().
+		GetAllAnswers().
+		Return([]*models.Answer{{AnswerId: 1, QId: 2, Username: "riya", Text: "Answer"}}, nil)
 
-	questions, err := adminService.GetAllQuestions()
+	questions, err := adminService.GetAllQuestions(admin)
 	assert.NoError(t, err)
 	assert.NotNil(t, questions)
 	assert.Equal(t, len(mockQuestions), len(questions))
+	assert.Empty(t, questions[0].Answers)
+	assert.Equal(t, "riya", questions[1].Answers[0].Username)
+}
+
+func TestAdminService_ListQuestions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockQuesRepo := mocks.NewMockQuestionRepository(ctrl)
+	mockAnswerRepo := mocks.NewMockAnswerRepository(ctrl)
+	adminService := services.NewAdminService(mockUserRepo, nil, mockQuesRepo, mockAnswerRepo, nil, nil)
+	admin := staff(mockUserRepo, models.RoleModerator)
+
+	mockQuesRepo.EXPECT().
+		ListQuestions("", models.SortOldest, 3, 0).
+		Return([]*models.Question{{QId: 1}, {QId: 2}}, nil)
+	mockAnswerRepo.EXPECT().GetAnswersByQId(1).Return(nil, nil)
+	mockAnswerRepo.EXPECT().GetAnswersByQId(2).Return([]*models.Answer{{AnswerId: 1, QId: 2, Username: "riya"}}, nil)
+
+	page, err := adminService.ListQuestions(admin, "", models.PageRequest{Size: 2, Sort: models.SortOldest})
+	assert.NoError(t, err)
+	assert.Len(t, page.Items, 2)
+	assert.False(t, page.HasNext)
+	assert.False(t, page.HasPrev())
+	assert.Equal(t, "riya", page.Items[1].Answers[0].Username)
+
+	_, err = adminService.ListUsers(admin, models.PageRequest{})
+	assert.EqualError(t, err, config.Red+"You do not have permission to do this"+config.Reset)
 }
 
 func TestAdminService_GetAllQuestions_Error(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
 	mockQuesRepo := mocks.NewMockQuestionRepository(ctrl)
-	adminService := services.NewAdminService(nil, nil, mockQuesRepo)
+	adminService := services.NewAdminService(mockUserRepo, nil, mockQuesRepo, nil, nil, nil)
+	admin := staff(mockUserRepo, models.RoleAdmin)
 
 	mockQuesRepo.EXPECT().
 		GetAllQuestions().
 		Return(nil, errors.New("database error"))
 
-	questions, err := adminService.GetAllQuestions()
+	questions, err := adminService.GetAllQuestions(admin)
 	assert.Error(t, err)
 	assert.Nil(t, questions)
 }
 
+// newUnitOfWork returns a mock unit of work that runs the work against repos.
+func newUnitOfWork(ctrl *gomock.Controller, repos interfaces.Repositories) *mocks.MockUnitOfWork {
+	return newUnitsOfWork(ctrl, repos, 1)
+}
+
+// newUnitsOfWork is newUnitOfWork for a test that runs the work times times.
+func newUnitsOfWork(ctrl *gomock.Controller, repos interfaces.Repositories, times int) *mocks.MockUnitOfWork {
+	uow := mocks.NewMockUnitOfWork(ctrl)
+	uow.EXPECT().
+		Do(gomock.Any()).
+		DoAndReturn(func(fn func(repos interfaces.Repositories) error) error {
+			return fn(repos)
+		}).
+		Times(times)
+	return uow
+}
+
+// expectAudit expects one audit entry for action on the target.
+func expectAudit(auditRepo *mocks.MockAuditRepository, action, targetType string, targetId int) *gomock.Call {
+	return auditRepo.EXPECT().
+		Create(gomock.Any()).
+		DoAndReturn(func(entry *models.AuditEntry) error {
+			if entry.Action != action || entry.TargetType != targetType || entry.TargetId != targetId {
+				return fmt.Errorf("unexpected audit entry %s %s #%d", entry.Action, entry.TargetType, entry.TargetId)
+			}
+			return nil
+		})
+}
+
+// expectNotification expects the admin-action notification telling UId what
+// happened, with message in it.
+func expectNotification(notificationRepo *mocks.MockNotificationRepository, UId int, message string) *gomock.Call {
+	return notificationRepo.EXPECT().
+		Create(gomock.Any()).
+		DoAndReturn(func(notification *models.Notification) error {
+			if notification.UserId != UId || notification.Type != models.NotifyAdminAction || !strings.Contains(notification.Message, message) {
+				return fmt.Errorf("unexpected notification %s to #%d: %s", notification.Type, notification.UserId, notification.Message)
+			}
+			return nil
+		})
+}
+
 func TestAdminService_DeleteUser_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockUserRepo := mocks.NewMockUserRepository(ctrl)
 	mockPostRepo := mocks.NewMockPostRepository(ctrl)
-	adminService := services.NewAdminService(mockUserRepo, mockPostRepo, nil)
-
-	mockUserRepo.EXPECT().
-		DeleteByUId(1).
-		Return(nil)
-
-	mockPostRepo.EXPECT().
-		DeleteByUId(1).
-		Return(nil)
-
-	err := adminService.DeleteUser(1)
+	mockQuesRepo := mocks.NewMockQuestionRepository(ctrl)
+	mockAnswerRepo := mocks.NewMockAnswerRepository(ctrl)
+	mockLikeRepo := mocks.NewMockPostLikeRepository(ctrl)
+	mockSessionRepo := mocks.NewMockSessionRepository(ctrl)
+	mockAuditRepo := mocks.NewMockAuditRepository(ctrl)
+	mockCommentRepo := mocks.NewMockCommentRepository(ctrl)
+	mockNotificationRepo := mocks.NewMockNotificationRepository(ctrl)
+	mockPrefsRepo := mocks.NewMockNotificationPrefsRepository(ctrl)
+	mockOutboxRepo := mocks.NewMockOutboxRepository(ctrl)
+	uow := newUnitOfWork(ctrl, interfaces.Repositories{Users: mockUserRepo, Posts: mockPostRepo, Questions: mockQuesRepo, Answers: mockAnswerRepo, Likes: mockLikeRepo, Sessions: mockSessionRepo, Audit: mockAuditRepo, Comments: mockCommentRepo, Notifications: mockNotificationRepo, NotificationPrefs: mockPrefsRepo, Outbox: mockOutboxRepo})
+	adminService := services.NewAdminService(mockUserRepo, mockPostRepo, mockQuesRepo, mockAnswerRepo, nil, uow)
+	admin := staff(mockUserRepo, models.RoleAdmin)
+
+	gomock.InOrder(
+		mockUserRepo.EXPECT().FindByUId(1).Return(&models.User{UId: 1, Username: "riya"}, nil),
+		mockSessionRepo.EXPECT().DeleteForUser(1).Return(nil),
+		mockAnswerRepo.EXPECT().DeleteForUser(1).Return(nil),
+		mockCommentRepo.EXPECT().DeleteForUser(1).Return(nil),
+		mockNotificationRepo.EXPECT().DeleteForUser(1).Return(nil),
+		mockPrefsRepo.EXPECT().DeleteForUser(1).Return(nil),
+		mockQuesRepo.EXPECT().DeleteByPostOwner(1).Return(nil),
+		mockQuesRepo.EXPECT().DeleteByUId(1).Return(nil),
+		mockPostRepo.EXPECT().RemoveUserLikes(1).Return(nil),
+		mockLikeRepo.EXPECT().DeleteForUser(1).Return(nil),
+		mockOutboxRepo.EXPECT().DeleteForUser(1).Return(nil),
+		mockPostRepo.EXPECT().DeleteByUId(1).Return(nil),
+		mockUserRepo.EXPECT().DeleteByUId(1).Return(nil),
+		expectAudit(mockAuditRepo, models.AuditDeleteUser, models.TargetUser, 1),
+	)
+
+	err := adminService.DeleteUser(admin, 1)
 	assert.NoError(t, err)
 }
 
@@ -197,35 +425,81 @@ func TestAdminService_DeleteUser_Error(t *testing.T) {
 
 	mockUserRepo := mocks.NewMockUserRepository(ctrl)
 	mockPostRepo := mocks.NewMockPostRepository(ctrl)
-	adminService := services.NewAdminService(mockUserRepo, mockPostRepo, nil)
-
-	mockUserRepo.EXPECT().
-		DeleteByUId(1).
-		Return(errors.New("delete user error"))
-	mockPostRepo.EXPECT().
-		DeleteByUId(1).
-		Return(nil)
-	err := adminService.DeleteUser(1)
-	assert.Error(t, err)
+	mockQuesRepo := mocks.NewMockQuestionRepository(ctrl)
+	mockAnswerRepo := mocks.NewMockAnswerRepository(ctrl)
+	mockLikeRepo := mocks.NewMockPostLikeRepository(ctrl)
+	mockSessionRepo := mocks.NewMockSessionRepository(ctrl)
+	mockAuditRepo := mocks.NewMockAuditRepository(ctrl)
+	mockCommentRepo := mocks.NewMockCommentRepository(ctrl)
+	mockNotificationRepo := mocks.NewMockNotificationRepository(ctrl)
+	mockPrefsRepo := mocks.NewMockNotificationPrefsRepository(ctrl)
+	mockOutboxRepo := mocks.NewMockOutboxRepository(ctrl)
+	uow := newUnitOfWork(ctrl, interfaces.Repositories{Users: mockUserRepo, Posts: mockPostRepo, Questions: mockQuesRepo, Answers: mockAnswerRepo, Likes: mockLikeRepo, Sessions: mockSessionRepo, Audit: mockAuditRepo, Comments: mockCommentRepo, Notifications: mockNotificationRepo, NotificationPrefs: mockPrefsRepo, Outbox: mockOutboxRepo})
+	adminService := services.NewAdminService(mockUserRepo, mockPostRepo, mockQuesRepo, mockAnswerRepo, nil, uow)
+	admin := staff(mockUserRepo, models.RoleAdmin)
+
+	mockUserRepo.EXPECT().FindByUId(1).Return(&models.User{UId: 1, Username: "riya"}, nil)
+	mockSessionRepo.EXPECT().DeleteForUser(1).Return(nil)
+	mockAnswerRepo.EXPECT().DeleteForUser(1).Return(nil)
+	mockCommentRepo.EXPECT().DeleteForUser(1).Return(nil)
+	mockNotificationRepo.EXPECT().DeleteForUser(1).Return(nil)
+	mockPrefsRepo.EXPECT().DeleteForUser(1).Return(nil)
+	mockQuesRepo.EXPECT().DeleteByPostOwner(1).Return(nil)
+	mockQuesRepo.EXPECT().DeleteByUId(1).Return(errors.New("delete question error"))
+
+	err := adminService.DeleteUser(admin, 1)
+	assert.EqualError(t, err, "delete question error")
 }
 
 func TestAdminService_DeletePost_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
 	mockPostRepo := mocks.NewMockPostRepository(ctrl)
 	mockQuesRepo := mocks.NewMockQuestionRepository(ctrl)
-	adminService := services.NewAdminService(nil, mockPostRepo, mockQuesRepo)
+	mockAnswerRepo := mocks.NewMockAnswerRepository(ctrl)
+	mockLikeRepo := mocks.NewMockPostLikeRepository(ctrl)
+	mockAuditRepo := mocks.NewMockAuditRepository(ctrl)
+	mockCommentRepo := mocks.NewMockCommentRepository(ctrl)
+	mockNotificationRepo := mocks.NewMockNotificationRepository(ctrl)
+	mockOutboxRepo := mocks.NewMockOutboxRepository(ctrl)
+	uow := newUnitOfWork(ctrl, interfaces.Repositories{Posts: mockPostRepo, Questions: mockQuesRepo, Answers: mockAnswerRepo, Likes: mockLikeRepo, Audit: mockAuditRepo, Comments: mockCommentRepo, Notifications: mockNotificationRepo, Outbox: mockOutboxRepo})
+	adminService := services.NewAdminService(mockUserRepo, mockPostRepo, mockQuesRepo, mockAnswerRepo, nil, uow)
+	admin := staff(mockUserRepo, models.RoleAdmin)
+
+	mockPostRepo.EXPECT().
+		GetPostsByPId(1).
+		Return([]*models.Post{{PostId: 1, UId: 2, Title: "Momos"}}, nil)
 
 	mockPostRepo.EXPECT().
 		DeleteByPId(1).
 		Return(nil)
 
+	mockLikeRepo.EXPECT().
+		DeleteByPId(1).
+		Return(nil)
+
+	mockAnswerRepo.EXPECT().
+		DeleteByPId(1).
+		Return(nil)
+
 	mockQuesRepo.EXPECT().
 		DeleteByPId(1).
 		Return(nil)
 
-	err := adminService.DeletePost(1)
+	mockCommentRepo.EXPECT().
+		DeleteByPId(1).
+		Return(nil)
+
+	mockOutboxRepo.EXPECT().
+		DeleteByPId(1).
+		Return(nil)
+
+	expectAudit(mockAuditRepo, models.AuditDeletePost, models.TargetPost, 1)
+	expectNotification(mockNotificationRepo, 2, `removed your post "Momos"`)
+
+	err := adminService.DeletePost(admin, 1)
 	assert.NoError(t, err)
 }
 
@@ -233,32 +507,106 @@ func TestAdminService_DeletePost_Error(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
 	mockPostRepo := mocks.NewMockPostRepository(ctrl)
 	mockQuesRepo := mocks.NewMockQuestionRepository(ctrl)
-	adminService := services.NewAdminService(nil, mockPostRepo, mockQuesRepo)
+	mockAnswerRepo := mocks.NewMockAnswerRepository(ctrl)
+	mockLikeRepo := mocks.NewMockPostLikeRepository(ctrl)
+	mockAuditRepo := mocks.NewMockAuditRepository(ctrl)
+	uow := newUnitOfWork(ctrl, interfaces.Repositories{Posts: mockPostRepo, Questions: mockQuesRepo, Answers: mockAnswerRepo, Likes: mockLikeRepo, Audit: mockAuditRepo})
+	adminService := services.NewAdminService(mockUserRepo, mockPostRepo, mockQuesRepo, mockAnswerRepo, nil, uow)
+	admin := staff(mockUserRepo, models.RoleAdmin)
 
+	mockPostRepo.EXPECT().
+		GetPostsByPId(1).
+		Return([]*models.Post{{PostId: 1, UId: 2, Title: "Momos"}}, nil)
 	mockPostRepo.EXPECT().
 		DeleteByPId(1).
 		Return(errors.New("delete post error"))
-	mockQuesRepo.EXPECT().
-		DeleteByPId(1).
-		Return(nil)
-	err := adminService.DeletePost(1)
+	err := adminService.DeletePost(admin, 1)
 	assert.Error(t, err)
 }
 
+func TestAdminService_DeleteUser_InMemoryRollsBack(t *testing.T) {
+	users := repositories.NewInMemoryUserRepository()
+	posts := repositories.NewInMemoryPostRepository()
+	questions := repositories.NewInMemoryQuestionRepository()
+	answers := repositories.NewInMemoryAnswerRepository()
+	likes := repositories.NewInMemoryPostLikeRepository()
+	audit := repositories.NewInMemoryAuditRepository()
+	uow := repositories.NewInMemoryUnitOfWork(users, posts, questions, answers, likes, repositories.NewInMemorySessionRepository(), audit, repositories.NewInMemoryCategoryRepository(), repositories.NewInMemoryCityRepository(), repositories.NewInMemoryCommentRepository(), repositories.NewInMemoryNotificationRepository(), repositories.NewInMemoryNotificationPrefsRepository(), repositories.NewInMemoryOutboxRepository())
+	adminService := services.NewAdminService(users, posts, questions, answers, audit, uow)
+
+	assert.NoError(t, users.Create(&models.User{Username: "author"}))
+	assert.NoError(t, users.Create(&models.User{Username: "reader"}))
+	adminUser := &models.User{Username: "admin", Role: models.RoleAdmin, IsActive: true}
+	assert.NoError(t, users.Create(adminUser))
+	admin := &models.Admin{User: *adminUser}
+	assert.NoError(t, posts.Create(&models.Post{UId: 1, Title: "Momos"}))
+	assert.NoError(t, posts.Create(&models.Post{UId: 2, Title: "Metro"}))
+	assert.NoError(t, questions.Create(&models.Question{PostId: 1, UserId: 2, Text: "Where?"}))
+	assert.NoError(t, questions.Create(&models.Question{PostId: 2, UserId: 1, Text: "Which line?"}))
+	assert.NoError(t, answers.Create(&models.Answer{QId: 1, UserId: 1, Text: "Near the gate"}))
+	assert.NoError(t, answers.Create(&models.Answer{QId: 2, UserId: 2, Text: "Yellow"}))
+	assert.NoError(t, likes.Create(1, 2))
+	assert.NoError(t, posts.UpdateLike(2))
+	assert.NoError(t, likes.Create(2, 1))
+	assert.NoError(t, posts.UpdateLike(1))
+
+	// Posts and questions from a user id that does not exist must survive the failed delete.
+	assert.NoError(t, posts.Create(&models.Post{UId: 9, Title: "Orphan"}))
+	assert.Error(t, adminService.DeleteUser(admin, 9))
+	orphans, _ := posts.GetPostsByUId(9)
+	assert.Len(t, orphans, 1)
+	entries, _ := audit.Find(models.AuditFilter{})
+	assert.Empty(t, entries)
+
+	assert.NoError(t, adminService.DeleteUser(admin, 1))
+	remainingPosts, _ := posts.GetAllPosts()
+	remainingQuestions, _ := questions.GetAllQuestions()
+	remainingAnswers, _ := answers.GetAllAnswers()
+	assert.Len(t, remainingPosts, 2)
+	assert.Empty(t, remainingQuestions)
+	assert.Empty(t, remainingAnswers)
+	assert.Equal(t, 0, remainingPosts[0].Likes) // the deleted author's like on "Metro" is taken back
+	readerLikes, _ := likes.GetLikedPIds(2)
+	assert.Empty(t, readerLikes)
+
+	entries, _ = audit.Find(models.AuditFilter{TargetType: models.TargetUser, TargetId: 1})
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, models.AuditDeleteUser, entries[0].Action)
+		assert.Equal(t, "admin", entries[0].ActorName)
+		assert.Contains(t, entries[0].Before, `"username":"author"`)
+		assert.Empty(t, entries[0].After)
+	}
+}
+
 func TestAdminService_DeleteQuestion_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
 	mockQuesRepo := mocks.NewMockQuestionRepository(ctrl)
-	adminService := services.NewAdminService(nil, nil, mockQuesRepo)
+	mockAnswerRepo := mocks.NewMockAnswerRepository(ctrl)
+	mockAuditRepo := mocks.NewMockAuditRepository(ctrl)
+	mockNotificationRepo := mocks.NewMockNotificationRepository(ctrl)
+	uow := newUnitOfWork(ctrl, interfaces.Repositories{Questions: mockQuesRepo, Answers: mockAnswerRepo, Audit: mockAuditRepo, Notifications: mockNotificationRepo})
+	adminService := services.NewAdminService(mockUserRepo, nil, mockQuesRepo, mockAnswerRepo, nil, uow)
+	admin := staff(mockUserRepo, models.RoleAdmin)
 
+	mockQuesRepo.EXPECT().
+		FindByQId(1).
+		Return(&models.Question{QId: 1, PostId: 1, UserId: 2, Text: "Open on Sunday?"}, nil)
 	mockQuesRepo.EXPECT().
 		DeleteByQId(1).
 		Return(nil)
+	mockAnswerRepo.EXPECT().
+		DeleteByQId(1).
+		Return(nil)
+	expectAudit(mockAuditRepo, models.AuditDeleteQuestion, models.TargetQuestion, 1)
+	expectNotification(mockNotificationRepo, 2, `removed your question "Open on Sunday?"`)
 
-	err := adminService.DeleteQuestion(1)
+	err := adminService.DeleteQuestion(admin, 1)
 	assert.NoError(t, err)
 }
 
@@ -266,14 +614,20 @@ func TestAdminService_DeleteQuestion_Error(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
 	mockQuesRepo := mocks.NewMockQuestionRepository(ctrl)
-	adminService := services.NewAdminService(nil, nil, mockQuesRepo)
+	uow := newUnitOfWork(ctrl, interfaces.Repositories{Questions: mockQuesRepo})
+	adminService := services.NewAdminService(mockUserRepo, nil, mockQuesRepo, nil, nil, uow)
+	admin := staff(mockUserRepo, models.RoleAdmin)
 
+	mockQuesRepo.EXPECT().
+		FindByQId(1).
+		Return(&models.Question{QId: 1, PostId: 1, Text: "Open on Sunday?"}, nil)
 	mockQuesRepo.EXPECT().
 		DeleteByQId(1).
 		Return(errors.New("delete question error"))
 
-	err := adminService.DeleteQuestion(1)
+	err := adminService.DeleteQuestion(admin, 1)
 	assert.Error(t, err)
 }
 
@@ -282,13 +636,31 @@ func TestAdminService_ReActivate_Success(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockUserRepo := mocks.NewMockUserRepository(ctrl)
-	adminService := services.NewAdminService(mockUserRepo, nil, nil)
+	mockAuditRepo := mocks.NewMockAuditRepository(ctrl)
+	mockNotificationRepo := mocks.NewMockNotificationRepository(ctrl)
+	uow := newUnitOfWork(ctrl, interfaces.Repositories{Users: mockUserRepo, Audit: mockAuditRepo, Notifications: mockNotificationRepo})
+	adminService := services.NewAdminService(mockUserRepo, nil, nil, nil, mockAuditRepo, uow)
+	admin := staff(mockUserRepo, models.RoleAdmin)
 
+	mockUserRepo.EXPECT().
+		FindByUId(1).
+		Return(&models.User{UId: 1, Username: "riya"}, nil)
 	mockUserRepo.EXPECT().
 		UpdateActiveStatus(1, true).
 		Return(nil)
-
-	err := adminService.ReActivate(1)
+	mockAuditRepo.EXPECT().
+		Create(gomock.Any()).
+		DoAndReturn(func(entry *models.AuditEntry) error {
+			assert.Equal(t, models.AuditReactivateUser, entry.Action)
+			assert.Equal(t, admin.User.UId, entry.ActorId)
+			assert.Contains(t, entry.Before, `"active":false`)
+			assert.Contains(t, entry.After, `"active":true`)
+			assert.NotContains(t, entry.Before, "password")
+			return nil
+		})
+	expectNotification(mockNotificationRepo, 1, "reactivated your account")
+
+	err := adminService.ReActivate(admin, 1)
 	assert.NoError(t, err)
 }
 
@@ -297,12 +669,276 @@ func TestAdminService_ReActivate_Error(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockUserRepo := mocks.NewMockUserRepository(ctrl)
-	adminService := services.NewAdminService(mockUserRepo, nil, nil)
+	uow := newUnitOfWork(ctrl, interfaces.Repositories{Users: mockUserRepo})
+	adminService := services.NewAdminService(mockUserRepo, nil, nil, nil, nil, uow)
+	admin := staff(mockUserRepo, models.RoleAdmin)
 
+	mockUserRepo.EXPECT().
+		FindByUId(1).
+		Return(&models.User{UId: 1, Username: "riya", IsActive: true}, nil)
 	mockUserRepo.EXPECT().
 		UpdateActiveStatus(1, true).
 		Return(errors.New("reactivate error"))
 
-	err := adminService.ReActivate(1)
+	err := adminService.ReActivate(admin, 1)
 	assert.Error(t, err)
 }
+
+func TestAdminService_DeleteAnswer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockAnswerRepo := mocks.NewMockAnswerRepository(ctrl)
+	mockAuditRepo := mocks.NewMockAuditRepository(ctrl)
+	uow := newUnitsOfWork(ctrl, interfaces.Repositories{Answers: mockAnswerRepo, Audit: mockAuditRepo}, 2)
+	adminService := services.NewAdminService(mockUserRepo, nil, nil, mockAnswerRepo, mockAuditRepo, uow)
+	admin := staff(mockUserRepo, models.RoleAdmin)
+
+	mockAnswerRepo.EXPECT().FindByAnswerId(3).Return(&models.Answer{AnswerId: 3, QId: 1, Text: "Yes"}, nil)
+	mockAnswerRepo.EXPECT().DeleteByAnswerId(3).Return(nil)
+	expectAudit(mockAuditRepo, models.AuditDeleteAnswer, models.TargetAnswer, 3)
+	mockAnswerRepo.EXPECT().FindByAnswerId(4).Return(nil, sql.ErrNoRows)
+
+	assert.NoError(t, adminService.DeleteAnswer(admin, 3))
+	assert.EqualError(t, adminService.DeleteAnswer(admin, 4), config.Red+"No Answer exist with this id"+config.Reset)
+}
+
+func TestAdminService_DeleteComment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockCommentRepo := mocks.NewMockCommentRepository(ctrl)
+	mockAuditRepo := mocks.NewMockAuditRepository(ctrl)
+	uow := newUnitsOfWork(ctrl, interfaces.Repositories{Comments: mockCommentRepo, Audit: mockAuditRepo}, 2)
+	adminService := services.NewAdminService(mockUserRepo, nil, nil, nil, mockAuditRepo, uow)
+	admin := staff(mockUserRepo, models.RoleModerator)
+
+	mockCommentRepo.EXPECT().FindByCommentId(3).Return(&models.Comment{CommentId: 3, PostId: 1, UserId: admin.User.UId, Text: "Spam"}, nil)
+	mockCommentRepo.EXPECT().CountReplies(3).Return(1, nil)
+	mockCommentRepo.EXPECT().MarkDeleted(3).Return(nil)
+	expectAudit(mockAuditRepo, models.AuditDeleteComment, models.TargetComment, 3)
+	mockCommentRepo.EXPECT().FindByCommentId(4).Return(&models.Comment{CommentId: 4, Deleted: true}, nil)
+
+	assert.NoError(t, adminService.DeleteComment(admin, 3))
+	assert.EqualError(t, adminService.DeleteComment(admin, 4), config.Red+"No Comment exist with this id"+config.Reset)
+}
+
+func TestAdminService_GetAuditLog(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockAuditRepo := mocks.NewMockAuditRepository(ctrl)
+	adminService := services.NewAdminService(mockUserRepo, nil, nil, nil, mockAuditRepo, nil)
+	admin := staff(mockUserRepo, models.RoleAdmin)
+
+	filter := models.AuditFilter{ActorId: 1, TargetType: models.TargetPost}
+	mockAuditRepo.EXPECT().
+		Find(filter).
+		Return([]*models.AuditEntry{{Id: 1, ActorId: 1, Action: models.AuditDeletePost, TargetType: models.TargetPost, TargetId: 4}}, nil)
+
+	entries, err := adminService.GetAuditLog(admin, filter)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	now := time.Now()
+	_, err = adminService.GetAuditLog(admin, models.AuditFilter{From: now, To: now.Add(-time.Hour)})
+	assert.Error(t, err)
+
+	// Moderators act on content but cannot read the log.
+	moderator := &models.Admin{User: models.User{UId: 101, Role: models.RoleModerator, IsActive: true}}
+	mockUserRepo.EXPECT().FindByUId(101).Return(&moderator.User, nil)
+	_, err = adminService.GetAuditLog(moderator, filter)
+	assert.EqualError(t, err, config.Red+"You do not have permission to do this"+config.Reset)
+}
+
+func TestAdminService_CreateCategory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockCategoryRepo := mocks.NewMockCategoryRepository(ctrl)
+	mockAuditRepo := mocks.NewMockAuditRepository(ctrl)
+	uow := newUnitsOfWork(ctrl, interfaces.Repositories{Categories: mockCategoryRepo, Audit: mockAuditRepo}, 2)
+	adminService := services.NewAdminService(mockUserRepo, nil, nil, nil, nil, uow)
+	admin := staff(mockUserRepo, models.RoleAdmin)
+
+	gomock.InOrder(
+		mockCategoryRepo.EXPECT().FindByName("healthcare").Return(nil, sql.ErrNoRows),
+		mockCategoryRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(category *models.Category) error {
+			assert.Equal(t, &models.Category{Name: "healthcare", Description: "Clinics", IsActive: true}, category)
+			category.Id = 5
+			return nil
+		}),
+		expectAudit(mockAuditRepo, models.AuditCreateCategory, models.TargetCategory, 5),
+	)
+	category, err := adminService.CreateCategory(admin, "healthcare", "Clinics")
+	assert.NoError(t, err)
+	assert.Equal(t, 5, category.Id)
+
+	mockCategoryRepo.EXPECT().FindByName("food").Return(&models.Category{Id: 1, Name: "food"}, nil)
+	_, err = adminService.CreateCategory(admin, "food", "")
+	assert.EqualError(t, err, config.Red+"Category food already exists"+config.Reset)
+
+	_, err = adminService.CreateCategory(admin, "Late Night", "")
+	assert.ErrorContains(t, err, "Category names are 2 to 30 lowercase letters")
+}
+
+func TestAdminService_UpdateCategory_RenameMovesPosts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockPostRepo := mocks.NewMockPostRepository(ctrl)
+	mockCategoryRepo := mocks.NewMockCategoryRepository(ctrl)
+	mockAuditRepo := mocks.NewMockAuditRepository(ctrl)
+	uow := newUnitOfWork(ctrl, interfaces.Repositories{Posts: mockPostRepo, Categories: mockCategoryRepo, Audit: mockAuditRepo})
+	adminService := services.NewAdminService(mockUserRepo, mockPostRepo, nil, nil, nil, uow)
+	admin := staff(mockUserRepo, models.RoleAdmin)
+
+	name, active := "events", false
+	gomock.InOrder(
+		mockCategoryRepo.EXPECT().FindById(5).Return(&models.Category{Id: 5, Name: "fairs", IsActive: true}, nil),
+		mockCategoryRepo.EXPECT().FindByName("events").Return(nil, sql.ErrNoRows),
+		mockPostRepo.EXPECT().UpdateType("fairs", "events").Return(nil),
+		mockCategoryRepo.EXPECT().Update(&models.Category{Id: 5, Name: "events", IsActive: false}).Return(nil),
+		expectAudit(mockAuditRepo, models.AuditUpdateCategory, models.TargetCategory, 5),
+	)
+
+	category, err := adminService.UpdateCategory(admin, 5, models.CategoryUpdate{Name: &name, IsActive: &active})
+	assert.NoError(t, err)
+	assert.Equal(t, "events", category.Name)
+}
+
+func TestAdminService_DeleteCategory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockPostRepo := mocks.NewMockPostRepository(ctrl)
+	mockCategoryRepo := mocks.NewMockCategoryRepository(ctrl)
+	mockAuditRepo := mocks.NewMockAuditRepository(ctrl)
+	mockPrefsRepo := mocks.NewMockNotificationPrefsRepository(ctrl)
+	uow := newUnitsOfWork(ctrl, interfaces.Repositories{Posts: mockPostRepo, Categories: mockCategoryRepo, Audit: mockAuditRepo, NotificationPrefs: mockPrefsRepo}, 2)
+	adminService := services.NewAdminService(mockUserRepo, mockPostRepo, nil, nil, nil, uow)
+	admin := staff(mockUserRepo, models.RoleAdmin)
+
+	mockCategoryRepo.EXPECT().FindById(1).Return(&models.Category{Id: 1, Name: "food"}, nil)
+	mockPostRepo.EXPECT().ListPosts(models.PostFilter{Type: "food"}, models.SortNewest, 1, 0).Return([]*models.Post{{PostId: 3}}, nil)
+	err := adminService.DeleteCategory(admin, 1)
+	assert.EqualError(t, err, config.Red+"Category food still has posts, deactivate it instead"+config.Reset)
+
+	gomock.InOrder(
+		mockCategoryRepo.EXPECT().FindById(5).Return(&models.Category{Id: 5, Name: "events"}, nil),
+		mockPostRepo.EXPECT().ListPosts(models.PostFilter{Type: "events"}, models.SortNewest, 1, 0).Return(nil, nil),
+		mockCategoryRepo.EXPECT().DeleteById(5).Return(nil),
+		mockPrefsRepo.EXPECT().DeleteSubscriptionsTo(models.TargetCategory, 5).Return(nil),
+		expectAudit(mockAuditRepo, models.AuditDeleteCategory, models.TargetCategory, 5),
+	)
+	assert.NoError(t, adminService.DeleteCategory(admin, 5))
+}
+
+func TestAdminService_ModeratorCannotManageCategories(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	adminService := services.NewAdminService(mockUserRepo, nil, nil, nil, nil, nil)
+	moderator := staff(mockUserRepo, models.RoleModerator)
+
+	_, err := adminService.CreateCategory(moderator, "events", "")
+	assert.ErrorContains(t, err, "You do not have permission to do this")
+	assert.ErrorContains(t, adminService.DeleteCategory(moderator, 1), "You do not have permission to do this")
+}
+
+func TestAdminService_ListPostsByCity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockPostRepo := mocks.NewMockPostRepository(ctrl)
+	adminService := services.NewAdminService(mockUserRepo, mockPostRepo, nil, nil, nil, nil)
+	moderator := staff(mockUserRepo, models.RoleModerator)
+
+	mockPostRepo.EXPECT().ListPosts(models.PostFilter{City: "pune"}, models.SortNewest, 11, 0).Return([]*models.Post{{PostId: 2, City: "pune"}}, nil)
+	page, err := adminService.ListPosts(moderator, " Pune ", models.PageRequest{})
+	assert.NoError(t, err)
+	assert.Len(t, page.Items, 1)
+
+	mockPostRepo.EXPECT().ListPosts(models.PostFilter{}, models.SortNewest, 11, 0).Return(nil, nil)
+	_, err = adminService.ListPosts(moderator, "", models.PageRequest{})
+	assert.NoError(t, err)
+}
+
+func TestAdminService_CreateCity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockCityRepo := mocks.NewMockCityRepository(ctrl)
+	mockAuditRepo := mocks.NewMockAuditRepository(ctrl)
+	uow := newUnitsOfWork(ctrl, interfaces.Repositories{Cities: mockCityRepo, Audit: mockAuditRepo}, 2)
+	adminService := services.NewAdminService(mockUserRepo, nil, nil, nil, nil, uow)
+	admin := staff(mockUserRepo, models.RoleAdmin)
+
+	gomock.InOrder(
+		mockCityRepo.EXPECT().FindByName("navi mumbai").Return(nil, sql.ErrNoRows),
+		mockCityRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(city *models.City) error {
+			assert.Equal(t, &models.City{Name: "navi mumbai"}, city)
+			city.Id = 3
+			return nil
+		}),
+		expectAudit(mockAuditRepo, models.AuditCreateCity, models.TargetCity, 3),
+	)
+	city, err := adminService.CreateCity(admin, " Navi Mumbai")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, city.Id)
+
+	mockCityRepo.EXPECT().FindByName("delhi").Return(&models.City{Id: 1, Name: "delhi"}, nil)
+	_, err = adminService.CreateCity(admin, "delhi")
+	assert.EqualError(t, err, config.Red+"City delhi already exists"+config.Reset)
+
+	_, err = adminService.CreateCity(admin, "city 17")
+	assert.ErrorContains(t, err, "City names are 2 to 40 letters")
+}
+
+func TestAdminService_BootstrapAdmin_InMemory(t *testing.T) {
+	users := repositories.NewInMemoryUserRepository()
+	audit := repositories.NewInMemoryAuditRepository()
+	uow := repositories.NewInMemoryUnitOfWork(users, repositories.NewInMemoryPostRepository(), repositories.NewInMemoryQuestionRepository(),
+		repositories.NewInMemoryAnswerRepository(), repositories.NewInMemoryPostLikeRepository(), repositories.NewInMemorySessionRepository(), audit, repositories.NewInMemoryCategoryRepository(), repositories.NewInMemoryCityRepository(), repositories.NewInMemoryCommentRepository(), repositories.NewInMemoryNotificationRepository(), repositories.NewInMemoryNotificationPrefsRepository(), repositories.NewInMemoryOutboxRepository())
+	userService := services.NewUserService(users, uow)
+	adminService := services.NewAdminService(users, nil, nil, nil, audit, uow)
+
+	_, err := adminService.BootstrapAdmin("riya")
+	assert.ErrorIs(t, err, models.ErrNotFound)
+
+	assert.NoError(t, userService.Signup("riya", "secret@1", "Delhi", 1, "resident"))
+	_, err = adminService.Login("riya", "secret@1")
+	assert.Error(t, err, "signing up never grants a role")
+
+	user, err := adminService.BootstrapAdmin("riya")
+	assert.NoError(t, err)
+	assert.Equal(t, models.RoleAdmin, user.Role)
+	admin, err := adminService.Login("riya", "secret@1")
+	assert.NoError(t, err)
+	assert.Equal(t, models.RoleAdmin, admin.User.Role)
+
+	// Once there is an admin, bootstrapping is refused, so a revoked role
+	// stays revoked and nobody else can claim it.
+	assert.NoError(t, userService.Signup("aman", "secret@1", "Delhi", 1, "resident"))
+	_, err = adminService.BootstrapAdmin("aman")
+	assert.ErrorIs(t, err, models.ErrConflict)
+	aman, err := users.FindByUsername("aman")
+	assert.NoError(t, err)
+	assert.Equal(t, models.RoleUser, aman.Role)
+
+	entries, err := audit.Find(models.AuditFilter{TargetType: models.TargetUser})
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, models.AuditChangeRole, entries[0].Action)
+		assert.Equal(t, admin.User.UId, entries[0].TargetId)
+	}
+}
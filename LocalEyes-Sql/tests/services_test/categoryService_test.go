@@ -0,0 +1,50 @@
+package services_test
+
+import (
+	"database/sql"
+	"localEyes/config"
+	"localEyes/internal/models"
+	"localEyes/internal/services"
+	"localEyes/tests/mocks"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCategoryService_GiveCategories(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockCategoryRepository(ctrl)
+	service := services.NewCategoryService(mockRepo)
+	all := []*models.Category{{Id: 1, Name: "events", IsActive: false}, {Id: 2, Name: "food", IsActive: true}}
+	mockRepo.EXPECT().GetAll().Return(all, nil).Times(2)
+
+	categories, err := service.GiveCategories(false)
+	assert.NoError(t, err)
+	assert.Equal(t, all, categories)
+
+	categories, err = service.GiveCategories(true)
+	assert.NoError(t, err)
+	assert.Equal(t, all[1:], categories)
+}
+
+func TestCategoryService_ValidateCategory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockCategoryRepository(ctrl)
+	service := services.NewCategoryService(mockRepo)
+
+	assert.NoError(t, service.ValidateCategory(""))
+
+	// Inactive categories still filter, their old posts are still around.
+	mockRepo.EXPECT().FindByName("events").Return(&models.Category{Id: 1, Name: "events", IsActive: false}, nil)
+	assert.NoError(t, service.ValidateCategory("events"))
+
+	mockRepo.EXPECT().FindByName("sports").Return(nil, sql.ErrNoRows)
+	mockRepo.EXPECT().GetAll().Return([]*models.Category{{Name: "food", IsActive: true}, {Name: "healthcare", IsActive: true}}, nil)
+	err := service.ValidateCategory("sports")
+	assert.EqualError(t, err, config.Red+"Unknown category sports, use food, healthcare"+config.Reset)
+}
@@ -0,0 +1,35 @@
+package services_test
+
+import (
+	"database/sql"
+	"localEyes/config"
+	"localEyes/internal/models"
+	"localEyes/internal/services"
+	"localEyes/tests/mocks"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCityService_ValidateCity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockCityRepository(ctrl)
+	service := services.NewCityService(mockRepo)
+
+	assert.NoError(t, service.ValidateCity(" "))
+
+	mockRepo.EXPECT().FindByName("pune").Return(&models.City{Id: 2, Name: "pune"}, nil)
+	assert.NoError(t, service.ValidateCity("Pune"))
+
+	mockRepo.EXPECT().FindByName("goa").Return(nil, sql.ErrNoRows)
+	mockRepo.EXPECT().GetAll().Return([]*models.City{{Id: 1, Name: "delhi"}, {Id: 2, Name: "pune"}}, nil)
+	err := service.ValidateCity("goa")
+	assert.EqualError(t, err, config.Red+"Unknown city goa, use delhi, pune"+config.Reset)
+}
+
+func TestCityName(t *testing.T) {
+	assert.Equal(t, "new delhi", services.CityName("  New Delhi "))
+}
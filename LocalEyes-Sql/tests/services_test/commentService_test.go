@@ -0,0 +1,110 @@
+package services_test
+
+import (
+	"localEyes/config"
+	"localEyes/internal/interfaces"
+	"localEyes/internal/models"
+	"localEyes/internal/repositories"
+	"localEyes/internal/services"
+	"localEyes/tests/mocks"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newCommentService returns a comment service over in-memory repositories
+// holding users 1 and 2 and a post by user 1.
+func newCommentService(t *testing.T) (*services.CommentService, *repositories.InMemoryCommentRepository) {
+	users := repositories.NewInMemoryUserRepository()
+	posts := repositories.NewInMemoryPostRepository()
+	comments := repositories.NewInMemoryCommentRepository()
+	uow := repositories.NewInMemoryUnitOfWork(users, posts, repositories.NewInMemoryQuestionRepository(), repositories.NewInMemoryAnswerRepository(), repositories.NewInMemoryPostLikeRepository(), repositories.NewInMemorySessionRepository(), repositories.NewInMemoryAuditRepository(), repositories.NewInMemoryCategoryRepository(), repositories.NewInMemoryCityRepository(), comments, repositories.NewInMemoryNotificationRepository(), repositories.NewInMemoryNotificationPrefsRepository(), repositories.NewInMemoryOutboxRepository())
+	require.NoError(t, users.Create(&models.User{Username: "author"}))
+	require.NoError(t, users.Create(&models.User{Username: "reader"}))
+	require.NoError(t, posts.Create(&models.Post{UId: 1, Title: "Momos"}))
+	return services.NewCommentService(comments, uow), comments
+}
+
+func TestCommentService_Threads(t *testing.T) {
+	service, _ := newCommentService(t)
+
+	require.NoError(t, service.AddComment(2, 1, " Which stall? "))
+	require.NoError(t, service.AddComment(1, 1, "Open till 10"))
+	require.NoError(t, service.Reply(1, 1, "The one by the gate"))
+	require.NoError(t, service.Reply(2, 3, "Thanks"))
+	require.NoError(t, service.Reply(2, 1, "Also, veg?"))
+
+	threads, err := service.GetPostComments(1)
+	require.NoError(t, err)
+	require.Len(t, threads, 2)
+	assert.Equal(t, "Which stall?", threads[0].Text)
+	assert.Equal(t, "reader", threads[0].Username)
+	require.Len(t, threads[0].Replies, 2)
+	assert.Equal(t, "The one by the gate", threads[0].Replies[0].Text)
+	assert.Equal(t, "Thanks", threads[0].Replies[0].Replies[0].Text)
+	assert.Equal(t, "Also, veg?", threads[0].Replies[1].Text)
+	assert.Empty(t, threads[1].Replies)
+
+	thread, err := service.GetThread(3)
+	require.NoError(t, err)
+	assert.Equal(t, "The one by the gate", thread.Text)
+	require.Len(t, thread.Replies, 1)
+	assert.Equal(t, 4, thread.Replies[0].CommentId)
+
+	_, err = service.GetThread(9)
+	assert.EqualError(t, err, config.Red+"No Comment exist with this id"+config.Reset)
+	assert.EqualError(t, service.AddComment(2, 7, "Hi"), config.Red+"No Post exist with this id"+config.Reset)
+	assert.EqualError(t, service.Reply(2, 9, "Hi"), config.Red+"No Comment exist with this id"+config.Reset)
+}
+
+func TestCommentService_DeleteKeepsRepliedThreads(t *testing.T) {
+	service, comments := newCommentService(t)
+	require.NoError(t, service.AddComment(2, 1, "Which stall?"))
+	require.NoError(t, service.Reply(1, 1, "By the gate"))
+	require.NoError(t, service.Reply(2, 2, "Thanks"))
+
+	assert.EqualError(t, service.DeleteMyComment(1, 1), config.Red+"You can only delete your comment"+config.Reset)
+
+	// A comment with replies stays as a placeholder and takes no new ones.
+	require.NoError(t, service.DeleteMyComment(2, 1))
+	threads, err := service.GetPostComments(1)
+	require.NoError(t, err)
+	require.Len(t, threads, 1)
+	assert.True(t, threads[0].Deleted)
+	assert.Empty(t, threads[0].Text)
+	assert.Len(t, threads[0].Replies, 1)
+	assert.EqualError(t, service.Reply(1, 1, "Hello?"), config.Red+"No Comment exist with this id"+config.Reset)
+	assert.EqualError(t, service.DeleteMyComment(2, 1), config.Red+"No Comment exist with this id"+config.Reset)
+	assert.Error(t, service.EditComment(2, 1, "Back again"))
+
+	require.NoError(t, service.DeleteMyComment(1, 2))
+	threads, err = service.GetPostComments(1)
+	require.NoError(t, err)
+	require.Len(t, threads, 1)
+	assert.True(t, threads[0].Replies[0].Deleted)
+
+	// Deleting the last live reply takes the placeholders above it along.
+	require.NoError(t, service.DeleteMyComment(2, 3))
+	left, err := comments.GetCommentsByPId(1)
+	require.NoError(t, err)
+	assert.Empty(t, left)
+}
+
+func TestCommentService_Text(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockCommentRepository(ctrl)
+	service := services.NewCommentService(mockRepo, newUnitsOfWork(ctrl, interfaces.Repositories{Comments: mockRepo}, 0))
+
+	assert.EqualError(t, service.AddComment(1, 1, "   "), config.Red+"Comments cannot be empty"+config.Reset)
+	assert.EqualError(t, service.Reply(1, 1, strings.Repeat("a", models.MaxCommentLength+1)),
+		config.Red+"Comments are at most 1000 characters"+config.Reset)
+	assert.EqualError(t, service.EditComment(1, 1, ""), config.Red+"Comments cannot be empty"+config.Reset)
+
+	mockRepo.EXPECT().UpdateUserComment(1, 2, "Edited").Return(nil)
+	assert.NoError(t, service.EditComment(2, 1, " Edited "))
+}
@@ -0,0 +1,213 @@
+package services_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"localEyes/internal/interfaces"
+	"localEyes/internal/models"
+	"localEyes/internal/services"
+	"localEyes/tests/mocks"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationHub_ListenPublish(t *testing.T) {
+	hub := services.NewNotificationHub()
+	first, stopFirst := hub.Listen(2)
+	second, stopSecond := hub.Listen(2)
+	other, stopOther := hub.Listen(3)
+	defer stopOther()
+
+	hub.Publish(&models.Notification{Id: 1, UserId: 2, Message: "New post: Momos"})
+	assert.Equal(t, "New post: Momos", (<-first).Message)
+	assert.Equal(t, "New post: Momos", (<-second).Message)
+	assert.Empty(t, other)
+
+	// Stopping closes the channel and is safe to repeat.
+	stopFirst()
+	stopFirst()
+	_, open := <-first
+	assert.False(t, open)
+	hub.Publish(&models.Notification{Id: 2, UserId: 2, Message: "New post: Metro"})
+	assert.Equal(t, "New post: Metro", (<-second).Message)
+
+	hub.Close()
+	_, open = <-second
+	assert.False(t, open)
+	stopSecond()
+	late, _ := hub.Listen(2)
+	_, open = <-late
+	assert.False(t, open)
+}
+
+func TestNotificationHub_PublishesEachIdOnce(t *testing.T) {
+	hub := services.NewNotificationHub()
+	live, stop := hub.Listen(2)
+	defer stop()
+
+	hub.Publish(&models.Notification{Id: 1, UserId: 2})
+	hub.Publish(&models.Notification{Id: 1, UserId: 2})
+	assert.Len(t, live, 1)
+}
+
+func TestNotificationHub_SlowListenerMissesOut(t *testing.T) {
+	hub := services.NewNotificationHub()
+	live, stop := hub.Listen(2)
+	defer stop()
+
+	for i := 1; i <= 100; i++ {
+		hub.Publish(&models.Notification{Id: i, UserId: 2})
+	}
+	assert.Less(t, len(live), 100)
+	assert.Equal(t, 1, (<-live).Id)
+}
+
+func TestNotificationService_FollowPushesNewNotifications(t *testing.T) {
+	service, repos := newNotificationServiceWithRepos(t)
+	require.NoError(t, service.NotifyNewPost(&models.Post{PostId: 1, UId: 1, City: "delhi", Title: "Old news"}))
+	source := services.NewPollingSource(repos.Notifications)
+	source.Interval = 5 * time.Millisecond
+	source.BatchSize = 1
+	ctx, cancel := context.WithCancel(context.Background())
+	followed := make(chan error, 1)
+	live, stop := service.Listen(2)
+	defer stop()
+	go func() { followed <- service.Follow(ctx, source) }()
+
+	// What was there before following started is not pushed.
+	time.Sleep(20 * time.Millisecond)
+	assert.Empty(t, live)
+	require.NoError(t, service.NotifyNewPost(&models.Post{PostId: 2, UId: 1, City: "delhi", Title: "Momos"}))
+	require.NoError(t, service.NotifyNewPost(&models.Post{PostId: 3, UId: 1, City: "delhi", Title: "Metro"}))
+	for _, want := range []string{"New post: Momos", "New post: Metro"} {
+		select {
+		case notification := <-live:
+			assert.Equal(t, want, notification.Message)
+			assert.Equal(t, 2, notification.UserId)
+		case <-time.After(time.Second):
+			t.Fatal("no notification pushed")
+		}
+	}
+	// The feed finds them too, but they were pushed when they committed.
+	time.Sleep(20 * time.Millisecond)
+	assert.Empty(t, live)
+
+	cancel()
+	assert.NoError(t, <-followed)
+	_, open := <-live
+	assert.False(t, open, "listeners end with the feed")
+}
+
+func TestNotificationService_PublishesWhatCommits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, repos := newNotificationServiceWithRepos(t)
+	uow := service.Publishing(newUnitsOfWork(ctrl, repos, 2))
+	live, stop := service.Listen(2)
+	defer stop()
+
+	require.NoError(t, service.NotifyNewPost(&models.Post{PostId: 1, UId: 1, City: "delhi", Title: "Momos"}))
+	assert.Equal(t, "New post: Momos", (<-live).Message)
+
+	err := uow.Do(func(repos interfaces.Repositories) error {
+		require.NoError(t, repos.Notifications.Create(&models.Notification{UserId: 2, Message: "Rolled back"}))
+		return sql.ErrConnDone
+	})
+	assert.ErrorIs(t, err, sql.ErrConnDone)
+	require.NoError(t, uow.Do(func(repos interfaces.Repositories) error {
+		require.NoError(t, repos.Notifications.Create(&models.Notification{UserId: 2, Message: "Held", Held: true}))
+		return repos.Notifications.Create(&models.Notification{UserId: 2, Message: "Answered"})
+	}))
+	assert.Equal(t, "Answered", (<-live).Message)
+	assert.Empty(t, live)
+}
+
+func TestPollingSource_StartFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockNotificationRepository(ctrl)
+	repo.EXPECT().LastId().Return(0, errors.New("database is locked"))
+	service := services.NewNotificationService(repo, nil, nil)
+	live, _ := service.Listen(2)
+
+	err := service.Follow(context.Background(), services.NewPollingSource(repo))
+
+	assert.EqualError(t, err, "database is locked")
+	_, open := <-live
+	assert.False(t, open)
+}
+
+func TestPollingSource_LateCommitIsPushedOnce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockNotificationRepository(ctrl)
+	now := time.Now()
+	source := services.NewPollingSource(repo)
+	source.Interval = time.Millisecond
+	source.Now = func() time.Time { return now }
+	late := &models.Notification{Id: 2, UserId: 2, Message: "Late"}
+	early := &models.Notification{Id: 3, UserId: 2, Message: "Early"}
+	released := &models.Notification{Id: 5, UserId: 2, Message: "Digested", IsRead: true}
+	latest := &models.Notification{Id: 6, UserId: 2, Message: "Latest"}
+	ctx, cancel := context.WithCancel(context.Background())
+	gomock.InOrder(
+		repo.EXPECT().LastId().Return(1, nil),
+		// 2 is still being committed when 3 shows up.
+		repo.EXPECT().GetSince(1, 100).Return([]*models.Notification{early}, nil),
+		repo.EXPECT().GetSince(1, 100).Return([]*models.Notification{late, early}, nil),
+		// 4 never shows up and is given up on once Settle has passed.
+		repo.EXPECT().GetSince(3, 100).Return([]*models.Notification{released, latest}, nil),
+		repo.EXPECT().GetSince(3, 100).DoAndReturn(func(int, int) ([]*models.Notification, error) {
+			now = now.Add(source.Settle)
+			return []*models.Notification{released, latest}, nil
+		}),
+		repo.EXPECT().GetSince(6, 100).DoAndReturn(func(int, int) ([]*models.Notification, error) {
+			cancel()
+			return nil, nil
+		}),
+	)
+
+	var pushed []string
+	assert.NoError(t, source.Run(ctx, func(notification *models.Notification) {
+		pushed = append(pushed, notification.Message)
+	}))
+	assert.Equal(t, []string{"Early", "Late", "Latest"}, pushed)
+}
+
+func TestPollingSource_LargeGapIsBounded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockNotificationRepository(ctrl)
+	source := services.NewPollingSource(repo)
+	source.Interval = time.Millisecond
+	source.MaxGaps = 10
+	jumped := 1 << 40
+	far := &models.Notification{Id: jumped, UserId: 2, Message: "Far"}
+	late := &models.Notification{Id: jumped - 3, UserId: 2, Message: "Late"}
+	ctx, cancel := context.WithCancel(context.Background())
+	gomock.InOrder(
+		repo.EXPECT().LastId().Return(1, nil),
+		repo.EXPECT().GetSince(1, 100).Return([]*models.Notification{far}, nil),
+		// Only the ten ids just below the jump are looked for again.
+		repo.EXPECT().GetSince(jumped-11, 100).Return([]*models.Notification{late, far}, nil),
+		repo.EXPECT().GetSince(jumped-11, 100).DoAndReturn(func(int, int) ([]*models.Notification, error) {
+			cancel()
+			return []*models.Notification{late, far}, nil
+		}),
+	)
+
+	var pushed []string
+	assert.NoError(t, source.Run(ctx, func(notification *models.Notification) {
+		pushed = append(pushed, notification.Message)
+	}))
+	assert.Equal(t, []string{"Far", "Late"}, pushed)
+}
@@ -0,0 +1,226 @@
+package services_test
+
+import (
+	"database/sql"
+	"localEyes/config"
+	"localEyes/internal/interfaces"
+	"localEyes/internal/models"
+	"localEyes/internal/repositories"
+	"localEyes/internal/services"
+	"localEyes/tests/mocks"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newNotificationService returns a notification service over in-memory
+// repositories holding users 1 and 2 in delhi and user 3 in pune.
+func newNotificationService(t *testing.T) *services.NotificationService {
+	service, _ := newNotificationServiceWithRepos(t)
+	return service
+}
+
+// newNotificationServiceWithRepos is newNotificationService also returning
+// the repositories, for tests that set up state the service cannot.
+func newNotificationServiceWithRepos(t *testing.T) (*services.NotificationService, interfaces.Repositories) {
+	users := repositories.NewInMemoryUserRepository()
+	notifications := repositories.NewInMemoryNotificationRepository()
+	notificationPrefs := repositories.NewInMemoryNotificationPrefsRepository()
+	uow := repositories.NewInMemoryUnitOfWork(users, repositories.NewInMemoryPostRepository(), repositories.NewInMemoryQuestionRepository(), repositories.NewInMemoryAnswerRepository(), repositories.NewInMemoryPostLikeRepository(), repositories.NewInMemorySessionRepository(), repositories.NewInMemoryAuditRepository(), repositories.NewInMemoryCategoryRepository(), repositories.NewInMemoryCityRepository(), repositories.NewInMemoryCommentRepository(), notifications, notificationPrefs, repositories.NewInMemoryOutboxRepository())
+	require.NoError(t, users.Create(&models.User{Username: "author", City: "delhi"}))
+	require.NoError(t, users.Create(&models.User{Username: "reader", City: "delhi"}))
+	require.NoError(t, users.Create(&models.User{Username: "faraway", City: "pune"}))
+	return services.NewNotificationService(notifications, notificationPrefs, uow),
+		interfaces.Repositories{Users: users, Notifications: notifications, NotificationPrefs: notificationPrefs}
+}
+
+func TestNotificationService_InboxKeepsWhatWasShown(t *testing.T) {
+	service := newNotificationService(t)
+	require.NoError(t, service.NotifyNewPost(&models.Post{PostId: 1, UId: 1, City: "delhi", Title: "Momos"}))
+	require.NoError(t, service.NotifyNewPost(&models.Post{PostId: 2, UId: 1, City: "delhi", Title: "Metro"}))
+
+	for uId, want := range map[int]int{1: 0, 2: 2, 3: 0} {
+		count, err := service.UnreadCount(uId)
+		require.NoError(t, err)
+		assert.Equal(t, want, count, "user %d", uId)
+	}
+
+	unread, err := service.TakeUnread(2)
+	require.NoError(t, err)
+	require.Len(t, unread, 2)
+	assert.Equal(t, "New post: Momos", unread[0].Message)
+	assert.Equal(t, models.TargetPost, unread[0].SourceType)
+	assert.Equal(t, 1, unread[0].SourceId)
+	unread, err = service.TakeUnread(2)
+	require.NoError(t, err)
+	assert.Empty(t, unread)
+
+	inbox, err := service.Inbox(2, false, models.PageRequest{})
+	require.NoError(t, err)
+	require.Len(t, inbox.Items, 2)
+	assert.Equal(t, "New post: Metro", inbox.Items[0].Message)
+	assert.True(t, inbox.Items[0].IsRead)
+	unreadPage, err := service.Inbox(2, true, models.PageRequest{})
+	require.NoError(t, err)
+	assert.Empty(t, unreadPage.Items)
+
+	_, err = service.Inbox(2, false, models.PageRequest{Sort: models.SortMostLiked})
+	assert.Error(t, err)
+}
+
+func TestNotificationService_MarkRead(t *testing.T) {
+	service := newNotificationService(t)
+	require.NoError(t, service.NotifyNewPost(&models.Post{PostId: 1, UId: 1, City: "delhi", Title: "Momos"}))
+	require.NoError(t, service.NotifyNewPost(&models.Post{PostId: 2, UId: 2, City: "delhi", Title: "Metro"}))
+
+	assert.EqualError(t, service.MarkRead(1, 1), config.Red+"No Notification exist with this id"+config.Reset)
+	assert.EqualError(t, service.MarkRead(2, 9), config.Red+"No Notification exist with this id"+config.Reset)
+	require.NoError(t, service.MarkRead(2, 1))
+	count, err := service.UnreadCount(2)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	require.NoError(t, service.MarkAllRead(1))
+	count, err = service.UnreadCount(1)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
+
+func TestNotificationService_TakeUnreadError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockNotificationRepository(ctrl)
+	mockPrefsRepo := mocks.NewMockNotificationPrefsRepository(ctrl)
+	service := services.NewNotificationService(mockRepo, mockPrefsRepo, mocks.NewMockUnitOfWork(ctrl))
+
+	mockPrefsRepo.EXPECT().FindByUId(2).Return(&models.NotificationPrefs{UserId: 2, Delivery: models.DeliverImmediately, Posts: models.PostsAll}, nil)
+	mockRepo.EXPECT().GetUserNotifications(2, true, models.SortOldest, models.MaxPageSize, 0).
+		Return([]*models.Notification{{Id: 4, UserId: 2}}, nil)
+	mockRepo.EXPECT().MarkRead(2, []int{4}).Return(sql.ErrConnDone)
+
+	_, err := service.TakeUnread(2)
+	assert.ErrorIs(t, err, sql.ErrConnDone)
+}
+
+func TestNotificationService_UpdatePreferences(t *testing.T) {
+	service := newNotificationService(t)
+
+	_, err := service.UpdatePreferences(2, "weekly", "")
+	assert.EqualError(t, err, config.Red+"Unknown delivery weekly, use immediate, digest, off"+config.Reset)
+	_, err = service.UpdatePreferences(2, "", "some")
+	assert.EqualError(t, err, config.Red+"Unknown posts setting some, use all, subscribed, none"+config.Reset)
+
+	prefs, err := service.UpdatePreferences(2, models.DeliverOff, "")
+	require.NoError(t, err)
+	assert.Equal(t, models.PostsAll, prefs.Posts)
+	require.NoError(t, service.NotifyNewPost(&models.Post{PostId: 1, UId: 1, City: "delhi", Title: "Momos"}))
+	count, err := service.UnreadCount(2)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	_, err = service.UpdatePreferences(2, models.DeliverImmediately, models.PostsNone)
+	require.NoError(t, err)
+	require.NoError(t, service.NotifyNewPost(&models.Post{PostId: 2, UId: 1, City: "delhi", Title: "Metro"}))
+	prefs, err = service.Preferences(2)
+	require.NoError(t, err)
+	assert.Equal(t, models.DeliverImmediately, prefs.Delivery)
+	assert.Equal(t, models.PostsNone, prefs.Posts)
+	count, err = service.UnreadCount(2)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
+
+func TestNotificationService_Subscriptions(t *testing.T) {
+	service := newNotificationService(t)
+	_, err := service.UpdatePreferences(2, "", models.PostsSubscribed)
+	require.NoError(t, err)
+
+	assert.EqualError(t, service.Subscribe(2, models.TargetCategory, "gossip"), config.Red+"Unknown category gossip, use food, other, shopping, travel"+config.Reset)
+	assert.EqualError(t, service.Subscribe(2, models.TargetUser, "nobody"), config.Red+"No user exist with this username"+config.Reset)
+	assert.EqualError(t, service.Subscribe(2, models.TargetUser, "reader"), config.Red+"You cannot subscribe to yourself"+config.Reset)
+	require.NoError(t, service.Subscribe(2, models.TargetCategory, "food"))
+	assert.EqualError(t, service.Subscribe(2, models.TargetCategory, "food"), config.Red+"You are already subscribed to that category"+config.Reset)
+
+	require.NoError(t, service.NotifyNewPost(&models.Post{PostId: 1, UId: 1, City: "delhi", Type: "food", Title: "Momos"}))
+	require.NoError(t, service.NotifyNewPost(&models.Post{PostId: 2, UId: 1, City: "delhi", Type: "travel", Title: "Metro"}))
+	unread, err := service.TakeUnread(2)
+	require.NoError(t, err)
+	require.Len(t, unread, 1)
+	assert.Equal(t, "New post: Momos", unread[0].Message)
+
+	require.NoError(t, service.Subscribe(2, models.TargetUser, "author"))
+	require.NoError(t, service.NotifyNewPost(&models.Post{PostId: 3, UId: 1, City: "delhi", Type: "travel", Title: "Auto fares"}))
+	prefs, err := service.Preferences(2)
+	require.NoError(t, err)
+	assert.Len(t, prefs.Subscriptions, 2)
+	require.NoError(t, service.Unsubscribe(2, models.TargetUser, "author"))
+	assert.EqualError(t, service.Unsubscribe(2, models.TargetUser, "author"), config.Red+"You are not subscribed to that author"+config.Reset)
+	require.NoError(t, service.NotifyNewPost(&models.Post{PostId: 4, UId: 1, City: "delhi", Type: "travel", Title: "Metro again"}))
+	unread, err = service.TakeUnread(2)
+	require.NoError(t, err)
+	require.Len(t, unread, 1)
+	assert.Equal(t, "New post: Auto fares", unread[0].Message)
+}
+
+func TestNotificationService_Digest(t *testing.T) {
+	service, repos := newNotificationServiceWithRepos(t)
+	_, err := service.UpdatePreferences(2, models.DeliverDigest, "")
+	require.NoError(t, err)
+	for i, title := range []string{"Momos", "Metro", "Chaat", "Kulfi", "Lassi"} {
+		require.NoError(t, service.NotifyNewPost(&models.Post{PostId: i + 1, UId: 1, City: "delhi", Title: title}))
+	}
+
+	// Nothing shows until a digest is due.
+	count, err := service.UnreadCount(2)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+	assert.EqualError(t, service.MarkRead(2, 1), config.Red+"No Notification exist with this id"+config.Reset)
+
+	prefs, err := repos.NotificationPrefs.FindByUId(2)
+	require.NoError(t, err)
+	prefs.DigestAt = time.Now().Add(-models.DigestInterval)
+	require.NoError(t, repos.NotificationPrefs.Save(prefs))
+	unread, err := service.TakeUnread(2)
+	require.NoError(t, err)
+	require.Len(t, unread, 1)
+	assert.Equal(t, models.NotifyDigest, unread[0].Type)
+	assert.Equal(t, "Digest of 5 notifications: New post: Momos; New post: Metro; New post: Chaat; and 2 more", unread[0].Message)
+	inbox, err := service.Inbox(2, false, models.PageRequest{})
+	require.NoError(t, err)
+	assert.Len(t, inbox.Items, 6)
+
+	// The clock restarted, and leaving digests sends what is held at once.
+	require.NoError(t, service.NotifyNewPost(&models.Post{PostId: 6, UId: 1, City: "delhi", Title: "Paratha"}))
+	count, err = service.UnreadCount(2)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+	_, err = service.UpdatePreferences(2, models.DeliverImmediately, "")
+	require.NoError(t, err)
+	unread, err = service.TakeUnread(2)
+	require.NoError(t, err)
+	require.Len(t, unread, 1)
+	assert.Equal(t, "Digest of 1 notifications: New post: Paratha", unread[0].Message)
+}
+
+func TestNotificationService_DigestUndoneOnError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockNotificationRepository(ctrl)
+	mockPrefsRepo := mocks.NewMockNotificationPrefsRepository(ctrl)
+	uow := newUnitOfWork(ctrl, interfaces.Repositories{Notifications: mockRepo, NotificationPrefs: mockPrefsRepo})
+	service := services.NewNotificationService(mockRepo, mockPrefsRepo, uow)
+
+	prefs := &models.NotificationPrefs{UserId: 2, Delivery: models.DeliverDigest, Posts: models.PostsAll, DigestAt: time.Now().Add(-48 * time.Hour)}
+	mockPrefsRepo.EXPECT().FindByUId(2).Return(prefs, nil)
+	mockRepo.EXPECT().GetHeld(2).Return([]*models.Notification{{Id: 4, UserId: 2, Message: "New post: Momos", Held: true}}, nil)
+	mockRepo.EXPECT().Create(gomock.Any()).Return(nil)
+	mockRepo.EXPECT().Release(2).Return(sql.ErrConnDone)
+
+	_, err := service.UnreadCount(2)
+	assert.ErrorIs(t, err, sql.ErrConnDone)
+}
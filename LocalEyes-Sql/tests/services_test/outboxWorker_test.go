@@ -0,0 +1,213 @@
+package services_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"localEyes/internal/interfaces"
+	"localEyes/internal/models"
+	"localEyes/internal/repositories"
+	"localEyes/internal/services"
+	"localEyes/tests/mocks"
+	"localEyes/utils"
+	"log"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The outbox worker logs the deliveries that fail.
+func TestMain(m *testing.M) {
+	utils.Logger = log.New(io.Discard, "", 0)
+	os.Exit(m.Run())
+}
+
+func TestOutboxWorker_AnnouncesNewPosts(t *testing.T) {
+	users := repositories.NewInMemoryUserRepository()
+	posts := repositories.NewInMemoryPostRepository()
+	notifications := repositories.NewInMemoryNotificationRepository()
+	outbox := repositories.NewInMemoryOutboxRepository()
+	uow := repositories.NewInMemoryUnitOfWork(users, posts, repositories.NewInMemoryQuestionRepository(), repositories.NewInMemoryAnswerRepository(), repositories.NewInMemoryPostLikeRepository(), repositories.NewInMemorySessionRepository(), repositories.NewInMemoryAuditRepository(), repositories.NewInMemoryCategoryRepository(), repositories.NewInMemoryCityRepository(), repositories.NewInMemoryCommentRepository(), notifications, repositories.NewInMemoryNotificationPrefsRepository(), outbox)
+	require.NoError(t, users.Create(&models.User{Username: "author", City: "delhi"}))
+	require.NoError(t, users.Create(&models.User{Username: "reader", City: "delhi"}))
+	postService := services.NewPostService(posts, nil, uow)
+	worker := services.NewOutboxWorker(outbox, uow)
+
+	momos, err := postService.CreatePost(1, "Momos", "Near the metro", "food", models.Location{})
+	require.NoError(t, err)
+	metro, err := postService.CreatePost(1, "Metro", "Closed today", "travel", models.Location{})
+	require.NoError(t, err)
+	require.NoError(t, posts.DeleteByPId(metro.PostId))
+
+	// Nothing goes out until the worker runs.
+	count, err := notifications.CountUnread(2)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	require.NoError(t, worker.Flush())
+	inbox, err := notifications.GetUserNotifications(2, true, models.SortOldest, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, inbox, 1, "the deleted post is not announced")
+	assert.Equal(t, "New post: Momos", inbox[0].Message)
+	assert.Equal(t, momos.PostId, inbox[0].SourceId)
+	due, err := outbox.GetDue(time.Now().Add(time.Hour), 10)
+	require.NoError(t, err)
+	assert.Empty(t, due)
+}
+
+// stalledUnitOfWork runs stall before its first unit of work, standing in
+// for a delivery held up past its lease.
+type stalledUnitOfWork struct {
+	interfaces.UnitOfWork
+	stall   func()
+	stalled atomic.Bool
+}
+
+func (u *stalledUnitOfWork) Do(fn func(repos interfaces.Repositories) error) error {
+	if u.stalled.CompareAndSwap(false, true) {
+		u.stall()
+	}
+	return u.UnitOfWork.Do(fn)
+}
+
+func TestOutboxWorker_LeaseRunsOutMidDelivery(t *testing.T) {
+	users := repositories.NewInMemoryUserRepository()
+	posts := repositories.NewInMemoryPostRepository()
+	notifications := repositories.NewInMemoryNotificationRepository()
+	outbox := repositories.NewInMemoryOutboxRepository()
+	uow := repositories.NewInMemoryUnitOfWork(users, posts, repositories.NewInMemoryQuestionRepository(), repositories.NewInMemoryAnswerRepository(), repositories.NewInMemoryPostLikeRepository(), repositories.NewInMemorySessionRepository(), repositories.NewInMemoryAuditRepository(), repositories.NewInMemoryCategoryRepository(), repositories.NewInMemoryCityRepository(), repositories.NewInMemoryCommentRepository(), notifications, repositories.NewInMemoryNotificationPrefsRepository(), outbox)
+	require.NoError(t, users.Create(&models.User{Username: "author", City: "delhi"}))
+	require.NoError(t, users.Create(&models.User{Username: "reader", City: "delhi"}))
+	_, err := services.NewPostService(posts, nil, uow).CreatePost(1, "Momos", "Near the metro", "food", models.Location{})
+	require.NoError(t, err)
+	now := time.Now()
+
+	// While the first worker is held up, its lease runs out and a second
+	// worker claims the entry and delivers it.
+	second := services.NewOutboxWorker(outbox, uow)
+	second.Now = func() time.Time { return now.Add(2 * services.DefaultOutboxLease) }
+	first := services.NewOutboxWorker(outbox, &stalledUnitOfWork{UnitOfWork: uow, stall: func() {
+		assert.NoError(t, second.Flush())
+	}})
+	first.Now = func() time.Time { return now }
+
+	require.NoError(t, first.Flush())
+
+	inbox, err := notifications.GetUserNotifications(2, false, models.SortOldest, 10, 0)
+	require.NoError(t, err)
+	assert.Len(t, inbox, 1, "the post is announced once")
+	_, err = outbox.FindById(1)
+	assert.ErrorIs(t, err, sql.ErrNoRows, "the late first worker neither retries nor buries the entry")
+}
+
+func TestOutboxWorker_RetriesThenGivesUp(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	outbox := repositories.NewInMemoryOutboxRepository()
+	uow := mocks.NewMockUnitOfWork(ctrl)
+	uow.EXPECT().Do(gomock.Any()).Return(errors.New("database is locked")).Times(3)
+	now := time.Now()
+	require.NoError(t, outbox.Create(&models.OutboxEntry{PostId: 1, AvailableAt: now, CreatedAt: now}))
+	worker := services.NewOutboxWorker(outbox, uow)
+	worker.MaxAttempts = 3
+	worker.Now = func() time.Time { return now }
+	var waits []int
+	worker.Backoff = func(attempts int) time.Duration {
+		waits = append(waits, attempts)
+		return time.Minute
+	}
+
+	for attempt := 1; attempt <= 3; attempt++ {
+		require.NoError(t, worker.Flush())
+		entry, err := outbox.FindById(1)
+		require.NoError(t, err)
+		assert.Equal(t, attempt, entry.Attempts)
+		assert.Equal(t, "database is locked", entry.LastError)
+		// A failed entry waits out its backoff.
+		require.NoError(t, worker.Flush())
+		now = now.Add(time.Minute)
+	}
+	entry, err := outbox.FindById(1)
+	require.NoError(t, err)
+	assert.True(t, entry.Dead)
+	assert.Equal(t, []int{1, 2}, waits)
+	require.NoError(t, worker.Flush())
+}
+
+func TestOutboxBackoff(t *testing.T) {
+	assert.Equal(t, 2*time.Second, services.OutboxBackoff(1))
+	assert.Equal(t, 8*time.Second, services.OutboxBackoff(3))
+	assert.Equal(t, 10*time.Minute, services.OutboxBackoff(20))
+}
+
+func TestOutboxWorker_BoundsConcurrency(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	outbox := repositories.NewInMemoryOutboxRepository()
+	now := time.Now()
+	for i := 1; i <= 6; i++ {
+		require.NoError(t, outbox.Create(&models.OutboxEntry{PostId: i, AvailableAt: now, CreatedAt: now}))
+	}
+	var mu sync.Mutex
+	running, most := 0, 0
+	uow := mocks.NewMockUnitOfWork(ctrl)
+	uow.EXPECT().Do(gomock.Any()).DoAndReturn(func(fn func(repos interfaces.Repositories) error) error {
+		mu.Lock()
+		running++
+		most = max(most, running)
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		running--
+		mu.Unlock()
+		return fn(interfaces.Repositories{Posts: repositories.NewInMemoryPostRepository(), Outbox: outbox})
+	}).Times(6)
+	worker := services.NewOutboxWorker(outbox, uow)
+	worker.Workers = 2
+	worker.BatchSize = 4
+
+	require.NoError(t, worker.Flush())
+	assert.Equal(t, 2, most)
+	due, err := outbox.GetDue(now, 10)
+	require.NoError(t, err)
+	assert.Empty(t, due)
+}
+
+func TestOutboxWorker_StartStop(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	outbox := repositories.NewInMemoryOutboxRepository()
+	release := make(chan struct{})
+	uow := mocks.NewMockUnitOfWork(ctrl)
+	uow.EXPECT().Do(gomock.Any()).DoAndReturn(func(fn func(repos interfaces.Repositories) error) error {
+		<-release
+		return fn(interfaces.Repositories{Posts: repositories.NewInMemoryPostRepository(), Outbox: outbox})
+	})
+	worker := services.NewOutboxWorker(outbox, uow)
+	worker.PollInterval = time.Hour
+	worker.Start()
+	now := time.Now()
+	require.NoError(t, outbox.Create(&models.OutboxEntry{PostId: 1, AvailableAt: now, CreatedAt: now}))
+
+	// Stop gives up when its context ends before the delivery does...
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, worker.Stop(ctx), context.DeadlineExceeded)
+
+	// ...and the delivery still finishes once it can.
+	close(release)
+	assert.Eventually(t, func() bool {
+		_, err := outbox.FindById(1)
+		return err != nil
+	}, time.Second, 5*time.Millisecond)
+}
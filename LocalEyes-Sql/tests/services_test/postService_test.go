@@ -1,9 +1,15 @@
 package services_test
 
 import (
+	"database/sql"
+	"errors"
+	"localEyes/config"
+	"localEyes/internal/interfaces"
 	"localEyes/internal/models"
+	"localEyes/internal/repositories"
 	"localEyes/internal/services"
 	"localEyes/tests/mocks"
+	"strings"
 	"testing"
 	"time"
 
@@ -16,25 +22,125 @@ func TestCreatePost(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockPostRepository(ctrl)
-	service := services.NewPostService(mockRepo)
+	mockCategoryRepo := mocks.NewMockCategoryRepository(ctrl)
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockOutboxRepo := mocks.NewMockOutboxRepository(ctrl)
+	uow := newUnitOfWork(ctrl, interfaces.Repositories{Posts: mockRepo, Categories: mockCategoryRepo, Users: mockUserRepo, Outbox: mockOutboxRepo})
+	service := services.NewPostService(mockRepo, nil, uow)
 
+	latitude, longitude := 18.5362, 73.8939
 	post := &models.Post{
+		PostId:    7,
 		UId:       1,
 		Title:     "Test Post",
 		Content:   "Test Content",
-		Type:      "Travel",
+		Type:      "travel",
 		CreatedAt: time.Now(),
 		Likes:     0,
+		City:      "pune",
+		Location:  models.Location{Neighbourhood: "koregaon park", Latitude: &latitude, Longitude: &longitude},
 	}
 
-	// Set up expectations
-	mockRepo.EXPECT().Create(post).Return(nil)
+	// Set up expectations; CreatedAt is stamped inside the service and the
+	// city comes from the author
+	mockCategoryRepo.EXPECT().FindByName("travel").Return(&models.Category{Id: 2, Name: "travel", IsActive: true}, nil)
+	mockUserRepo.EXPECT().FindByUId(1).Return(&models.User{UId: 1, City: "pune"}, nil)
+	mockRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(created *models.Post) error {
+		assert.WithinDuration(t, post.CreatedAt, created.CreatedAt, time.Second)
+		created.PostId = 7
+		created.CreatedAt = post.CreatedAt
+		assert.Equal(t, post, created)
+		return nil
+	})
+	// The post is queued for announcing in the same unit of work.
+	mockOutboxRepo.EXPECT().Create(&models.OutboxEntry{PostId: 7, AvailableAt: post.CreatedAt, CreatedAt: post.CreatedAt}).Return(nil)
 
 	// Call the method
-	err := service.CreatePost(post.UId, post.Title, post.Content, post.Type)
+	created, err := service.CreatePost(post.UId, post.Title, post.Content, post.Type,
+		models.Location{Neighbourhood: " Koregaon  Park", Latitude: &latitude, Longitude: &longitude})
 
 	// Assert results
 	assert.NoError(t, err)
+	assert.Equal(t, post, created)
+}
+
+func TestCreatePost_Category(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPostRepository(ctrl)
+	mockCategoryRepo := mocks.NewMockCategoryRepository(ctrl)
+	uow := newUnitsOfWork(ctrl, interfaces.Repositories{Posts: mockRepo, Categories: mockCategoryRepo}, 2)
+	service := services.NewPostService(mockRepo, nil, uow)
+
+	mockCategoryRepo.EXPECT().FindByName("sports").Return(nil, sql.ErrNoRows)
+	mockCategoryRepo.EXPECT().GetAll().Return([]*models.Category{
+		{Name: "food", IsActive: true}, {Name: "events", IsActive: false}, {Name: "travel", IsActive: true}}, nil)
+	_, err := service.CreatePost(1, "Match day", "Who is going?", "sports", models.Location{})
+	assert.EqualError(t, err, config.Red+"Unknown category sports, use food, travel"+config.Reset)
+
+	mockCategoryRepo.EXPECT().FindByName("events").Return(&models.Category{Id: 5, Name: "events", IsActive: false}, nil)
+	_, err = service.CreatePost(1, "Fair", "This weekend", "events", models.Location{})
+	assert.EqualError(t, err, config.Red+"Category events is not taking new posts"+config.Reset)
+}
+
+func TestCreatePost_Location(t *testing.T) {
+	service := services.NewPostService(nil, nil, nil)
+	latitude, longitude := 28.5494, 191.0
+
+	_, err := service.CreatePost(1, "Chaat", "Best in town", "food", models.Location{Latitude: &latitude})
+	assert.EqualError(t, err, config.Red+"Give both latitude and longitude, or neither"+config.Reset)
+
+	_, err = service.CreatePost(1, "Chaat", "Best in town", "food", models.Location{Latitude: &latitude, Longitude: &longitude})
+	assert.EqualError(t, err, config.Red+"Latitude must be between -90 and 90 and longitude between -180 and 180"+config.Reset)
+
+	_, err = service.CreatePost(1, "Chaat", "Best in town", "food", models.Location{Neighbourhood: strings.Repeat("a", 61)})
+	assert.EqualError(t, err, config.Red+"Neighbourhoods are at most 60 characters"+config.Reset)
+}
+
+func TestNearPosts_InMemory(t *testing.T) {
+	posts := repositories.NewInMemoryPostRepository()
+	likes := repositories.NewInMemoryPostLikeRepository()
+	service := services.NewPostService(posts, likes, nil)
+	at := func(latitude, longitude float64) models.Location {
+		return models.Location{Latitude: &latitude, Longitude: &longitude}
+	}
+	// Distances are from Connaught Place.
+	assert.NoError(t, posts.Create(&models.Post{UId: 1, Title: "Lodhi Garden", Location: at(28.5931, 77.2197)})) // 3.7 km
+	assert.NoError(t, posts.Create(&models.Post{UId: 1, Title: "Janpath", Location: at(28.6250, 77.2187)}))      // 0.6 km
+	assert.NoError(t, posts.Create(&models.Post{UId: 1, Title: "Gurgaon", Location: at(28.4595, 77.0266)}))      // 25 km
+	assert.NoError(t, posts.Create(&models.Post{UId: 1, Title: "Hauz Khas", Location: models.Location{Neighbourhood: "hauz khas"}}))
+	assert.NoError(t, posts.Create(&models.Post{UId: 1, Title: "Chandni Chowk", Location: at(28.6506, 77.2303)})) // 2.7 km
+
+	page, err := service.NearPosts(2, 28.6315, 77.2167, 5, models.PageRequest{Size: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Janpath", "Chandni Chowk"}, titles(page.Items))
+	assert.InDelta(t, 0.7, *page.Items[0].Distance, 0.1)
+	assert.True(t, page.HasNext)
+
+	page, err = service.NearPosts(2, 28.6315, 77.2167, 5, models.PageRequest{Number: 2, Size: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Lodhi Garden"}, titles(page.Items))
+	assert.False(t, page.HasNext)
+
+	page, err = service.NearPosts(2, 28.6315, 77.2167, 30, models.PageRequest{Sort: models.SortNearest})
+	assert.NoError(t, err)
+	assert.Len(t, page.Items, 4)
+
+	_, err = service.NearPosts(2, 28.6315, 77.2167, 51, models.PageRequest{})
+	assert.EqualError(t, err, config.Red+"The radius must be more than 0 and at most 50 km"+config.Reset)
+	_, err = service.NearPosts(2, 95, 77.2167, 5, models.PageRequest{})
+	assert.Error(t, err)
+	_, err = service.NearPosts(2, 28.6315, 77.2167, 5, models.PageRequest{Sort: models.SortNewest})
+	assert.Error(t, err)
+}
+
+func titles(posts []*models.Post) []string {
+	var names []string
+	for _, post := range posts {
+		names = append(names, post.Title)
+	}
+	return names
 }
 
 func TestUpdateMyPost(t *testing.T) {
@@ -42,7 +148,7 @@ func TestUpdateMyPost(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockPostRepository(ctrl)
-	service := services.NewPostService(mockRepo)
+	service := services.NewPostService(mockRepo, nil, nil)
 
 	postId := 1
 	userId := 1
@@ -64,22 +170,26 @@ func TestGiveAllPosts(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockPostRepository(ctrl)
-	service := services.NewPostService(mockRepo)
+	mockLikeRepo := mocks.NewMockPostLikeRepository(ctrl)
+	service := services.NewPostService(mockRepo, mockLikeRepo, nil)
 
 	posts := []*models.Post{
-		{UId: 1, Title: "Post 1"},
-		{UId: 2, Title: "Post 2"},
+		{PostId: 1, UId: 1, Title: "Post 1"},
+		{PostId: 2, UId: 2, Title: "Post 2"},
 	}
 
 	// Set up expectations
 	mockRepo.EXPECT().GetAllPosts().Return(posts, nil)
+	mockLikeRepo.EXPECT().GetLikedPIds(3).Return([]int{2}, nil)
 
 	// Call the method
-	result, err := service.GiveAllPosts()
+	result, err := service.GiveAllPosts(3)
 
 	// Assert results
 	assert.NoError(t, err)
 	assert.Equal(t, posts, result)
+	assert.False(t, result[0].LikedByMe)
+	assert.True(t, result[1].LikedByMe)
 }
 
 func TestGiveMyPosts(t *testing.T) {
@@ -87,7 +197,7 @@ func TestGiveMyPosts(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockPostRepository(ctrl)
-	service := services.NewPostService(mockRepo)
+	service := services.NewPostService(mockRepo, nil, nil)
 
 	userId := 1
 	posts := []*models.Post{
@@ -111,13 +221,24 @@ func TestDeleteMyPost(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockPostRepository(ctrl)
-	service := services.NewPostService(mockRepo)
+	mockQuesRepo := mocks.NewMockQuestionRepository(ctrl)
+	mockAnswerRepo := mocks.NewMockAnswerRepository(ctrl)
+	mockLikeRepo := mocks.NewMockPostLikeRepository(ctrl)
+	mockCommentRepo := mocks.NewMockCommentRepository(ctrl)
+	mockOutboxRepo := mocks.NewMockOutboxRepository(ctrl)
+	uow := newUnitOfWork(ctrl, interfaces.Repositories{Posts: mockRepo, Questions: mockQuesRepo, Answers: mockAnswerRepo, Likes: mockLikeRepo, Comments: mockCommentRepo, Outbox: mockOutboxRepo})
+	service := services.NewPostService(mockRepo, mockLikeRepo, uow)
 
 	userId := 1
 	postId := 1
 
 	// Set up expectations
 	mockRepo.EXPECT().DeleteByUIdPId(userId, postId).Return(nil)
+	mockLikeRepo.EXPECT().DeleteByPId(postId).Return(nil)
+	mockAnswerRepo.EXPECT().DeleteByPId(postId).Return(nil)
+	mockQuesRepo.EXPECT().DeleteByPId(postId).Return(nil)
+	mockCommentRepo.EXPECT().DeleteByPId(postId).Return(nil)
+	mockOutboxRepo.EXPECT().DeleteByPId(postId).Return(nil)
 
 	// Call the method
 	err := service.DeleteMyPost(userId, postId)
@@ -131,26 +252,110 @@ func TestLike(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockPostRepository(ctrl)
-	service := services.NewPostService(mockRepo)
+	mockLikeRepo := mocks.NewMockPostLikeRepository(ctrl)
+	uow := newUnitOfWork(ctrl, interfaces.Repositories{Posts: mockRepo, Likes: mockLikeRepo})
+	service := services.NewPostService(mockRepo, mockLikeRepo, uow)
 
+	userId := 2
 	postId := 1
 
 	// Set up expectations
-	mockRepo.EXPECT().UpdateLike(postId).Return(nil)
+	gomock.InOrder(
+		mockRepo.EXPECT().GetPostsByPId(postId).Return([]*models.Post{{PostId: postId, UId: 1}}, nil),
+		mockLikeRepo.EXPECT().Create(userId, postId).Return(nil),
+		mockRepo.EXPECT().UpdateLike(postId).Return(nil),
+	)
 
 	// Call the method
-	err := service.Like(postId)
+	err := service.Like(userId, postId)
 
 	// Assert results
 	assert.NoError(t, err)
 }
 
+func TestLike_OwnPost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPostRepository(ctrl)
+	uow := newUnitOfWork(ctrl, interfaces.Repositories{Posts: mockRepo})
+	service := services.NewPostService(mockRepo, nil, uow)
+
+	mockRepo.EXPECT().GetPostsByPId(1).Return([]*models.Post{{PostId: 1, UId: 1}}, nil)
+
+	err := service.Like(1, 1)
+
+	assert.EqualError(t, err, config.Red+"You cannot like your own post"+config.Reset)
+}
+
+func TestLike_Twice(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPostRepository(ctrl)
+	mockLikeRepo := mocks.NewMockPostLikeRepository(ctrl)
+	uow := newUnitOfWork(ctrl, interfaces.Repositories{Posts: mockRepo, Likes: mockLikeRepo})
+	service := services.NewPostService(mockRepo, mockLikeRepo, uow)
+
+	mockRepo.EXPECT().GetPostsByPId(1).Return([]*models.Post{{PostId: 1, UId: 1}}, nil)
+	mockLikeRepo.EXPECT().Create(2, 1).Return(errors.New(config.Red + "You have already liked this post" + config.Reset))
+
+	err := service.Like(2, 1)
+
+	assert.EqualError(t, err, config.Red+"You have already liked this post"+config.Reset)
+}
+
+func TestUnlike(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPostRepository(ctrl)
+	mockLikeRepo := mocks.NewMockPostLikeRepository(ctrl)
+	uow := newUnitOfWork(ctrl, interfaces.Repositories{Posts: mockRepo, Likes: mockLikeRepo})
+	service := services.NewPostService(mockRepo, mockLikeRepo, uow)
+
+	gomock.InOrder(
+		mockLikeRepo.EXPECT().Delete(2, 1).Return(nil),
+		mockRepo.EXPECT().RemoveLike(1).Return(nil),
+	)
+
+	err := service.Unlike(2, 1)
+
+	assert.NoError(t, err)
+}
+
+func TestLikeUnlike_InMemory(t *testing.T) {
+	users := repositories.NewInMemoryUserRepository()
+	posts := repositories.NewInMemoryPostRepository()
+	likes := repositories.NewInMemoryPostLikeRepository()
+	uow := repositories.NewInMemoryUnitOfWork(users, posts, repositories.NewInMemoryQuestionRepository(), repositories.NewInMemoryAnswerRepository(), likes, repositories.NewInMemorySessionRepository(), repositories.NewInMemoryAuditRepository(), repositories.NewInMemoryCategoryRepository(), repositories.NewInMemoryCityRepository(), repositories.NewInMemoryCommentRepository(), repositories.NewInMemoryNotificationRepository(), repositories.NewInMemoryNotificationPrefsRepository(), repositories.NewInMemoryOutboxRepository())
+	service := services.NewPostService(posts, likes, uow)
+	assert.NoError(t, posts.Create(&models.Post{UId: 1, Title: "Momos", Type: "food"}))
+
+	assert.NoError(t, service.Like(2, 1))
+	assert.Error(t, service.Like(2, 1))
+	assert.NoError(t, service.Like(3, 1))
+
+	feed, err := service.GiveAllPosts(2)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, feed[0].Likes)
+	assert.True(t, feed[0].LikedByMe)
+
+	assert.NoError(t, service.Unlike(2, 1))
+	assert.EqualError(t, service.Unlike(2, 1), config.Red+"You have not liked this post"+config.Reset)
+	feed, err = service.GiveFilteredPosts(2, "food")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, feed[0].Likes)
+	assert.False(t, feed[0].LikedByMe)
+}
+
 func TestGiveFilteredPosts(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockPostRepository(ctrl)
-	service := services.NewPostService(mockRepo)
+	mockLikeRepo := mocks.NewMockPostLikeRepository(ctrl)
+	service := services.NewPostService(mockRepo, mockLikeRepo, nil)
 
 	filterType := "Food"
 	posts := []*models.Post{
@@ -160,21 +365,58 @@ func TestGiveFilteredPosts(t *testing.T) {
 
 	// Set up expectations
 	mockRepo.EXPECT().GetPostsByFilter(filterType).Return(posts, nil)
+	mockLikeRepo.EXPECT().GetLikedPIds(1).Return(nil, nil)
 
 	// Call the method
-	result, err := service.GiveFilteredPosts(filterType)
+	result, err := service.GiveFilteredPosts(1, filterType)
 
 	// Assert results
 	assert.NoError(t, err)
 	assert.Equal(t, posts, result)
 }
 
+func TestListPosts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPostRepository(ctrl)
+	mockLikeRepo := mocks.NewMockPostLikeRepository(ctrl)
+	service := services.NewPostService(mockRepo, mockLikeRepo, nil)
+
+	filter := models.PostFilter{Type: "food"}
+	// One post more than the page holds tells the service there is a next page.
+	mockRepo.EXPECT().ListPosts(filter, models.SortNewest, models.DefaultPageSize+1, models.DefaultPageSize).
+		DoAndReturn(func(models.PostFilter, string, int, int) ([]*models.Post, error) {
+			posts := make([]*models.Post, models.DefaultPageSize+1)
+			for i := range posts {
+				posts[i] = &models.Post{PostId: 20 - i}
+			}
+			return posts, nil
+		})
+	mockLikeRepo.EXPECT().GetLikedPIds(1).Return([]int{20}, nil)
+
+	page, err := service.ListPosts(1, filter, models.PageRequest{Number: 2})
+	assert.NoError(t, err)
+	assert.Len(t, page.Items, models.DefaultPageSize)
+	assert.True(t, page.HasNext)
+	assert.True(t, page.HasPrev())
+	assert.True(t, page.Items[0].LikedByMe)
+	assert.Equal(t, models.PageRequest{Number: 2, Size: models.DefaultPageSize, Sort: models.SortNewest}, page.Request)
+}
+
+func TestListPosts_UnknownSort(t *testing.T) {
+	service := services.NewPostService(nil, nil, nil)
+
+	_, err := service.ListPosts(1, models.PostFilter{}, models.PageRequest{Sort: "title"})
+	assert.EqualError(t, err, config.Red+"Unknown sort order title, use newest, oldest, most-liked, most-questions"+config.Reset)
+}
+
 func TestPostIdExist(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockPostRepository(ctrl)
-	service := services.NewPostService(mockRepo)
+	service := services.NewPostService(mockRepo, nil, nil)
 
 	postId := 1
 	posts := []*models.Post{
@@ -2,11 +2,14 @@ package services_test
 
 import (
 	"errors"
+	"localEyes/config"
+	"localEyes/internal/interfaces"
 	"localEyes/internal/models"
+	"localEyes/internal/repositories"
 	"localEyes/internal/services"
 	"localEyes/tests/mocks"
 	"testing"
-	_ "time"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -17,42 +20,70 @@ func TestQuestionService_AskQuestion(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockQuestionRepository(ctrl)
-	questionService := services.NewQuestionService(mockRepo)
+	mockPostRepo := mocks.NewMockPostRepository(ctrl)
+	mockNotificationRepo := mocks.NewMockNotificationRepository(ctrl)
+	mockPrefsRepo := mocks.NewMockNotificationPrefsRepository(ctrl)
+	uow := newUnitsOfWork(ctrl, interfaces.Repositories{Questions: mockRepo, Posts: mockPostRepo, Notifications: mockNotificationRepo, NotificationPrefs: mockPrefsRepo}, 3)
+	questionService := services.NewQuestionService(mockRepo, nil, uow)
 
 	tests := []struct {
 		name    string
 		userId  int
 		postId  int
 		content string
+		posts   []*models.Post
 		mockErr error
-		wantErr bool
+		wantErr string
 	}{
 		{
 			name:    "successful question creation",
 			userId:  1,
 			postId:  1,
 			content: "Is this place good?",
+			posts:   []*models.Post{{PostId: 1, UId: 2, Title: "Momos"}},
 			mockErr: nil,
-			wantErr: false,
 		},
 		{
 			name:    "repo returns error on create",
 			userId:  1,
 			postId:  1,
 			content: "Is this place good?",
+			posts:   []*models.Post{{PostId: 1, UId: 2, Title: "Momos"}},
 			mockErr: errors.New("DB error"),
-			wantErr: true,
+			wantErr: "DB error",
+		},
+		{
+			name:    "post does not exist",
+			userId:  1,
+			postId:  9,
+			content: "Is this place good?",
+			wantErr: config.Red + "No Post exist with this id" + config.Reset,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			mockRepo.EXPECT().Create(gomock.Any()).Return(tt.mockErr)
+			mockPostRepo.EXPECT().GetPostsByPId(tt.postId).Return(tt.posts, nil)
+			if len(tt.posts) > 0 {
+				mockRepo.EXPECT().Create(gomock.Any()).Return(tt.mockErr)
+			}
+			if len(tt.posts) > 0 && tt.mockErr == nil {
+				mockPrefsRepo.EXPECT().FindByUId(2).Return(&models.NotificationPrefs{UserId: 2, Delivery: models.DeliverDigest, Posts: models.PostsNone}, nil)
+				mockNotificationRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(notification *models.Notification) error {
+					assert.Equal(t, 2, notification.UserId)
+					// Digest users get it held; the posts setting only covers new posts.
+					assert.True(t, notification.Held)
+					assert.Equal(t, models.NotifyNewQuestion, notification.Type)
+					assert.Equal(t, tt.postId, notification.SourceId)
+					assert.Equal(t, `New question on your post "Momos": "Is this place good?"`, notification.Message)
+					return nil
+				})
+			}
 
 			err := questionService.AskQuestion(tt.userId, tt.postId, tt.content)
 
-			if tt.wantErr {
-				assert.Error(t, err)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
 			} else {
 				assert.NoError(t, err)
 			}
@@ -65,7 +96,7 @@ func TestQuestionService_DeleteQuesByPId(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockQuestionRepository(ctrl)
-	questionService := services.NewQuestionService(mockRepo)
+	mockAnswerRepo := mocks.NewMockAnswerRepository(ctrl)
 
 	tests := []struct {
 		name    string
@@ -89,6 +120,9 @@ func TestQuestionService_DeleteQuesByPId(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			uow := newUnitOfWork(ctrl, interfaces.Repositories{Questions: mockRepo, Answers: mockAnswerRepo})
+			questionService := services.NewQuestionService(mockRepo, mockAnswerRepo, uow)
+			mockAnswerRepo.EXPECT().DeleteByPId(tt.postId).Return(nil)
 			mockRepo.EXPECT().DeleteByPId(tt.postId).Return(tt.mockErr)
 
 			err := questionService.DeleteQuesByPId(tt.postId)
@@ -107,7 +141,8 @@ func TestQuestionService_GetPostQuestions(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockQuestionRepository(ctrl)
-	questionService := services.NewQuestionService(mockRepo)
+	mockAnswerRepo := mocks.NewMockAnswerRepository(ctrl)
+	questionService := services.NewQuestionService(mockRepo, mockAnswerRepo, nil)
 
 	tests := []struct {
 		name       string
@@ -135,6 +170,9 @@ func TestQuestionService_GetPostQuestions(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo.EXPECT().GetQuestionsByPId(tt.postId).Return(tt.mockResult, tt.mockErr)
+			if tt.mockErr == nil {
+				mockAnswerRepo.EXPECT().GetAnswersByPId(tt.postId).Return(nil, nil)
+			}
 
 			result, err := questionService.GetPostQuestions(tt.postId)
 
@@ -149,12 +187,38 @@ func TestQuestionService_GetPostQuestions(t *testing.T) {
 	}
 }
 
-func TestQuestionService_AddAnswer(t *testing.T) {
+func TestQuestionService_SearchQuestions(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockQuestionRepository(ctrl)
-	questionService := services.NewQuestionService(mockRepo)
+	mockAnswerRepo := mocks.NewMockAnswerRepository(ctrl)
+	service := services.NewQuestionService(mockRepo, mockAnswerRepo, nil)
+
+	mockRepo.EXPECT().SearchQuestions("momos", models.DefaultPageSize+1, 0).Return([]*models.Question{{QId: 3}}, nil)
+	mockAnswerRepo.EXPECT().GetAnswersByQId(3).Return([]*models.Answer{{AnswerId: 1, QId: 3, Text: "Yes"}}, nil)
+
+	page, err := service.SearchQuestions("  momos ", models.PageRequest{})
+	assert.NoError(t, err)
+	assert.Len(t, page.Items, 1)
+	assert.False(t, page.HasNext)
+	assert.Equal(t, "Yes", page.Items[0].Answers[0].Text)
+
+	_, err = service.SearchQuestions(" ", models.PageRequest{})
+	assert.EqualError(t, err, config.Red+"Enter something to search for"+config.Reset)
+	_, err = service.SearchQuestions("momos", models.PageRequest{Sort: models.SortNewest})
+	assert.EqualError(t, err, config.Red+"Unknown sort order newest, use relevance"+config.Reset)
+}
+
+func TestQuestionService_AddAnswer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAnswerRepo := mocks.NewMockAnswerRepository(ctrl)
+	mockQuesRepo := mocks.NewMockQuestionRepository(ctrl)
+	mockNotificationRepo := mocks.NewMockNotificationRepository(ctrl)
+	uow := newUnitsOfWork(ctrl, interfaces.Repositories{Answers: mockAnswerRepo, Questions: mockQuesRepo, Notifications: mockNotificationRepo}, 2)
+	questionService := services.NewQuestionService(mockQuesRepo, mockAnswerRepo, uow)
 
 	tests := []struct {
 		name    string
@@ -181,9 +245,21 @@ func TestQuestionService_AddAnswer(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			mockRepo.EXPECT().UpdateQuestion(tt.qId, tt.answer).Return(tt.mockErr)
+			mockAnswerRepo.EXPECT().
+				Create(gomock.Any()).
+				DoAndReturn(func(answer *models.Answer) error {
+					assert.Equal(t, tt.qId, answer.QId)
+					assert.Equal(t, 7, answer.UserId)
+					assert.Equal(t, tt.answer, answer.Text)
+					assert.WithinDuration(t, time.Now(), answer.CreatedAt, time.Second)
+					return tt.mockErr
+				})
+			if tt.mockErr == nil {
+				// The asker answering their own question is not told about it.
+				mockQuesRepo.EXPECT().FindByQId(tt.qId).Return(&models.Question{QId: tt.qId, UserId: 7, Text: "Is it good?"}, nil)
+			}
 
-			err := questionService.AddAnswer(tt.qId, tt.answer)
+			err := questionService.AddAnswer(tt.qId, 7, tt.answer)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -199,13 +275,16 @@ func TestDeleteUserQues_Success(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockQuestionRepository(ctrl)
-	service := services.NewQuestionService(mockRepo)
+	mockAnswerRepo := mocks.NewMockAnswerRepository(ctrl)
+	uow := newUnitOfWork(ctrl, interfaces.Repositories{Questions: mockRepo, Answers: mockAnswerRepo})
+	service := services.NewQuestionService(mockRepo, mockAnswerRepo, uow)
 
 	QId := 1
 	UId := 123
 
 	// Setup expectations
 	mockRepo.EXPECT().DeleteByQIdUId(QId, UId).Return(nil)
+	mockAnswerRepo.EXPECT().DeleteByQId(QId).Return(nil)
 
 	// Call the service method
 	err := service.DeleteUserQues(UId, QId)
@@ -219,7 +298,8 @@ func TestDeleteUserQues_Error(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockQuestionRepository(ctrl)
-	service := services.NewQuestionService(mockRepo)
+	uow := newUnitOfWork(ctrl, interfaces.Repositories{Questions: mockRepo})
+	service := services.NewQuestionService(mockRepo, nil, uow)
 
 	QId := 1
 	UId := 123
@@ -235,3 +315,62 @@ func TestDeleteUserQues_Error(t *testing.T) {
 	assert.Error(t, err)
 	assert.Equal(t, expectedError, err)
 }
+
+func TestQuestionService_AnswersInMemory(t *testing.T) {
+	users := repositories.NewInMemoryUserRepository()
+	posts := repositories.NewInMemoryPostRepository()
+	questions := repositories.NewInMemoryQuestionRepository()
+	answers := repositories.NewInMemoryAnswerRepository()
+	notifications := repositories.NewInMemoryNotificationRepository()
+	notificationPrefs := repositories.NewInMemoryNotificationPrefsRepository()
+	uow := repositories.NewInMemoryUnitOfWork(users, posts, questions, answers, repositories.NewInMemoryPostLikeRepository(), repositories.NewInMemorySessionRepository(), repositories.NewInMemoryAuditRepository(), repositories.NewInMemoryCategoryRepository(), repositories.NewInMemoryCityRepository(), repositories.NewInMemoryCommentRepository(), notifications, notificationPrefs, repositories.NewInMemoryOutboxRepository())
+	service := services.NewQuestionService(questions, answers, uow)
+
+	assert.NoError(t, users.Create(&models.User{Username: "asker"}))
+	assert.NoError(t, users.Create(&models.User{Username: "local"}))
+	assert.NoError(t, posts.Create(&models.Post{UId: 1, City: "delhi", Title: "Chaat"}))
+	assert.EqualError(t, service.AskQuestion(1, 5, "Lost"), config.Red+"No Post exist with this id"+config.Reset)
+	assert.NoError(t, service.AskQuestion(1, 1, "Best chaat nearby?"))
+
+	assert.NoError(t, service.AddAnswer(1, 2, "Try the corner stall"))
+	assert.EqualError(t, service.AddAnswer(9, 2, "Lost"), config.Red+"No Question exist with this id"+config.Reset)
+
+	// The asker hears about the answer; they wrote the post, so nobody
+	// hears about the question.
+	inbox, err := notifications.GetUserNotifications(1, true, models.SortOldest, 10, 0)
+	assert.NoError(t, err)
+	if assert.Len(t, inbox, 1) {
+		assert.Equal(t, models.NotifyNewAnswer, inbox[0].Type)
+		assert.Equal(t, models.TargetQuestion, inbox[0].SourceType)
+		assert.Equal(t, 1, inbox[0].SourceId)
+		assert.Equal(t, `New answer to your question "Best chaat nearby?": "Try the corner stall"`, inbox[0].Message)
+	}
+	count, err := notifications.CountUnread(2)
+	assert.NoError(t, err)
+	assert.Zero(t, count)
+
+	// Nor once they turn notifications off.
+	assert.NoError(t, notificationPrefs.Save(&models.NotificationPrefs{UserId: 1, Delivery: models.DeliverOff, Posts: models.PostsAll}))
+	assert.NoError(t, service.AddAnswer(1, 2, "Or the one by the metro"))
+	count, err = notifications.CountUnread(1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	result, err := service.GetPostQuestions(1)
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Len(t, result[0].Answers, 2)
+	assert.Equal(t, "local", result[0].Answers[0].Username)
+	assert.False(t, result[0].Answers[0].CreatedAt.IsZero())
+
+	answerId := result[0].Answers[0].AnswerId
+	assert.EqualError(t, service.EditAnswer(1, answerId, "hijack"), config.Red+"You can only update your answer"+config.Reset)
+	assert.NoError(t, service.EditAnswer(2, answerId, "Try the stall by the gate"))
+	assert.EqualError(t, service.DeleteMyAnswer(1, answerId), config.Red+"No Answer exist with this id"+config.Reset)
+
+	// Deleting the question takes its answers along.
+	assert.NoError(t, service.DeleteUserQues(1, 1))
+	remaining, err := answers.GetAllAnswers()
+	assert.NoError(t, err)
+	assert.Empty(t, remaining)
+}
@@ -0,0 +1,130 @@
+package services_test
+
+import (
+	"database/sql"
+	"errors"
+	"localEyes/config"
+	"localEyes/internal/models"
+	"localEyes/internal/repositories"
+	"localEyes/internal/services"
+	"localEyes/tests/mocks"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionService_StartStoresOnlyTheHash(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSessionRepo := mocks.NewMockSessionRepository(ctrl)
+	sessionService := services.NewSessionService(mockSessionRepo, mocks.NewMockUserRepository(ctrl))
+
+	var stored *models.Session
+	mockSessionRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(session *models.Session) error {
+		stored = session
+		return nil
+	})
+	mockSessionRepo.EXPECT().DeleteExpired(gomock.Any()).Return(int64(0), nil)
+
+	token, session, err := sessionService.Start(1)
+
+	require.NoError(t, err)
+	assert.Len(t, token, 43)
+	assert.Len(t, stored.TokenHash, 64)
+	assert.NotContains(t, stored.TokenHash, token)
+	assert.Equal(t, 1, session.UserId)
+	assert.WithinDuration(t, time.Now().Add(services.DefaultSessionTTL), session.ExpiresAt, time.Minute)
+}
+
+func TestSessionService_StartError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSessionRepo := mocks.NewMockSessionRepository(ctrl)
+	sessionService := services.NewSessionService(mockSessionRepo, mocks.NewMockUserRepository(ctrl))
+
+	mockSessionRepo.EXPECT().Create(gomock.Any()).Return(errors.New("insert error"))
+
+	token, session, err := sessionService.Start(1)
+
+	assert.EqualError(t, err, "insert error")
+	assert.Empty(t, token)
+	assert.Nil(t, session)
+}
+
+func TestSessionService_Authenticate_Unknown(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSessionRepo := mocks.NewMockSessionRepository(ctrl)
+	sessionService := services.NewSessionService(mockSessionRepo, mocks.NewMockUserRepository(ctrl))
+
+	mockSessionRepo.EXPECT().FindByTokenHash(gomock.Any()).Return(nil, sql.ErrNoRows)
+	_, err := sessionService.Authenticate("nope")
+	assert.EqualError(t, err, config.Red+"Invalid or expired session"+config.Reset)
+
+	mockSessionRepo.EXPECT().FindByTokenHash(gomock.Any()).Return(nil, errors.New("db down"))
+	_, err = sessionService.Authenticate("nope")
+	assert.EqualError(t, err, "db down")
+}
+
+func TestSessionService_Authenticate_Expired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSessionRepo := mocks.NewMockSessionRepository(ctrl)
+	sessionService := services.NewSessionService(mockSessionRepo, mocks.NewMockUserRepository(ctrl))
+
+	expired := &models.Session{TokenHash: "hash", UserId: 1, ExpiresAt: time.Now().Add(-time.Second)}
+	mockSessionRepo.EXPECT().FindByTokenHash(gomock.Any()).Return(expired, nil)
+	mockSessionRepo.EXPECT().DeleteByTokenHash("hash").Return(nil)
+
+	_, err := sessionService.Authenticate("token")
+	assert.EqualError(t, err, config.Red+"Invalid or expired session"+config.Reset)
+}
+
+func TestSessionService_InMemory(t *testing.T) {
+	users := repositories.NewInMemoryUserRepository()
+	sessions := repositories.NewInMemorySessionRepository()
+	sessionService := services.NewSessionService(sessions, users)
+	require.NoError(t, users.Create(&models.User{Username: "riya", IsActive: true}))
+	require.NoError(t, users.Create(&models.User{Username: "aman", IsActive: true}))
+
+	phone, _, err := sessionService.Start(1)
+	require.NoError(t, err)
+	laptop, _, err := sessionService.Start(1)
+	require.NoError(t, err)
+	other, _, err := sessionService.Start(2)
+	require.NoError(t, err)
+	assert.NotEqual(t, phone, laptop)
+
+	user, err := sessionService.Authenticate(phone)
+	require.NoError(t, err)
+	assert.Equal(t, "riya", user.Username)
+
+	require.NoError(t, sessionService.Logout(phone))
+	_, err = sessionService.Authenticate(phone)
+	assert.Error(t, err)
+	_, err = sessionService.Authenticate(laptop)
+	assert.NoError(t, err)
+
+	require.NoError(t, sessionService.LogoutAll(1))
+	_, err = sessionService.Authenticate(laptop)
+	assert.Error(t, err)
+	_, err = sessionService.Authenticate(other)
+	assert.NoError(t, err)
+
+	require.NoError(t, users.UpdateActiveStatus(2, false))
+	_, err = sessionService.Authenticate(other)
+	assert.EqualError(t, err, config.Red+"InActive Account"+config.Reset)
+
+	sessionService.TTL = -time.Minute
+	expired, _, err := sessionService.Start(1)
+	require.NoError(t, err)
+	_, err = sessionService.Authenticate(expired)
+	assert.EqualError(t, err, config.Red+"Invalid or expired session"+config.Reset)
+}
@@ -0,0 +1,148 @@
+package services_test
+
+import (
+	"database/sql"
+	"fmt"
+	"localEyes/config"
+	"localEyes/internal/interfaces"
+	"localEyes/internal/migrations"
+	"localEyes/internal/models"
+	"localEyes/internal/repositories"
+	"localEyes/internal/services"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+// newSQLiteRepositories opens a private in-memory SQLite database with every
+// migration applied and users 1 ("author") and 2 ("reader") in delhi, for
+// tests that need the SQL repositories' own behaviour.
+func newSQLiteRepositories(t *testing.T) (interfaces.Repositories, interfaces.UnitOfWork) {
+	db, err := sql.Open("sqlite", "file::memory:")
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	migrator, err := migrations.NewMigrator(db, config.SQLiteDriver)
+	require.NoError(t, err)
+	_, err = migrator.Up()
+	require.NoError(t, err)
+
+	repos := repositories.NewSQLRepositories(config.SQLiteDriver, db)
+	for _, name := range []string{"author", "reader"} {
+		require.NoError(t, repos.Users.Create(&models.User{Username: name, Password: "hash", City: "delhi", IsActive: true, Tag: "newbie", Notification: []string{}}))
+	}
+	return repos, repositories.NewSQLUnitOfWork(db, config.SQLiteDriver)
+}
+
+func TestSQLite_NewPostReachesTheCity(t *testing.T) {
+	repos, uow := newSQLiteRepositories(t)
+	postService := services.NewPostService(repos.Posts, repos.Likes, uow)
+	notificationService := services.NewNotificationService(repos.Notifications, repos.NotificationPrefs, uow)
+
+	post, err := postService.CreatePost(1, "Momos", "Near the metro", "food", models.Location{})
+	require.NoError(t, err)
+	assert.NotZero(t, post.PostId)
+	require.NoError(t, services.NewOutboxWorker(repos.Outbox, uow).Flush())
+
+	inbox, err := notificationService.Inbox(2, true, models.PageRequest{})
+	require.NoError(t, err)
+	require.Len(t, inbox.Items, 1)
+	assert.Equal(t, models.NotifyNewPost, inbox.Items[0].Type)
+	assert.Equal(t, "New post: Momos", inbox.Items[0].Message)
+	assert.Equal(t, models.TargetPost, inbox.Items[0].SourceType)
+	assert.Equal(t, post.PostId, inbox.Items[0].SourceId)
+	stored, err := postService.GivePost(2, post.PostId)
+	require.NoError(t, err)
+	assert.Equal(t, "Momos", stored.Title)
+	_, err = repos.Outbox.FindById(1)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestSQLite_MissingUserIsNotFound(t *testing.T) {
+	repos, uow := newSQLiteRepositories(t)
+	userService := services.NewUserService(repos.Users, uow)
+	postService := services.NewPostService(repos.Posts, repos.Likes, uow)
+	notFound := config.Red + "No user exist with this id" + config.Reset
+
+	assert.EqualError(t, userService.DeActivate(9), notFound)
+	assert.EqualError(t, userService.ChangeCity(9, "delhi"), notFound)
+	_, err := postService.CreatePost(9, "Momos", "Near the metro", "food", models.Location{})
+	assert.EqualError(t, err, notFound)
+	_, err = userService.Login("nobody", "secret@1")
+	assert.EqualError(t, err, config.Red+"Invalid Account credentials"+config.Reset)
+}
+
+func TestSQLite_SubscribeToUnknownAuthor(t *testing.T) {
+	repos, uow := newSQLiteRepositories(t)
+	service := services.NewNotificationService(repos.Notifications, repos.NotificationPrefs, uow)
+	noSuchUser := config.Red + "No user exist with this username" + config.Reset
+
+	assert.EqualError(t, service.Subscribe(2, models.TargetUser, "nobody"), noSuchUser)
+	assert.EqualError(t, service.Unsubscribe(2, models.TargetUser, "nobody"), noSuchUser)
+	require.NoError(t, service.Subscribe(2, models.TargetUser, "author"))
+	prefs, err := service.Preferences(2)
+	require.NoError(t, err)
+	require.Len(t, prefs.Subscriptions, 1)
+	assert.Equal(t, "author", prefs.Subscriptions[0].Name)
+}
+
+func TestSQLite_DeletingDropsPendingNotifications(t *testing.T) {
+	repos, uow := newSQLiteRepositories(t)
+	postService := services.NewPostService(repos.Posts, repos.Likes, uow)
+	adminService := services.NewAdminService(repos.Users, repos.Posts, repos.Questions, repos.Answers, repos.Audit, uow)
+	require.NoError(t, repos.Users.UpdateRole(2, models.RoleAdmin))
+	admin := &models.Admin{User: models.User{UId: 2, Username: "reader", Role: models.RoleAdmin, IsActive: true}}
+
+	mine, err := postService.CreatePost(1, "Momos", "Near the metro", "food", models.Location{})
+	require.NoError(t, err)
+	moderated, err := postService.CreatePost(1, "Metro", "Line 3 is shut", "food", models.Location{})
+	require.NoError(t, err)
+	last, err := postService.CreatePost(1, "Chaat", "By the gate", "food", models.Location{})
+	require.NoError(t, err)
+
+	require.NoError(t, postService.DeleteMyPost(1, mine.PostId))
+	require.NoError(t, adminService.DeletePost(admin, moderated.PostId))
+	due, err := repos.Outbox.GetDue(time.Now(), 10)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	assert.Equal(t, last.PostId, due[0].PostId)
+
+	require.NoError(t, adminService.DeleteUser(admin, 1))
+	due, err = repos.Outbox.GetDue(time.Now(), 10)
+	require.NoError(t, err)
+	assert.Empty(t, due)
+}
+
+func TestSQLite_ConcurrentAnswersAreAllKept(t *testing.T) {
+	repos, uow := newSQLiteRepositories(t)
+	postService := services.NewPostService(repos.Posts, repos.Likes, uow)
+	questionService := services.NewQuestionService(repos.Questions, repos.Answers, uow)
+	post, err := postService.CreatePost(1, "Momos", "Near the metro", "food", models.Location{})
+	require.NoError(t, err)
+	require.NoError(t, questionService.AskQuestion(2, post.PostId, "Best time to visit?"))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			assert.NoError(t, questionService.AddAnswer(1, 1, fmt.Sprintf(`answer "%d"]`, i)))
+		}(i)
+	}
+	wg.Wait()
+
+	answers, err := repos.Answers.GetAnswersByQId(1)
+	require.NoError(t, err)
+	require.Len(t, answers, 20)
+	texts := make([]string, len(answers))
+	for i, answer := range answers {
+		texts[i] = answer.Text
+	}
+	for i := 0; i < 20; i++ {
+		assert.Contains(t, texts, fmt.Sprintf(`answer "%d"]`, i))
+	}
+}
@@ -4,10 +4,13 @@ import (
 	"database/sql"
 	"errors"
 	"localEyes/config"
+	"localEyes/internal/interfaces"
 	"localEyes/internal/models"
+	"localEyes/internal/repositories"
 	"localEyes/internal/services"
 	"localEyes/tests/mocks"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -18,26 +21,39 @@ func TestUserService_Signup(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockUserRepository(ctrl)
-	userService := services.NewUserService(mockRepo)
+	mockCityRepo := mocks.NewMockCityRepository(ctrl)
+	uow := newUnitsOfWork(ctrl, interfaces.Repositories{Users: mockRepo, Cities: mockCityRepo}, 3)
+	userService := services.NewUserService(mockRepo, uow)
 
 	tests := []struct {
 		name          string
 		username      string
 		password      string
+		city          string
 		dwellingAge   int
 		tag           string
 		mockError     error
 		expectedError string
 	}{
-		{"Signup Success", "testuser", "password", 5, "tourist", nil, ""},
-		{"Signup Error", "testuser", "password", 5, "tourist", errors.New("creation error"), "creation error"},
+		{"Signup Success", "testuser", "password", " Pune", 5, "tourist", nil, ""},
+		{"Signup Error", "testuser", "password", "pune", 5, "tourist", errors.New("creation error"), "creation error"},
+		{"Signup Unknown City", "testuser", "password", "atlantis", 5, "tourist", nil, config.Red + "Unknown city atlantis, use delhi, pune" + config.Reset},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			mockRepo.EXPECT().Create(gomock.Any()).Return(tt.mockError)
+			if tt.city == "atlantis" {
+				mockCityRepo.EXPECT().FindByName("atlantis").Return(nil, sql.ErrNoRows)
+				mockCityRepo.EXPECT().GetAll().Return([]*models.City{{Id: 1, Name: "delhi"}, {Id: 2, Name: "pune"}}, nil)
+			} else {
+				mockCityRepo.EXPECT().FindByName("pune").Return(&models.City{Id: 2, Name: "pune"}, nil)
+				mockRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(user *models.User) error {
+					assert.Equal(t, "pune", user.City)
+					return tt.mockError
+				})
+			}
 
-			err := userService.Signup(tt.username, tt.password, tt.dwellingAge, tt.tag)
+			err := userService.Signup(tt.username, tt.password, tt.city, tt.dwellingAge, tt.tag)
 
 			if tt.expectedError != "" {
 				assert.Error(t, err)
@@ -54,7 +70,9 @@ func TestUserService_Login(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockUserRepository(ctrl)
-	userService := services.NewUserService(mockRepo)
+	userService := services.NewUserService(mockRepo, nil)
+	hashedPassword, err := services.HashPassword("password")
+	assert.NoError(t, err)
 
 	tests := []struct {
 		name          string
@@ -67,10 +85,17 @@ func TestUserService_Login(t *testing.T) {
 		{
 			"Login Success",
 			"testuser", "password",
-			&models.User{Username: "testuser", Password: services.HashPassword("password"), IsActive: true},
+			&models.User{Username: "testuser", Password: hashedPassword, IsActive: true},
 			nil,
 			"",
 		},
+		{
+			"Login Wrong Password",
+			"testuser", "wrongpassword",
+			&models.User{Username: "testuser", Password: hashedPassword, IsActive: true},
+			nil,
+			config.Red + "Invalid Account credentials" + config.Reset,
+		},
 		{
 			"Login Invalid Credentials",
 			"testuser", "password",
@@ -81,7 +106,7 @@ func TestUserService_Login(t *testing.T) {
 		{
 			"Login Inactive Account",
 			"testuser", "password",
-			&models.User{Username: "testuser", Password: services.HashPassword("password"), IsActive: false},
+			&models.User{Username: "testuser", Password: hashedPassword, IsActive: false},
 			nil,
 			config.Red + "InActive Account" + config.Reset,
 		},
@@ -89,7 +114,7 @@ func TestUserService_Login(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			mockRepo.EXPECT().FindByUsernamePassword(tt.username, services.HashPassword(tt.password)).Return(tt.mockUser, tt.mockError)
+			mockRepo.EXPECT().FindByUsername(tt.username).Return(tt.mockUser, tt.mockError)
 
 			user, err := userService.Login(tt.username, tt.password)
 
@@ -106,62 +131,109 @@ func TestUserService_Login(t *testing.T) {
 	}
 }
 
-func TestUserService_DeActivate(t *testing.T) {
+func TestUserService_Login_UpgradesLegacyHash(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockUserRepository(ctrl)
-	userService := services.NewUserService(mockRepo)
+	userService := services.NewUserService(mockRepo, nil)
+
+	// Unsalted SHA-256 of "password", as stored before bcrypt.
+	legacyHash := "5e884898da28047151d0e56f8dc6292773603d0d6aabbdd62a11ef721d1542d8"
+	mockRepo.EXPECT().FindByUsername("testuser").
+		Return(&models.User{UId: 7, Username: "testuser", Password: legacyHash, IsActive: true}, nil)
+	mockRepo.EXPECT().UpdatePassword(7, gomock.Any()).
+		DoAndReturn(func(UId int, hashed string) error {
+			match, needsRehash := services.VerifyPassword(hashed, "password")
+			assert.True(t, match)
+			assert.False(t, needsRehash)
+			return nil
+		})
 
-	tests := []struct {
-		name          string
-		UId           int
-		mockError     error
-		expectedError string
-	}{
-		{"DeActivate Success", 1, nil, ""},
-		{"DeActivate Error", 1, errors.New("update error"), "update error"},
-	}
+	user, err := userService.Login("testuser", "password")
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			mockRepo.EXPECT().UpdateActiveStatus(tt.UId, false).Return(tt.mockError)
+	assert.NoError(t, err)
+	assert.NotEqual(t, legacyHash, user.Password)
+}
 
-			err := userService.DeActivate(tt.UId)
+func TestUserService_Login_UnknownUserTakesAsLongAsWrongPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
 
-			if tt.expectedError != "" {
-				assert.Error(t, err)
-				assert.Equal(t, tt.expectedError, err.Error())
-			} else {
-				assert.NoError(t, err)
-			}
-		})
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+	userService := services.NewUserService(mockRepo, nil)
+	hashedPassword, err := services.HashPassword("password")
+	assert.NoError(t, err)
+	mockRepo.EXPECT().FindByUsername("testuser").
+		Return(&models.User{Username: "testuser", Password: hashedPassword, IsActive: true}, nil).AnyTimes()
+	mockRepo.EXPECT().FindByUsername("nobody").Return(nil, sql.ErrNoRows).AnyTimes()
+
+	// Warm up so neither side pays for the stand-in hash being built.
+	_, _ = userService.Login("nobody", "guess")
+	timeLogin := func(username string) time.Duration {
+		start := time.Now()
+		_, err := userService.Login(username, "guess")
+		assert.Error(t, err)
+		return time.Since(start)
 	}
+	wrongPassword, unknownUser := timeLogin("testuser"), timeLogin("nobody")
+
+	// Both run one bcrypt comparison; without it the unknown user returns
+	// thousands of times sooner.
+	assert.Greater(t, unknownUser, wrongPassword/4)
+}
+
+func TestVerifyPassword(t *testing.T) {
+	hashed, err := services.HashPassword("secret@1")
+	assert.NoError(t, err)
+	again, err := services.HashPassword("secret@1")
+	assert.NoError(t, err)
+	assert.NotEqual(t, hashed, again, "hashes should be salted")
+
+	match, needsRehash := services.VerifyPassword(hashed, "secret@1")
+	assert.True(t, match)
+	assert.False(t, needsRehash)
+
+	match, _ = services.VerifyPassword(hashed, "secret@2")
+	assert.False(t, match)
 }
 
-func TestUserService_NotifyUsers(t *testing.T) {
+func TestUserService_DeActivate(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockUserRepository(ctrl)
-	userService := services.NewUserService(mockRepo)
+	mockAuditRepo := mocks.NewMockAuditRepository(ctrl)
 
 	tests := []struct {
 		name          string
 		UId           int
-		title         string
 		mockError     error
 		expectedError string
 	}{
-		{"NotifyUsers Success", 1, "Test Notification", nil, ""},
-		{"NotifyUsers Error", 1, "Test Notification", errors.New("notification error"), "notification error"},
+		{"DeActivate Success", 1, nil, ""},
+		{"DeActivate Error", 1, errors.New("update error"), "update error"},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			mockRepo.EXPECT().PushNotification(tt.UId, tt.title).Return(tt.mockError)
+			uow := newUnitOfWork(ctrl, interfaces.Repositories{Users: mockRepo, Audit: mockAuditRepo})
+			userService := services.NewUserService(mockRepo, uow)
+			mockRepo.EXPECT().FindByUId(tt.UId).Return(&models.User{UId: tt.UId, Username: "riya", IsActive: true}, nil)
+			mockRepo.EXPECT().UpdateActiveStatus(tt.UId, false).Return(tt.mockError)
+			if tt.mockError == nil {
+				mockAuditRepo.EXPECT().
+					Create(gomock.Any()).
+					DoAndReturn(func(entry *models.AuditEntry) error {
+						assert.Equal(t, models.AuditDeactivateUser, entry.Action)
+						assert.Equal(t, tt.UId, entry.ActorId)
+						assert.Contains(t, entry.Before, `"active":true`)
+						assert.Contains(t, entry.After, `"active":false`)
+						return nil
+					})
+			}
 
-			err := userService.NotifyUsers(tt.UId, tt.title)
+			err := userService.DeActivate(tt.UId)
 
 			if tt.expectedError != "" {
 				assert.Error(t, err)
@@ -173,36 +245,62 @@ func TestUserService_NotifyUsers(t *testing.T) {
 	}
 }
 
-func TestUserService_UnNotifyUsers(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
+func TestUserService_InMemory_SignupLoginDeactivate(t *testing.T) {
+	users := repositories.NewInMemoryUserRepository()
+	audit := repositories.NewInMemoryAuditRepository()
+	uow := repositories.NewInMemoryUnitOfWork(users, repositories.NewInMemoryPostRepository(), repositories.NewInMemoryQuestionRepository(),
+		repositories.NewInMemoryAnswerRepository(), repositories.NewInMemoryPostLikeRepository(), repositories.NewInMemorySessionRepository(), audit, repositories.NewInMemoryCategoryRepository(), repositories.NewInMemoryCityRepository(), repositories.NewInMemoryCommentRepository(), repositories.NewInMemoryNotificationRepository(), repositories.NewInMemoryNotificationPrefsRepository(), repositories.NewInMemoryOutboxRepository())
+	userService := services.NewUserService(users, uow)
 
-	mockRepo := mocks.NewMockUserRepository(ctrl)
-	userService := services.NewUserService(mockRepo)
+	err := userService.Signup("riya", "secret@1", "Delhi", 3, "resident")
+	assert.NoError(t, err)
 
-	tests := []struct {
-		name          string
-		UId           int
-		mockError     error
-		expectedError string
-	}{
-		{"UnNotifyUsers Success", 1, nil, ""},
-		{"UnNotifyUsers NoRows Error", 1, sql.ErrNoRows, ""},
-		{"UnNotifyUsers Error", 1, errors.New("clear notification error"), "clear notification error"},
-	}
+	user, err := userService.Login("riya", "secret@1")
+	assert.NoError(t, err)
+	assert.Equal(t, "delhi", user.City)
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			mockRepo.EXPECT().ClearNotification(tt.UId).Return(tt.mockError)
+	_, err = userService.Login("riya", "wrong@1")
+	assert.EqualError(t, err, config.Red+"Invalid Account credentials"+config.Reset)
 
-			err := userService.UnNotifyUsers(tt.UId)
+	assert.NoError(t, userService.DeActivate(user.UId))
+	_, err = userService.Login("riya", "secret@1")
+	assert.EqualError(t, err, config.Red+"InActive Account"+config.Reset)
 
-			if tt.expectedError != "" {
-				assert.Error(t, err)
-				assert.Equal(t, tt.expectedError, err.Error())
-			} else {
-				assert.NoError(t, err)
-			}
-		})
+	entries, err := audit.Find(models.AuditFilter{ActorId: user.UId})
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, models.AuditDeactivateUser, entries[0].Action)
+		assert.Equal(t, "riya", entries[0].ActorName)
 	}
 }
+
+func TestUserService_InMemory_ChangeCity(t *testing.T) {
+	users := repositories.NewInMemoryUserRepository()
+	cities := repositories.NewInMemoryCityRepository()
+	notifications := repositories.NewInMemoryNotificationRepository()
+	notificationPrefs := repositories.NewInMemoryNotificationPrefsRepository()
+	uow := repositories.NewInMemoryUnitOfWork(users, repositories.NewInMemoryPostRepository(), repositories.NewInMemoryQuestionRepository(),
+		repositories.NewInMemoryAnswerRepository(), repositories.NewInMemoryPostLikeRepository(), repositories.NewInMemorySessionRepository(),
+		repositories.NewInMemoryAuditRepository(), repositories.NewInMemoryCategoryRepository(), cities, repositories.NewInMemoryCommentRepository(), notifications, notificationPrefs, repositories.NewInMemoryOutboxRepository())
+	userService := services.NewUserService(users, uow)
+	assert.NoError(t, cities.Create(&models.City{Name: "pune"}))
+	assert.NoError(t, userService.Signup("riya", "secret@1", "delhi", 3, "resident"))
+	assert.NoError(t, userService.Signup("arjun", "secret@1", "pune", 1, "newbie"))
+
+	assert.EqualError(t, userService.ChangeCity(1, "Delhi"), config.Red+"You already live in delhi"+config.Reset)
+	assert.EqualError(t, userService.ChangeCity(1, "goa"), config.Red+"Unknown city goa, use delhi, pune"+config.Reset)
+	assert.EqualError(t, userService.ChangeCity(9, "pune"), config.Red+"No user exist with this id"+config.Reset)
+	assert.NoError(t, userService.ChangeCity(1, "pune"))
+
+	user, err := users.FindByUId(1)
+	assert.NoError(t, err)
+	assert.Equal(t, "pune", user.City)
+
+	// New posts now reach the people of the new city.
+	notificationService := services.NewNotificationService(notifications, notificationPrefs, uow)
+	assert.NoError(t, notificationService.NotifyNewPost(&models.Post{PostId: 1, UId: 1, City: user.City, Title: "Vada pav"}))
+	unread, err := notificationService.TakeUnread(2)
+	assert.NoError(t, err)
+	assert.Len(t, unread, 1)
+	assert.Equal(t, "New post: Vada pav", unread[0].Message)
+}
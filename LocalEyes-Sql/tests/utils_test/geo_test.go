@@ -0,0 +1,38 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"localEyes/utils"
+)
+
+func TestValidCoordinates(t *testing.T) {
+	assert.True(t, utils.ValidCoordinates(28.6315, 77.2167))
+	assert.True(t, utils.ValidCoordinates(-90, 180))
+	assert.False(t, utils.ValidCoordinates(90.5, 0))
+	assert.False(t, utils.ValidCoordinates(0, -180.5))
+}
+
+func TestDistanceKm(t *testing.T) {
+	// Connaught Place to India Gate, and Delhi to Mumbai.
+	assert.InDelta(t, 2.4, utils.DistanceKm(28.6315, 77.2167, 28.6129, 77.2295), 0.1)
+	assert.InDelta(t, 1150, utils.DistanceKm(28.6139, 77.2090, 19.0760, 72.8777), 10)
+	assert.Zero(t, utils.DistanceKm(28.6315, 77.2167, 28.6315, 77.2167))
+}
+
+func TestAreaAround(t *testing.T) {
+	area := utils.AreaAround(28.6315, 77.2167, 5)
+	for _, bearing := range [][2]float64{{1, 0}, {-1, 0}, {0, 1}, {0, -1}} {
+		// A point just inside the radius in each direction is inside the box.
+		lat := 28.6315 + bearing[0]*0.0449
+		lng := 77.2167 + bearing[1]*0.0511
+		assert.LessOrEqual(t, utils.DistanceKm(28.6315, 77.2167, lat, lng), 5.0)
+		assert.True(t, lat >= area.MinLatitude && lat <= area.MaxLatitude && lng >= area.MinLongitude && lng <= area.MaxLongitude)
+	}
+
+	polar := utils.AreaAround(89.99, 10, 50)
+	assert.Equal(t, 90.0, polar.MaxLatitude)
+	assert.Equal(t, -180.0, polar.MinLongitude)
+	assert.Equal(t, 180.0, polar.MaxLongitude)
+}
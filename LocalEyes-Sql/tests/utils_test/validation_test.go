@@ -26,21 +26,25 @@ func TestValidatePassword(t *testing.T) {
 	}
 }
 
-func TestValidateFilter(t *testing.T) {
+func TestValidateCategoryName(t *testing.T) {
 	tests := []struct {
-		filter   string
+		name     string
 		expected bool
 	}{
 		{"food", true},
-		{"travel", true},
-		{"shopping", true},
-		{"other", true},
-		{"invalid", false},
+		{"healthcare", true},
+		{"street-food", true},
+		{"top10", true},
+		{"", false},
+		{"x", false},
+		{"Events", false},
+		{"late night", false},
+		{"a-very-long-category-name-indeed", false},
 	}
 
 	for _, test := range tests {
-		result := utils.ValidateFilter(test.filter)
-		assert.Equal(t, test.expected, result)
+		result := utils.ValidateCategoryName(test.name)
+		assert.Equal(t, test.expected, result, test.name)
 	}
 }
 
@@ -75,3 +79,36 @@ func TestValidateUsername_UsernameNotFound(t *testing.T) {
 	result := utils.ValidateUsername("newuser", mockRepo)
 	assert.True(t, result, "Username not found in the repository should be valid")
 }
+
+func TestValidateUsername_Reserved(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// The repository is never asked, the name is refused outright.
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+
+	for _, username := range []string{"admin", "Admin", "ADMIN"} {
+		assert.False(t, utils.ValidateUsername(username, mockRepo), username+" should be reserved")
+	}
+}
+
+func TestValidateCityName(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected bool
+	}{
+		{"delhi", true},
+		{"new delhi", true},
+		{"navi-mumbai", true},
+		{"", false},
+		{"x", false},
+		{"Pune", false},
+		{" pune", false},
+		{"sector 17", false},
+	}
+
+	for _, test := range tests {
+		result := utils.ValidateCityName(test.name)
+		assert.Equal(t, test.expected, result, test.name)
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"localEyes/internal/interfaces"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 )
 
 func PromptInput(prompt string) string {
@@ -55,3 +57,31 @@ func PromptPassword(promptInstance interfaces.PromptInterface) string {
 	}
 	return result
 }
+
+var colours = strings.NewReplacer(config.Reset, "", config.Red, "", config.Green, "", config.Yellow, "",
+	config.Blue, "", config.Magenta, "", config.Cyan, "", config.Gray, "")
+
+// PlainText strips the terminal colours the services put into their
+// messages, for front ends that are not a terminal.
+func PlainText(message string) string {
+	return strings.TrimSpace(colours.Replace(message))
+}
+
+var timeLayouts = []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02"}
+
+// ParseTimeBound reads one end of a time range in local time. A bare date
+// covers the whole day, so as the end of a range it means the day's last second.
+func ParseTimeBound(value string, end bool) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range timeLayouts {
+		parsed, err := time.ParseInLocation(layout, value, time.Local)
+		if err != nil {
+			continue
+		}
+		if end && layout == "2006-01-02" {
+			parsed = parsed.Add(24*time.Hour - time.Second)
+		}
+		return parsed, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q, use YYYY-MM-DD, YYYY-MM-DD HH:MM or RFC 3339", value)
+}
@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"localEyes/internal/models"
+	"math"
+)
+
+// earthRadiusKm is the mean radius of the Earth.
+const earthRadiusKm = 6371.0
+
+// ValidCoordinates reports whether latitude and longitude name a point on
+// the globe.
+func ValidCoordinates(latitude, longitude float64) bool {
+	return latitude >= -90 && latitude <= 90 && longitude >= -180 && longitude <= 180
+}
+
+// DistanceKm is the great-circle distance between two points, by the
+// haversine formula.
+func DistanceKm(lat1, lng1, lat2, lng2 float64) float64 {
+	phi1, phi2 := radians(lat1), radians(lat2)
+	dPhi, dLambda := radians(lat2-lat1), radians(lng2-lng1)
+	a := math.Sin(dPhi/2)*math.Sin(dPhi/2) + math.Cos(phi1)*math.Cos(phi2)*math.Sin(dLambda/2)*math.Sin(dLambda/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// AreaAround returns a box holding every point within radiusKm of the
+// centre. A circle over a pole takes every longitude; one over the
+// antimeridian is clamped rather than wrapped, which only loses points no
+// city of ours is near.
+func AreaAround(latitude, longitude, radiusKm float64) models.Area {
+	dLat := radiusKm / earthRadiusKm * 180 / math.Pi
+	area := models.Area{
+		MinLatitude:  math.Max(-90, latitude-dLat),
+		MaxLatitude:  math.Min(90, latitude+dLat),
+		MinLongitude: -180,
+		MaxLongitude: 180,
+	}
+	if area.MinLatitude > -90 && area.MaxLatitude < 90 {
+		dLng := dLat / math.Cos(radians(latitude))
+		area.MinLongitude = math.Max(-180, longitude-dLng)
+		area.MaxLongitude = math.Min(180, longitude+dLng)
+	}
+	return area
+}
+
+func radians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
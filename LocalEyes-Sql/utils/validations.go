@@ -5,18 +5,15 @@ import (
 	"strings"
 )
 
+// ValidateUsername reports whether username is still free. "admin" stays
+// reserved in any case so nobody can sign up passing for the staff; admin
+// rights themselves come from roles rather than names.
 func ValidateUsername(username string, userRepo interfaces.UserRepository) bool {
-	if username == "admin" || username == "Admin" {
+	if strings.EqualFold(username, "admin") {
 		return false
 	}
-	user, err := userRepo.FindByUsername(username)
-	if user == nil || err != nil {
-		return true
-	}
-	//if errors.Is(err, sql.ErrNoRows) {
-	//	return true
-	//}
-	return false
+	_, err := userRepo.FindByUsername(username)
+	return err != nil
 }
 
 func ValidatePassword(password string) bool {
@@ -30,6 +27,30 @@ func ValidatePassword(password string) bool {
 	return false
 }
 
-func ValidateFilter(filter string) bool {
-	return filter == "food" || filter == "travel" || filter == "shopping" || filter == "other" || filter == ""
+// ValidateCategoryName reports whether name can name a category: 2 to 30
+// lowercase letters, digits and hyphens, so it reads well as a --type value.
+func ValidateCategoryName(name string) bool {
+	if len(name) < 2 || len(name) > 30 {
+		return false
+	}
+	for _, r := range name {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidateCityName reports whether name can name a city: 2 to 40 lowercase
+// letters, spaces and hyphens, such as "new delhi" or "navi-mumbai".
+func ValidateCityName(name string) bool {
+	if len(name) < 2 || len(name) > 40 || strings.TrimSpace(name) != name {
+		return false
+	}
+	for _, r := range name {
+		if (r < 'a' || r > 'z') && r != ' ' && r != '-' {
+			return false
+		}
+	}
+	return true
 }